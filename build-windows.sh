@@ -12,6 +12,10 @@ OUTPUT_NAME="game-control.exe"
 DIST_DIR="${ROOT_DIR}/dist/${GOOS_TARGET}-${GOARCH_TARGET}"
 OUTPUT_PATH="${DIST_DIR}/${OUTPUT_NAME}"
 SOURCE_PATH="./cmd/game-control"
+VERSION="${VERSION:-$(git -C "${ROOT_DIR}" describe --tags --always --dirty 2>/dev/null || echo dev)}"
+COMMIT="$(git -C "${ROOT_DIR}" rev-parse --short HEAD 2>/dev/null || echo dev)"
+BUILD_DATE="$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+LDFLAGS="-X main.Version=${VERSION} -X main.Commit=${COMMIT} -X main.BuildDate=${BUILD_DATE}"
 
 echo "=========================================="
 echo "Windows 版本编译脚本"
@@ -22,6 +26,7 @@ echo "  目标平台: ${GOOS_TARGET}/${GOARCH_TARGET}"
 echo "  输出目录: ${DIST_DIR}"
 echo "  输出文件: ${OUTPUT_PATH}"
 echo "  构建入口: ${SOURCE_PATH}"
+echo "  版本: ${VERSION} (${COMMIT})"
 echo ""
 
 mkdir -p "${DIST_DIR}"
@@ -29,7 +34,7 @@ mkdir -p "${DIST_DIR}"
 echo "开始编译..."
 (
   cd "${ROOT_DIR}"
-  GOOS="${GOOS_TARGET}" GOARCH="${GOARCH_TARGET}" go build -o "${OUTPUT_PATH}" "${SOURCE_PATH}"
+  GOOS="${GOOS_TARGET}" GOARCH="${GOARCH_TARGET}" go build -ldflags "${LDFLAGS}" -o "${OUTPUT_PATH}" "${SOURCE_PATH}"
 )
 
 # 打包运行所需附加文件
@@ -64,5 +69,6 @@ echo "  add-autostart.bat"
 echo "  remove-autostart.bat"
 echo "  game-control.exe status [config]"
 echo "  game-control.exe validate [config]"
+echo "  game-control.exe version"
 echo "  game-control.exe help"
 echo ""
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/singleinstance"
+)
+
+// backgroundStartTimeout 等待后台守护进程取得单实例锁的最长时间
+const backgroundStartTimeout = 10 * time.Second
+
+// backgroundArgs 返回后台重新启动自身时使用的参数。
+// 子进程显式以 --foreground 运行，避免再次派生；配置路径已转为绝对路径，不依赖工作目录
+func backgroundArgs(opts startOptions, absConfig string) []string {
+	args := append([]string{"start", "--foreground"}, startFlags(opts)...)
+	return append(args, absConfig)
+}
+
+// startFlags 返回重新启动守护进程时需要原样保留的 start 开关（后台启动、看护进程重启时使用）
+func startFlags(opts startOptions) []string {
+	var flags []string
+	if opts.requireAdmin {
+		flags = append(flags, "--require-admin")
+	}
+	if opts.dryRun {
+		flags = append(flags, "--dry-run")
+	}
+	return flags
+}
+
+// detachedCommand 构造脱离当前终端运行的命令：
+// Windows 下不创建控制台窗口，其他平台下在新会话中运行，关闭终端不会结束子进程
+func detachedCommand(exePath string, args ...string) *exec.Cmd {
+	cmd := exec.Command(exePath, args...)
+	cmd.SysProcAttr = detachAttrs()
+	return cmd
+}
+
+// startBackground 在后台重新启动守护进程，子进程取得单实例锁后返回
+func startBackground(opts startOptions, lockName string, lockOpts singleinstance.Options) error {
+	if pid, err := runningPID(lockName, lockOpts); err == nil {
+		return alreadyRunning(i18n.T("cli.background.alreadyRunning", pid))
+	}
+	absConfig, err := absConfigPath(opts.configPath)
+	if err != nil {
+		return err
+	}
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.exePathFailed"), err)
+	}
+
+	cmd := detachedCommand(exePath, backgroundArgs(opts, absConfig)...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.background.startFailed"), err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	pid, err := waitForDaemon(func() (int, error) {
+		return runningPID(lockName, lockOpts)
+	}, exited, backgroundStartTimeout)
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("cli.background.started", pid))
+	return nil
+}
+
+// waitForDaemon 轮询 running 直到守护进程取得锁；子进程提前退出或超时时返回错误
+func waitForDaemon(running func() (int, error), exited <-chan error, timeout time.Duration) (int, error) {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if pid, err := running(); err == nil {
+			return pid, nil
+		}
+		select {
+		case err := <-exited:
+			// 退出前可能刚好取得过锁，以最后一次检查为准
+			if pid, checkErr := running(); checkErr == nil {
+				return pid, nil
+			}
+			if err == nil {
+				return 0, errors.New(i18n.T("cli.background.exited"))
+			}
+			return 0, fmt.Errorf("%s: %w", i18n.T("cli.background.exited"), err)
+		case <-deadline:
+			return 0, errors.New(i18n.T("cli.background.timeout", timeout))
+		case <-ticker.C:
+		}
+	}
+}
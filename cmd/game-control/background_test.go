@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBackgroundArgs(t *testing.T) {
+	opts := startOptions{configPath: "kid.yaml", dryRun: true, background: true}
+	got := backgroundArgs(opts, "/abs/kid.yaml")
+	want := []string{"start", "--foreground", "--dry-run", "/abs/kid.yaml"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("后台启动参数应为 %v，实际 %v", want, got)
+	}
+	if slices.Contains(got, "--background") {
+		t.Error("子进程不应再次以 --background 启动")
+	}
+}
+
+func TestDetachedCommand(t *testing.T) {
+	cmd := detachedCommand("/bin/game-control", "start", "--foreground", "/abs/kid.yaml")
+	if want := []string{"/bin/game-control", "start", "--foreground", "/abs/kid.yaml"}; !slices.Equal(cmd.Args, want) {
+		t.Errorf("命令参数应为 %v，实际 %v", want, cmd.Args)
+	}
+	if cmd.SysProcAttr == nil {
+		t.Fatal("后台命令应设置脱离终端的进程属性")
+	}
+	if cmd.Stdout != nil || cmd.Stderr != nil {
+		t.Error("后台进程不应连接到父进程的输出")
+	}
+}
+
+func TestWaitForDaemon(t *testing.T) {
+	t.Run("取得锁后返回", func(t *testing.T) {
+		checks := 0
+		pid, err := waitForDaemon(func() (int, error) {
+			checks++
+			if checks < 3 {
+				return 0, errors.New("未运行")
+			}
+			return 4321, nil
+		}, make(chan error), time.Second)
+		if err != nil || pid != 4321 {
+			t.Fatalf("应返回子进程 PID 4321，实际 %d, %v", pid, err)
+		}
+	})
+
+	t.Run("子进程提前退出", func(t *testing.T) {
+		exited := make(chan error, 1)
+		exited <- errors.New("exit status 1")
+		_, err := waitForDaemon(func() (int, error) {
+			return 0, errors.New("未运行")
+		}, exited, time.Second)
+		if err == nil {
+			t.Fatal("子进程未取得锁即退出时应返回错误")
+		}
+	})
+
+	t.Run("超时", func(t *testing.T) {
+		_, err := waitForDaemon(func() (int, error) {
+			return 0, errors.New("未运行")
+		}, make(chan error), 200*time.Millisecond)
+		if err == nil {
+			t.Fatal("超时未取得锁时应返回错误")
+		}
+	})
+}
+
+func TestWatchdogArgs_ForwardStartFlags(t *testing.T) {
+	dir := t.TempDir()
+	opts := startOptions{configPath: filepath.Join(dir, "kid.yaml"), dryRun: true, requireAdmin: true, background: true}
+
+	args, err := watchdogArgs(opts)
+	if err != nil {
+		t.Fatalf("watchdogArgs 失败: %v", err)
+	}
+	want := []string{"watchdog", "--require-admin", "--dry-run", opts.configPath}
+	if !slices.Equal(args, want) {
+		t.Fatalf("看护进程参数应为 %v，实际 %v", want, args)
+	}
+
+	// 看护进程以收到的开关重新启动守护进程，不会变成强制模式或跳过管理员检查
+	watched, err := parseStartArgs(args[1:])
+	if err != nil {
+		t.Fatalf("看护进程应能解析转交的参数: %v", err)
+	}
+	respawn := backgroundArgs(watched, opts.configPath)
+	want = []string{"start", "--foreground", "--require-admin", "--dry-run", opts.configPath}
+	if !slices.Equal(respawn, want) {
+		t.Errorf("重新启动的守护进程参数应为 %v，实际 %v", want, respawn)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package main
+
+import "syscall"
+
+const (
+	detachedProcess = 0x00000008 // DETACHED_PROCESS：不继承父进程的控制台
+	createNoWindow  = 0x08000000 // CREATE_NO_WINDOW：不创建新的控制台窗口
+)
+
+// detachAttrs 使子进程不继承也不创建控制台窗口，关闭启动它的命令行窗口不会结束子进程
+func detachAttrs() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: detachedProcess | createNoWindow | syscall.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/privilege"
+	"github.com/yourusername/game-control/pkg/quota"
+	"github.com/yourusername/game-control/pkg/singleinstance"
+)
+
+// 命令行退出码，供包装脚本区分失败原因
+const (
+	exitOK             = 0
+	exitError          = 1 // 其他错误（参数错误、执行失败等）
+	exitConfig         = 2 // 配置文件无法加载或未通过校验
+	exitAlreadyRunning = 3 // 守护进程或看护进程已在运行
+	exitNeedsAdmin     = 4 // 需要管理员权限
+	exitNoState        = 5 // 没有状态文件（守护进程尚未运行过）
+)
+
+// exitCode 按错误链中的哨兵错误返回退出码，err 为 nil 时返回 exitOK
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, config.ErrInvalid):
+		return exitConfig
+	case errors.Is(err, singleinstance.ErrAlreadyRunning):
+		return exitAlreadyRunning
+	case errors.Is(err, privilege.ErrNotElevated):
+		return exitNeedsAdmin
+	case errors.Is(err, quota.ErrNoState):
+		return exitNoState
+	default:
+		return exitError
+	}
+}
+
+// runningError 已有实例在运行时的错误，文字由调用方给出，同时包含 singleinstance.ErrAlreadyRunning
+type runningError struct {
+	msg string
+}
+
+func (e runningError) Error() string {
+	return e.msg
+}
+
+func (e runningError) Unwrap() error {
+	return singleinstance.ErrAlreadyRunning
+}
+
+// alreadyRunning 以 msg 为文字创建包含 singleinstance.ErrAlreadyRunning 的错误
+func alreadyRunning(msg string) error {
+	return runningError{msg: msg}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/privilege"
+	"github.com/yourusername/game-control/pkg/singleinstance"
+)
+
+func TestExitCode_ConfigErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("dailyLimit: [1, 2\n"), 0644); err != nil {
+		t.Fatalf("写入配置失败: %v", err)
+	}
+	_, err := loadConfig(path)
+	if got := exitCode(err); got != exitConfig {
+		t.Errorf("配置无法解析时退出码应为 %d，实际 %d（%v）", exitConfig, got, err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.DailyLimit = 0
+	err = fmt.Errorf("配置验证失败: %w", cfg.Validate())
+	if got := exitCode(err); got != exitConfig {
+		t.Errorf("配置校验失败时退出码应为 %d，实际 %d（%v）", exitConfig, got, err)
+	}
+}
+
+func TestExitCode_AlreadyRunning(t *testing.T) {
+	lockOpts := singleinstance.Options{Dir: t.TempDir()}
+	guard, err := singleinstance.AcquireWithOptions("exit-code-test", lockOpts)
+	if err != nil {
+		t.Fatalf("获取单实例锁失败: %v", err)
+	}
+	defer guard.Release()
+
+	err = startBackground(startOptions{configPath: "config.yaml"}, "exit-code-test", lockOpts)
+	if got := exitCode(err); got != exitAlreadyRunning {
+		t.Errorf("已在运行时退出码应为 %d，实际 %d（%v）", exitAlreadyRunning, got, err)
+	}
+	if err == nil || err.Error() != fmt.Sprintf("控制器已在运行 (PID: %d)", os.Getpid()) {
+		t.Errorf("错误文字不应改变，实际 %v", err)
+	}
+}
+
+func TestExitCode_NoState(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StateFile = filepath.Join(t.TempDir(), "state.json")
+
+	err := printQuotaStatus(cfg, nil, nil)
+	if got := exitCode(err); got != exitNoState {
+		t.Errorf("没有状态文件时退出码应为 %d，实际 %d（%v）", exitNoState, got, err)
+	}
+}
+
+func TestExitCode_Others(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "成功", err: nil, want: exitOK},
+		{name: "需要管理员权限", err: fmt.Errorf("终止游戏进程需要管理员权限: %w", privilege.ErrNotElevated), want: exitNeedsAdmin},
+		{name: "看护进程已在运行", err: alreadyRunning("看护进程已在运行"), want: exitAlreadyRunning},
+		{name: "其他错误", err: errors.New("未知参数: --x"), want: exitError},
+	}
+
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("%s: 退出码应为 %d，实际 %d", tt.name, tt.want, got)
+		}
+	}
+}
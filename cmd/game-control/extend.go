@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/yourusername/game-control/internal"
+	"github.com/yourusername/game-control/pkg/i18n"
+)
+
+// extendOptions extend 命令参数
+type extendOptions struct {
+	minutes    int
+	configPath string
+	profile    string
+	password   string
+}
+
+// parseExtendArgs 解析 extend 命令参数（不含命令名本身）：<minutes> [config] [--profile NAME] [--password P]
+func parseExtendArgs(args []string) (extendOptions, error) {
+	var opts extendOptions
+	password, rest, err := takePasswordFlag(args)
+	if err != nil {
+		return opts, err
+	}
+	opts.password = password
+
+	profile, rest, err := takeProfileFlag(rest)
+	if err != nil {
+		return opts, err
+	}
+	opts.profile = profile
+
+	positional, err := parseFlags(rest, map[string]*bool{})
+	if err != nil {
+		return opts, err
+	}
+	if len(positional) == 0 {
+		return opts, errors.New(i18n.T("cli.extend.missingMinutes"))
+	}
+	if opts.minutes, err = strconv.Atoi(positional[0]); err != nil {
+		return opts, errors.New(i18n.T("cli.extend.invalidMinutes", positional[0]))
+	}
+	opts.configPath, err = configPathArg(positional[1:])
+	return opts, err
+}
+
+// runExtend 临时延长当天的游戏时间，下次重置时失效；守护进程在下一个周期生效
+func runExtend() error {
+	opts, err := parseExtendArgs(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	if err := requirePassword(cfg, opts.password); err != nil {
+		return err
+	}
+	target, err := controlTarget(cfg, opts.profile)
+	if err != nil {
+		return err
+	}
+
+	if err := internal.SendExtendCommand(target, opts.minutes); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("cli.extend.requested", opts.minutes))
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/logger"
+)
+
+// logsOptions logs 命令参数
+type logsOptions struct {
+	configPath string
+	follow     bool
+	rawJSON    bool
+	minLevel   logger.LogLevel
+}
+
+// parseLogsArgs 解析 logs 命令参数（不含命令名本身）
+func parseLogsArgs(args []string) (logsOptions, error) {
+	opts := logsOptions{minLevel: logger.LevelDebug}
+
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--level":
+			if i+1 >= len(args) {
+				return opts, errors.New(i18n.T("cli.logs.levelMissing"))
+			}
+			i++
+			arg = "--level=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--level="):
+			level, err := logger.ParseLevel(strings.TrimPrefix(arg, "--level="))
+			if err != nil {
+				return opts, err
+			}
+			opts.minLevel = level
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	positional, err := parseFlags(rest, map[string]*bool{
+		"--follow": &opts.follow,
+		"--json":   &opts.rawJSON,
+	})
+	if err != nil {
+		return opts, err
+	}
+	opts.configPath, err = configPathArg(positional)
+	return opts, err
+}
+
+func runLogs() error {
+	opts, err := parseLogsArgs(os.Args[2:])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	if cfg.LogFile == "" {
+		return errors.New(i18n.T("cli.logs.noLogFile"))
+	}
+
+	file, err := os.Open(cfg.LogFile)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.logs.openFailed"), err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	pending, err := emitLogLines(reader, nil, opts, os.Stdout)
+	if err != nil || !opts.follow {
+		return err
+	}
+
+	return followLog(cfg.LogFile, file, reader, pending, opts, os.Stdout)
+}
+
+// followLog 持续输出新追加的日志；检测到文件被轮转（替换或截断）时从头重新打开
+func followLog(path string, file *os.File, reader *bufio.Reader, pending []byte, opts logsOptions, w io.Writer) error {
+	var err error
+	for {
+		time.Sleep(500 * time.Millisecond)
+
+		if rotated(path, file) {
+			_ = file.Close()
+			file, err = os.Open(path)
+			if err != nil {
+				// 轮转过程中文件可能暂时不存在
+				continue
+			}
+			reader = bufio.NewReader(file)
+			pending = nil
+		}
+
+		pending, err = emitLogLines(reader, pending, opts, w)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// rotated 判断路径上的文件是否已被替换或截断
+func rotated(path string, file *os.File) bool {
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	opened, err := file.Stat()
+	if err != nil {
+		return true
+	}
+	if !os.SameFile(current, opened) {
+		return true
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	return err == nil && current.Size() < offset
+}
+
+// emitLogLines 输出 reader 中所有完整的行，返回末尾尚未写完的半行
+func emitLogLines(reader *bufio.Reader, pending []byte, opts logsOptions, w io.Writer) ([]byte, error) {
+	for {
+		chunk, err := reader.ReadBytes('\n')
+		pending = append(pending, chunk...)
+		if err == io.EOF {
+			return pending, nil
+		}
+		if err != nil {
+			return pending, fmt.Errorf("%s: %w", i18n.T("cli.logs.readFailed"), err)
+		}
+
+		writeLogLine(w, pending, opts)
+		pending = nil
+	}
+}
+
+// writeLogLine 按级别过滤并输出一行日志；无法解析的行原样输出
+func writeLogLine(w io.Writer, line []byte, opts logsOptions) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	entry, err := logger.ParseEntry(line)
+	if err != nil {
+		fmt.Fprintln(w, string(line))
+		return
+	}
+	if !entry.Level.AtLeast(opts.minLevel) {
+		return
+	}
+
+	if opts.rawJSON {
+		fmt.Fprintln(w, string(line))
+		return
+	}
+	fmt.Fprintln(w, logger.FormatEntry(entry, time.Local))
+}
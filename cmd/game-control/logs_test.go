@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/logger"
+)
+
+func TestParseLogsArgs(t *testing.T) {
+	opts, err := parseLogsArgs([]string{"--follow", "--level", "warn", "kid.yaml"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if !opts.follow || opts.minLevel != logger.LevelWarn || opts.configPath != "kid.yaml" {
+		t.Fatalf("解析结果不正确: %+v", opts)
+	}
+
+	opts, err = parseLogsArgs([]string{"--level=error", "--json"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if !opts.rawJSON || opts.minLevel != logger.LevelError || opts.configPath != "config.yaml" {
+		t.Fatalf("解析结果不正确: %+v", opts)
+	}
+
+	if _, err := parseLogsArgs([]string{"--level"}); err == nil {
+		t.Fatal("--level 缺少值时应报错")
+	}
+	if _, err := parseLogsArgs([]string{"--level", "loud"}); err == nil {
+		t.Fatal("无效级别应报错")
+	}
+}
+
+func TestEmitLogLinesFiltersAndKeepsPartialLine(t *testing.T) {
+	input := `{"level":"info","timestamp":"2026-02-12T08:00:00Z","message":"started"}
+{"level":"warn","timestamp":"2026-02-12T08:01:00Z","message":"limit","event":"limit_exceeded"}
+not json
+{"level":"error","timestamp":"2026-02-12T08:02:00Z","mess`
+
+	var out bytes.Buffer
+	opts := logsOptions{minLevel: logger.LevelWarn}
+	pending, err := emitLogLines(bufio.NewReader(strings.NewReader(input)), nil, opts, &out)
+	if err != nil {
+		t.Fatalf("emitLogLines 失败: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("应输出2行（warn 与无法解析的行），实际为:\n%s", out.String())
+	}
+	if !strings.Contains(lines[0], "WARN") || !strings.Contains(lines[0], "event=limit_exceeded") {
+		t.Errorf("第一行应为格式化后的警告，实际为 %s", lines[0])
+	}
+	if lines[1] != "not json" {
+		t.Errorf("无法解析的行应原样输出，实际为 %s", lines[1])
+	}
+	if !strings.HasPrefix(string(pending), `{"level":"error"`) {
+		t.Errorf("未写完的行应保留待续，实际为 %q", pending)
+	}
+}
+
+func TestWriteLogLineRawJSON(t *testing.T) {
+	line := `{"level":"info","timestamp":"2026-02-12T08:00:00Z","message":"started"}`
+
+	var out bytes.Buffer
+	writeLogLine(&out, []byte(line+"\n"), logsOptions{rawJSON: true, minLevel: logger.LevelDebug})
+	if strings.TrimSpace(out.String()) != line {
+		t.Errorf("--json 应原样输出，实际为 %s", out.String())
+	}
+}
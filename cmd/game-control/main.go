@@ -1,203 +1,880 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/yourusername/game-control/internal"
+	"github.com/yourusername/game-control/pkg/autostart"
 	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/gamecontrol"
+	"github.com/yourusername/game-control/pkg/i18n"
 	"github.com/yourusername/game-control/pkg/logger"
+	"github.com/yourusername/game-control/pkg/privilege"
+	"github.com/yourusername/game-control/pkg/process"
 	"github.com/yourusername/game-control/pkg/quota"
 	"github.com/yourusername/game-control/pkg/singleinstance"
+	"github.com/yourusername/game-control/pkg/timeutil"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Windows 上 time.LoadLocation 依赖内嵌时区数据
 )
 
+// 构建信息，发布时通过 -ldflags "-X main.Version=... -X main.Commit=... -X main.BuildDate=..." 注入
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildDate = "dev"
+)
+
+// 构建时嵌入的锁定策略，通过 -ldflags "-X main.PolicyURL=... -X main.PolicyPublicKey=..." 注入；
+// 设置后各命令只使用签名策略，不读取本地配置文件的内容，本地修改无法解除限制
+var (
+	PolicyURL       = ""
+	PolicyPublicKey = ""
+)
+
+// instanceName 守护进程单实例锁的基础名称，实际锁名附加配置文件路径的摘要
+const instanceName = "game-control-main"
+
 func main() {
-	if len(os.Args) < 2 {
-		printHelp()
-		os.Exit(1)
+	// 配置加载前（如参数错误）也按环境变量选择语言，加载配置后以配置为准
+	i18n.SetLanguage(os.Getenv(config.EnvLanguage))
+
+	global, args, err := parseGlobalArgs(os.Args[1:])
+	if err != nil {
+		printError(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	if len(args) == 0 {
+		printHelp(os.Stdout)
+		os.Exit(exitError)
 	}
 
-	command := os.Args[1]
+	// 去掉全局参数后，各子命令仍从 os.Args[2:] 读取自己的参数
+	command := args[0]
+	args, err = takeConfigDirFlag(args[1:])
+	if err != nil {
+		printError(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	os.Args = append([]string{os.Args[0], command}, args...)
+
+	verbose = global.verbose
+	if global.quiet {
+		if err := silenceStdout(); err != nil {
+			printError(os.Stderr, err)
+			os.Exit(exitError)
+		}
+	}
 
 	switch command {
 	case "start":
-		if err := runStart(); err != nil {
-			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-			os.Exit(1)
-		}
+		err = runStart()
 	case "status":
-		if err := runStatus(); err != nil {
-			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-			os.Exit(1)
-		}
+		err = runStatus()
 	case "validate":
-		if err := runValidate(); err != nil {
-			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-			os.Exit(1)
-		}
+		err = runValidate()
+	case "logs":
+		err = runLogs()
+	case "stop":
+		err = runStop()
+	case "watchdog":
+		err = runWatchdog()
+	case "pause", "resume":
+		err = runPause(command == "pause")
+	case "extend":
+		err = runExtend()
+	case "install-autostart":
+		err = runInstallAutostart()
+	case "remove-autostart":
+		err = runRemoveAutostart()
+	case "uninstall":
+		err = runUninstall()
+	case "set-password":
+		err = runSetPassword()
+	case "policy-keygen":
+		err = runPolicyKeygen()
+	case "sign-policy":
+		err = runSignPolicy()
+	case "selftest":
+		err = runSelftest()
+	case "schema":
+		err = printSchema(os.Stdout)
+	case "version", "--version", "-v":
+		printVersion(os.Stdout)
 	case "help", "--help", "-h":
-		printHelp()
+		printHelp(os.Stdout)
 	default:
-		fmt.Printf("未知命令: %s\n", command)
-		printHelp()
-		os.Exit(1)
+		fmt.Println(i18n.T("cli.unknownCommand", command))
+		printHelp(os.Stdout)
+		os.Exit(exitError)
+	}
+	if err != nil {
+		printError(os.Stderr, err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// startOptions start 命令参数
+type startOptions struct {
+	configPath   string
+	requireAdmin bool
+	dryRun       bool // 等同于 enforcement.mode: monitor
+	background   bool // 脱离终端在后台重新启动自身，确认启动成功后返回
+	foreground   bool // 在当前终端运行（默认行为）
+}
+
+// parseFlags 拆分布尔开关与位置参数，flags 中未列出的 "--" 参数视为错误
+func parseFlags(args []string, flags map[string]*bool) ([]string, error) {
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+		target, ok := flags[arg]
+		if !ok {
+			return nil, errors.New(i18n.T("cli.unknownFlag", arg))
+		}
+		*target = true
+	}
+	return positional, nil
+}
+
+// takeConfigDirFlag 将 --config-dir DIR 改写为位置参数 DIR（配置路径是目录时合并其中的配置片段），
+// 返回其余参数；DIR 必须是已存在的目录
+func takeConfigDirFlag(args []string) ([]string, error) {
+	var dir string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--config-dir":
+			if i+1 >= len(args) {
+				return nil, errors.New(i18n.T("cli.configDir.missing"))
+			}
+			i++
+			dir = args[i]
+		case strings.HasPrefix(arg, "--config-dir="):
+			dir = strings.TrimPrefix(arg, "--config-dir=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if dir == "" {
+		return rest, nil
+	}
+
+	expanded, err := config.ExpandPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(expanded); err != nil || !info.IsDir() {
+		return nil, errors.New(i18n.T("cli.configDir.notDir", dir))
+	}
+	return append(rest, dir), nil
+}
+
+// configPathArg 从位置参数中取配置路径，最多允许一个
+func configPathArg(positional []string) (string, error) {
+	switch len(positional) {
+	case 0:
+		return "config.yaml", nil
+	case 1:
+		return positional[0], nil
+	default:
+		return "", errors.New(i18n.T("cli.extraArg", positional[1]))
+	}
+}
+
+// parseStartArgs 解析 start 命令参数（不含命令名本身）
+func parseStartArgs(args []string) (startOptions, error) {
+	var opts startOptions
+	positional, err := parseFlags(args, map[string]*bool{
+		"--require-admin": &opts.requireAdmin,
+		"--dry-run":       &opts.dryRun,
+		"--background":    &opts.background,
+		"--foreground":    &opts.foreground,
+	})
+	if err != nil {
+		return opts, err
+	}
+	if opts.background && opts.foreground {
+		return opts, errors.New(i18n.T("cli.start.backgroundConflict"))
+	}
+	opts.configPath, err = configPathArg(positional)
+	return opts, err
+}
+
+// validateOptions validate 命令参数
+type validateOptions struct {
+	configPaths  []string // 要校验的配置文件，可含通配符；未指定时为 config.yaml
+	checkRunning bool
+	fix          bool // 校验前将旧版本的配置文件升级并写回
+}
+
+// parseValidateArgs 解析 validate 命令参数（不含命令名本身），可指定多个配置文件
+func parseValidateArgs(args []string) (validateOptions, error) {
+	var opts validateOptions
+	positional, err := parseFlags(args, map[string]*bool{
+		"--check-running": &opts.checkRunning,
+		"--fix":           &opts.fix,
+	})
+	if err != nil {
+		return opts, err
+	}
+	if len(positional) == 0 {
+		positional = []string{"config.yaml"}
+	}
+	opts.configPaths = positional
+	return opts, nil
+}
+
+// statusOptions status 命令参数
+type statusOptions struct {
+	configPath string
+	profile    string // 只显示该档案，为空时显示全部档案
+}
+
+// parseStatusArgs 解析 status 命令参数（不含命令名本身）
+func parseStatusArgs(args []string) (statusOptions, error) {
+	var opts statusOptions
+	profile, rest, err := takeProfileFlag(args)
+	if err != nil {
+		return opts, err
+	}
+	opts.profile = profile
+
+	positional, err := parseFlags(rest, map[string]*bool{})
+	if err != nil {
+		return opts, err
+	}
+	opts.configPath, err = configPathArg(positional)
+	return opts, err
+}
+
+// takeProfileFlag 从参数中取出 --profile 的值，返回其余参数
+func takeProfileFlag(args []string) (string, []string, error) {
+	var profile string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--profile":
+			if i+1 >= len(args) {
+				return "", nil, errors.New(i18n.T("cli.profile.missing"))
+			}
+			i++
+			profile = args[i]
+		case strings.HasPrefix(arg, "--profile="):
+			profile = strings.TrimPrefix(arg, "--profile=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return profile, rest, nil
+}
+
+// checkElevation 检查管理员权限，未提权时输出醒目警告；required 为 true 时由 privilege.Require 检查，
+// 未提权或无法检测时直接返回错误
+func checkElevation(action string, required bool) (bool, error) {
+	if required {
+		if err := privilege.Require(); err != nil {
+			return false, fmt.Errorf("%s: %w", i18n.T("cli.elevation.required", action), err)
+		}
+		return true, nil
+	}
+
+	elevated, err := privilege.IsElevated()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.elevation.checkFailed", err))
+		return false, nil
 	}
+	if !elevated {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.elevation.warn", action))
+	}
+	return elevated, nil
 }
 
 func runStart() error {
-	configPath := "config.yaml"
-	if len(os.Args) > 2 {
-		configPath = os.Args[2]
+	opts, err := parseStartArgs(os.Args[2:])
+	if err != nil {
+		return err
+	}
+
+	elevated, err := checkElevation(i18n.T("cli.action.terminate"), opts.requireAdmin)
+	if err != nil {
+		return err
 	}
 
-	cfg, err := config.LoadFromFile(configPath)
+	cfg, err := loadConfig(opts.configPath)
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %w", err)
+		return err
 	}
 	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("配置验证失败: %w", err)
+		return fmt.Errorf("%s: %w", i18n.T("cli.validateFailed"), err)
+	}
+	if opts.dryRun {
+		cfg.Enforcement.Mode = config.ModeMonitor
 	}
 
-	guard, err := singleinstance.Acquire("game-control-main")
+	lockName, lockOpts := instanceLock(cfg, opts.configPath)
+	if opts.background {
+		return startBackground(opts, lockName, lockOpts)
+	}
+	guard, err := singleinstance.AcquireWithOptions(lockName, lockOpts)
 	if err != nil {
 		if errors.Is(err, singleinstance.ErrAlreadyRunning) {
-			return fmt.Errorf("控制器已在运行")
+			return alreadyRunning(i18n.T("cli.start.alreadyRunning"))
 		}
-		return fmt.Errorf("获取单实例锁失败: %w", err)
+		return fmt.Errorf("%s: %w", i18n.T("cli.start.lockFailed"), err)
 	}
 	defer guard.Release()
 
-	log, err := logger.NewLogger(cfg.LogFile)
+	level := logger.LevelInfo
+	if cfg.Logging.Level != "" {
+		if level, err = logger.ParseLevel(cfg.Logging.Level); err != nil {
+			return err
+		}
+	}
+
+	log, err := logger.NewLoggerWithOptions(logger.Options{
+		OutputPath: cfg.LogFile,
+		EventsPath: cfg.Logging.EventsPath,
+		Level:      level,
+		Console:    cfg.Logging.Console,
+	})
 	if err != nil {
-		return fmt.Errorf("创建日志记录器失败: %w", err)
+		return fmt.Errorf("%s: %w", i18n.T("cli.start.loggerFailed"), err)
 	}
 	defer log.Close()
 
-	var qState *quota.QuotaState
-	loadedState, err := quota.LoadFromFile(cfg)
-	if err != nil || loadedState == nil {
-		qState, err = quota.NewQuotaState(cfg)
+	if !elevated {
+		log.Warnf("%s", i18n.T("cli.start.notElevated"))
+	}
+	for _, warning := range cfg.GameNameWarnings() {
+		log.Warnf("%s", warning)
+	}
+	if cfg.Watchdog.Enabled {
+		if err := ensureWatchdog(lockName, lockOpts, opts); err != nil {
+			log.Warnf("%s", i18n.T("cli.start.watchdogFailed", err))
+		}
+	}
+	if cfg.Enforcement.MonitorOnly() {
+		log.Warnf("%s", i18n.T("cli.start.monitorMode"))
+	}
+
+	if len(cfg.Profiles) > 0 {
+		return runProfiles(cfg, opts.configPath, log)
+	}
+
+	watchPath, err := config.ExpandPath(opts.configPath)
+	if err != nil {
+		watchPath = ""
+	}
+	engine, err := gamecontrol.New(cfg, gamecontrol.Options{ConfigPath: watchPath})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := engine.Start(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return engine.Stop()
+}
+
+// runProfiles 以多档案模式运行：每个档案使用自己的游戏列表、时间限制与状态文件
+func runProfiles(cfg *config.Config, configPath string, log *logger.Logger) error {
+	states := make(map[string]*quota.QuotaState, len(cfg.Profiles))
+	for _, p := range cfg.Profiles {
+		pcfg, err := cfg.ForProfile(p.Name)
 		if err != nil {
-			return fmt.Errorf("创建配额状态失败: %w", err)
+			return err
 		}
-	} else {
-		qState = loadedState
-		if err := qState.Validate(); err != nil {
-			log.Warnf("状态验证失败，创建新状态: %v", err)
-			qState, err = quota.NewQuotaState(cfg)
-			if err != nil {
-				return fmt.Errorf("创建配额状态失败: %w", err)
-			}
+		if states[p.Name], err = gamecontrol.LoadState(pcfg); err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("cli.profile.error", p.Name), err)
 		}
 	}
 
-	controller := internal.NewController(cfg, qState)
+	controller, err := internal.NewMultiController(cfg, states)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.start.multiFailed"), err)
+	}
+	if configPath, err := config.ExpandPath(configPath); err == nil {
+		if err := controller.WatchConfigFile(configPath); err != nil {
+			log.Debugf("%s", i18n.T("cli.start.noConfigWatch", err))
+		}
+	}
 	return controller.Run()
 }
 
-func runStatus() error {
-	configPath := "config.yaml"
-	if len(os.Args) > 2 {
-		configPath = os.Args[2]
+// loadConfig 加载配置（path 可以是配置文件或配置片段目录）并按配置（或 GAMECTL_LANG）切换输出语言
+func loadConfig(path string) (*config.Config, error) {
+	var cfg *config.Config
+	var err error
+	if PolicyURL != "" {
+		cfg, err = config.LoadPolicy(config.PolicyConfig{URL: PolicyURL, PublicKey: PolicyPublicKey}, path)
+	} else {
+		cfg, err = config.Load(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("cli.loadConfigFailed"), err)
 	}
+	i18n.SetLanguage(cfg.Language)
+	printEffectiveConfig(path, cfg)
+	return cfg, nil
+}
 
-	cfg, err := config.LoadFromFile(configPath)
+func runStatus() error {
+	opts, err := parseStatusArgs(os.Args[2:])
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %w", err)
+		return err
 	}
 
-	qState, err := quota.LoadFromFile(cfg)
+	cfg, err := loadConfig(opts.configPath)
 	if err != nil {
-		return fmt.Errorf("加载状态失败: %w", err)
+		return err
 	}
-	if qState == nil {
-		return fmt.Errorf("没有找到状态文件，请先运行 start 命令")
+	if opts.profile != "" {
+		if _, ok := cfg.Profile(opts.profile); !ok {
+			return errors.New(i18n.T("cli.profile.unknown", opts.profile))
+		}
 	}
 
 	log, _ := logger.NewLogger("")
-	controller := internal.NewController(cfg, qState)
+	defer log.Close()
+
+	fmt.Println(i18n.T("status.header"))
+	line, running := daemonStatus(instanceLock(cfg, opts.configPath))
+	fmt.Println(line)
+	if record, err := internal.ReadExitRecord(cfg); err == nil {
+		if line := lastExitLine(record, running); line != "" {
+			fmt.Println(line)
+		}
+	}
+
+	scanner := process.NewScanner()
+	scanner.SetExemptUsers(cfg.Enforcement.ExemptUsers)
+	scanner.SetMatchMode(cfg.Matching.MatchMode())
+	if len(cfg.Profiles) == 0 {
+		return printQuotaStatus(cfg, scanner, log)
+	}
+
+	scanner.SetQueryOwners(true)
+	for _, p := range cfg.Profiles {
+		if opts.profile != "" && !strings.EqualFold(p.Name, opts.profile) {
+			continue
+		}
+		pcfg, err := cfg.ForProfile(p.Name)
+		if err != nil {
+			return err
+		}
+		fmt.Println("\n" + i18n.T("status.profile", p.Name, strings.Join(p.Users, ", ")))
+		if err := printQuotaStatus(pcfg, internal.FilterByOwner(scanner, p.Users), log); err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("cli.profile.error", p.Name), err)
+		}
+	}
+	return nil
+}
+
+// printQuotaStatus 输出 cfg 对应配额状态的用量与活跃进程，到达重置时间时先重置
+func printQuotaStatus(cfg *config.Config, scanner internal.ProcessScanner, log *logger.Logger) error {
+	qState, err := quota.LoadFromFile(cfg)
+	if errors.Is(err, quota.ErrNoState) {
+		return fmt.Errorf("%w%s", err, i18n.T("cli.status.runStartFirst"))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.status.loadFailed"), err)
+	}
+
+	controller := internal.NewControllerWithDeps(cfg, qState, scanner, nil)
 
 	shouldReset, err := qState.ShouldReset()
 	if err != nil {
-		return fmt.Errorf("检查重置状态失败: %v", err)
+		return fmt.Errorf("%s: %v", i18n.T("cli.status.checkResetFailed"), err)
 	}
 
 	if shouldReset {
+		summary := qState.Summary()
 		if err := qState.Reset(); err != nil {
-			return fmt.Errorf("重置配额失败: %v", err)
+			return fmt.Errorf("%s: %v", i18n.T("cli.status.resetFailed"), err)
 		}
+		log.LogDailySummary(summary.PlayedSeconds, summary.LimitReached, summary.Terminations, summary.GameSeconds)
 		log.LogQuotaReset()
 		if err := qState.SaveToFile(); err != nil {
-			return fmt.Errorf("保存重置状态失败: %v", err)
+			return fmt.Errorf("%s: %v", i18n.T("cli.status.saveFailed"), err)
 		}
 	}
 
 	status := controller.GetStatus()
 
-	fmt.Println("=== 游戏时间控制状态 ===")
-	fmt.Printf("累计游戏时间: %d 分钟\n", status.AccumulatedTime)
-	fmt.Printf("剩余游戏时间: %d 分钟\n", status.RemainingTime)
-	fmt.Printf("每日时间限制: %d 分钟\n", status.DailyLimit)
+	fmt.Println(i18n.T("status.accumulated", timeutil.FormatMinutesSeconds(time.Duration(status.AccumulatedSeconds)*time.Second)))
+	fmt.Println(i18n.T("status.remaining", timeutil.FormatMinutesSeconds(time.Duration(status.RemainingSeconds)*time.Second)))
+	fmt.Println(i18n.T("status.dailyLimit", status.DailyLimit))
+	if status.EarnedMinutes > 0 {
+		fmt.Println(i18n.T("status.earned", status.EarnedMinutes))
+	}
+	if status.ExtensionMinutes > 0 {
+		fmt.Println(i18n.T("status.extension", status.ExtensionMinutes))
+	}
+	if status.ScanMilliseconds > 0 {
+		fmt.Println(i18n.T("status.scanDuration", status.ScanMilliseconds))
+	}
+
+	if len(status.GameTimes) > 0 {
+		fmt.Println("\n" + i18n.T("status.gameTimes"))
+		for _, line := range gameTimeLines(status.GameTimes) {
+			fmt.Println("  " + line)
+		}
+	}
+
+	if machines := qState.GetMachineSeconds(); len(machines) > 1 {
+		fmt.Println("\n" + i18n.T("status.machineTimes"))
+		for _, line := range gameTimeLines(machines) {
+			fmt.Println("  " + line)
+		}
+	}
+
+	if len(status.PausedGames) > 0 {
+		fmt.Println("\n" + i18n.T("status.pausedGames", strings.Join(status.PausedGames, ", ")))
+	}
 
 	if status.ActiveProcessCount > 0 {
-		fmt.Printf("\n活跃游戏进程: %d 个\n", status.ActiveProcessCount)
-		fmt.Println("  (进程详情需要实时扫描，此处只显示数量)")
+		fmt.Println("\n" + i18n.T("status.activeProcesses", status.ActiveProcessCount))
+		for _, line := range activeProcessLines(status.ActiveProcesses) {
+			fmt.Println("  " + line)
+		}
 	} else {
-		fmt.Println("\n当前没有活跃的游戏进程")
+		fmt.Println("\n" + i18n.T("status.noActive"))
+	}
+
+	fmt.Println("\n" + i18n.T("status.nextReset", timeutil.FormatDuration(status.NextResetTime)))
+	return nil
+}
+
+// instanceLock 返回配置文件对应的单实例锁名称与选项，监控不同配置文件的守护进程互不冲突
+func instanceLock(cfg *config.Config, configPath string) (string, singleinstance.Options) {
+	if expanded, err := config.ExpandPath(configPath); err == nil {
+		configPath = expanded
+	}
+	return singleinstance.NamespacedName(instanceName, configPath), singleinstance.Options{
+		Dir:        cfg.Instance.LockDir,
+		StaleAfter: time.Duration(cfg.Instance.StaleLockSeconds) * time.Second,
 	}
+}
 
-	nextReset := status.NextResetTime
-	hours := int(nextReset.Hours())
-	minutes := int(nextReset.Minutes()) % 60
-	fmt.Printf("\n距离下次重置: %d 小时 %d 分钟\n", hours, minutes)
+// daemonStatus 描述守护进程是否在运行
+func daemonStatus(lockName string, lockOpts singleinstance.Options) (line string, running bool) {
+	pid, since, err := singleinstance.ReadOwnerWithOptions(lockName, lockOpts)
+	if err != nil || !singleinstance.IsProcessAlive(pid) {
+		return i18n.T("status.daemon.stopped"), false
+	}
+	if since.IsZero() {
+		return i18n.T("status.daemon.running", pid), true
+	}
+	return i18n.T("status.daemon.since", pid, since.Format("2006-01-02 15:04:05")), true
+}
 
-	_ = log.Close()
+// lastExitLine 描述守护进程上次停止的时间与原因；守护进程未运行且本次运行没有留下退出记录，
+// 或上次运行被强制结束时说明原因未知；从未停止过时返回空串
+func lastExitLine(record internal.ExitRecord, running bool) string {
+	if (!running && !record.Stopped()) || (record.StoppedAt.IsZero() && record.Reason == internal.ExitKilled) {
+		return i18n.T("status.lastExitUnknown")
+	}
+	if record.StoppedAt.IsZero() {
+		return ""
+	}
+	reason := i18n.T("exit." + record.Reason)
+	if record.Detail != "" {
+		reason += ": " + record.Detail
+	}
+	return i18n.T("status.lastExit", record.StoppedAt.Format("2006-01-02 15:04:05"), reason)
+}
+
+func runStop() error {
+	password, rest, err := takePasswordFlag(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	configPath, err := configPathArg(rest)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if err := requirePassword(cfg, password); err != nil {
+		return err
+	}
+
+	lockName, lockOpts := instanceLock(cfg, configPath)
+
+	// 先停止看护进程，否则它会重新启动守护进程
+	if pid, err := runningPID(watchdogLock(lockName), lockOpts); err == nil {
+		if err := stopAndWait(pid); err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("cli.stop.watchdogFailed"), err)
+		}
+		fmt.Println(i18n.T("cli.stop.watchdogStopped", pid))
+	}
+
+	pid, err := runningPID(lockName, lockOpts)
+	if err != nil {
+		return err
+	}
+	if err := stopAndWait(pid); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("cli.stop.daemonStopped", pid))
 	return nil
 }
 
+// runningPID 读取锁文件记录的进程 PID，进程未运行时返回错误
+func runningPID(lockName string, lockOpts singleinstance.Options) (int, error) {
+	pid, _, err := singleinstance.ReadOwnerWithOptions(lockName, lockOpts)
+	if errors.Is(err, singleinstance.ErrNotRunning) {
+		return 0, errors.New(i18n.T("cli.daemon.notRunning"))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", i18n.T("cli.daemon.readFailed"), err)
+	}
+	if !singleinstance.IsProcessAlive(pid) {
+		return 0, errors.New(i18n.T("cli.daemon.exited", pid))
+	}
+	return pid, nil
+}
+
+// stopAndWait 请求进程退出，并等待其完成清理（保存状态）
+func stopAndWait(pid int) error {
+	if err := requestStop(pid); err != nil {
+		return err
+	}
+	for i := 0; i < 20; i++ {
+		if !singleinstance.IsProcessAlive(pid) {
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return errors.New(i18n.T("cli.stop.timeout", pid))
+}
+
 func runValidate() error {
+	opts, err := parseValidateArgs(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	paths, err := expandConfigPaths(opts.configPaths)
+	if err != nil {
+		return err
+	}
+	if opts.fix {
+		if err := fixConfigFiles(paths, os.Stdout); err != nil {
+			return err
+		}
+	}
+	if len(paths) > 1 {
+		if opts.checkRunning {
+			return errors.New(i18n.T("cli.validate.checkRunningSingle"))
+		}
+		return validateFiles(paths, os.Stdout)
+	}
+
+	cfg, err := loadConfig(paths[0])
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.validateFailed"), err)
+	}
+
+	fmt.Println(i18n.T("cli.validate.ok"))
+	for _, warning := range cfg.GameNameWarnings() {
+		fmt.Println(i18n.T("cli.warning", warning))
+	}
+	fmt.Println(i18n.T("cli.validate.dailyLimit", cfg.DailyLimit))
+	fmt.Println(i18n.T("cli.validate.resetTime", cfg.ResetTime))
+	if cfg.Timezone != "" {
+		fmt.Println(i18n.T("cli.validate.timezone", cfg.Timezone))
+	}
+	fmt.Println(i18n.T("cli.validate.games", cfg.Games))
+	if len(cfg.Warnings) > 0 {
+		fmt.Println(i18n.T("cli.validate.warningLevels", cfg.WarningLevels()))
+	} else {
+		fmt.Println(i18n.T("cli.validate.thresholds", cfg.FirstThreshold, cfg.FinalThreshold))
+	}
+
+	if opts.checkRunning {
+		scanner := process.NewScanner()
+		scanner.SetMatchMode(cfg.Matching.MatchMode())
+		processes, err := scanner.FindGameProcesses(cfg.GameNames())
+		if err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("cli.validate.scanFailed"), err)
+		}
+
+		fmt.Println()
+		fmt.Println(i18n.T("cli.validate.matchable"))
+		for _, line := range runningGamesReport(cfg.GameNames(), processes) {
+			fmt.Println("  " + line)
+		}
+	}
+
+	return nil
+}
+
+// gameTimeLines 按时间从多到少列出当天各游戏（或共享状态下各电脑）的累计时间
+func gameTimeLines(gameTimes map[string]int64) []string {
+	games := make([]string, 0, len(gameTimes))
+	for game := range gameTimes {
+		games = append(games, game)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if gameTimes[games[i]] != gameTimes[games[j]] {
+			return gameTimes[games[i]] > gameTimes[games[j]]
+		}
+		return games[i] < games[j]
+	})
+
+	lines := make([]string, 0, len(games))
+	for _, game := range games {
+		duration := time.Duration(gameTimes[game]) * time.Second
+		lines = append(lines, fmt.Sprintf("%s: %s", game, timeutil.FormatDuration(duration)))
+	}
+	return lines
+}
+
+// activeProcessLines 列出每个活跃游戏进程的名称、PID 与本次会话时长
+func activeProcessLines(processes []internal.ActiveProcess) []string {
+	lines := make([]string, 0, len(processes))
+	for _, proc := range processes {
+		duration := i18n.T("status.unknown")
+		if proc.Duration > 0 {
+			duration = timeutil.FormatDuration(proc.Duration)
+		}
+		lines = append(lines, i18n.T("status.processLine", proc.Name, proc.PID, duration))
+	}
+	return lines
+}
+
+// runningGamesReport 按配置顺序列出每个游戏当前是否能匹配到运行中的进程
+func runningGamesReport(games []string, processes []process.ProcessInfo) []string {
+	lines := make([]string, 0, len(games))
+	for _, game := range games {
+		var pids []string
+		for _, proc := range processes {
+			if proc.Matches(game) {
+				pids = append(pids, strconv.Itoa(proc.PID))
+			}
+		}
+		if len(pids) > 0 {
+			lines = append(lines, i18n.T("status.gameRunning", game, strings.Join(pids, ", ")))
+		} else {
+			lines = append(lines, i18n.T("status.gameNotRunning", game))
+		}
+	}
+	return lines
+}
+
+func runInstallAutostart() error {
 	configPath := "config.yaml"
 	if len(os.Args) > 2 {
 		configPath = os.Args[2]
 	}
 
-	cfg, err := config.LoadFromFile(configPath)
+	// 自启动任务的工作目录不确定，统一使用展开后的绝对路径
+	absConfig, err := absConfigPath(configPath)
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %w", err)
+		return err
+	}
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.exePathFailed"), err)
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("配置验证失败: %w", err)
+	// Windows 计划任务以最高权限运行，创建时需要管理员权限
+	if _, err := checkElevation(i18n.T("cli.action.installAutostart"), false); err != nil {
+		return err
 	}
 
-	fmt.Println("配置文件验证通过")
-	fmt.Printf("每日时间限制: %d 分钟\n", cfg.DailyLimit)
-	fmt.Printf("重置时间: %s\n", cfg.ResetTime)
-	fmt.Printf("游戏进程列表: %v\n", cfg.Games)
-	fmt.Printf("警告阈值: %d 分钟 (第一次), %d 分钟 (最后)\n",
-		cfg.FirstThreshold, cfg.FinalThreshold)
+	if err := autostart.InstallTask(exePath, absConfig); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.autostart.installFailed"), err)
+	}
 
+	fmt.Println(i18n.T("cli.autostart.installed"))
 	return nil
 }
 
-func printHelp() {
-	fmt.Println("游戏时间控制工具")
-	fmt.Println()
-	fmt.Println("使用方法:")
-	fmt.Println("  game-control <command> [参数]")
-	fmt.Println()
-	fmt.Println("可用命令:")
-	fmt.Println("  start [config]                    启动游戏时间控制守护进程")
-	fmt.Println("  status [config]                   查询当前游戏时间状态")
-	fmt.Println("  validate [config]                 验证配置文件")
-	fmt.Println("  help                              显示此帮助信息")
-	fmt.Println()
-	fmt.Println("说明:")
-	fmt.Println("  - 默认配置文件路径: config.yaml")
-	fmt.Println("  - 需要管理员权限来终止游戏进程")
-	fmt.Println("  - 仅支持 Windows 系统")
-	fmt.Println("  - 后台运行请使用 PowerShell Start-Process 或 bat 脚本启动")
-	fmt.Println()
-	fmt.Println("示例:")
-	fmt.Println("  game-control start")
+// absConfigPath 返回展开后的配置文件绝对路径，供工作目录不确定的自启动任务与看护进程使用
+func absConfigPath(configPath string) (string, error) {
+	configPath, err := config.ExpandPath(configPath)
+	if err != nil {
+		return "", err
+	}
+	absConfig, err := filepath.Abs(configPath)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("cli.absConfigFailed"), err)
+	}
+	return absConfig, nil
+}
+
+func runRemoveAutostart() error {
+	password, rest, err := takePasswordFlag(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	configPath, err := configPathArg(rest)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if err := requirePassword(cfg, password); err != nil {
+		return err
+	}
+
+	if err := autostart.RemoveTask(); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.autostart.removeFailed"), err)
+	}
+
+	fmt.Println(i18n.T("cli.autostart.removed"))
+	return nil
+}
+
+func printVersion(w io.Writer) {
+	fmt.Fprintf(w, "game-control %s\n", Version)
+	fmt.Fprintln(w, i18n.T("cli.version.commit", Commit))
+	fmt.Fprintln(w, i18n.T("cli.version.buildDate", BuildDate))
+}
+
+// printSchema 输出配置文件的 JSON Schema，供编辑器校验与自动补全
+func printSchema(w io.Writer) error {
+	data, err := json.MarshalIndent(config.Schema(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.schemaFailed"), err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func printHelp(w io.Writer) {
+	fmt.Fprint(w, i18n.T("cli.help"))
+}
+
+// printError 按当前语言输出命令失败的错误信息
+func printError(w io.Writer, err error) {
+	fmt.Fprintln(w, i18n.T("cli.error", err))
 }
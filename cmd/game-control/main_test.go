@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/internal"
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+func TestParseStartArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantConfig   string
+		wantRequired bool
+		wantDryRun   bool
+		wantBg       bool
+		wantErr      bool
+	}{
+		{name: "默认参数", args: nil, wantConfig: "config.yaml"},
+		{name: "指定配置", args: []string{"kid.yaml"}, wantConfig: "kid.yaml"},
+		{name: "要求管理员", args: []string{"--require-admin", "kid.yaml"}, wantConfig: "kid.yaml", wantRequired: true},
+		{name: "只观察", args: []string{"--dry-run"}, wantConfig: "config.yaml", wantDryRun: true},
+		{name: "后台运行", args: []string{"kid.yaml", "--background"}, wantConfig: "kid.yaml", wantBg: true},
+		{name: "显式前台", args: []string{"--foreground"}, wantConfig: "config.yaml"},
+		{name: "前后台冲突", args: []string{"--background", "--foreground"}, wantErr: true},
+		{name: "未知参数", args: []string{"--unknown"}, wantErr: true},
+		{name: "多余参数", args: []string{"a.yaml", "b.yaml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseStartArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("预期解析失败")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if opts.configPath != tt.wantConfig {
+				t.Errorf("配置路径应为 %s，实际为 %s", tt.wantConfig, opts.configPath)
+			}
+			if opts.requireAdmin != tt.wantRequired {
+				t.Errorf("requireAdmin 应为 %v，实际为 %v", tt.wantRequired, opts.requireAdmin)
+			}
+			if opts.dryRun != tt.wantDryRun {
+				t.Errorf("dryRun 应为 %v，实际为 %v", tt.wantDryRun, opts.dryRun)
+			}
+			if opts.background != tt.wantBg {
+				t.Errorf("background 应为 %v，实际为 %v", tt.wantBg, opts.background)
+			}
+		})
+	}
+}
+
+func TestParseValidateArgs(t *testing.T) {
+	opts, err := parseValidateArgs([]string{"kid.yaml", "--check-running"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(opts.configPaths) != 1 || opts.configPaths[0] != "kid.yaml" || !opts.checkRunning {
+		t.Fatalf("解析结果不正确: %+v", opts)
+	}
+
+	opts, err = parseValidateArgs([]string{"a.yaml", "b.yaml"})
+	if err != nil || strings.Join(opts.configPaths, ",") != "a.yaml,b.yaml" {
+		t.Fatalf("应接受多个配置文件，实际 %+v, %v", opts, err)
+	}
+	if opts, _ := parseValidateArgs(nil); strings.Join(opts.configPaths, ",") != "config.yaml" {
+		t.Errorf("未指定时应校验 config.yaml，实际 %v", opts.configPaths)
+	}
+
+	if opts, err := parseValidateArgs([]string{"--fix", "old.yaml"}); err != nil || !opts.fix {
+		t.Errorf("应接受 --fix，实际 %+v, %v", opts, err)
+	}
+
+	if _, err := parseValidateArgs([]string{"--require-admin"}); err == nil {
+		t.Fatal("validate 不应接受 --require-admin")
+	}
+}
+
+func TestParseStatusArgs(t *testing.T) {
+	opts, err := parseStatusArgs([]string{"--profile", "alice", "kid.yaml"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if opts.configPath != "kid.yaml" || opts.profile != "alice" {
+		t.Fatalf("解析结果不正确: %+v", opts)
+	}
+
+	opts, err = parseStatusArgs([]string{"--profile=bob"})
+	if err != nil || opts.configPath != "config.yaml" || opts.profile != "bob" {
+		t.Fatalf("解析结果不正确: %+v, %v", opts, err)
+	}
+
+	if _, err := parseStatusArgs([]string{"--profile"}); err == nil {
+		t.Fatal("--profile 缺少值时应报错")
+	}
+}
+
+func TestParsePauseArgs(t *testing.T) {
+	opts, err := parsePauseArgs([]string{"edu.exe", "kid.yaml", "--profile", "alice", "--password=x"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if opts.game != "edu.exe" || opts.configPath != "kid.yaml" || opts.profile != "alice" || opts.password != "x" {
+		t.Fatalf("解析结果不正确: %+v", opts)
+	}
+
+	if _, err := parsePauseArgs(nil); err == nil {
+		t.Fatal("缺少游戏名时应报错")
+	}
+	if _, err := parsePauseArgs([]string{"a.exe", "b.yaml", "c.yaml"}); err == nil {
+		t.Fatal("多余的参数应报错")
+	}
+}
+
+func TestParseExtendArgs(t *testing.T) {
+	opts, err := parseExtendArgs([]string{"10", "kid.yaml", "--profile=alice", "--password", "x"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if opts.minutes != 10 || opts.configPath != "kid.yaml" || opts.profile != "alice" || opts.password != "x" {
+		t.Fatalf("解析结果不正确: %+v", opts)
+	}
+
+	if _, err := parseExtendArgs(nil); err == nil {
+		t.Fatal("缺少分钟数时应报错")
+	}
+	if _, err := parseExtendArgs([]string{"ten"}); err == nil {
+		t.Fatal("分钟数不是整数时应报错")
+	}
+}
+
+func TestRunningGamesReport(t *testing.T) {
+	processes := []process.ProcessInfo{
+		{PID: 10, Name: "GAME.exe"},
+		{PID: 11, Name: "game.exe"},
+	}
+
+	lines := runningGamesReport([]string{"game.exe", "minecraft.exe"}, processes)
+	want := []string{
+		"game.exe: 运行中 (PID: 10, 11)",
+		"minecraft.exe: 未运行",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("报告行数应为 %d，实际为 %d", len(want), len(lines))
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("第 %d 行应为 %q，实际为 %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestActiveProcessLines(t *testing.T) {
+	lines := activeProcessLines([]internal.ActiveProcess{
+		{Name: "game.exe", PID: 10, Duration: 95 * time.Minute},
+		{Name: "launcher.exe", PID: 11, Duration: 40 * time.Second},
+		{Name: "other.exe", PID: 12},
+	})
+	want := []string{
+		"game.exe (PID: 10) 已运行 1 小时 35 分钟",
+		"launcher.exe (PID: 11) 已运行 40 秒",
+		"other.exe (PID: 12) 已运行 未知",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("报告行数应为 %d，实际为 %d", len(want), len(lines))
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("第 %d 行应为 %q，实际为 %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestGameTimeLines(t *testing.T) {
+	lines := gameTimeLines(map[string]int64{
+		"b.exe": 30 * 60,
+		"a.exe": 90 * 60,
+		"c.exe": 30 * 60,
+	})
+	want := []string{"a.exe: 1 小时 30 分钟", "b.exe: 30 分钟", "c.exe: 30 分钟"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("各游戏时间应按时长降序、同时长按名称排列，实际 %v", lines)
+	}
+}
+
+func TestLastExitLine(t *testing.T) {
+	started := time.Date(2026, 3, 9, 8, 0, 0, 0, time.Local)
+	stopped := internal.ExitRecord{StartedAt: started, StoppedAt: started.Add(time.Hour), Reason: internal.ExitPanic, Detail: "boom"}
+	if got := lastExitLine(stopped, false); got != "守护进程上次于 2026-03-09 09:00:00 停止，原因: 程序崩溃: boom" {
+		t.Errorf("应显示停止时间与原因，实际 %q", got)
+	}
+
+	// 重新启动后仍显示上一次的停止
+	restarted := stopped
+	restarted.StartedAt = started.Add(2 * time.Hour)
+	if got := lastExitLine(restarted, true); !strings.Contains(got, "09:00:00") {
+		t.Errorf("运行中应显示上一次停止，实际 %q", got)
+	}
+	if got := lastExitLine(restarted, false); !strings.Contains(got, "未记录退出原因") {
+		t.Errorf("未运行且本次运行没有退出记录时应说明原因未知，实际 %q", got)
+	}
+	if got := lastExitLine(internal.ExitRecord{StartedAt: started}, true); got != "" {
+		t.Errorf("从未停止过时不应输出，实际 %q", got)
+	}
+}
+
+func TestPrintVersion(t *testing.T) {
+	origVersion, origCommit, origDate := Version, Commit, BuildDate
+	defer func() { Version, Commit, BuildDate = origVersion, origCommit, origDate }()
+
+	Version, Commit, BuildDate = "v1.2.3", "abc1234", "2026-02-12T08:00:00Z"
+
+	var buf bytes.Buffer
+	printVersion(&buf)
+
+	out := buf.String()
+	for _, want := range []string{"v1.2.3", "abc1234", "2026-02-12T08:00:00Z"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("版本输出应包含 %q，实际为:\n%s", want, out)
+		}
+	}
+}
+
+func TestCLIOutput_English(t *testing.T) {
+	i18n.SetLanguage(i18n.EN)
+	defer i18n.SetLanguage(i18n.ZH)
+
+	var help bytes.Buffer
+	printHelp(&help)
+	for _, want := range []string{"Usage:", "Commands:", "Exit codes:"} {
+		if !strings.Contains(help.String(), want) {
+			t.Errorf("英文帮助应包含 %q，实际为:\n%s", want, help.String())
+		}
+	}
+
+	var errOut bytes.Buffer
+	printError(&errOut, errors.New(i18n.T("cli.daemon.notRunning")))
+	if got := errOut.String(); got != "Error: The daemon is not running\n" {
+		t.Errorf("英文错误输出应为 %q，实际 %q", "Error: The daemon is not running\n", got)
+	}
+
+	if _, err := parseStartArgs([]string{"--bogus"}); err == nil || err.Error() != "Unknown flag: --bogus" {
+		t.Errorf("参数错误应按英文输出，实际 %v", err)
+	}
+}
+
+func TestPrintSchema(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printSchema(&buf); err != nil {
+		t.Fatalf("输出 schema 失败: %v", err)
+	}
+
+	var schema struct {
+		Type       string                     `json:"type"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &schema); err != nil {
+		t.Fatalf("输出应为合法 JSON: %v", err)
+	}
+	if schema.Type != "object" || schema.Properties["dailyLimit"] == nil || schema.Properties["enforcement"] == nil {
+		t.Errorf("schema 应描述顶层配置项，实际为:\n%s", buf.String())
+	}
+}
+
+func TestTakeConfigDirFlag(t *testing.T) {
+	dir := t.TempDir()
+
+	args, err := takeConfigDirFlag([]string{"--config-dir", dir, "--dry-run"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if strings.Join(args, " ") != "--dry-run "+dir {
+		t.Errorf("--config-dir 应改写为位置参数，实际 %v", args)
+	}
+
+	if args, _ := takeConfigDirFlag([]string{"--config-dir=" + dir}); len(args) != 1 || args[0] != dir {
+		t.Errorf("应支持 --config-dir=DIR 写法，实际 %v", args)
+	}
+	if _, err := takeConfigDirFlag([]string{"--config-dir"}); err == nil {
+		t.Error("缺少目录时应返回错误")
+	}
+	if _, err := takeConfigDirFlag([]string{"--config-dir", dir + "/missing"}); err == nil {
+		t.Error("目录不存在时应返回错误")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/i18n"
+)
+
+// globalOptions 写在子命令之前、对所有命令生效的参数
+type globalOptions struct {
+	quiet   bool // 不输出非错误的标准输出，便于脚本只依据退出码判断
+	verbose bool // 额外输出诊断信息（解析后的路径与生效的配置）到标准错误
+}
+
+// verbose 是否输出诊断信息，由 --verbose 设置
+var verbose bool
+
+// parseGlobalArgs 取出子命令之前的 --quiet/--verbose，返回其余参数（以子命令开头）。
+// 子命令之后的同名参数仍交给子命令解析
+func parseGlobalArgs(args []string) (globalOptions, []string, error) {
+	var opts globalOptions
+	rest := args
+flags:
+	for len(rest) > 0 {
+		switch rest[0] {
+		case "--quiet":
+			opts.quiet = true
+		case "--verbose":
+			opts.verbose = true
+		default:
+			break flags
+		}
+		rest = rest[1:]
+	}
+	if opts.quiet && opts.verbose {
+		return opts, nil, errors.New(i18n.T("cli.quietVerbose"))
+	}
+	return opts, rest, nil
+}
+
+// silenceStdout 将标准输出指向空设备，错误信息仍写入标准错误
+func silenceStdout() error {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.devNullFailed", os.DevNull), err)
+	}
+	os.Stdout = devNull
+	return nil
+}
+
+// verboseLine 在 --verbose 时向标准错误输出一行诊断信息，文字为 i18n 中 key 对应的文字
+func verboseLine(key string, args ...any) {
+	if verbose {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.verbose.prefix")+i18n.T(key, args...))
+	}
+}
+
+// printEffectiveConfig 在 --verbose 时输出配置来源、解析后的文件路径与生效的配置，密码哈希与状态密钥不输出
+func printEffectiveConfig(path string, cfg *config.Config) {
+	if !verbose {
+		return
+	}
+	if expanded, err := config.ExpandPath(path); err == nil {
+		if abs, err := filepath.Abs(expanded); err == nil {
+			path = abs
+		}
+	}
+	verboseLine("cli.verbose.config", path)
+	verboseLine("cli.verbose.stateFile", cfg.StateFile)
+	verboseLine("cli.verbose.logFile", cfg.LogFile)
+	if cfg.Logging.EventsPath != "" {
+		verboseLine("cli.verbose.eventsFile", cfg.Logging.EventsPath)
+	}
+	if cfg.Export.RemainingFile != "" {
+		verboseLine("cli.verbose.exportFile", cfg.Export.RemainingFile)
+	}
+
+	effective := *cfg
+	if effective.Admin.PasswordHash != "" {
+		effective.Admin.PasswordHash = i18n.T("cli.verbose.hidden")
+	}
+	if effective.State.HMACKey != "" {
+		effective.State.HMACKey = i18n.T("cli.verbose.hidden")
+	}
+	data, err := yaml.Marshal(&effective)
+	if err != nil {
+		verboseLine("cli.verbose.marshalFailed", err)
+		return
+	}
+	verboseLine("cli.verbose.effective", data)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGlobalArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    globalOptions
+		rest    string
+		wantErr bool
+	}{
+		{args: []string{"status"}, rest: "status"},
+		{args: []string{"--quiet", "validate", "a.yaml"}, want: globalOptions{quiet: true}, rest: "validate a.yaml"},
+		{args: []string{"--verbose", "status", "--profile", "alice"}, want: globalOptions{verbose: true}, rest: "status --profile alice"},
+		// 子命令之后的参数原样交给子命令
+		{args: []string{"start", "--quiet"}, rest: "start --quiet"},
+		{args: []string{"--verbose", "--version"}, want: globalOptions{verbose: true}, rest: "--version"},
+		{args: []string{"--quiet"}, want: globalOptions{quiet: true}},
+		{args: []string{"--quiet", "--verbose", "status"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		opts, rest, err := parseGlobalArgs(tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%v 应返回错误", tt.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v 解析失败: %v", tt.args, err)
+			continue
+		}
+		if opts != tt.want || strings.Join(rest, " ") != tt.rest {
+			t.Errorf("%v 应解析为 %+v 与 %q，实际 %+v 与 %q", tt.args, tt.want, tt.rest, opts, strings.Join(rest, " "))
+		}
+	}
+}
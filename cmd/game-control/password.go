@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yourusername/game-control/pkg/admin"
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/i18n"
+)
+
+// takePasswordFlag 从参数中取出 --password 的值，返回其余参数
+func takePasswordFlag(args []string) (string, []string, error) {
+	var password string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--password":
+			if i+1 >= len(args) {
+				return "", nil, errors.New(i18n.T("cli.password.missing"))
+			}
+			i++
+			password = args[i]
+		case strings.HasPrefix(arg, "--password="):
+			password = strings.TrimPrefix(arg, "--password=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return password, rest, nil
+}
+
+// requirePassword 配置了 admin.passwordHash 时验证家长密码，未通过 --password 提供时提示输入
+func requirePassword(cfg *config.Config, password string) error {
+	if cfg.Admin.PasswordHash == "" {
+		return nil
+	}
+	if password == "" {
+		var err error
+		if password, err = readPassword(i18n.T("cli.password.prompt")); err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("cli.password.readFailed"), err)
+		}
+	}
+	if err := admin.VerifyPassword(cfg.Admin.PasswordHash, password); err != nil {
+		if errors.Is(err, admin.ErrWrongPassword) {
+			return err
+		}
+		return fmt.Errorf("admin.passwordHash: %w", err)
+	}
+	return nil
+}
+
+// runSetPassword 生成家长密码哈希；配置中已有密码时需先验证旧密码
+func runSetPassword() error {
+	password, rest, err := takePasswordFlag(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	configPath, err := configPathArg(rest)
+	if err != nil {
+		return err
+	}
+
+	if cfg, err := loadConfig(configPath); err == nil && cfg.Admin.PasswordHash != "" {
+		fmt.Println(i18n.T("cli.password.verifyCurrent"))
+		if err := requirePassword(cfg, ""); err != nil {
+			return err
+		}
+	}
+
+	if password == "" {
+		if password, err = readPassword(i18n.T("cli.password.newPrompt")); err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("cli.password.readFailed"), err)
+		}
+		confirm, err := readPassword(i18n.T("cli.password.confirmPrompt"))
+		if err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("cli.password.readFailed"), err)
+		}
+		if confirm != password {
+			return errors.New(i18n.T("cli.password.mismatch"))
+		}
+	}
+
+	hash, err := admin.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(i18n.T("cli.password.writeConfig", configPath))
+	fmt.Println("admin:")
+	fmt.Printf("  passwordHash: %q\n", hash)
+	return nil
+}
+
+// stdinReader 在多次读取之间共享缓冲，标准输入为管道时不会丢失后续行
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// readLine 从标准输入读取一行，去掉行尾换行
+func readLine() (string, error) {
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
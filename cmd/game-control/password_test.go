@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/admin"
+	"github.com/yourusername/game-control/pkg/config"
+)
+
+func TestTakePasswordFlag(t *testing.T) {
+	password, rest, err := takePasswordFlag([]string{"kid.yaml", "--password", "secret"})
+	if err != nil || password != "secret" || len(rest) != 1 || rest[0] != "kid.yaml" {
+		t.Fatalf("解析结果不正确: %q %v %v", password, rest, err)
+	}
+
+	password, rest, err = takePasswordFlag([]string{"--password=a b"})
+	if err != nil || password != "a b" || len(rest) != 0 {
+		t.Fatalf("解析结果不正确: %q %v %v", password, rest, err)
+	}
+
+	if _, _, err := takePasswordFlag([]string{"--password"}); err == nil {
+		t.Fatal("--password 缺少值时应报错")
+	}
+}
+
+func TestRequirePassword(t *testing.T) {
+	hash, err := admin.HashPassword("parent-secret")
+	if err != nil {
+		t.Fatalf("HashPassword 失败: %v", err)
+	}
+	cfg := &config.Config{Admin: config.AdminConfig{PasswordHash: hash}}
+
+	if err := requirePassword(cfg, "parent-secret"); err != nil {
+		t.Fatalf("正确的密码应通过验证: %v", err)
+	}
+	if err := requirePassword(cfg, "wrong"); !errors.Is(err, admin.ErrWrongPassword) {
+		t.Fatalf("错误的密码应被拒绝，实际 %v", err)
+	}
+
+	if err := requirePassword(&config.Config{}, ""); err != nil {
+		t.Fatalf("未设置密码时不应要求验证: %v", err)
+	}
+}
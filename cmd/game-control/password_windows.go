@@ -0,0 +1,34 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+const enableEchoInput = 0x0004
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+)
+
+// readPassword 提示并读取密码，控制台中关闭回显（不是控制台时直接读取一行）
+func readPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	handle := syscall.Handle(os.Stdin.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err == nil {
+		ret, _, _ := procSetConsoleMode.Call(uintptr(handle), uintptr(mode&^enableEchoInput))
+		if ret != 0 {
+			defer func() {
+				_, _, _ = procSetConsoleMode.Call(uintptr(handle), uintptr(mode))
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+	return readLine()
+}
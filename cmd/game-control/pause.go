@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/game-control/internal"
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/i18n"
+)
+
+// pauseOptions pause/resume 命令参数
+type pauseOptions struct {
+	game       string
+	configPath string
+	profile    string
+	password   string
+}
+
+// parsePauseArgs 解析 pause/resume 命令参数（不含命令名本身）：<game> [config] [--profile NAME] [--password P]
+func parsePauseArgs(args []string) (pauseOptions, error) {
+	var opts pauseOptions
+	password, rest, err := takePasswordFlag(args)
+	if err != nil {
+		return opts, err
+	}
+	opts.password = password
+
+	profile, rest, err := takeProfileFlag(rest)
+	if err != nil {
+		return opts, err
+	}
+	opts.profile = profile
+
+	positional, err := parseFlags(rest, map[string]*bool{})
+	if err != nil {
+		return opts, err
+	}
+	if len(positional) == 0 {
+		return opts, errors.New(i18n.T("cli.pause.missingGame"))
+	}
+	opts.game = positional[0]
+	opts.configPath, err = configPathArg(positional[1:])
+	return opts, err
+}
+
+// runPause 暂停（pause 为 true）或恢复对单个游戏的限制，守护进程在下一个周期生效
+func runPause(pause bool) error {
+	opts, err := parsePauseArgs(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	if err := requirePassword(cfg, opts.password); err != nil {
+		return err
+	}
+
+	target, err := controlTarget(cfg, opts.profile)
+	if err != nil {
+		return err
+	}
+
+	command, requested := internal.CommandResume, "cli.pause.resumeRequested"
+	if pause {
+		command, requested = internal.CommandPause, "cli.pause.pauseRequested"
+	}
+	if err := internal.SendControlCommand(target, command, opts.game); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T(requested, config.NormalizeGameName(opts.game)))
+	return nil
+}
+
+// controlTarget 返回控制命令作用的配置：指定了档案时为该档案，配置了多个档案时必须指定
+func controlTarget(cfg *config.Config, profile string) (*config.Config, error) {
+	if profile != "" {
+		return cfg.ForProfile(profile)
+	}
+	if len(cfg.Profiles) > 0 {
+		return nil, errors.New(i18n.T("cli.profile.required"))
+	}
+	return cfg, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/i18n"
+)
+
+// runPolicyKeygen 生成锁定策略的 Ed25519 密钥对：私钥写入指定文件（已存在时拒绝覆盖），公钥输出为配置项
+func runPolicyKeygen() error {
+	positional, err := parseFlags(os.Args[2:], map[string]*bool{})
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New(i18n.T("cli.policy.keygenUsage"))
+	}
+	return policyKeygen(os.Stdout, positional[0])
+}
+
+// policyKeygen 生成密钥对，私钥以 0600 权限写入 keyPath
+func policyKeygen(w io.Writer, keyPath string) error {
+	publicKey, privateKey, err := config.GeneratePolicyKey()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(keyPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.policy.writeKeyFailed"), err)
+	}
+	if _, err := fmt.Fprintln(f, privateKey); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", i18n.T("cli.policy.writeKeyFailed"), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.policy.writeKeyFailed"), err)
+	}
+
+	fmt.Fprintln(w, i18n.T("cli.policy.keyWritten", keyPath))
+	fmt.Fprintln(w, i18n.T("cli.policy.addToConfig"))
+	fmt.Fprintln(w, "policy:")
+	fmt.Fprintf(w, "  publicKey: %q\n", publicKey)
+	return nil
+}
+
+// takeKeyFlag 从参数中取出 --key 的值，返回其余参数
+func takeKeyFlag(args []string) (string, []string, error) {
+	var key string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--key":
+			if i+1 >= len(args) {
+				return "", nil, errors.New(i18n.T("cli.policy.keyFlagMissing"))
+			}
+			i++
+			key = args[i]
+		case strings.HasPrefix(arg, "--key="):
+			key = strings.TrimPrefix(arg, "--key=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return key, rest, nil
+}
+
+// runSignPolicy 用私钥签名配置文件，签名策略输出到标准输出
+func runSignPolicy() error {
+	keyPath, rest, err := takeKeyFlag(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	if keyPath == "" {
+		return errors.New(i18n.T("cli.policy.keyMissing"))
+	}
+	positional, err := parseFlags(rest, map[string]*bool{})
+	if err != nil {
+		return err
+	}
+	configPath, err := configPathArg(positional)
+	if err != nil {
+		return err
+	}
+	return signPolicy(os.Stdout, configPath, keyPath)
+}
+
+// signPolicy 读取配置原文与私钥，输出签名策略
+func signPolicy(w io.Writer, configPath, keyPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.policy.readConfigFailed"), err)
+	}
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.policy.readKeyFailed"), err)
+	}
+	signed, err := config.SignPolicy(data, string(key))
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.policy.signFailed"), err)
+	}
+	_, err = fmt.Fprintln(w, string(signed))
+	return err
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/config"
+)
+
+func TestPolicyKeygenAndSign(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "policy.key")
+
+	var out bytes.Buffer
+	if err := policyKeygen(&out, keyPath); err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	var publicKey string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "publicKey: "); ok {
+			publicKey = strings.Trim(value, `"`)
+		}
+	}
+	if publicKey == "" {
+		t.Fatalf("应输出 publicKey 配置，实际:\n%s", out.String())
+	}
+	if err := policyKeygen(&out, keyPath); err == nil {
+		t.Error("私钥文件已存在时不应覆盖")
+	}
+
+	configPath := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(configPath, []byte("dailyLimit: 30\n"), 0644); err != nil {
+		t.Fatalf("写入配置失败: %v", err)
+	}
+	var signed bytes.Buffer
+	if err := signPolicy(&signed, configPath, keyPath); err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	data, err := config.VerifyPolicy(signed.Bytes(), publicKey)
+	if err != nil {
+		t.Fatalf("签名策略应能用生成的公钥校验: %v", err)
+	}
+	if string(data) != "dailyLimit: 30\n" {
+		t.Errorf("策略内容 = %q", data)
+	}
+}
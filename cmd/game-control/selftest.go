@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/yourusername/game-control/internal"
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/logger"
+)
+
+// selftestTargetLifetime 自检目标进程最长的运行时间，自检异常退出时也不会一直留在系统中
+const selftestTargetLifetime = 10 * time.Minute
+
+// selftestTargetArgs 以自检目标模式运行本程序副本的参数，测试中替换为测试二进制的辅助进程参数
+var selftestTargetArgs = []string{"selftest", "--target"}
+
+// runSelftest 启动一个无害的进程作为“游戏”，用加速的控制循环验证其被发现、计时并在达到限制后被终止
+func runSelftest() error {
+	var target bool
+	positional, err := parseFlags(os.Args[2:], map[string]*bool{"--target": &target})
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New(i18n.T("cli.selftest.noArgs", positional))
+	}
+	if target {
+		// 自检目标：什么也不做，等待被终止
+		time.Sleep(selftestTargetLifetime)
+		return nil
+	}
+
+	if runtime.GOOS != "windows" {
+		return errors.New(i18n.T("cli.selftest.windowsOnly"))
+	}
+	return selftest(os.Stdout, selftestTargetArgs)
+}
+
+// selftest 在临时目录中运行自检并输出每一步的结果，未通过时保留临时目录中的自检日志
+func selftest(w io.Writer, targetArgs []string) (err error) {
+	dir, err := os.MkdirTemp("", "gamectl-selftest-")
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.selftest.dirFailed"), err)
+	}
+	logPath := filepath.Join(dir, "selftest.log")
+	defer func() {
+		if err == nil {
+			_ = os.RemoveAll(dir)
+		} else {
+			fmt.Fprintln(w, i18n.T("cli.selftest.log", logPath))
+		}
+	}()
+
+	log, err := logger.NewLogger(logPath)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+
+	name := fmt.Sprintf("gamectl-selftest-%d.exe", os.Getpid())
+	cmd, err := startSelftestTarget(dir, name, targetArgs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// 自检失败时目标进程可能仍在运行
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	fmt.Fprintln(w, i18n.T("cli.selftest.started", name, cmd.Process.Pid))
+
+	steps, err := internal.RunSelfTest(internal.SelfTestOptions{
+		GameName: name,
+		PID:      cmd.Process.Pid,
+		Dir:      dir,
+	})
+	if err != nil {
+		return err
+	}
+
+	passed := true
+	for _, step := range steps {
+		result := i18n.T("cli.selftest.pass")
+		if !step.OK {
+			result = i18n.T("cli.selftest.fail")
+			passed = false
+		}
+		fmt.Fprintf(w, "[%s] %s: %s\n", result, step.Name, step.Detail)
+	}
+	if !passed {
+		return errors.New(i18n.T("cli.selftest.failed"))
+	}
+	fmt.Fprintln(w, i18n.T("cli.selftest.passed"))
+	return nil
+}
+
+// startSelftestTarget 将本程序复制为 dir 中的 name 并以 args 启动，作为自检的目标进程。
+// 使用唯一的映像名，自检不会匹配或终止其他进程
+func startSelftestTarget(dir, name string, args []string) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("cli.exePathFailed"), err)
+	}
+	path := filepath.Join(dir, name)
+	if err := copyExecutable(self, path); err != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("cli.selftest.copyFailed"), err)
+	}
+
+	cmd := exec.Command(path, args...)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("cli.selftest.startFailed"), err)
+	}
+	return cmd, nil
+}
+
+// copyExecutable 复制可执行文件
+func copyExecutable(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
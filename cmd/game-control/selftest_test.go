@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSelftestHelperProcess 自检目标进程：只在 TestSelftest 启动的测试二进制副本中等待被终止
+func TestSelftestHelperProcess(t *testing.T) {
+	if os.Getenv("GAMECTL_SELFTEST_HELPER") != "1" {
+		return
+	}
+	time.Sleep(selftestTargetLifetime)
+	os.Exit(0)
+}
+
+func TestSelftest(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("自检依赖 tasklist/taskkill，仅在 Windows 上运行")
+	}
+	t.Setenv("GAMECTL_SELFTEST_HELPER", "1")
+
+	var out bytes.Buffer
+	if err := selftest(&out, []string{"-test.run=^TestSelftestHelperProcess$"}); err != nil {
+		t.Fatalf("自检应通过: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "自检通过") || strings.Contains(out.String(), "[失败]") {
+		t.Errorf("输出应报告每一步通过，实际:\n%s", out.String())
+	}
+}
+
+func TestRunSelftest_RejectsArgs(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"game-control", "selftest", "config.yaml"}
+	if err := runSelftest(); err == nil {
+		t.Error("selftest 不应接受位置参数")
+	}
+}
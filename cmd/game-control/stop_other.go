@@ -0,0 +1,23 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/yourusername/game-control/pkg/i18n"
+)
+
+// requestStop 向守护进程发送 SIGTERM，触发其保存状态并退出
+func requestStop(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.stop.findFailed"), err)
+	}
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.stop.signalFailed"), err)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/game-control/pkg/i18n"
+)
+
+// requestStop 使用不带 /F 的 taskkill 请求守护进程正常关闭。
+// 无窗口的后台进程可能无法响应该请求，此时需要手动执行 taskkill /F（状态最多丢失一个保存间隔）。
+func requestStop(pid int) error {
+	cmd := exec.Command("taskkill", "/PID", strconv.Itoa(pid))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.stop.requestFailed", strings.TrimSpace(string(output)), pid), err)
+	}
+	return nil
+}
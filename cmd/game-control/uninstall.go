@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yourusername/game-control/internal"
+	"github.com/yourusername/game-control/pkg/autostart"
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/singleinstance"
+)
+
+// removeAutostartTask 移除开机自启动，测试中替换为假实现
+var removeAutostartTask = autostart.RemoveTask
+
+// uninstallOptions uninstall 命令参数
+type uninstallOptions struct {
+	configPath string
+	password   string
+	yes        bool // 确认执行；未指定时只列出将要清理的内容
+	archive    bool // 将状态与日志移入归档目录而不是删除
+}
+
+// parseUninstallArgs 解析 uninstall 命令参数（不含命令名本身）：[config] [--yes] [--archive] [--password P]
+func parseUninstallArgs(args []string) (uninstallOptions, error) {
+	var opts uninstallOptions
+	password, rest, err := takePasswordFlag(args)
+	if err != nil {
+		return opts, err
+	}
+	opts.password = password
+
+	positional, err := parseFlags(rest, map[string]*bool{
+		"--yes":     &opts.yes,
+		"--archive": &opts.archive,
+	})
+	if err != nil {
+		return opts, err
+	}
+	opts.configPath, err = configPathArg(positional)
+	return opts, err
+}
+
+// uninstallPlan uninstall 要清理的文件（均为已存在的文件）
+type uninstallPlan struct {
+	runtime []string // 锁文件、控制文件、导出文件等运行时文件，总是删除
+	history []string // 状态文件与日志，--archive 时移入归档目录
+}
+
+// planUninstall 列出配置对应的运行时文件与历史文件，包括各档案的状态文件与导出文件
+func planUninstall(cfg *config.Config, lockName string, lockOpts singleinstance.Options) uninstallPlan {
+	var plan uninstallPlan
+	seen := make(map[string]bool)
+	add := func(list *[]string, path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			return
+		}
+		seen[path] = true
+		*list = append(*list, path)
+	}
+
+	add(&plan.runtime, singleinstance.LockFilePath(lockName, lockOpts))
+	add(&plan.runtime, singleinstance.LockFilePath(watchdogLock(lockName), lockOpts))
+
+	configs := []*config.Config{cfg}
+	for _, p := range cfg.Profiles {
+		if pc, err := cfg.ForProfile(p.Name); err == nil {
+			configs = append(configs, pc)
+		}
+	}
+	for _, c := range configs {
+		add(&plan.runtime, internal.ControlFilePath(c))
+		add(&plan.runtime, internal.ExitRecordPath(c))
+		add(&plan.runtime, c.StateFile+".lock")
+		add(&plan.runtime, c.Export.RemainingFile)
+
+		add(&plan.history, c.StateFile)
+		// 加载时隔离的损坏状态文件
+		corrupt, _ := filepath.Glob(c.StateFile + ".corrupt-*")
+		for _, path := range corrupt {
+			add(&plan.history, path)
+		}
+	}
+	add(&plan.history, cfg.LogFile)
+	add(&plan.history, cfg.Logging.EventsPath)
+	return plan
+}
+
+func runUninstall() error {
+	opts, err := parseUninstallArgs(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	if err := requirePassword(cfg, opts.password); err != nil {
+		return err
+	}
+
+	lockName, lockOpts := instanceLock(cfg, opts.configPath)
+	// 守护进程运行时会重新写入状态与锁文件，需先停止
+	for _, name := range []string{lockName, watchdogLock(lockName)} {
+		if pid, err := runningPID(name, lockOpts); err == nil {
+			return errors.New(i18n.T("cli.uninstall.running", pid))
+		}
+	}
+
+	return uninstall(os.Stdout, cfg, lockName, lockOpts, opts, time.Now())
+}
+
+// uninstall 移除开机自启动并清理 plan 中的文件，逐项输出处理结果；
+// 未指定 --yes 时只列出将要清理的内容并返回错误
+func uninstall(w io.Writer, cfg *config.Config, lockName string, lockOpts singleinstance.Options, opts uninstallOptions, now time.Time) error {
+	plan := planUninstall(cfg, lockName, lockOpts)
+
+	if !opts.yes {
+		fmt.Fprintln(w, i18n.T("cli.uninstall.plan"))
+		for _, path := range plan.runtime {
+			fmt.Fprintln(w, "  "+i18n.T("cli.uninstall.planDelete", path))
+		}
+		action := "cli.uninstall.planDelete"
+		if opts.archive {
+			action = "cli.uninstall.planArchive"
+		}
+		for _, path := range plan.history {
+			fmt.Fprintln(w, "  "+i18n.T(action, path))
+		}
+		return errors.New(i18n.T("cli.uninstall.notConfirmed"))
+	}
+
+	// 自启动可能从未安装，移除失败不影响清理文件
+	if err := removeAutostartTask(); err != nil {
+		fmt.Fprintln(w, i18n.T("cli.uninstall.autostartFailed", err))
+	} else {
+		fmt.Fprintln(w, i18n.T("cli.uninstall.autostartRemoved"))
+	}
+
+	var failed int
+	for _, path := range plan.runtime {
+		if err := os.Remove(path); err != nil {
+			fmt.Fprintln(w, i18n.T("cli.uninstall.deleteFailed", path, err))
+			failed++
+			continue
+		}
+		fmt.Fprintln(w, i18n.T("cli.uninstall.deleted", path))
+	}
+
+	if opts.archive && len(plan.history) > 0 {
+		dir := filepath.Join(filepath.Dir(cfg.StateFile), "game-control-history-"+now.Format("20060102-150405"))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("cli.uninstall.archiveDirFailed"), err)
+		}
+		for _, path := range plan.history {
+			target := filepath.Join(dir, filepath.Base(path))
+			if err := os.Rename(path, target); err != nil {
+				fmt.Fprintln(w, i18n.T("cli.uninstall.archiveFailed", path, err))
+				failed++
+				continue
+			}
+			fmt.Fprintln(w, i18n.T("cli.uninstall.archived", path, target))
+		}
+	} else {
+		for _, path := range plan.history {
+			if err := os.Remove(path); err != nil {
+				fmt.Fprintln(w, i18n.T("cli.uninstall.deleteFailed", path, err))
+				failed++
+				continue
+			}
+			fmt.Fprintln(w, i18n.T("cli.uninstall.deleted", path))
+		}
+	}
+
+	if failed > 0 {
+		return errors.New(i18n.T("cli.uninstall.incomplete", failed))
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/singleinstance"
+)
+
+// setupUninstall 在临时目录写入配置及其状态、日志、锁等文件，并将自启动移除替换为计数的假实现
+func setupUninstall(t *testing.T) (dir string, lockName string, lockOpts singleinstance.Options, removeCalls *int) {
+	t.Helper()
+	dir = t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	content := "dailyLimit: 120\ngames: [game.exe]\nlogging:\n  eventsPath: events.jsonl\nexport:\n  remainingFile: remaining.json\n" +
+		"instance:\n  lockDir: locks\nprofiles:\n  - name: kid\n    users: [kid]\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("写入配置失败: %v", err)
+	}
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	lockName, lockOpts = instanceLock(cfg, configPath)
+	if err := os.MkdirAll(lockOpts.Dir, 0755); err != nil {
+		t.Fatalf("创建锁目录失败: %v", err)
+	}
+
+	for _, path := range []string{
+		singleinstance.LockFilePath(lockName, lockOpts),
+		singleinstance.LockFilePath(watchdogLock(lockName), lockOpts),
+		filepath.Join(dir, "state.json"),
+		filepath.Join(dir, "state.json.control"),
+		filepath.Join(dir, "state.json.exit"),
+		filepath.Join(dir, "state.json.corrupt-20260101-000000"),
+		filepath.Join(dir, "state-kid.json"),
+		filepath.Join(dir, "game-control.log"),
+		filepath.Join(dir, "events.jsonl"),
+		filepath.Join(dir, "remaining.json"),
+	} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("写入 %s 失败: %v", path, err)
+		}
+	}
+
+	removeCalls = new(int)
+	original := removeAutostartTask
+	removeAutostartTask = func() error {
+		*removeCalls++
+		return nil
+	}
+	t.Cleanup(func() { removeAutostartTask = original })
+	return dir, lockName, lockOpts, removeCalls
+}
+
+// remainingFiles 返回 dir 下（含子目录）剩余的文件，路径相对于 dir
+func remainingFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("遍历目录失败: %v", err)
+	}
+	return files
+}
+
+func TestUninstall_RemovesAutostartAndFiles(t *testing.T) {
+	dir, lockName, lockOpts, removeCalls := setupUninstall(t)
+	cfg, err := loadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := uninstall(&out, cfg, lockName, lockOpts, uninstallOptions{yes: true}, time.Now()); err != nil {
+		t.Fatalf("卸载失败: %v\n%s", err, out.String())
+	}
+
+	if *removeCalls != 1 {
+		t.Errorf("应移除自启动一次，实际 %d 次", *removeCalls)
+	}
+	if got := remainingFiles(t, dir); len(got) != 1 || got[0] != "config.yaml" {
+		t.Errorf("应只保留配置文件，实际 %v", got)
+	}
+	if !strings.Contains(out.String(), "已删除 "+filepath.Join(dir, "state-kid.json")) {
+		t.Errorf("应输出已删除的档案状态文件，实际:\n%s", out.String())
+	}
+}
+
+func TestUninstall_ArchivesHistory(t *testing.T) {
+	dir, lockName, lockOpts, _ := setupUninstall(t)
+	cfg, err := loadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	now := time.Date(2026, 10, 16, 21, 30, 0, 0, time.Local)
+	var out bytes.Buffer
+	if err := uninstall(&out, cfg, lockName, lockOpts, uninstallOptions{yes: true, archive: true}, now); err != nil {
+		t.Fatalf("卸载失败: %v\n%s", err, out.String())
+	}
+
+	archive := "game-control-history-20261016-213000/"
+	want := []string{
+		"config.yaml",
+		archive + "events.jsonl",
+		archive + "game-control.log",
+		archive + "state-kid.json",
+		archive + "state.json",
+		archive + "state.json.corrupt-20260101-000000",
+	}
+	if got := remainingFiles(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("归档后剩余文件 = %v，预期 %v", got, want)
+	}
+}
+
+func TestUninstall_RequiresConfirmation(t *testing.T) {
+	dir, lockName, lockOpts, removeCalls := setupUninstall(t)
+	cfg, err := loadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	before := remainingFiles(t, dir)
+
+	var out bytes.Buffer
+	if err := uninstall(&out, cfg, lockName, lockOpts, uninstallOptions{}, time.Now()); err == nil {
+		t.Fatal("未指定 --yes 时应返回错误")
+	}
+	if *removeCalls != 0 {
+		t.Error("未确认时不应移除自启动")
+	}
+	if got := remainingFiles(t, dir); len(got) != len(before) {
+		t.Errorf("未确认时不应删除文件，剩余 %v", got)
+	}
+	if !strings.Contains(out.String(), filepath.Join(dir, "state.json")) {
+		t.Errorf("应列出将要清理的状态文件，实际:\n%s", out.String())
+	}
+}
+
+func TestParseUninstallArgs(t *testing.T) {
+	opts, err := parseUninstallArgs([]string{"kid.yaml", "--archive", "--yes", "--password", "p"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if opts.configPath != "kid.yaml" || !opts.yes || !opts.archive || opts.password != "p" {
+		t.Errorf("解析结果不符: %+v", opts)
+	}
+	if _, err := parseUninstallArgs([]string{"--force"}); err == nil {
+		t.Error("未知参数应返回错误")
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/config"
+)
+
+// writeConfigs 在临时目录中写入配置文件，返回目录
+func writeConfigs(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("写入 %s 失败: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestValidateFiles(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		"alice.yaml":  "dailyLimit: 90\nresetTime: \"07:00\"\ngames: [a.exe, b.exe]\n",
+		"bob.yaml":    "dailyLimit: 60\nresetTime: \"08:00\"\ngames: [c.exe]\n",
+		"broken.yaml": "dailyLimit: [1, 2\n",
+		"bad.yaml":    "dailyLimit: 60\nresetTime: \"25:00\"\ngames: [c.exe]\n",
+	})
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	var out bytes.Buffer
+	if err := validateFiles([]string{path("alice.yaml"), path("bob.yaml")}, &out); err != nil {
+		t.Fatalf("全部通过时不应返回错误: %v\n%s", err, out.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("应输出表头与每个文件一行，实际:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[1]); !slices.Equal(fields[1:6], []string{"通过", "90", "分钟", "07:00", "2"}) {
+		t.Errorf("alice.yaml 行应列出每日限制、重置时间与游戏数，实际 %q", lines[1])
+	}
+
+	out.Reset()
+	err := validateFiles([]string{path("alice.yaml"), path("broken.yaml"), path("bad.yaml")}, &out)
+	if code := exitCode(err); code != exitConfig {
+		t.Fatalf("有文件未通过时退出码应为 %d，实际 %d（%v）", exitConfig, code, err)
+	}
+	if !strings.Contains(err.Error(), "2/3") {
+		t.Errorf("错误应说明未通过的文件数，实际 %v", err)
+	}
+	results := make(map[string]string)
+	for _, line := range strings.Split(out.String(), "\n")[1:] {
+		if fields := strings.Fields(line); len(fields) > 1 {
+			results[filepath.Base(fields[0])] = fields[1]
+		}
+	}
+	want := map[string]string{"alice.yaml": "通过", "broken.yaml": "失败", "bad.yaml": "失败"}
+	for name, result := range want {
+		if results[name] != result {
+			t.Errorf("%s 应为%s，实际:\n%s", name, result, out.String())
+		}
+	}
+}
+
+func TestExpandConfigPaths(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{"a.yaml": "", "b.yaml": "", "c.yml": ""})
+
+	paths, err := expandConfigPaths([]string{filepath.Join(dir, "*.yaml"), "kid.yaml"})
+	if err != nil {
+		t.Fatalf("展开失败: %v", err)
+	}
+	want := []string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml"), "kid.yaml"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("应展开通配符并保留普通路径，实际 %v", paths)
+	}
+
+	if _, err := expandConfigPaths([]string{filepath.Join(dir, "*.json")}); err == nil {
+		t.Error("通配符没有匹配时应返回错误")
+	}
+}
+
+func TestFixConfigFiles_RewritesOldVersion(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		"old.yaml":     "dailyLimit: 60\nresetTime: \"08:00\"\ngames: [game.exe]\n",
+		"current.yaml": "version: 1\ndailyLimit: 60\nresetTime: \"08:00\"\ngames: [game.exe]\n",
+	})
+	old, current := filepath.Join(dir, "old.yaml"), filepath.Join(dir, "current.yaml")
+	before, _ := os.ReadFile(current)
+
+	var out bytes.Buffer
+	if err := fixConfigFiles([]string{old, current}, &out); err != nil {
+		t.Fatalf("fixConfigFiles 失败: %v", err)
+	}
+	if !strings.Contains(out.String(), "old.yaml") || strings.Contains(out.String(), "current.yaml") {
+		t.Errorf("应只报告被升级的文件，实际输出:\n%s", out.String())
+	}
+	cfg, err := config.LoadFromFile(old)
+	if err != nil {
+		t.Fatalf("加载升级后的配置失败: %v", err)
+	}
+	if data, _ := os.ReadFile(old); !strings.Contains(string(data), "version: 1") || cfg.DailyLimit != 60 {
+		t.Errorf("升级后的文件应写入当前版本并保留原有设置，实际:\n%s", data)
+	}
+	if after, _ := os.ReadFile(current); !bytes.Equal(before, after) {
+		t.Error("已是当前版本的配置文件不应被改写")
+	}
+
+	if err := fixConfigFiles([]string{dir}, &out); err == nil {
+		t.Error("--fix 不应接受配置目录")
+	}
+}
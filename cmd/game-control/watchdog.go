@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/singleinstance"
+)
+
+// watchdogLock 返回守护进程锁名对应的看护进程锁名
+func watchdogLock(lockName string) string {
+	return lockName + "-watchdog"
+}
+
+// runWatchdog 看护守护进程：按 watchdog.intervalSeconds 检查守护进程是否存活，消失时以相同的 start 开关重新启动。
+// 由启用了 watchdog.enabled 的 start 自动启动，stop 会先停止看护进程
+func runWatchdog() error {
+	opts, err := parseStartArgs(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	configPath := opts.configPath
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	absConfig, err := absConfigPath(configPath)
+	if err != nil {
+		return err
+	}
+
+	lockName, lockOpts := instanceLock(cfg, configPath)
+	guard, err := singleinstance.AcquireWithOptions(watchdogLock(lockName), lockOpts)
+	if err != nil {
+		if errors.Is(err, singleinstance.ErrAlreadyRunning) {
+			return alreadyRunning(i18n.T("cli.watchdog.alreadyRunning"))
+		}
+		return fmt.Errorf("%s: %w", i18n.T("cli.watchdog.lockFailed"), err)
+	}
+	defer guard.Release()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	ticker := time.NewTicker(cfg.Watchdog.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := runningPID(lockName, lockOpts); err == nil {
+				continue
+			}
+			fmt.Fprintln(os.Stderr, time.Now().Format(time.RFC3339)+" "+i18n.T("cli.watchdog.restarting"))
+			if err := spawnSelf(backgroundArgs(opts, absConfig)...); err != nil {
+				fmt.Fprintln(os.Stderr, i18n.T("cli.watchdog.restartFailed", err))
+			}
+
+		case <-sigChan:
+			return nil
+		}
+	}
+}
+
+// ensureWatchdog 看护进程未运行时在后台启动它，并转交 start 的开关（如 --dry-run、--require-admin），
+// 重新启动的守护进程与原来的一致
+func ensureWatchdog(lockName string, lockOpts singleinstance.Options, opts startOptions) error {
+	if _, err := runningPID(watchdogLock(lockName), lockOpts); err == nil {
+		return nil
+	}
+	args, err := watchdogArgs(opts)
+	if err != nil {
+		return err
+	}
+	return spawnSelf(args...)
+}
+
+// watchdogArgs 返回启动看护进程的参数，配置路径转为绝对路径
+func watchdogArgs(opts startOptions) ([]string, error) {
+	absConfig, err := absConfigPath(opts.configPath)
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"watchdog"}, startFlags(opts)...)
+	return append(args, absConfig), nil
+}
+
+// spawnSelf 以给定参数在后台启动本程序的另一个进程（脱离当前终端）
+func spawnSelf(args ...string) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.exePathFailed"), err)
+	}
+	cmd := detachedCommand(exePath, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("cli.watchdog.startFailed", args[0]), err)
+	}
+	// 回收子进程，避免在非 Windows 平台留下僵尸进程
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/logger"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+// 控制命令：CLI 追加到控制文件，守护进程在下一个周期读取并执行
+const (
+	CommandPause  = "PAUSE"  // PAUSE <game.exe>：暂停对该游戏的限制
+	CommandResume = "RESUME" // RESUME <game.exe>：恢复对该游戏的限制
+	CommandExtend = "EXTEND" // EXTEND <分钟>：临时延长当天的游戏时间，下次重置时失效
+)
+
+// MaxExtensionMinutes 单次临时延长的上限（分钟）
+const MaxExtensionMinutes = 24 * 60
+
+// ControlFilePath 返回守护进程读取控制命令的文件路径（状态文件旁的 .control 文件）
+func ControlFilePath(cfg *config.Config) string {
+	return cfg.StateFile + ".control"
+}
+
+// SendControlCommand 向控制文件追加一条命令；守护进程未运行时，命令在下次启动后执行
+func SendControlCommand(cfg *config.Config, command, game string) error {
+	game = config.NormalizeGameName(game)
+	if game == "" {
+		return fmt.Errorf("缺少游戏名")
+	}
+	return appendControlCommand(cfg, command, game)
+}
+
+// SendExtendCommand 请求守护进程临时延长当天的游戏时间 minutes 分钟
+func SendExtendCommand(cfg *config.Config, minutes int) error {
+	if err := validateExtension(minutes); err != nil {
+		return err
+	}
+	return appendControlCommand(cfg, CommandExtend, strconv.Itoa(minutes))
+}
+
+// validateExtension 检查临时延长的分钟数
+func validateExtension(minutes int) error {
+	if minutes <= 0 || minutes > MaxExtensionMinutes {
+		return fmt.Errorf("延长时间应为 1 到 %d 分钟", MaxExtensionMinutes)
+	}
+	return nil
+}
+
+// appendControlCommand 向控制文件追加一行 "<command> <arg>"。
+// 配置了签名密钥时追加 " <随机数> <重置周期> <签名>"：重置周期为当前周期结束的时间（Unix 时间戳），
+// 守护进程只在同一周期内执行、且每个随机数只执行一次，抄下的命令行无法在当天重复执行或留到以后再用
+func appendControlCommand(cfg *config.Config, command, arg string) error {
+	line := command + " " + arg
+	if key := controlKey(cfg); key != "" {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("生成控制命令随机数失败: %w", err)
+		}
+		period, err := quota.NextResetAfter(cfg, time.Now())
+		if err != nil {
+			return err
+		}
+		line += " " + hex.EncodeToString(nonce) + " " + strconv.FormatInt(period.Unix(), 10)
+		line += " " + signControlLine(key, line)
+	}
+
+	f, err := os.OpenFile(ControlFilePath(cfg), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开控制文件失败: %w", err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		return fmt.Errorf("写入控制命令失败: %w", err)
+	}
+	return nil
+}
+
+// controlKey 返回控制命令的签名密钥：优先使用 state.hmacKey，其次为 admin.passwordHash；都未配置时为空，不签名。
+// 签名只能阻止直接向控制文件追加或重放命令：密钥保存在配置文件中，能读取配置文件的人（包括以孩子账户运行时的孩子）
+// 可以自行计算签名，要防止这一点需通过文件权限让配置文件只对管理员可读
+func controlKey(cfg *config.Config) string {
+	if cfg.State.HMACKey != "" {
+		return cfg.State.HMACKey
+	}
+	return cfg.Admin.PasswordHash
+}
+
+// signControlLine 计算控制命令 "<command> <arg> <随机数> <重置周期>" 的 HMAC-SHA256 签名（十六进制）
+func signControlLine(key, line string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("game-control control:" + line))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyControlLine 校验控制命令：签名相符、属于当前重置周期且随机数未执行过时返回 true 与该随机数，并记录该随机数。
+// 未配置签名密钥时不校验，随机数为空
+func (c *Controller) verifyControlLine(fields []string) (string, bool) {
+	key := controlKey(c.config)
+	if key == "" {
+		return "", true
+	}
+	if len(fields) != 5 {
+		return "", false
+	}
+	line, signature := strings.Join(fields[:4], " "), fields[4]
+	if !hmac.Equal([]byte(signature), []byte(signControlLine(key, line))) {
+		return "", false
+	}
+	if fields[3] != strconv.FormatInt(c.quotaState.NextReset().Unix(), 10) {
+		return "", false
+	}
+	nonce := fields[2]
+	return nonce, c.quotaState.UseControlNonce(nonce)
+}
+
+// applyControlCommands 读取并执行控制文件中的命令，执行后删除该文件；状态有变化时立即保存
+func (c *Controller) applyControlCommands() {
+	// 先改名再读取，读取期间 CLI 新追加的命令写入新的控制文件，留到下个周期执行
+	path := ControlFilePath(c.config)
+	applying := path + ".applying"
+	if err := os.Rename(path, applying); err != nil {
+		if !os.IsNotExist(err) {
+			logger.Errorf("读取控制文件失败: %v", err)
+		}
+		return
+	}
+	data, err := os.ReadFile(applying)
+	_ = os.Remove(applying)
+	if err != nil {
+		logger.Errorf("读取控制文件失败: %v", err)
+		return
+	}
+
+	changed := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			logger.Warnf("忽略无效的控制命令: %q", scanner.Text())
+			continue
+		}
+		nonce, ok := c.verifyControlLine(fields)
+		if !ok {
+			logger.LogControlRejected(fields[0] + " " + fields[1])
+			continue
+		}
+		// 已记录随机数，即使命令没有改变暂停或延长状态也立即保存，防止重启后被重放
+		changed = changed || nonce != ""
+		switch strings.ToUpper(fields[0]) {
+		case CommandPause:
+			game := config.NormalizeGameName(fields[1])
+			if c.quotaState.PauseGame(game) {
+				logger.Infof("已暂停对游戏 %s 的限制", game)
+				changed = true
+			}
+		case CommandResume:
+			game := config.NormalizeGameName(fields[1])
+			if c.quotaState.ResumeGame(game) {
+				logger.Infof("已恢复对游戏 %s 的限制", game)
+				changed = true
+			}
+		case CommandExtend:
+			minutes, err := strconv.Atoi(fields[1])
+			if err == nil {
+				err = validateExtension(minutes)
+			}
+			if err != nil {
+				logger.Warnf("忽略无效的控制命令: %q", scanner.Text())
+				continue
+			}
+			logger.LogTimeExtended(minutes, c.quotaState.AddExtension(minutes), nonce)
+			changed = true
+		default:
+			logger.Warnf("忽略未知的控制命令: %q", scanner.Text())
+		}
+	}
+	if changed {
+		c.saveNow()
+	}
+}
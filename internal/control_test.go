@@ -0,0 +1,271 @@
+package internal
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/process"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+func TestControllerTick_PausedGameLeavesOtherEnforced(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Games = []string{"edu.exe", "action.exe"}
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{
+			{PID: 1, Name: "Edu.exe", StartTime: time.Now()},
+			{PID: 2, Name: "action.exe", StartTime: time.Now()},
+		}, nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		return nil
+	}
+
+	if err := SendControlCommand(controller.config, CommandPause, "edu.exe"); err != nil {
+		t.Fatalf("SendControlCommand 失败: %v", err)
+	}
+	qState.AddTime(120 * 60)
+	controller.tick()
+
+	if !qState.IsGamePaused("edu.exe") {
+		t.Fatal("控制命令执行后 edu.exe 应处于暂停状态")
+	}
+	if _, err := os.Stat(ControlFilePath(controller.config)); !os.IsNotExist(err) {
+		t.Fatal("控制命令执行后应删除控制文件")
+	}
+	if len(terminated) != 1 || terminated[0] != 2 {
+		t.Fatalf("超限时应只终止未暂停的游戏，实际 %v", terminated)
+	}
+	if got := qState.GetGameSeconds()["edu.exe"]; got != 0 {
+		t.Errorf("暂停的游戏不应计时，实际 %d 秒", got)
+	}
+
+	if err := SendControlCommand(controller.config, CommandResume, "edu.exe"); err != nil {
+		t.Fatalf("SendControlCommand 失败: %v", err)
+	}
+	terminated = nil
+	controller.tick()
+	if len(terminated) != 2 {
+		t.Fatalf("恢复后两个游戏都应被终止，实际 %v", terminated)
+	}
+}
+
+func TestControllerTick_PausedGameAloneDoesNotAccrue(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Games = []string{"edu.exe", "action.exe"}
+	qState.PauseGame(config.NormalizeGameName("edu.exe"))
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1, Name: "edu.exe"}}, nil
+	}
+	controller.tick()
+
+	if qState.AccumulatedTime != 0 {
+		t.Fatalf("只有暂停的游戏在运行时不应计时，实际 %d 秒", qState.AccumulatedTime)
+	}
+	if status := controller.GetStatus(); len(status.PausedGames) != 1 || status.PausedGames[0] != "edu.exe" {
+		t.Fatalf("状态应显示暂停的游戏，实际 %v", status.PausedGames)
+	}
+}
+
+func TestControllerTick_ExtendAfterLimit(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return nil, nil
+	}
+
+	if err := SendExtendCommand(controller.config, 0); err == nil {
+		t.Fatal("延长 0 分钟应返回错误")
+	}
+	if err := SendExtendCommand(controller.config, 10); err != nil {
+		t.Fatalf("SendExtendCommand 失败: %v", err)
+	}
+	qState.AddTime(120 * 60)
+	readLoggedEvents(t, "")
+	controller.tick()
+
+	if status := controller.GetStatus(); status.ExtensionMinutes != 10 || status.RemainingTime != 10 {
+		t.Fatalf("延长后剩余时间应为 10 分钟，实际延长 %d 分钟、剩余 %d 分钟", status.ExtensionMinutes, status.RemainingTime)
+	}
+	if events := readLoggedEvents(t, "time_extended"); len(events) != 1 {
+		t.Errorf("应记录一条 time_extended 事件，实际 %d 条", len(events))
+	}
+
+	qState.NextResetTime = time.Now().Add(-time.Minute).Unix()
+	controller.tick()
+	if status := controller.GetStatus(); status.ExtensionMinutes != 0 {
+		t.Errorf("重置后临时延长应失效，实际 %d 分钟", status.ExtensionMinutes)
+	}
+}
+
+func TestControllerTick_RejectsUnsignedExtend(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return nil, nil
+	}
+	controller.config.State.HMACKey = "secret"
+
+	// 绕过 CLI 的密码验证，直接向控制文件追加命令
+	f, err := os.OpenFile(ControlFilePath(controller.config), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("打开控制文件失败: %v", err)
+	}
+	_, _ = f.WriteString("EXTEND 1440\nEXTEND 60 0123abcd\n")
+	f.Close()
+	readLoggedEvents(t, "")
+	controller.tick()
+
+	if got := qState.GetExtensionMinutes(); got != 0 {
+		t.Fatalf("未签名或签名不符的延长命令不应执行，实际延长 %d 分钟", got)
+	}
+	if events := readLoggedEvents(t, "control_rejected"); len(events) != 2 {
+		t.Errorf("每条被拒绝的命令应记录一条 control_rejected 事件，实际 %d 条", len(events))
+	}
+
+	// CLI 写入的命令带有签名，正常执行
+	if err := SendExtendCommand(controller.config, 10); err != nil {
+		t.Fatalf("SendExtendCommand 失败: %v", err)
+	}
+	controller.tick()
+	if got := qState.GetExtensionMinutes(); got != 10 {
+		t.Errorf("签名的延长命令应执行，实际延长 %d 分钟", got)
+	}
+}
+
+func TestControllerTick_RejectsUnsignedPause(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return nil, nil
+	}
+	controller.config.Admin.PasswordHash = "pbkdf2-sha256$1$c2FsdA$aGFzaA"
+
+	if err := os.WriteFile(ControlFilePath(controller.config), []byte("PAUSE game.exe\n"), 0644); err != nil {
+		t.Fatalf("写入控制文件失败: %v", err)
+	}
+	readLoggedEvents(t, "")
+	controller.tick()
+
+	if qState.IsGamePaused("game.exe") {
+		t.Fatal("未签名的暂停命令不应执行")
+	}
+	if events := readLoggedEvents(t, "control_rejected"); len(events) != 1 {
+		t.Errorf("应记录一条 control_rejected 事件，实际 %d 条", len(events))
+	}
+
+	if err := SendControlCommand(controller.config, CommandPause, "game.exe"); err != nil {
+		t.Fatalf("SendControlCommand 失败: %v", err)
+	}
+	controller.tick()
+	if !qState.IsGamePaused("game.exe") {
+		t.Fatal("签名的暂停命令应执行")
+	}
+	if err := SendControlCommand(controller.config, CommandResume, "game.exe"); err != nil {
+		t.Fatalf("SendControlCommand 失败: %v", err)
+	}
+	controller.tick()
+	if qState.IsGamePaused("game.exe") {
+		t.Error("签名的恢复命令应执行")
+	}
+}
+
+func TestControllerTick_RejectsReplayedPause(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return nil, nil
+	}
+	controller.config.State.HMACKey = "secret"
+
+	if err := SendControlCommand(controller.config, CommandPause, "game.exe"); err != nil {
+		t.Fatalf("SendControlCommand 失败: %v", err)
+	}
+	// 孩子在守护进程读取前抄下了签名的命令行
+	captured, err := os.ReadFile(ControlFilePath(controller.config))
+	if err != nil {
+		t.Fatalf("读取控制文件失败: %v", err)
+	}
+	controller.tick()
+	if err := SendControlCommand(controller.config, CommandResume, "game.exe"); err != nil {
+		t.Fatalf("SendControlCommand 失败: %v", err)
+	}
+	controller.tick()
+	if qState.IsGamePaused("game.exe") {
+		t.Fatal("签名的恢复命令应执行")
+	}
+
+	// 同一周期内重放：随机数已执行过
+	if err := os.WriteFile(ControlFilePath(controller.config), captured, 0644); err != nil {
+		t.Fatalf("写入控制文件失败: %v", err)
+	}
+	readLoggedEvents(t, "")
+	controller.tick()
+	if qState.IsGamePaused("game.exe") {
+		t.Fatal("重放的暂停命令不应执行")
+	}
+	if events := readLoggedEvents(t, "control_rejected"); len(events) != 1 {
+		t.Errorf("重放的命令应记录 control_rejected，实际 %d 条", len(events))
+	}
+
+	// 已执行的随机数随状态保存，守护进程重启后仍拒绝重放
+	loaded, err := quota.LoadFromFile(controller.config)
+	if err != nil {
+		t.Fatalf("加载状态失败: %v", err)
+	}
+	if loaded.UseControlNonce(strings.Fields(string(captured))[2]) {
+		t.Error("已执行的随机数应写入状态文件")
+	}
+
+	// 重置后重放：命令属于之前的重置周期
+	if err := qState.Reset(); err != nil {
+		t.Fatalf("Reset 失败: %v", err)
+	}
+	qState.NextResetTime += 24 * 60 * 60
+	if err := os.WriteFile(ControlFilePath(controller.config), captured, 0644); err != nil {
+		t.Fatalf("写入控制文件失败: %v", err)
+	}
+	controller.tick()
+	if qState.IsGamePaused("game.exe") {
+		t.Fatal("之前周期的暂停命令不应在新周期执行")
+	}
+}
+
+func TestControllerTick_RejectsReplayedExtend(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return nil, nil
+	}
+	controller.config.State.HMACKey = "secret"
+
+	if err := SendExtendCommand(controller.config, 30); err != nil {
+		t.Fatalf("SendExtendCommand 失败: %v", err)
+	}
+	captured, err := os.ReadFile(ControlFilePath(controller.config))
+	if err != nil {
+		t.Fatalf("读取控制文件失败: %v", err)
+	}
+	readLoggedEvents(t, "")
+	controller.tick()
+	events := readLoggedEvents(t, "time_extended")
+	if len(events) != 1 || events[0].Nonce != strings.Fields(string(captured))[2] {
+		t.Fatalf("延长事件应记录命令的随机数，实际 %+v", events)
+	}
+
+	// 反复追加同一条签名命令不能继续累加延长时间
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(ControlFilePath(controller.config), captured, 0644); err != nil {
+			t.Fatalf("写入控制文件失败: %v", err)
+		}
+		controller.tick()
+	}
+	if got := qState.GetExtensionMinutes(); got != 30 {
+		t.Errorf("重放的延长命令不应执行，实际延长 %d 分钟", got)
+	}
+	if events := readLoggedEvents(t, "control_rejected"); len(events) != 3 {
+		t.Errorf("每次重放应记录 control_rejected，实际 %d 条", len(events))
+	}
+}
@@ -1,81 +1,289 @@
 package internal
 
 import (
-	"os"
+	"context"
+	"errors"
+	"fmt"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/yourusername/game-control/pkg/clock"
 	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/desktop"
 	"github.com/yourusername/game-control/pkg/logger"
 	"github.com/yourusername/game-control/pkg/notifier"
 	"github.com/yourusername/game-control/pkg/process"
 	"github.com/yourusername/game-control/pkg/quota"
+	"github.com/yourusername/game-control/pkg/session"
+	"github.com/yourusername/game-control/pkg/sysexec"
 )
 
-type processScanner interface {
+// tickInterval 控制循环的扫描间隔
+const tickInterval = 5 * time.Second
+
+// neverSeenCheckAfter 运行多久后检查从未出现过的游戏进程名，之后按同样的间隔重新检查
+const neverSeenCheckAfter = 30 * time.Minute
+
+// scannerDegradedAfter 连续扫描失败多少次后记录 scanner_degraded
+const scannerDegradedAfter = 3
+
+// relaunchScanInterval 启用 enforcement.blockRelaunch 且已超限终止游戏后，检查重新启动的游戏的间隔
+const relaunchScanInterval = time.Second
+
+// 处理游戏进程的原因，记录在 termination_result 与 limit_action 事件的 terminate_reason 字段
+const (
+	reasonQuota  = "quota"  // 当天游戏时间已用尽
+	reasonCurfew = "curfew" // 不在允许的游戏时段内
+	reasonBreak  = "break"  // 强制休息中
+)
+
+// softLimitReminders 超过软限制后各次提醒之间的间隔，逐次缩短，超出列表后沿用最后一项
+var softLimitReminders = []time.Duration{10 * time.Minute, 5 * time.Minute, 2 * time.Minute}
+
+// ProcessScanner 控制器依赖的进程扫描能力，由 process.Scanner 实现，测试或嵌入时可替换
+type ProcessScanner interface {
 	FindGameProcesses(gameNames []string) ([]process.ProcessInfo, error)
 	TerminateWithRetry(pid int, maxRetries int, retryDelay time.Duration) error
 }
 
+// sessionActions 超限动作 lock / logoff 依赖的会话操作，由 session.Actions 实现，可在测试中替换
+type sessionActions interface {
+	Lock() error
+	Logoff() error
+}
+
 // Controller 主控制器
 type Controller struct {
+	// mu 串行化控制循环的每次 tick 与 GetStatus 等外部调用
+	mu sync.Mutex
+
 	config       *config.Config
 	quotaState   *quota.QuotaState
-	scanner      processScanner
+	scanner      ProcessScanner
+	tracker      *process.ProcessTracker
+	metrics      *metrics
 	notifier     notifier.Notifier
 	lastSaveTime time.Time
+	// clock 当前时间的来源，测试中通过 SetClock 替换
+	clock clock.Clock
+
+	// 配置与状态文件完整性检查
+	configPath  string
+	configPrint fileFingerprint
+	stateSaved  bool
+
+	// foregroundPID 查询前台窗口所属进程，可在测试中替换
+	foregroundPID func() (int, error)
+	// visibleWindowPIDs 查询拥有可见未最小化窗口的进程，可在测试中替换
+	visibleWindowPIDs func() (map[int]bool, error)
+	// idleDuration 查询用户空闲时长，可在测试中替换
+	idleDuration func() (time.Duration, error)
+	idlePaused   bool
+	// screenLocked 查询工作站是否已锁定，可在测试中替换
+	screenLocked func() (bool, error)
+	lockPaused   bool
+	// cpuTime 查询进程累计占用的 CPU 时间，可在测试中替换；cpuSamples 上次扫描时各游戏进程的采样
+	cpuTime    func(pid int) (time.Duration, error)
+	cpuSamples map[process.ProcessKey]cpuSample
+
+	// 最短会话时长未达到前暂存的游戏时间（秒）
+	pendingSeconds int64
+	pendingGames   map[string]int64
+	// playingSince 本段连续游戏（期间始终有游戏会话）的开始时间，没有活跃会话时为零值
+	playingSince time.Time
+
+	// lastHeartbeat 上次记录 game_running 心跳的时间
+	lastHeartbeat time.Time
+
+	// 强制休息：本段连续计时的游戏时间（秒）、最后一次计时的时间，以及休息结束时间（零值表示未在休息）
+	playStreak   int64
+	lastPlayedAt time.Time
+	breakUntil   time.Time
+
+	// graceDeadline 超限宽限期截止时间，零值表示尚未进入宽限期
+	graceDeadline time.Time
+
+	// limitEnforced 当天是否已在宽限期后执行过超限终止；blockedPIDs 此后已处理过的游戏进程 PID，
+	// 用于在 enforcement.blockRelaunch 下识别重新启动的游戏
+	limitEnforced bool
+	blockedPIDs   map[int]bool
+
+	// terminationFailedNotified 本次连续终止失败期间是否已发出终止失败通知，某轮全部终止成功后清除
+	terminationFailedNotified bool
+
+	// deniedProcs 因权限不足终止失败的游戏进程，之后不再对其调用 taskkill（权限不会自行变化），每日重置时清空；
+	// accessDeniedNotified 已发出权限不足通知的游戏（小写进程名），守护进程运行期间每个游戏只通知一次
+	deniedProcs          map[process.ProcessKey]bool
+	accessDeniedNotified map[string]bool
+
+	// scheduleNotified 本段时段外时间是否已发出时段外通知，回到允许时段后清除
+	scheduleNotified bool
+
+	// killRunner 执行 enforcement.killCommands 中的自定义结束命令，可在测试中替换
+	killRunner sysexec.CommandRunner
+
+	// session 执行锁定与注销；suspendProcess/resumeProcess 挂起与恢复进程，均可在测试中替换
+	session        sessionActions
+	suspendProcess func(pid int) error
+	resumeProcess  func(pid int) error
+	// suspended 因超限被挂起的进程 PID，配额重置时恢复
+	suspended map[int]bool
+
+	// 超过软限制后已提醒的次数与上次提醒时间
+	softNotices    int
+	lastSoftNotice time.Time
+
+	// 最近一次成功扫描到的游戏进程，以及此后连续扫描失败的次数
+	lastGameProcesses []process.ProcessInfo
+	scanFailures      int
+	// slowScan 已记录 slow_scan 且扫描耗时尚未恢复正常
+	slowScan bool
+
+	// 配置的游戏名是否在扫描中出现过（小写名称），用于发现拼写错误；
+	// neverSeenCheckedAt 为上次检查的时间，尚未检查时为零值
+	startedAt          time.Time
+	seenGames          map[string]bool
+	neverSeenCheckedAt time.Time
+
+	// 上次写入 export.remainingFile 的内容，以及写入是否正在失败
+	lastExport   []byte
+	exportFailed bool
 }
 
 // NewController 创建新的控制器
 func NewController(cfg *config.Config, qState *quota.QuotaState) *Controller {
-	return NewControllerWithDeps(cfg, qState, process.NewScanner(), notifier.NewNotifier())
+	return NewControllerWithDeps(cfg, qState, defaultScanner(cfg), defaultNotifier(cfg))
+}
+
+// defaultScanner 返回按配置设置了豁免账户与匹配方式的 tasklist/taskkill 扫描器
+func defaultScanner(cfg *config.Config) *process.Scanner {
+	scanner := process.NewScanner()
+	scanner.SetExemptUsers(cfg.Enforcement.ExemptUsers)
+	scanner.SetMatchMode(cfg.Matching.MatchMode())
+	return scanner
+}
+
+// defaultNotifier 返回当前会话适用的通知器，配置了 notifications.sound 时附带提示音。
+// 弹窗在后台执行并受 notifications.timeoutSeconds 限制，不会阻塞控制循环
+func defaultNotifier(cfg *config.Config) notifier.Notifier {
+	n := notifier.WithSound(notifier.NewNotifier(cfg.Notifications.Timeout()), cfg.Notifications.Sound, func(err error) {
+		logger.Warnf("%v", err)
+	})
+	return notifier.Async(n, func(err error) {
+		logger.Errorf("弹窗失败: %v", err)
+	})
 }
 
-// NewControllerWithDeps 创建可注入依赖的控制器（用于测试）
+// NewControllerWithDeps 创建可注入依赖的控制器（用于测试或嵌入），scanner/n 为 nil 时使用默认实现
 func NewControllerWithDeps(
 	cfg *config.Config,
 	qState *quota.QuotaState,
-	scanner processScanner,
+	scanner ProcessScanner,
 	n notifier.Notifier,
 ) *Controller {
 	if scanner == nil {
-		scanner = process.NewScanner()
+		scanner = defaultScanner(cfg)
 	}
 	if n == nil {
-		n = notifier.NewNotifier()
+		n = defaultNotifier(cfg)
 	}
+	// 沿用配额状态的时钟（如 NewQuotaStateWithClock 注入的时钟），控制器与会话跟踪器从创建起就与其一致
+	clk := qState.Clock()
+	tracker := process.NewProcessTracker()
+	tracker.SetClock(clk)
+	tracker.SetStopDebounce(cfg.Tracking.StopDebounceScans)
+	tracker.Restore(qState.GetSessions())
+
 	return &Controller{
 		config:       cfg,
 		quotaState:   qState,
 		scanner:      scanner,
+		tracker:      tracker,
+		metrics:      &metrics{},
 		notifier:     n,
-		lastSaveTime: time.Now(),
+		lastSaveTime: clk.Now(),
+		clock:        clk,
+		startedAt:    clk.Now(),
+
+		foregroundPID:     desktop.ForegroundPID,
+		visibleWindowPIDs: desktop.VisibleWindowPIDs,
+		idleDuration:      desktop.IdleDuration,
+		screenLocked:      desktop.ScreenLocked,
+		cpuTime:           process.CPUTime,
+		seenGames:         make(map[string]bool),
+
+		killRunner:     sysexec.ExecRunner{},
+		session:        session.NewActions(),
+		suspendProcess: process.SuspendProcess,
+		resumeProcess:  process.ResumeProcess,
+		suspended:      make(map[int]bool),
 	}
 }
 
-// Run 运行主控制循环
+// SetClock 替换控制器、配额状态与会话跟踪器共用的时钟（默认使用系统时间），用于测试。
+// 需在开始运行前调用，启动时间与上次保存时间从该时钟的当前时间重新计起
+func (c *Controller) SetClock(clk clock.Clock) {
+	c.clock = clk
+	c.quotaState.SetClock(clk)
+	c.tracker.SetClock(clk)
+	c.startedAt = clk.Now()
+	c.lastSaveTime = clk.Now()
+}
+
+// now 返回控制器时钟的当前时间
+func (c *Controller) now() time.Time {
+	return c.clock.Now()
+}
+
+// Run 运行主控制循环，收到 SIGINT/SIGTERM 时保存状态并退出
 func (c *Controller) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err := c.run(ctx, ExitSignal)
+	_ = logger.Close()
+	return err
+}
+
+// RunContext 运行主控制循环，ctx 结束时保存状态并返回（用于嵌入，不处理信号，也不关闭日志）
+func (c *Controller) RunContext(ctx context.Context) error {
+	return c.run(ctx, ExitContext)
+}
+
+// run 运行主控制循环，ctx 结束时保存状态，并以 stopReason 记录退出原因；控制循环崩溃时记录 panic 后重新抛出
+func (c *Controller) run(ctx context.Context, stopReason string) error {
+	defer guardDaemonExit(c.config)
+	markDaemonStarted(c.config)
+
 	logger.Infof("游戏时间控制守护进程启动")
-	logger.Infof("每日时间限制: %d 分钟", c.config.DailyLimit)
+	logger.Infof("每日时间限制: %d 分钟", c.config.RuleFor(c.now()).DailyLimit)
 	logger.Infof("游戏进程列表: %v", c.config.Games)
 
-	// 设置信号处理
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	if server := c.startHTTP(); server != nil {
+		defer server.Close()
+	}
 
 	// 主控制循环
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(tickInterval)
 	defer ticker.Stop()
+	relaunch, stopRelaunch := relaunchTicks(c.config)
+	defer stopRelaunch()
 
 	for {
 		select {
 		case <-ticker.C:
 			c.tick()
 
-		case sig := <-sigChan:
-			logger.Infof("接收到信号 %v，正在关闭...", sig)
+		case <-relaunch:
+			c.guardRelaunch()
+
+		case <-ctx.Done():
+			logger.Infof("正在关闭...")
 			c.cleanup()
+			recordDaemonExit(c.config, stopReason, "")
 			return nil
 		}
 	}
@@ -83,6 +291,24 @@ func (c *Controller) Run() error {
 
 // tick 每次循环执行的任务
 func (c *Controller) tick() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 每个周期只枚举一次进程，游戏、禁止运行的进程与奖励应用都从同一份快照中匹配
+	snapshot, err := takeSnapshot(c.scanner, scanNames(c.config))
+	c.checkScanDuration()
+	if err != nil {
+		c.process(nil, nil, err)
+		return
+	}
+	gameProcesses, prohibited, earnApps := classify(snapshot, c.config.GameNames(), c.config.ProhibitedNames(), c.config.EarnNames())
+	terminateProhibited(c.config, c.scanner, prohibited)
+	c.process(gameProcesses, earnApps, nil)
+}
+
+// process 处理一次扫描结果：重置配额、计时并执行限制。earnApps 为正在运行的奖励应用，scanErr 非 nil 表示本次扫描失败。
+// 多档案模式下由 MultiController 统一扫描后分别调用
+func (c *Controller) process(gameProcesses, earnApps []process.ProcessInfo, scanErr error) {
 	// 1. 检查是否需要重置
 	shouldReset, err := c.quotaState.ShouldReset()
 	if err != nil {
@@ -91,70 +317,939 @@ func (c *Controller) tick() {
 	}
 
 	if shouldReset {
+		summary := c.quotaState.Summary()
 		if err := c.quotaState.Reset(); err != nil {
 			logger.Errorf("重置配额失败: %v", err)
 		} else {
+			c.reportDailySummary(summary)
 			logger.LogQuotaReset()
+			c.metrics.resetDaily()
+			c.resumeSuspended()
+			c.deniedProcs = nil
+			// 立即保存，避免重置后崩溃导致旧的累计时间被重新加载
+			c.saveNow()
 		}
 	}
 
-	// 2. 扫描游戏进程
-	gameProcesses, err := c.scanner.FindGameProcesses(c.config.Games)
-	if err != nil {
-		logger.Errorf("扫描游戏进程失败: %v", err)
+	// 2. 执行 pause/resume 等控制命令
+	c.applyControlCommands()
+
+	// 3. 处理扫描结果，暂停限制的游戏不计时也不终止
+	if scanErr != nil {
+		c.handleScanFailure(scanErr)
 		return
 	}
+	if c.scanFailures > 0 {
+		logger.Infof("进程扫描已恢复（此前连续失败 %d 次）", c.scanFailures)
+		c.scanFailures = 0
+	}
+	c.recordSeenGames(gameProcesses)
+	gameProcesses = c.withoutPausedGames(gameProcesses)
+	c.lastGameProcesses = gameProcesses
+	c.trackSessions(gameProcesses)
 
-	// 3. 简化：只要检测到有游戏进程就累加扫描间隔时间
-	if len(gameProcesses) > 0 {
+	// 4. 简化：只要检测到有游戏进程就累加扫描间隔时间。
+	// 按墙钟计时：同时运行多个匹配进程（如主程序 + 反作弊）也只累加一次；强制休息期间不计时
+	onBreak := c.breakActive()
+	accrued := !onBreak && c.shouldAccrue(gameProcesses)
+	if accrued {
 		// 扫描间隔是5秒
-		c.quotaState.AddTime(5)
+		c.accrue(gameProcesses, 5)
 		logger.Debugf("检测到 %d 个游戏进程，累加5秒时间", len(gameProcesses))
 	}
+	c.updatePlayStreak(accrued, 5)
+	c.earnTime(earnApps, gameProcesses, 5)
 
-	// 4. 检查时间限制
+	// 5. 检查时间限制与允许时段
 	if c.quotaState.IsLimitExceeded() {
-		logger.LogLimitExceeded()
-		if c.quotaState.ConsumeLimitNotification() {
-			if err := c.notifier.NotifyLimitExceeded(); err != nil {
-				logger.Errorf("超限弹窗失败: %v", err)
+		c.enforceLimit(gameProcesses)
+	} else {
+		c.graceDeadline = time.Time{}
+		c.limitEnforced = false
+		c.blockedPIDs = nil
+
+		allowed := c.config.AllowedAt(c.now())
+		if allowed {
+			c.scheduleNotified = false
+		}
+		if !allowed {
+			c.enforceSchedule(gameProcesses)
+		} else if onBreak || c.startBreak() {
+			c.enforceBreak(gameProcesses)
+		} else {
+			c.checkSoftLimit(gameProcesses)
+			c.checkWarnings()
+		}
+	}
+
+	// 6. 定期保存状态
+	if c.now().Sub(c.lastSaveTime) >= c.config.Controller.SaveInterval() {
+		c.saveNow()
+	}
+
+	// 7. 检查配置与状态文件是否被外部修改
+	c.checkIntegrity()
+
+	c.metrics.update(
+		c.quotaState.GetAccumulatedMinutes(),
+		c.quotaState.GetRemainingMinutes(),
+		int(c.config.RuleFor(c.now()).DailyLimit),
+		len(gameProcesses),
+	)
+
+	// 8. 导出剩余时间供叠加层读取
+	c.exportRemaining()
+}
+
+// withoutPausedGames 去掉暂停限制的游戏进程
+func (c *Controller) withoutPausedGames(gameProcesses []process.ProcessInfo) []process.ProcessInfo {
+	if len(c.quotaState.GetPausedGames()) == 0 {
+		return gameProcesses
+	}
+	enforced := make([]process.ProcessInfo, 0, len(gameProcesses))
+	for _, proc := range gameProcesses {
+		if !c.quotaState.IsGamePaused(proc.Name) && (proc.Match == "" || !c.quotaState.IsGamePaused(proc.Match)) {
+			enforced = append(enforced, proc)
+		}
+	}
+	return enforced
+}
+
+// handleScanFailure 处理扫描失败：连续失败达到 scannerDegradedAfter 次时记录 scanner_degraded，
+// 期间不累计时间，但已超限时仍对上次扫描到的游戏进程执行终止，避免扫描故障让游戏逃过限制
+func (c *Controller) handleScanFailure(err error) {
+	c.scanFailures++
+	logger.Errorf("扫描游戏进程失败: %v", err)
+	if c.scanFailures == scannerDegradedAfter {
+		logger.LogScannerDegraded(c.scanFailures, err)
+	}
+
+	if len(c.lastGameProcesses) > 0 && c.quotaState.IsLimitExceeded() {
+		c.enforceLimit(c.lastGameProcesses)
+	}
+}
+
+// accrue 累加游戏时间。配置了最短会话时长时，在有会话达到该时长之前时间先暂存，
+// 达到后一并计入；所有会话都在达到前结束则丢弃暂存的时间
+func (c *Controller) accrue(gameProcesses []process.ProcessInfo, seconds int64) {
+	games := c.runningGames(gameProcesses)
+
+	if !c.sessionQualified() {
+		c.pendingSeconds += seconds
+		if c.pendingGames == nil {
+			c.pendingGames = make(map[string]int64)
+		}
+		for _, game := range games {
+			c.pendingGames[game] += seconds
+		}
+		return
+	}
+
+	c.quotaState.AddTime(c.pendingSeconds + seconds)
+	for game, pending := range c.pendingGames {
+		c.quotaState.AddGameTime(game, pending)
+	}
+	for _, game := range games {
+		c.quotaState.AddGameTime(game, seconds)
+	}
+	c.pendingSeconds = 0
+	c.pendingGames = nil
+}
+
+// sessionQualified 判断是否有活跃会话达到最短会话时长，或连续游戏（中途重启游戏也算连续）已达到该时长
+func (c *Controller) sessionQualified() bool {
+	minSession := time.Duration(c.config.Tracking.MinSessionSeconds) * time.Second
+	if minSession <= 0 {
+		return true
+	}
+	for _, session := range c.tracker.ActiveSessions() {
+		if session.Duration() >= minSession {
+			return true
+		}
+	}
+	// 反复重启游戏时每个会话都达不到最短时长，按连续游戏的总时长判断，避免暂存时间一直不计入
+	return !c.playingSince.IsZero() && c.now().Sub(c.playingSince) >= minSession
+}
+
+// runningGames 返回正在运行的游戏（按配置中的名称去重），用于按游戏统计时间。
+// 同时运行的不同游戏各自计时，因此各游戏时间之和可能大于总累计时间。
+func (c *Controller) runningGames(gameProcesses []process.ProcessInfo) []string {
+	var games []string
+	seen := make(map[string]bool)
+	for _, proc := range gameProcesses {
+		game := proc.Name
+		for _, configured := range c.config.GameNames() {
+			if proc.Matches(configured) {
+				game = configured
+				break
 			}
 		}
+		if !seen[game] {
+			seen[game] = true
+			games = append(games, game)
+		}
+	}
+	return games
+}
+
+// saveNow 保存状态并记录保存时间，失败时只记录日志
+func (c *Controller) saveNow() {
+	if err := c.saveState(); err != nil {
+		logger.Errorf("保存状态失败: %v", err)
+		return
+	}
+	c.lastSaveTime = c.now()
+	c.stateSaved = true
+}
+
+// trackSessions 更新游戏会话并记录启动/停止事件
+func (c *Controller) trackSessions(gameProcesses []process.ProcessInfo) {
+	started, stopped := c.tracker.Update(gameProcesses)
+	for _, session := range started {
+		logger.LogGameStart(session.Name)
+	}
+	minSession := time.Duration(c.config.Tracking.MinSessionSeconds) * time.Second
+	for _, session := range stopped {
+		if session.Duration() < minSession {
+			logger.LogShortSessionIgnored(session.Name, session.Duration().Milliseconds())
+		} else {
+			logger.LogGameStop(session.Name, session.Duration().Milliseconds())
+		}
+	}
+	if len(c.tracker.ActiveSessions()) == 0 {
+		// 所有会话都在达到最短时长前结束，暂存的时间不计入
+		c.pendingSeconds = 0
+		c.pendingGames = nil
+		c.playingSince = time.Time{}
+	} else if c.playingSince.IsZero() {
+		c.playingSince = c.now()
+	}
+	if len(stopped) > 0 {
+		// 游戏关闭时立即保存已计入的时间，之后即使守护进程被结束也不会丢失
+		c.saveNow()
+	}
+
+	c.heartbeat()
+}
+
+// heartbeat 按配置间隔为每个活跃会话记录 game_running 事件，
+// 守护进程在会话结束前意外退出时，仍可据此还原游戏时长
+func (c *Controller) heartbeat() {
+	now := c.now()
+	if c.lastHeartbeat.IsZero() {
+		c.lastHeartbeat = now
+		return
+	}
+	if now.Sub(c.lastHeartbeat) < c.config.Logging.HeartbeatInterval() {
+		return
+	}
+	c.lastHeartbeat = now
+
+	for _, session := range c.tracker.ActiveSessions() {
+		logger.LogGameRunning(session.Name, session.Duration().Milliseconds())
+	}
+}
+
+// saveState 将配额状态连同活跃会话写入状态文件
+func (c *Controller) saveState() error {
+	c.quotaState.SetSessions(c.tracker.ActiveSessions())
+	return c.quotaState.SaveToFile()
+}
+
+// notify 发出桌面弹窗；处于 notifications.quietHours 静默时段内时只记录日志，不打扰用户
+func (c *Controller) notify(what string, send func() error) {
+	if c.config.QuietAt(c.now()) {
+		logger.Infof("静默时段内，不弹出%s提醒", what)
+		return
+	}
+	if err := send(); err != nil {
+		logger.Errorf("%s弹窗失败: %v", what, err)
+	}
+}
+
+// reportDailySummary 记录刚结束的配额周期的 daily_summary 事件，启用 notifications.dailySummary 时同时弹出汇总
+func (c *Controller) reportDailySummary(summary quota.DailySummary) {
+	logger.LogDailySummary(summary.PlayedSeconds, summary.LimitReached, summary.Terminations, summary.GameSeconds)
+	if !c.config.Notifications.DailySummary {
+		return
+	}
+	gameMinutes := make(map[string]int, len(summary.GameSeconds))
+	for game, seconds := range summary.GameSeconds {
+		gameMinutes[game] = int(seconds / 60)
+	}
+	c.notify("每日汇总", func() error {
+		return c.notifier.NotifyDailySummary(int(summary.PlayedSeconds/60), summary.LimitReached, summary.Terminations, gameMinutes)
+	})
+}
+
+// checkWarnings 检查提醒阈值并发出提醒，最小的阈值使用最后提醒
+func (c *Controller) checkWarnings() {
+	threshold, crossed := c.quotaState.ConsumeWarningNotifications()
+	if !crossed {
+		return
+	}
+
+	remaining := c.quotaState.GetRemainingMinutes()
+	if levels := c.config.WarningLevels(); threshold == int(levels[len(levels)-1]) {
+		logger.Warnf("最后警告：剩余游戏时间仅剩 %d 分钟！", remaining)
+		c.notify("最后警告", func() error { return c.notifier.NotifyFinalWarning(remaining) })
+		return
+	}
+	logger.Warnf("警告：剩余游戏时间不足 %d 分钟（剩余 %d 分钟）", threshold, remaining)
+	c.notify("首次警告", func() error { return c.notifier.NotifyFirstWarning(remaining) })
+}
+
+// checkSoftLimit 超过软限制后在游戏运行期间反复提醒，间隔按 softLimitReminders 逐次缩短；不终止游戏
+func (c *Controller) checkSoftLimit(gameProcesses []process.ProcessInfo) {
+	if !c.quotaState.IsSoftLimitExceeded() {
+		c.softNotices = 0
+		c.lastSoftNotice = time.Time{}
+		return
+	}
+	if len(gameProcesses) == 0 {
+		return
+	}
+
+	now := c.now()
+	if c.softNotices > 0 {
+		interval := softLimitReminders[min(c.softNotices, len(softLimitReminders))-1]
+		if now.Sub(c.lastSoftNotice) < interval {
+			return
+		}
+	}
+	c.softNotices++
+	c.lastSoftNotice = now
+
+	over := c.quotaState.GetAccumulatedMinutes() - int(c.config.SoftLimitFor(now))
+	remaining := c.quotaState.GetRemainingMinutes()
+	logger.LogSoftLimitExceeded(over, remaining)
+	c.notify("软限制", func() error { return c.notifier.NotifySoftLimit(over, remaining) })
+}
+
+// shouldAccrue 判断本次扫描是否应累计游戏时间
+func (c *Controller) shouldAccrue(gameProcesses []process.ProcessInfo) bool {
+	if len(gameProcesses) == 0 {
+		return false
+	}
+	if c.userIdle() || c.screenIsLocked() {
+		return false
+	}
+	if c.config.CountForegroundOnly && !c.anyForeground(gameProcesses) {
+		return false
+	}
+	if c.config.CountVisibleOnly && !c.anyVisible(gameProcesses) {
+		return false
+	}
+	if c.config.Tracking.MinCPUPercent > 0 && !c.anyBusy(gameProcesses) {
+		logger.Debugf("游戏进程 CPU 占用率均低于 %g%%，本次不计时", c.config.Tracking.MinCPUPercent)
+		return false
+	}
+	return true
+}
+
+// cpuSample 进程在某次扫描时累计占用的 CPU 时间
+type cpuSample struct {
+	cpu time.Duration
+	at  time.Time
+}
+
+// anyBusy 判断是否有游戏进程自上次采样以来的 CPU 占用率达到 tracking.minCpuPercent，并记录本次采样。
+// 首次采样（刚启动的进程）或无法读取 CPU 时间时视为繁忙
+func (c *Controller) anyBusy(gameProcesses []process.ProcessInfo) bool {
+	now := c.now()
+	samples := make(map[process.ProcessKey]cpuSample, len(gameProcesses))
+	busy := false
+	for _, proc := range gameProcesses {
+		cpu, err := c.cpuTime(proc.PID)
+		if err != nil {
+			logger.Debugf("无法读取进程 CPU 时间，按繁忙计时 (PID: %d): %v", proc.PID, err)
+			busy = true
+			continue
+		}
+		samples[proc.Key()] = cpuSample{cpu: cpu, at: now}
+
+		prev, ok := c.cpuSamples[proc.Key()]
+		elapsed := now.Sub(prev.at)
+		if !ok || elapsed <= 0 || cpu < prev.cpu {
+			busy = true
+			continue
+		}
+		if float64(cpu-prev.cpu)/float64(elapsed)*100 >= c.config.Tracking.MinCPUPercent {
+			busy = true
+		}
+	}
+	c.cpuSamples = samples
+	return busy
+}
+
+// anyForeground 判断是否有游戏进程拥有前台窗口，无法判断时视为有
+func (c *Controller) anyForeground(gameProcesses []process.ProcessInfo) bool {
+	fgPID, err := c.foregroundPID()
+	if err != nil {
+		logger.Debugf("无法获取前台窗口，按全部游戏进程计时: %v", err)
+		return true
+	}
+	for _, proc := range gameProcesses {
+		if proc.PID == fgPID {
+			return true
+		}
+	}
+	return false
+}
+
+// anyVisible 判断是否有游戏进程拥有可见且未最小化的窗口，无法判断时视为有
+func (c *Controller) anyVisible(gameProcesses []process.ProcessInfo) bool {
+	visible, err := c.visibleWindowPIDs()
+	if err != nil {
+		logger.Debugf("无法枚举窗口，按全部游戏进程计时: %v", err)
+		return true
+	}
+	for _, proc := range gameProcesses {
+		if visible[proc.PID] {
+			return true
+		}
+	}
+	return false
+}
+
+// userIdle 判断用户是否空闲超过阈值，并在状态切换时记录事件
+func (c *Controller) userIdle() bool {
+	threshold := time.Duration(c.config.Idle.PauseAfterSeconds) * time.Second
+	if threshold <= 0 {
+		return false
+	}
+
+	idle, err := c.idleDuration()
+	if err != nil {
+		logger.Debugf("无法获取用户空闲时长: %v", err)
+		idle = 0
+	}
+
+	if idle >= threshold {
+		if !c.idlePaused {
+			c.idlePaused = true
+			logger.LogIdlePaused(idle)
+		}
+		return true
+	}
+
+	if c.idlePaused {
+		c.idlePaused = false
+		logger.LogIdleResumed()
+	}
+	return false
+}
+
+// screenIsLocked 判断工作站是否已锁定（锁屏时没有人在玩，留在后台的游戏不计时），并在状态切换时记录事件；
+// 无法判断时视为未锁定
+func (c *Controller) screenIsLocked() bool {
+	locked, err := c.screenLocked()
+	if err != nil {
+		logger.Debugf("无法检测锁屏状态: %v", err)
+		locked = false
+	}
+
+	if locked != c.lockPaused {
+		c.lockPaused = locked
+		if locked {
+			logger.LogScreenLocked()
+		} else {
+			logger.LogScreenUnlocked()
+		}
+	}
+	return locked
+}
+
+// recordSeenGames 记录本次扫描出现的游戏，并每隔 neverSeenCheckAfter 报告仍从未出现过的配置项
+func (c *Controller) recordSeenGames(gameProcesses []process.ProcessInfo) {
+	for _, proc := range gameProcesses {
+		c.seenGames[strings.ToLower(proc.Name)] = true
+		if proc.Match != "" {
+			c.seenGames[strings.ToLower(proc.Match)] = true
+		}
+	}
+
+	now := c.now()
+	since := c.startedAt
+	if !c.neverSeenCheckedAt.IsZero() {
+		since = c.neverSeenCheckedAt
+	}
+	if now.Sub(since) < neverSeenCheckAfter {
+		return
+	}
+	c.neverSeenCheckedAt = now
+
+	if names := c.neverSeenGames(); len(names) > 0 {
+		logger.LogGamesNeverSeen(names)
+	}
+}
+
+// neverSeenGames 返回配置中从未在扫描结果中出现过的游戏名
+func (c *Controller) neverSeenGames() []string {
+	var names []string
+	for _, game := range c.config.GameNames() {
+		if !c.seenGames[strings.ToLower(game)] {
+			names = append(names, game)
+		}
+	}
+	return names
+}
+
+// resumeTime 返回恢复游戏的时间（下次重置），按配置的时区显示
+func (c *Controller) resumeTime() time.Time {
+	return c.inLocation(c.quotaState.NextReset())
+}
+
+// inLocation 将 t 转换到配置的时区用于显示，时区无效时原样返回
+func (c *Controller) inLocation(t time.Time) time.Time {
+	if loc, err := c.config.Location(); err == nil {
+		return t.In(loc)
+	}
+	return t
+}
+
+// enforceLimit 处理超限：宽限期内仅提醒，宽限期结束后通知并按 enforcement.onLimit 执行超限动作
+func (c *Controller) enforceLimit(gameProcesses []process.ProcessInfo) {
+	if c.blockingRelaunch() {
+		// 重新启动的游戏不再给宽限期，也不计为新的一次超限
+		c.reportRelaunches(gameProcesses)
+	} else if c.inGracePeriod(gameProcesses) {
+		return
+	}
+
+	logger.LogLimitExceeded()
+	if c.quotaState.ConsumeLimitNotification() {
+		c.notify("超限", func() error { return c.notifier.NotifyLimitExceeded(c.resumeTime()) })
+	}
+	c.enforce(reasonQuota, gameProcesses)
+
+	if c.config.Enforcement.LimitAction() == config.ActionTerminate && len(gameProcesses) > 0 {
+		c.limitEnforced = true
+	}
+	c.blockedPIDs = make(map[int]bool, len(gameProcesses))
+	for _, proc := range gameProcesses {
+		c.blockedPIDs[proc.PID] = true
+	}
+}
+
+// relaunchTicks 启用 enforcement.blockRelaunch 时返回按 relaunchScanInterval 触发的通道，
+// 否则返回 nil 通道（select 中永不触发）；stop 用于停止 ticker
+func relaunchTicks(cfg *config.Config) (<-chan time.Time, func()) {
+	if !cfg.Enforcement.BlockRelaunch {
+		return nil, func() {}
+	}
+	ticker := time.NewTicker(relaunchScanInterval)
+	return ticker.C, ticker.Stop
+}
+
+// blockingRelaunch 判断是否应立即终止重新启动的游戏：启用 enforcement.blockRelaunch、
+// 当天已超限并执行过终止，且不是监控模式
+func (c *Controller) blockingRelaunch() bool {
+	return c.config.Enforcement.BlockRelaunch && c.limitEnforced &&
+		c.config.Enforcement.LimitAction() == config.ActionTerminate &&
+		!c.config.Enforcement.MonitorOnly() && c.quotaState.IsLimitExceeded()
+}
+
+// reportRelaunches 为此前未处理过的游戏进程记录 relaunch_blocked 并提醒（每批重新启动只弹窗一次），返回这些进程
+func (c *Controller) reportRelaunches(gameProcesses []process.ProcessInfo) []process.ProcessInfo {
+	var relaunched []process.ProcessInfo
+	for _, proc := range gameProcesses {
+		if !c.blockedPIDs[proc.PID] && !c.isExemptPID(proc.PID) {
+			relaunched = append(relaunched, proc)
+			logger.LogRelaunchBlocked(proc.Name, proc.PID)
+		}
+	}
+	if len(relaunched) > 0 {
+		c.notify("重新启动", func() error { return c.notifier.NotifyLimitExceeded(c.resumeTime()) })
+	}
+	return relaunched
+}
+
+// guardRelaunch 在两次常规检查之间扫描游戏进程，立即终止超限后重新启动的游戏，
+// 避免重新启动的游戏最多运行一个检查周期；仍在运行的已处理进程留给常规检查重试
+func (c *Controller) guardRelaunch() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.blockingRelaunch() {
+		return
+	}
+	gameProcesses, err := c.scanner.FindGameProcesses(c.config.GameNames())
+	if err != nil {
+		logger.Debugf("检查重新启动的游戏进程失败: %v", err)
+		return
+	}
+	c.blockRelaunches(c.withoutPausedGames(gameProcesses))
+}
+
+// blockRelaunches 终止 gameProcesses 中重新启动的游戏进程（多档案模式下由 MultiController 按档案分配后调用）
+func (c *Controller) blockRelaunches(gameProcesses []process.ProcessInfo) {
+	if !c.blockingRelaunch() {
+		return
+	}
+	relaunched := c.reportRelaunches(gameProcesses)
+	if len(relaunched) == 0 {
+		return
+	}
+	for _, proc := range relaunched {
+		c.blockedPIDs[proc.PID] = true
+	}
+	c.reportTermination(reasonQuota, c.terminateGames(reasonQuota, relaunched))
+}
+
+// enforce 按 enforcement.onLimit 处理游戏进程：终止（默认）、挂起、锁定或注销；时段外总是终止。
+// reason 为处理原因，记录在事件的 terminate_reason 中
+func (c *Controller) enforce(reason string, gameProcesses []process.ProcessInfo) {
+	action := c.config.Enforcement.LimitAction()
+	if reason == reasonCurfew {
+		action = config.ActionTerminate
+	}
+	switch action {
+	case config.ActionSuspend:
+		c.suspendGames(reason, gameProcesses)
+	case config.ActionLock, config.ActionLogoff:
+		c.applySessionAction(reason, action, gameProcesses)
+	default:
+		c.terminate(reason, gameProcesses)
+	}
+}
+
+// terminate 终止游戏进程以及终止期间新启动的游戏进程，按 reason 记录 termination_result
+func (c *Controller) terminate(reason string, gameProcesses []process.ProcessInfo) {
+	result := c.terminateGames(reason, gameProcesses)
+	result.add(c.terminateLateStarters(reason, gameProcesses))
+	c.reportTermination(reason, result)
+}
+
+// suspendGames 挂起尚未挂起的游戏进程（豁免 PID 除外，监控模式下只记录），挂起的进程在配额重置或强制休息结束时恢复。
+// 已退出的进程不再跟踪
+func (c *Controller) suspendGames(reason string, gameProcesses []process.ProcessInfo) {
+	running := make(map[int]bool, len(gameProcesses))
+	var suspended []int
+	var failed int
+	var lastErr error
+	for _, proc := range gameProcesses {
+		running[proc.PID] = true
+		if c.suspended[proc.PID] || c.isExemptPID(proc.PID) || refuseCritical(proc) {
+			continue
+		}
+		if c.config.Enforcement.MonitorOnly() {
+			logger.Warnf("监控模式：本应挂起游戏进程 %s (PID: %d)", proc.Name, proc.PID)
+			continue
+		}
+		if err := c.suspendProcess(proc.PID); err != nil {
+			logger.Errorf("挂起进程失败 (PID: %d): %v", proc.PID, err)
+			failed++
+			lastErr = err
+			continue
+		}
+		c.suspended[proc.PID] = true
+		suspended = append(suspended, proc.PID)
+	}
+	for pid := range c.suspended {
+		if !running[pid] {
+			delete(c.suspended, pid)
+		}
+	}
+
+	if failed > 0 {
+		logger.LogLimitAction(reason, config.ActionSuspend, suspended, fmt.Errorf("%d 个进程挂起失败: %w", failed, lastErr))
+	} else if len(suspended) > 0 {
+		logger.LogLimitAction(reason, config.ActionSuspend, suspended, nil)
+	}
+}
+
+// breakActive 判断是否处于强制休息中；休息结束时清除休息状态，未超限时恢复休息期间挂起的进程
+func (c *Controller) breakActive() bool {
+	if c.breakUntil.IsZero() {
+		return false
+	}
+	if c.now().Before(c.breakUntil) {
+		return true
+	}
+	c.breakUntil = time.Time{}
+	c.playStreak = 0
+	logger.LogBreakEnded()
+	if !c.quotaState.IsLimitExceeded() {
+		c.resumeSuspended()
+	}
+	return false
+}
+
+// updatePlayStreak 累计本段连续游戏时间；停止计时（游戏关闭、空闲等）达到 breaks.breakMinutes 视为已自然休息，重新计算
+func (c *Controller) updatePlayStreak(accrued bool, seconds int64) {
+	if !c.config.Breaks.Enabled() {
+		return
+	}
+	now := c.now()
+	if accrued {
+		c.playStreak += seconds
+		c.lastPlayedAt = now
+	} else if c.playStreak > 0 && now.Sub(c.lastPlayedAt) >= c.config.Breaks.BreakDuration() {
+		c.playStreak = 0
+	}
+}
 
-		// 终止所有游戏进程
-		for _, proc := range gameProcesses {
-			if err := c.scanner.TerminateWithRetry(proc.PID, 3, 1*time.Second); err != nil {
+// startBreak 连续游戏达到 breaks.maxSessionMinutes 时开始强制休息并通知，返回是否开始了休息
+func (c *Controller) startBreak() bool {
+	if !c.config.Breaks.Enabled() || time.Duration(c.playStreak)*time.Second < c.config.Breaks.MaxSession() {
+		return false
+	}
+	played := int(c.playStreak / 60)
+	c.breakUntil = c.now().Add(c.config.Breaks.BreakDuration())
+	until := c.inLocation(c.breakUntil)
+	logger.LogBreakStarted(played, until)
+	c.notify("强制休息", func() error { return c.notifier.NotifyBreak(played, until) })
+	return true
+}
+
+// enforceBreak 强制休息期间按 enforcement.onLimit 处理游戏进程，休息结束前重新启动的游戏同样处理
+func (c *Controller) enforceBreak(gameProcesses []process.ProcessInfo) {
+	if len(gameProcesses) == 0 {
+		return
+	}
+	logger.Warnf("强制休息中（至 %s），处理游戏进程", c.inLocation(c.breakUntil).Format("15:04"))
+	c.enforce(reasonBreak, gameProcesses)
+}
+
+// resumeSuspended 恢复因超限或强制休息被挂起的进程
+func (c *Controller) resumeSuspended() {
+	for pid := range c.suspended {
+		if err := c.resumeProcess(pid); err != nil {
+			logger.Warnf("恢复被挂起的进程失败 (PID: %d): %v", pid, err)
+		} else {
+			logger.Infof("恢复被挂起的游戏进程 (PID: %d)", pid)
+		}
+		delete(c.suspended, pid)
+	}
+}
+
+// applySessionAction 有游戏进程运行时锁定工作站或注销当前用户（监控模式下只记录）。
+// 锁定不会关闭游戏，解锁后游戏仍在运行时下个周期会再次锁定
+func (c *Controller) applySessionAction(reason, action string, gameProcesses []process.ProcessInfo) {
+	if len(gameProcesses) == 0 {
+		return
+	}
+	if c.config.Enforcement.MonitorOnly() {
+		logger.Warnf("监控模式：本应执行超限动作 %s", action)
+		return
+	}
+
+	apply := c.session.Lock
+	if action == config.ActionLogoff {
+		apply = c.session.Logoff
+	}
+	err := apply()
+	logger.LogLimitAction(reason, action, nil, err)
+}
+
+// terminateLateStarters 终止后重新扫描一次，终止本轮扫描之后才启动的游戏进程。
+// 逐个终止进程需要数秒，其间新启动的游戏不在本轮扫描结果中，否则要到下一轮才会被终止。
+// 本轮未终止任何进程或仅监控时不重新扫描
+func (c *Controller) terminateLateStarters(reason string, handled []process.ProcessInfo) terminationResult {
+	if len(handled) == 0 || c.config.Enforcement.MonitorOnly() {
+		return terminationResult{}
+	}
+
+	current, err := c.scanner.FindGameProcesses(c.config.GameNames())
+	if err != nil {
+		logger.Debugf("终止后重新扫描游戏进程失败: %v", err)
+		return terminationResult{}
+	}
+
+	// 按 PID 去重：终止只需数秒，期间 PID 被复用的可能性可以忽略
+	seen := make(map[int]bool, len(handled))
+	for _, proc := range handled {
+		seen[proc.PID] = true
+	}
+	var late []process.ProcessInfo
+	for _, proc := range c.withoutPausedGames(current) {
+		if !seen[proc.PID] {
+			late = append(late, proc)
+		}
+	}
+	if len(late) == 0 {
+		return terminationResult{}
+	}
+	logger.Infof("终止期间检测到 %d 个新启动的游戏进程，一并终止", len(late))
+	return c.terminateGames(reason, late)
+}
+
+// enforceSchedule 不在当天允许的游戏时段内时终止游戏进程，每段时段外时间只通知一次下次开放的时间
+func (c *Controller) enforceSchedule(gameProcesses []process.ProcessInfo) {
+	if len(gameProcesses) == 0 {
+		return
+	}
+
+	logger.Warnf("当前不在允许的游戏时段内，终止游戏进程")
+	if !c.scheduleNotified {
+		c.scheduleNotified = true
+		nextAllowed := c.config.NextAllowed(c.now())
+		c.notify("时段外", func() error { return c.notifier.NotifyOutsideSchedule(nextAllowed) })
+	}
+	c.enforce(reasonCurfew, gameProcesses)
+}
+
+// terminationResult 一轮执行限制中终止游戏进程的结果
+type terminationResult struct {
+	succeeded []int
+	failed    []int
+	// denied failed 中因权限不足而失败的进程
+	denied []process.ProcessInfo
+}
+
+func (r *terminationResult) add(other terminationResult) {
+	r.succeeded = append(r.succeeded, other.succeeded...)
+	r.failed = append(r.failed, other.failed...)
+	r.denied = append(r.denied, other.denied...)
+}
+
+// reportTermination 按终止原因 reason 记录 termination_result 事件；有进程终止失败时通知一次，
+// 直到某轮全部终止成功后才会再次通知，避免每个扫描周期重复弹窗
+func (c *Controller) reportTermination(reason string, result terminationResult) {
+	if len(result.succeeded) == 0 && len(result.failed) == 0 {
+		return
+	}
+	logger.LogTerminationResult(reason, result.succeeded, result.failed)
+
+	for _, proc := range result.denied {
+		c.notifyAccessDenied(proc)
+	}
+	if len(result.failed) == 0 {
+		c.terminationFailedNotified = false
+		return
+	}
+	// 权限不足的失败已有专门的通知
+	if len(result.failed) > len(result.denied) && !c.terminationFailedNotified {
+		c.terminationFailedNotified = true
+		c.notify("终止失败", c.notifier.NotifyTerminationFailed)
+	}
+}
+
+// notifyAccessDenied 提示以管理员身份运行才能关闭 proc 所属的游戏，每个游戏只通知一次
+func (c *Controller) notifyAccessDenied(proc process.ProcessInfo) {
+	key := strings.ToLower(proc.Name)
+	if c.accessDeniedNotified[key] {
+		return
+	}
+	if c.accessDeniedNotified == nil {
+		c.accessDeniedNotified = make(map[string]bool)
+	}
+	c.accessDeniedNotified[key] = true
+	c.notify("权限不足", func() error { return c.notifier.NotifyAccessDenied(proc.Name) })
+}
+
+// terminateGames 终止所有游戏进程（豁免 PID 与关键进程除外），返回实际尝试终止的结果。
+// reason 为终止原因，记录在 terminate_access_denied 事件中
+func (c *Controller) terminateGames(reason string, gameProcesses []process.ProcessInfo) terminationResult {
+	var result terminationResult
+	for _, proc := range gameProcesses {
+		if c.isExemptPID(proc.PID) {
+			logger.Infof("跳过豁免进程 (PID: %d)", proc.PID)
+			continue
+		}
+		if refuseCritical(proc) {
+			continue
+		}
+		if c.config.Enforcement.MonitorOnly() {
+			logger.LogWouldTerminate(proc.Name, proc.PID)
+			continue
+		}
+		if c.deniedProcs[proc.Key()] {
+			logger.Debugf("%s (PID: %d) 此前已拒绝访问，不再尝试终止", proc.Name, proc.PID)
+			result.failed = append(result.failed, proc.PID)
+			result.denied = append(result.denied, proc)
+			continue
+		}
+		if err := c.terminateGame(proc); err != nil {
+			if errors.Is(err, process.ErrAccessDenied) {
+				logger.LogTerminateAccessDenied(reason, proc.Name, proc.PID)
+				if c.deniedProcs == nil {
+					c.deniedProcs = make(map[process.ProcessKey]bool)
+				}
+				c.deniedProcs[proc.Key()] = true
+				result.denied = append(result.denied, proc)
+			} else {
 				logger.Errorf("终止进程失败 (PID: %d): %v", proc.PID, err)
 			}
+			result.failed = append(result.failed, proc.PID)
+			continue
 		}
-	} else {
-		// 检查警告阈值
-		first, final := c.quotaState.ConsumeWarningNotifications()
-
-		if final {
-			remaining := c.quotaState.GetRemainingMinutes()
-			logger.Warnf("最后警告：剩余游戏时间仅剩 %d 分钟！", remaining)
-			if err := c.notifier.NotifyFinalWarning(remaining); err != nil {
-				logger.Errorf("最后警告弹窗失败: %v", err)
-			}
-		} else if first {
-			remaining := c.quotaState.GetRemainingMinutes()
-			logger.Warnf("警告：剩余游戏时间不足 %d 分钟（剩余 %d 分钟）",
-				c.config.FirstThreshold, remaining)
-			if err := c.notifier.NotifyFirstWarning(remaining); err != nil {
-				logger.Errorf("首次警告弹窗失败: %v", err)
+		c.metrics.recordTermination()
+		c.quotaState.RecordTermination()
+		result.succeeded = append(result.succeeded, proc.PID)
+	}
+	return result
+}
+
+// terminateGame 终止一个游戏进程：配置了 enforcement.killCommands 时先运行该游戏的结束命令，
+// 未配置或命令失败时使用 taskkill
+func (c *Controller) terminateGame(proc process.ProcessInfo) error {
+	if command := c.config.KillCommand(proc); command != "" {
+		name, args, err := process.RenderKillCommand(command, proc)
+		if err == nil {
+			var output []byte
+			if output, err = c.killRunner.Run(name, args...); err == nil {
+				logger.Infof("已通过结束命令终止 %s (PID: %d)", proc.Name, proc.PID)
+				return nil
 			}
+			err = fmt.Errorf("%w, 输出: %s", err, strings.TrimSpace(string(output)))
 		}
+		logger.Warnf("结束命令执行失败，改用 taskkill 终止 %s (PID: %d): %v", proc.Name, proc.PID, err)
 	}
+	return c.scanner.TerminateWithRetry(proc.PID, 3, 1*time.Second)
+}
 
-	// 5. 定期保存状态
-	if time.Since(c.lastSaveTime) >= 1*time.Minute {
-		if err := c.quotaState.SaveToFile(); err != nil {
-			logger.Errorf("保存状态失败: %v", err)
-		} else {
-			c.lastSaveTime = time.Now()
+// isExemptPID 判断 PID 是否在豁免列表中
+func (c *Controller) isExemptPID(pid int) bool {
+	for _, exempt := range c.config.Enforcement.ExemptPids {
+		if exempt == pid {
+			return true
 		}
 	}
+	return false
+}
+
+// inGracePeriod 判断当前是否处于超限宽限期内。
+// 超限状态下检测到游戏进程即视为一次超限，宽限时间按当天的超限次数逐级确定
+// （见 enforcement.escalation）；游戏进程全部退出后，再次启动将计为新的一次超限。
+func (c *Controller) inGracePeriod(gameProcesses []process.ProcessInfo) bool {
+	now := c.now()
+	if len(gameProcesses) == 0 {
+		if !c.graceDeadline.IsZero() && !now.Before(c.graceDeadline) {
+			c.graceDeadline = time.Time{}
+		}
+		return false
+	}
+
+	if c.graceDeadline.IsZero() {
+		hit := c.quotaState.RecordLimitHit()
+		grace := c.config.Enforcement.GraceForHit(hit)
+		c.graceDeadline = now.Add(grace)
+		if grace <= 0 {
+			return false
+		}
+
+		logger.Warnf("已达到每日游戏时间限制（今日第 %d 次），%d 秒后%s", hit, int(grace.Seconds()), limitActionText(c.config.Enforcement.LimitAction()))
+		c.notify("最后警告", func() error { return c.notifier.NotifyFinalWarning(0) })
+		return true
+	}
+
+	return now.Before(c.graceDeadline)
+}
+
+// limitActionText 返回超限动作的中文描述，用于日志
+func limitActionText(action string) string {
+	switch action {
+	case config.ActionSuspend:
+		return "挂起游戏进程"
+	case config.ActionLock:
+		return "锁定工作站"
+	case config.ActionLogoff:
+		return "注销当前用户"
+	default:
+		return "终止游戏进程"
+	}
 }
 
 // cleanup 清理资源
@@ -162,21 +1257,22 @@ func (c *Controller) cleanup() {
 	logger.Infof("正在保存状态...")
 
 	// 保存状态
-	if err := c.quotaState.SaveToFile(); err != nil {
+	if err := c.saveState(); err != nil {
 		logger.Errorf("保存状态失败: %v", err)
 	}
 
 	logger.Infof("游戏时间控制守护进程已关闭")
-	_ = logger.Close()
 }
 
-// GetStatus 获取当前状态
+// GetStatus 获取当前状态（实时扫描一次游戏进程），可在控制循环运行期间从其他 goroutine 调用
 func (c *Controller) GetStatus() StatusInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	// 扫描当前游戏进程
-	gameProcesses, err := c.scanner.FindGameProcesses(c.config.Games)
-	activeProcessCount := 0
-	if err == nil {
-		activeProcessCount = len(gameProcesses)
+	gameProcesses, err := c.scanner.FindGameProcesses(c.config.GameNames())
+	if err != nil {
+		gameProcesses = nil
 	}
 
 	remaining := c.quotaState.GetRemainingMinutes()
@@ -185,17 +1281,69 @@ func (c *Controller) GetStatus() StatusInfo {
 	return StatusInfo{
 		AccumulatedTime:    c.quotaState.GetAccumulatedMinutes(),
 		RemainingTime:      remaining,
-		DailyLimit:         c.config.DailyLimit,
-		ActiveProcessCount: activeProcessCount,
+		AccumulatedSeconds: c.quotaState.GetAccumulatedSeconds(),
+		RemainingSeconds:   c.quotaState.GetRemainingSeconds(),
+		DailyLimit:         int(c.config.RuleFor(c.now()).DailyLimit),
+		EarnedMinutes:      c.quotaState.GetEarnedMinutes(),
+		ExtensionMinutes:   c.quotaState.GetExtensionMinutes(),
+		ActiveProcessCount: len(gameProcesses),
+		ActiveProcesses:    c.activeProcesses(gameProcesses),
+		GameTimes:          c.quotaState.GetGameSeconds(),
+		PausedGames:        c.quotaState.GetPausedGames(),
 		NextResetTime:      nextReset,
+		ScanMilliseconds:   scanDuration(c.scanner).Milliseconds(),
 	}
 }
 
+// activeProcesses 计算每个游戏进程的当前会话时长：优先使用进程创建时间，
+// 未知时使用状态文件中保存的会话首次检测时间
+func (c *Controller) activeProcesses(gameProcesses []process.ProcessInfo) []ActiveProcess {
+	firstSeen := make(map[process.ProcessKey]time.Time)
+	for _, session := range c.tracker.ActiveSessions() {
+		firstSeen[session.Key()] = session.FirstSeen
+	}
+
+	now := c.now()
+	active := make([]ActiveProcess, 0, len(gameProcesses))
+	for _, proc := range gameProcesses {
+		started := proc.StartTime
+		if started.IsZero() {
+			started = firstSeen[proc.Key()]
+		}
+
+		var duration time.Duration
+		if !started.IsZero() {
+			duration = now.Sub(started)
+		}
+		active = append(active, ActiveProcess{
+			Name:     proc.Name,
+			PID:      proc.PID,
+			Duration: duration,
+		})
+	}
+	return active
+}
+
 // StatusInfo 状态信息
 type StatusInfo struct {
-	AccumulatedTime    int           `json:"accumulatedTime"`    // 累计时间（分钟）
-	RemainingTime      int           `json:"remainingTime"`      // 剩余时间（分钟）
-	DailyLimit         int           `json:"dailyLimit"`         // 每日限制（分钟）
-	ActiveProcessCount int           `json:"activeProcessCount"` // 活跃进程数
-	NextResetTime      time.Duration `json:"nextResetTime"`      // 距离下次重置的时间
+	AccumulatedTime    int              `json:"accumulatedTime"`    // 累计时间（分钟）
+	RemainingTime      int              `json:"remainingTime"`      // 剩余时间（分钟）
+	AccumulatedSeconds int64            `json:"accumulatedSeconds"` // 累计时间（秒）
+	RemainingSeconds   int64            `json:"remainingSeconds"`   // 剩余时间（秒）
+	DailyLimit         int              `json:"dailyLimit"`         // 每日限制（分钟，不含奖励时间）
+	EarnedMinutes      int              `json:"earnedMinutes"`      // 当天赚取的奖励时间（分钟），已计入剩余时间
+	ExtensionMinutes   int              `json:"extensionMinutes"`   // 当天临时延长的时间（分钟），已计入剩余时间，下次重置时失效
+	ActiveProcessCount int              `json:"activeProcessCount"` // 活跃进程数
+	ActiveProcesses    []ActiveProcess  `json:"activeProcesses"`    // 活跃游戏进程详情
+	GameTimes          map[string]int64 `json:"gameTimes"`          // 当天各游戏累计时间（秒）
+	PausedGames        []string         `json:"pausedGames"`        // 当天暂停限制的游戏
+	NextResetTime      time.Duration    `json:"nextResetTime"`      // 距离下次重置的时间
+	ScanMilliseconds   int64            `json:"scanMilliseconds"`   // 本次查询时进程扫描的耗时（毫秒），扫描器不支持计时时为 0
+}
+
+// ActiveProcess 活跃游戏进程
+type ActiveProcess struct {
+	Name     string        `json:"name"`
+	PID      int           `json:"pid"`
+	Duration time.Duration `json:"duration"` // 当前会话时长，未知时为 0
 }
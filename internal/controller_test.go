@@ -1,16 +1,67 @@
 package internal
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/yourusername/game-control/pkg/clock"
 	"github.com/yourusername/game-control/pkg/config"
 	"github.com/yourusername/game-control/pkg/logger"
 	"github.com/yourusername/game-control/pkg/process"
 	"github.com/yourusername/game-control/pkg/quota"
+	"github.com/yourusername/game-control/pkg/sysexec"
 )
 
+// testLogPath 控制器测试共用的日志文件（日志器为进程内单例）
+var testLogPath string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "controller-test-*")
+	if err != nil {
+		panic(err)
+	}
+	testLogPath = filepath.Join(dir, "test.log")
+	if _, err := logger.NewLoggerWithOptions(logger.Options{
+		OutputPath: testLogPath,
+		Level:      logger.LevelDebug,
+	}); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	_ = logger.Close()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// readLoggedEvents 读取并清空测试日志，返回其中名为 event 的条目
+func readLoggedEvents(t *testing.T, event string) []logger.LogEntry {
+	t.Helper()
+	data, err := os.ReadFile(testLogPath)
+	if err != nil {
+		t.Fatalf("读取测试日志失败: %v", err)
+	}
+	if err := os.WriteFile(testLogPath, nil, 0644); err != nil {
+		t.Fatalf("清空测试日志失败: %v", err)
+	}
+
+	var entries []logger.LogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry logger.LogEntry
+		if json.Unmarshal([]byte(line), &entry) == nil && entry.Event == event {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
 type mockScanner struct {
 	findGameProcessesFunc func([]string) ([]process.ProcessInfo, error)
 	terminateWithRetryFn  func(int, int, time.Duration) error
@@ -31,9 +82,18 @@ func (m *mockScanner) TerminateWithRetry(pid int, maxRetries int, retryDelay tim
 }
 
 type fakeNotifier struct {
-	firstCalls int
-	finalCalls int
-	limitCalls int
+	firstCalls             int
+	finalCalls             int
+	limitCalls             int
+	softCalls              int
+	terminationFailedCalls int
+	accessDeniedCalls      int
+	breakCalls             int
+	scheduleCalls          int
+	summaryCalls           int
+	lastNextReset          time.Time
+	lastNextAllowed        time.Time
+	lastSummaryMinutes     int
 }
 
 func (f *fakeNotifier) NotifyFirstWarning(remainingMinutes int) error {
@@ -46,8 +106,41 @@ func (f *fakeNotifier) NotifyFinalWarning(remainingMinutes int) error {
 	return nil
 }
 
-func (f *fakeNotifier) NotifyLimitExceeded() error {
+func (f *fakeNotifier) NotifyLimitExceeded(nextReset time.Time) error {
 	f.limitCalls++
+	f.lastNextReset = nextReset
+	return nil
+}
+
+func (f *fakeNotifier) NotifyTerminationFailed() error {
+	f.terminationFailedCalls++
+	return nil
+}
+
+func (f *fakeNotifier) NotifyAccessDenied(game string) error {
+	f.accessDeniedCalls++
+	return nil
+}
+
+func (f *fakeNotifier) NotifySoftLimit(overMinutes, remainingMinutes int) error {
+	f.softCalls++
+	return nil
+}
+
+func (f *fakeNotifier) NotifyBreak(playedMinutes int, until time.Time) error {
+	f.breakCalls++
+	return nil
+}
+
+func (f *fakeNotifier) NotifyOutsideSchedule(nextAllowed time.Time) error {
+	f.scheduleCalls++
+	f.lastNextAllowed = nextAllowed
+	return nil
+}
+
+func (f *fakeNotifier) NotifyDailySummary(playedMinutes int, limitReached bool, terminations int, gameMinutes map[string]int) error {
+	f.summaryCalls++
+	f.lastSummaryMinutes = playedMinutes
 	return nil
 }
 
@@ -69,9 +162,6 @@ func createTestController(t *testing.T) (*Controller, *mockScanner, *fakeNotifie
 	if err != nil {
 		t.Fatalf("创建测试配额状态失败: %v", err)
 	}
-	if _, err := logger.NewLogger(cfg.LogFile); err != nil {
-		t.Fatalf("创建测试日志器失败: %v", err)
-	}
 	mock := &mockScanner{}
 	n := &fakeNotifier{}
 	c := NewControllerWithDeps(cfg, qState, mock, n)
@@ -133,6 +223,9 @@ func TestControllerTick_LimitExceededNotifyAndTerminate(t *testing.T) {
 	if n.limitCalls != 1 {
 		t.Fatalf("超限弹窗应只弹一次，实际 %d", n.limitCalls)
 	}
+	if !n.lastNextReset.Equal(qState.NextReset()) {
+		t.Errorf("超限通知应带上下次重置时间 %v，实际 %v", qState.NextReset(), n.lastNextReset)
+	}
 	if terminateCalls == 0 {
 		t.Fatal("超限后应尝试终止进程")
 	}
@@ -158,3 +251,1859 @@ func TestControllerStatus(t *testing.T) {
 		t.Errorf("活跃进程数量应为1，实际为 %d", status.ActiveProcessCount)
 	}
 }
+
+func TestControllerStatus_ActiveProcessDurations(t *testing.T) {
+	controller, mock, _, _ := createTestController(t)
+
+	now := time.Date(2026, 2, 12, 20, 0, 0, 0, time.UTC)
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	controller.tracker.Restore([]process.Session{
+		{PID: 2, Name: "legacy.exe", FirstSeen: now.Add(-10 * time.Minute), LastSeen: now},
+	})
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{
+			{PID: 1, Name: "game.exe", StartTime: now.Add(-time.Hour)},
+			{PID: 2, Name: "legacy.exe"},
+		}, nil
+	}
+
+	active := controller.GetStatus().ActiveProcesses
+	if len(active) != 2 {
+		t.Fatalf("应列出2个活跃进程，实际 %+v", active)
+	}
+	if active[0].Duration != time.Hour {
+		t.Errorf("已知创建时间时应按创建时间计算会话时长，实际 %v", active[0].Duration)
+	}
+	if active[1].Duration != 10*time.Minute {
+		t.Errorf("创建时间未知时应按已保存会话计算时长，实际 %v", active[1].Duration)
+	}
+}
+
+func TestControllerTick_GracePeriodDelaysTermination(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+	controller.config.Enforcement.GraceSeconds = 30
+
+	now := time.Now()
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: now}}, nil
+	}
+	terminateCalls := 0
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminateCalls++
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	controller.tick()
+	if terminateCalls != 0 {
+		t.Fatalf("宽限期开始时不应终止进程，实际终止 %d 次", terminateCalls)
+	}
+	if n.finalCalls != 1 {
+		t.Fatalf("进入宽限期应触发一次最后警告，实际 %d", n.finalCalls)
+	}
+
+	now = now.Add(20 * time.Second)
+	controller.tick()
+	if terminateCalls != 0 {
+		t.Fatalf("宽限期内不应终止进程，实际终止 %d 次", terminateCalls)
+	}
+	if n.finalCalls != 1 {
+		t.Fatalf("宽限期不应重复开始，最后警告实际 %d 次", n.finalCalls)
+	}
+
+	now = now.Add(15 * time.Second)
+	controller.tick()
+	if terminateCalls != 1 {
+		t.Fatalf("宽限期结束后应终止进程，实际终止 %d 次", terminateCalls)
+	}
+	if n.limitCalls != 1 {
+		t.Fatalf("宽限期结束后应触发超限弹窗，实际 %d", n.limitCalls)
+	}
+}
+
+// fakeSessionActions 记录锁定与注销的调用次数
+type fakeSessionActions struct {
+	locks, logoffs int
+}
+
+func (f *fakeSessionActions) Lock() error {
+	f.locks++
+	return nil
+}
+
+func (f *fakeSessionActions) Logoff() error {
+	f.logoffs++
+	return nil
+}
+
+func TestControllerTick_SessionActionAfterMinimumGrace(t *testing.T) {
+	for _, action := range []string{config.ActionLock, config.ActionLogoff} {
+		t.Run(action, func(t *testing.T) {
+			controller, mock, n, qState := createTestController(t)
+			controller.config.Enforcement.OnLimit = action
+			fake := &fakeSessionActions{}
+			controller.session = fake
+
+			now := time.Now()
+			controller.SetClock(clock.Func(func() time.Time { return now }))
+			mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+				return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: now}}, nil
+			}
+			terminateCalls := 0
+			mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+				terminateCalls++
+				return nil
+			}
+
+			qState.AddTime(120 * 60)
+			controller.tick()
+			if fake.locks+fake.logoffs != 0 || n.finalCalls != 1 {
+				t.Fatalf("未配置宽限时也应先提醒并宽限，实际锁定 %d 次、注销 %d 次、最后警告 %d 次",
+					fake.locks, fake.logoffs, n.finalCalls)
+			}
+
+			now = now.Add(config.MinSessionActionGrace)
+			controller.tick()
+			if action == config.ActionLock && fake.locks != 1 || action == config.ActionLogoff && fake.logoffs != 1 {
+				t.Fatalf("宽限期结束后应执行 %s，实际锁定 %d 次、注销 %d 次", action, fake.locks, fake.logoffs)
+			}
+			if terminateCalls != 0 {
+				t.Errorf("执行 %s 时不应终止游戏进程，实际终止 %d 次", action, terminateCalls)
+			}
+			if events := readLoggedEvents(t, "limit_action"); len(events) != 1 {
+				t.Errorf("应记录一条 limit_action 事件，实际 %d 条", len(events))
+			}
+		})
+	}
+}
+
+func TestControllerTick_SuspendUntilReset(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Enforcement.OnLimit = config.ActionSuspend
+
+	var suspended, resumed []int
+	controller.suspendProcess = func(pid int) error {
+		suspended = append(suspended, pid)
+		return nil
+	}
+	controller.resumeProcess = func(pid int) error {
+		resumed = append(resumed, pid)
+		return nil
+	}
+	running := []process.ProcessInfo{{PID: 1, Name: "game.exe"}, {PID: 2, Name: "game.exe"}}
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return running, nil
+	}
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		t.Fatalf("挂起模式不应终止进程 (PID: %d)", pid)
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	controller.tick()
+	controller.tick()
+	if len(suspended) != 2 {
+		t.Fatalf("每个游戏进程应只挂起一次，实际 %v", suspended)
+	}
+
+	// PID 2 退出后不再跟踪，重置时只恢复仍在运行的 PID 1
+	running = running[:1]
+	controller.tick()
+	qState.NextResetTime = time.Now().Add(-time.Minute).Unix()
+	controller.tick()
+	if len(resumed) != 1 || resumed[0] != 1 {
+		t.Errorf("配额重置时应恢复仍被挂起的进程，实际 %v", resumed)
+	}
+	if len(controller.suspended) != 0 {
+		t.Errorf("恢复后应清空挂起记录，实际 %v", controller.suspended)
+	}
+}
+
+func TestControllerTick_FullDayCycle(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		DailyLimit:     120,
+		ResetTime:      "08:00",
+		Timezone:       "UTC",
+		Games:          []string{"game.exe"},
+		FirstThreshold: 15,
+		FinalThreshold: 5,
+		StateFile:      filepath.Join(tempDir, "state.json"),
+		LogFile:        filepath.Join(tempDir, "test.log"),
+	}
+	clk := clock.NewManual(time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC))
+	qState, err := quota.NewQuotaStateWithClock(cfg, clk)
+	if err != nil {
+		t.Fatalf("创建配额状态失败: %v", err)
+	}
+	mock := &mockScanner{}
+	n := &fakeNotifier{}
+	controller := NewControllerWithDeps(cfg, qState, mock, n)
+	controller.SetClock(clk)
+
+	running := true
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		if !running {
+			return nil, nil
+		}
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe"}}, nil
+	}
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		running = false
+		return nil
+	}
+	// play 以 5 秒的扫描间隔运行 d 时长
+	play := func(d time.Duration) {
+		for elapsed := time.Duration(0); elapsed < d; elapsed += 5 * time.Second {
+			controller.tick()
+			clk.Advance(5 * time.Second)
+		}
+	}
+
+	// 08:00 开始游戏，每次扫描计 5 秒：差 5 秒满 105 分钟时尚未提醒，下一次扫描剩余 15 分钟触发首次提醒
+	play(105*time.Minute - 5*time.Second)
+	if n.firstCalls != 0 || qState.GetRemainingMinutes() != 16 {
+		t.Fatalf("剩余 15 分钟前不应提醒，实际提醒 %d 次、剩余 %d", n.firstCalls, qState.GetRemainingMinutes())
+	}
+	play(5 * time.Second)
+	if n.firstCalls != 1 || qState.GetRemainingMinutes() != 15 {
+		t.Fatalf("剩余 15 分钟时应首次提醒，实际提醒 %d 次、剩余 %d", n.firstCalls, qState.GetRemainingMinutes())
+	}
+	play(10 * time.Minute)
+	if n.finalCalls != 1 {
+		t.Fatalf("剩余 5 分钟时应最后提醒，实际 %d 次", n.finalCalls)
+	}
+	play(5 * time.Minute)
+	if n.limitCalls != 1 || running {
+		t.Fatalf("用满 120 分钟应超限并终止游戏，实际超限通知 %d 次、仍在运行 %v", n.limitCalls, running)
+	}
+
+	// 次日 08:00 整点尚未越过重置时间
+	clk.Set(time.Date(2026, 2, 10, 8, 0, 0, 0, time.UTC))
+	controller.tick()
+	if qState.GetRemainingMinutes() != 0 {
+		t.Fatalf("重置时间前配额不应恢复，实际剩余 %d", qState.GetRemainingMinutes())
+	}
+
+	clk.Advance(5 * time.Second)
+	running = true
+	play(10 * time.Minute)
+	if got := qState.GetAccumulatedMinutes(); got != 10 {
+		t.Errorf("重置后应从零重新计时，玩 10 分钟后累计应为 10，实际 %d", got)
+	}
+	if qState.NextResetTime != time.Date(2026, 2, 11, 8, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("下次重置应为 2 月 11 日 08:00，实际 %v", time.Unix(qState.NextResetTime, 0).UTC())
+	}
+	play(95 * time.Minute)
+	if n.firstCalls != 2 {
+		t.Errorf("重置后应在新的一天重新提醒，实际共提醒 %d 次", n.firstCalls)
+	}
+}
+
+func TestNewControllerWithDeps_UsesQuotaClock(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		DailyLimit:     120,
+		ResetTime:      "08:00",
+		Timezone:       "UTC",
+		Games:          []string{"game.exe"},
+		FirstThreshold: 15,
+		FinalThreshold: 5,
+		StateFile:      filepath.Join(tempDir, "state.json"),
+		LogFile:        filepath.Join(tempDir, "test.log"),
+	}
+	clk := clock.NewManual(time.Date(2026, 2, 9, 6, 0, 0, 0, time.UTC))
+	qState, err := quota.NewQuotaStateWithClock(cfg, clk)
+	if err != nil {
+		t.Fatalf("创建配额状态失败: %v", err)
+	}
+
+	// 未调用 SetClock：控制器应从创建起沿用配额状态注入的时钟
+	controller := NewControllerWithDeps(cfg, qState, &mockScanner{}, &fakeNotifier{})
+	if !controller.startedAt.Equal(clk.Now()) || !controller.lastSaveTime.Equal(clk.Now()) {
+		t.Fatalf("启动与保存时间应取自注入的时钟 %v，实际 %v / %v", clk.Now(), controller.startedAt, controller.lastSaveTime)
+	}
+	clk.Advance(30 * time.Minute)
+	if got := controller.GetStatus().NextResetTime; got != 90*time.Minute {
+		t.Errorf("距下次重置应按注入的时钟计算为 1h30m，实际 %v", got)
+	}
+}
+
+func TestControllerTick_BreakAfterMaxSession(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+	controller.config.Breaks = config.BreaksConfig{MaxSessionMinutes: 1, BreakMinutes: 10}
+
+	now := time.Date(2026, 2, 12, 20, 0, 0, 0, time.UTC)
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: now}}, nil
+	}
+	terminateCalls := 0
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminateCalls++
+		return nil
+	}
+
+	// 连续游戏 55 秒仍未达到上限
+	for i := 0; i < 11; i++ {
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+	if terminateCalls != 0 || n.breakCalls != 0 {
+		t.Fatalf("未达到连续游戏上限时不应休息，终止 %d 次，通知 %d 次", terminateCalls, n.breakCalls)
+	}
+
+	controller.tick()
+	if n.breakCalls != 1 {
+		t.Fatalf("达到连续游戏上限时应通知开始休息一次，实际 %d", n.breakCalls)
+	}
+	if terminateCalls == 0 {
+		t.Fatal("达到连续游戏上限时应终止游戏")
+	}
+
+	// 休息期间重新启动的游戏不计时并继续被终止
+	accumulated := qState.GetAccumulatedSeconds()
+	terminateCalls = 0
+	now = now.Add(5 * time.Minute)
+	controller.tick()
+	if qState.GetAccumulatedSeconds() != accumulated {
+		t.Errorf("休息期间不应计时，累计时间从 %d 变为 %d", accumulated, qState.GetAccumulatedSeconds())
+	}
+	if terminateCalls == 0 {
+		t.Error("休息期间启动的游戏应被终止")
+	}
+	if n.breakCalls != 1 {
+		t.Errorf("休息期间不应重复通知，实际 %d 次", n.breakCalls)
+	}
+
+	// 休息结束后恢复计时，连续时间重新计算
+	terminateCalls = 0
+	now = now.Add(5 * time.Minute)
+	controller.tick()
+	if terminateCalls != 0 {
+		t.Errorf("休息结束后不应终止游戏，实际终止 %d 次", terminateCalls)
+	}
+	if qState.GetAccumulatedSeconds() != accumulated+5 {
+		t.Errorf("休息结束后应恢复计时，累计时间 %d", qState.GetAccumulatedSeconds())
+	}
+	if events := readLoggedEvents(t, "break_ended"); len(events) != 1 {
+		t.Errorf("休息结束时应记录 1 条 break_ended，实际 %d 条", len(events))
+	}
+}
+
+func TestControllerTick_NaturalBreakResetsStreak(t *testing.T) {
+	controller, mock, n, _ := createTestController(t)
+	controller.config.Breaks = config.BreaksConfig{MaxSessionMinutes: 1, BreakMinutes: 10}
+
+	now := time.Date(2026, 2, 12, 20, 0, 0, 0, time.UTC)
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	running := []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: now}}
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return running, nil
+	}
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		t.Fatalf("自然休息后不应终止游戏 (PID: %d)", pid)
+		return nil
+	}
+
+	play := func(ticks int) {
+		for i := 0; i < ticks; i++ {
+			controller.tick()
+			now = now.Add(5 * time.Second)
+		}
+	}
+
+	play(11)
+	// 关闭游戏并休息足够久，之后的游戏重新计算连续时间
+	saved := running
+	running = nil
+	controller.tick()
+	now = now.Add(10 * time.Minute)
+	controller.tick()
+	running = saved
+	play(11)
+
+	if n.breakCalls != 0 {
+		t.Errorf("两段游戏之间已休息足够久，不应强制休息，实际通知 %d 次", n.breakCalls)
+	}
+}
+
+func TestControllerTick_BlockRelaunchTerminatesEachLaunch(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+	controller.config.Enforcement.GraceSeconds = 30
+	controller.config.Enforcement.BlockRelaunch = true
+
+	now := time.Now()
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	var running []process.ProcessInfo
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return running, nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		running = nil
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	readLoggedEvents(t, "relaunch_blocked")
+
+	// 首次超限照常给宽限期
+	running = []process.ProcessInfo{{PID: 1, Name: "game.exe"}}
+	controller.tick()
+	if len(terminated) != 0 {
+		t.Fatalf("宽限期内不应终止，实际终止 %v", terminated)
+	}
+	now = now.Add(31 * time.Second)
+	controller.tick()
+	if len(terminated) != 1 || n.limitCalls != 1 {
+		t.Fatalf("宽限期结束后应终止并提醒一次，终止 %v，提醒 %d 次", terminated, n.limitCalls)
+	}
+	if events := readLoggedEvents(t, "relaunch_blocked"); len(events) != 0 {
+		t.Fatalf("首次超限不应记录 relaunch_blocked，实际 %d 条", len(events))
+	}
+
+	// 之后每次重新启动都在两次常规检查之间立即终止并提醒
+	for i, pid := range []int{2, 3} {
+		running = []process.ProcessInfo{{PID: pid, Name: "game.exe"}}
+		controller.guardRelaunch()
+		if len(terminated) != 2+i || terminated[len(terminated)-1] != pid {
+			t.Fatalf("重新启动的游戏 (PID: %d) 应被立即终止，实际终止 %v", pid, terminated)
+		}
+		if n.limitCalls != 2+i {
+			t.Errorf("每次重新启动都应提醒，实际提醒 %d 次", n.limitCalls)
+		}
+	}
+
+	// 常规检查中发现的重新启动同样不再给宽限期
+	running = []process.ProcessInfo{{PID: 4, Name: "game.exe"}}
+	controller.tick()
+	if terminated[len(terminated)-1] != 4 {
+		t.Fatalf("重新启动的游戏不应再有宽限期，实际终止 %v", terminated)
+	}
+
+	events := readLoggedEvents(t, "relaunch_blocked")
+	if len(events) != 3 {
+		t.Fatalf("应记录 3 条 relaunch_blocked，实际 %d 条", len(events))
+	}
+	if events[0].PID != 2 || events[0].Process != "game.exe" {
+		t.Errorf("relaunch_blocked 应包含进程名与 PID，实际 %+v", events[0])
+	}
+}
+
+func TestControllerGuardRelaunch_DisabledByDefault(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+
+	scans := 0
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		scans++
+		return []process.ProcessInfo{{PID: 1, Name: "game.exe"}}, nil
+	}
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	controller.tick()
+	scans = 0
+	controller.guardRelaunch()
+	if scans != 0 {
+		t.Errorf("未启用 blockRelaunch 时不应额外扫描，实际扫描 %d 次", scans)
+	}
+}
+
+func TestControllerTick_EarnTimeWhileLearningAppRuns(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Earn = config.EarnConfig{
+		Apps:       []config.EarnApp{{Name: "typing.exe", Ratio: 2}},
+		MaxMinutes: 1,
+	}
+
+	running := []process.ProcessInfo{{PID: 7, Name: "typing.exe"}}
+	var scanned []string
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		scanned = games
+		return running, nil
+	}
+	readLoggedEvents(t, "time_earned")
+
+	// 扫描间隔 5 秒，运行 2 分钟（24 次）赚到 1 分钟
+	for i := 0; i < 23; i++ {
+		controller.tick()
+	}
+	if got := qState.GetEarnedMinutes(); got != 0 {
+		t.Fatalf("运行不足 2 分钟不应赚到时间，实际 %d 分钟", got)
+	}
+	controller.tick()
+	if got := qState.GetEarnedMinutes(); got != 1 {
+		t.Fatalf("运行 2 分钟应赚到 1 分钟，实际 %d 分钟", got)
+	}
+	if qState.GetAccumulatedSeconds() != 0 {
+		t.Errorf("奖励应用不应计入游戏时间，实际 %d 秒", qState.GetAccumulatedSeconds())
+	}
+	if !slices.Contains(scanned, "typing.exe") {
+		t.Errorf("应同时扫描奖励应用，实际扫描 %v", scanned)
+	}
+
+	// 达到上限后不再增加，也不再记录
+	for i := 0; i < 48; i++ {
+		controller.tick()
+	}
+	if got := qState.GetEarnedMinutes(); got != 1 {
+		t.Errorf("奖励时间不应超过 earn.maxMinutes，实际 %d 分钟", got)
+	}
+	events := readLoggedEvents(t, "time_earned")
+	if len(events) != 1 || events[0].Process != "typing.exe" {
+		t.Errorf("应记录 1 条 time_earned，实际 %+v", events)
+	}
+}
+
+func TestControllerTick_NoEarnWhileGaming(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Earn = config.EarnConfig{
+		Apps:       []config.EarnApp{{Name: "typing.exe", Ratio: 1}},
+		MaxMinutes: 30,
+	}
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 7, Name: "typing.exe"}, {PID: 8, Name: "game.exe"}}, nil
+	}
+	for i := 0; i < 24; i++ {
+		controller.tick()
+	}
+
+	if got := qState.GetEarnedMinutes(); got != 0 {
+		t.Errorf("游戏运行期间不应赚取时间，实际 %d 分钟", got)
+	}
+	if got := qState.GetAccumulatedSeconds(); got != 120 {
+		t.Errorf("游戏应照常计时，实际 %d 秒", got)
+	}
+}
+
+func TestControllerTick_NeverSeenGamesBookkeeping(t *testing.T) {
+	controller, mock, _, _ := createTestController(t)
+	controller.config.Games = []string{"Game.exe", "typo.exe"}
+
+	now := time.Now()
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	controller.startedAt = now
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1, Name: "game.exe", StartTime: now}}, nil
+	}
+
+	controller.tick()
+	if !controller.neverSeenCheckedAt.IsZero() {
+		t.Fatal("运行时间不足时不应检查从未出现的游戏")
+	}
+
+	names := controller.neverSeenGames()
+	if len(names) != 1 || names[0] != "typo.exe" {
+		t.Fatalf("从未出现的游戏应为 [typo.exe]，实际为 %v", names)
+	}
+
+	now = now.Add(neverSeenCheckAfter)
+	controller.tick()
+	if !controller.neverSeenCheckedAt.Equal(now) {
+		t.Fatal("运行足够久后应完成从未出现游戏的检查")
+	}
+
+	// 检查后按同样的间隔重新检查，而不是只检查一次
+	first := now
+	now = now.Add(neverSeenCheckAfter - time.Second)
+	controller.tick()
+	if !controller.neverSeenCheckedAt.Equal(first) {
+		t.Fatal("距上次检查不足间隔时不应重新检查")
+	}
+	now = now.Add(time.Second)
+	controller.tick()
+	if !controller.neverSeenCheckedAt.Equal(now) {
+		t.Fatalf("距上次检查满间隔后应重新检查，上次检查时间为 %v", controller.neverSeenCheckedAt)
+	}
+}
+
+func TestControllerTick_ExemptPIDNeverTerminated(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Enforcement.ExemptPids = []int{1234}
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{
+			{PID: 1234, Name: "game.exe", StartTime: time.Now()},
+			{PID: 5678, Name: "game.exe", StartTime: time.Now()},
+		}, nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	controller.tick()
+
+	if len(terminated) != 1 || terminated[0] != 5678 {
+		t.Fatalf("只应终止非豁免进程 5678，实际终止 %v", terminated)
+	}
+}
+
+func TestControllerTick_LimitTerminatesGameStartedDuringTermination(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+
+	// 第一次扫描只有 1234；终止期间又启动了 5678，只出现在之后的扫描中
+	scans := 0
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		scans++
+		if scans == 1 {
+			return []process.ProcessInfo{{PID: 1234, Name: "game.exe"}}, nil
+		}
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe"}, {PID: 5678, Name: "game.exe"}}, nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	controller.tick()
+
+	if len(terminated) != 2 || terminated[0] != 1234 || terminated[1] != 5678 {
+		t.Fatalf("同一轮内应终止扫描后新启动的进程且不重复终止，实际终止 %v", terminated)
+	}
+}
+
+func TestControllerTick_TerminationFailureReportedAndNotified(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe"}, {PID: 5678, Name: "game.exe"}}, nil
+	}
+	failing := true
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		if failing && pid == 5678 {
+			return errors.New("拒绝访问")
+		}
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	readLoggedEvents(t, "termination_result")
+	controller.tick()
+	controller.tick()
+
+	events := readLoggedEvents(t, "termination_result")
+	if len(events) != 2 {
+		t.Fatalf("每轮执行应记录一次 termination_result，实际 %d 次", len(events))
+	}
+	if e := events[0]; e.Level != logger.LevelError || len(e.Succeeded) != 1 || e.Succeeded[0] != 1234 ||
+		len(e.Failed) != 1 || e.Failed[0] != 5678 {
+		t.Errorf("事件应记录成功 [1234] 与失败 [5678]，实际 %+v", e)
+	}
+	if n.terminationFailedCalls != 1 {
+		t.Fatalf("连续终止失败只应通知一次，实际 %d 次", n.terminationFailedCalls)
+	}
+
+	// 全部终止成功后清除标记，之后再次失败会重新通知
+	failing = false
+	controller.tick()
+	failing = true
+	controller.tick()
+	if n.terminationFailedCalls != 2 {
+		t.Fatalf("恢复后再次失败应重新通知，实际 %d 次", n.terminationFailedCalls)
+	}
+}
+
+func TestControllerTick_TerminateAccessDenied(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte("ERROR: The process with PID 1234 could not be terminated.\r\nReason: Access is denied.\r\n"),
+				errors.New("exit status 1")
+		},
+	}
+	scanner := process.NewScannerWithRunner(fake)
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe"}}, nil
+	}
+	mock.terminateWithRetryFn = scanner.TerminateWithRetry
+
+	qState.AddTime(120 * 60)
+	readLoggedEvents(t, "")
+	controller.tick()
+	controller.tick()
+
+	if len(fake.Calls) != 1 {
+		t.Errorf("拒绝访问后不应重试 taskkill，实际执行 %d 次", len(fake.Calls))
+	}
+	events := readLoggedEvents(t, "terminate_access_denied")
+	if len(events) != 1 || events[0].PID != 1234 || events[0].Reason != reasonQuota {
+		t.Fatalf("应记录一次带终止原因的 terminate_access_denied 事件，实际 %+v", events)
+	}
+	if n.accessDeniedCalls != 1 {
+		t.Errorf("权限不足只应通知一次，实际 %d 次", n.accessDeniedCalls)
+	}
+	if n.terminationFailedCalls != 0 {
+		t.Errorf("权限不足时不应再发出通用的终止失败通知，实际 %d 次", n.terminationFailedCalls)
+	}
+}
+
+func TestControllerRunContext_CancelStopsLoopAndSaves(t *testing.T) {
+	controller, _, _, qState := createTestController(t)
+	qState.AddTime(600)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- controller.RunContext(ctx) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("RunContext 应正常返回，实际 %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("取消 ctx 后控制循环应退出")
+	}
+
+	saved, err := quota.LoadFromFile(controller.config)
+	if err != nil {
+		t.Fatalf("退出时应保存状态: %v", err)
+	}
+	if saved.AccumulatedTime != 600 {
+		t.Fatalf("保存的累计时间应为 600 秒，实际 %d", saved.AccumulatedTime)
+	}
+}
+
+func TestControllerShouldAccrue_ForegroundOnly(t *testing.T) {
+	controller, _, _, _ := createTestController(t)
+	controller.config.CountForegroundOnly = true
+
+	games := []process.ProcessInfo{{PID: 100, Name: "game.exe"}, {PID: 200, Name: "game.exe"}}
+
+	tests := []struct {
+		name  string
+		fgPID int
+		fgErr error
+		procs []process.ProcessInfo
+		want  bool
+	}{
+		{name: "游戏在前台", fgPID: 200, procs: games, want: true},
+		{name: "其他程序在前台", fgPID: 999, procs: games, want: false},
+		{name: "无法获取前台时回退", fgErr: errors.New("no window"), procs: games, want: true},
+		{name: "没有游戏进程", fgPID: 100, procs: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller.foregroundPID = func() (int, error) { return tt.fgPID, tt.fgErr }
+			if got := controller.shouldAccrue(tt.procs); got != tt.want {
+				t.Errorf("shouldAccrue 应为 %v，实际为 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestControllerShouldAccrue_VisibleOnly(t *testing.T) {
+	controller, _, _, _ := createTestController(t)
+	controller.config.CountVisibleOnly = true
+
+	games := []process.ProcessInfo{{PID: 100, Name: "launcher.exe"}, {PID: 200, Name: "game.exe"}}
+
+	tests := []struct {
+		name    string
+		visible map[int]bool
+		err     error
+		want    bool
+	}{
+		{name: "游戏窗口可见", visible: map[int]bool{200: true}, want: true},
+		{name: "游戏窗口全部最小化", visible: map[int]bool{999: true}, want: false},
+		{name: "没有任何可见窗口", visible: map[int]bool{}, want: false},
+		{name: "无法枚举窗口时回退", err: errors.New("enum failed"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller.visibleWindowPIDs = func() (map[int]bool, error) { return tt.visible, tt.err }
+			if got := controller.shouldAccrue(games); got != tt.want {
+				t.Errorf("shouldAccrue 应为 %v，实际为 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestControllerTick_ForegroundOnlySkipsBackgroundGame(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.CountForegroundOnly = true
+	controller.foregroundPID = func() (int, error) { return 999, nil }
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+
+	controller.tick()
+	if qState.AccumulatedTime != 0 {
+		t.Fatalf("游戏不在前台时不应累计时间，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_IdlePausesAccrual(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Idle.PauseAfterSeconds = 300
+
+	idle := 10 * time.Minute
+	controller.idleDuration = func() (time.Duration, error) { return idle, nil }
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+
+	controller.tick()
+	if qState.AccumulatedTime != 0 {
+		t.Fatalf("用户空闲时不应累计时间，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+	if !controller.idlePaused {
+		t.Fatal("空闲超过阈值后应进入暂停状态")
+	}
+
+	idle = 2 * time.Second
+	controller.tick()
+	if qState.AccumulatedTime != 5 {
+		t.Fatalf("恢复输入后应继续累计，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+	if controller.idlePaused {
+		t.Fatal("恢复输入后应退出暂停状态")
+	}
+}
+
+func TestControllerTick_IdleQueryErrorKeepsCounting(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Idle.PauseAfterSeconds = 300
+	controller.idleDuration = func() (time.Duration, error) { return 0, errors.New("unsupported") }
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+
+	controller.tick()
+	if qState.AccumulatedTime != 5 {
+		t.Fatalf("无法检测空闲时应照常累计，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_ScreenLockPausesAccrual(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+
+	locked := true
+	controller.screenLocked = func() (bool, error) { return locked, nil }
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+
+	readLoggedEvents(t, "screen_locked")
+	controller.tick()
+	controller.tick()
+	if qState.AccumulatedTime != 0 {
+		t.Fatalf("锁屏期间不应累计时间，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+	if events := readLoggedEvents(t, "screen_locked"); len(events) != 1 {
+		t.Fatalf("锁屏应只记录一次 screen_locked，实际 %d 次", len(events))
+	}
+
+	locked = false
+	controller.tick()
+	if qState.AccumulatedTime != 5 {
+		t.Fatalf("解锁后应继续累计，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+	if events := readLoggedEvents(t, "screen_unlocked"); len(events) != 1 {
+		t.Fatalf("解锁应记录一次 screen_unlocked，实际 %d 次", len(events))
+	}
+}
+
+func TestControllerTick_ScreenLockQueryErrorKeepsCounting(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.screenLocked = func() (bool, error) { return false, errors.New("unsupported") }
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+
+	controller.tick()
+	if qState.AccumulatedTime != 5 {
+		t.Fatalf("无法检测锁屏时应照常累计，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_MinCPUPercentGatesAccrual(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Tracking.MinCPUPercent = 5
+
+	now := time.Now()
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	cpu := map[int]time.Duration{}
+	controller.cpuTime = func(pid int) (time.Duration, error) { return cpu[pid], nil }
+	started := now.Add(-time.Hour)
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{
+			{PID: 100, Name: "game.exe", StartTime: started},
+			{PID: 200, Name: "game.exe", StartTime: started},
+		}, nil
+	}
+
+	// 首次采样无法计算占用率，按繁忙计时
+	controller.tick()
+	if qState.AccumulatedTime != 5 {
+		t.Fatalf("首次采样应照常累计，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+
+	// 5 秒内两个进程各占用 100ms CPU（2%），低于阈值不计时
+	now = now.Add(5 * time.Second)
+	cpu[100] += 100 * time.Millisecond
+	cpu[200] += 100 * time.Millisecond
+	controller.tick()
+	if qState.AccumulatedTime != 5 {
+		t.Fatalf("CPU 占用率低于阈值时不应累计，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+
+	// 其中一个进程占用 1s CPU（20%），达到阈值即计时
+	now = now.Add(5 * time.Second)
+	cpu[200] += time.Second
+	controller.tick()
+	if qState.AccumulatedTime != 10 {
+		t.Fatalf("有进程 CPU 占用率达到阈值时应累计，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_CPUQueryErrorKeepsCounting(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Tracking.MinCPUPercent = 5
+	controller.cpuTime = func(pid int) (time.Duration, error) { return 0, errors.New("unsupported") }
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+
+	controller.tick()
+	controller.tick()
+	if qState.AccumulatedTime != 10 {
+		t.Fatalf("无法读取 CPU 时间时应照常累计，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_MultipleProcessesAccrueOnce(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{
+			{PID: 100, Name: "game.exe", StartTime: time.Now()},
+			{PID: 101, Name: "game.exe", StartTime: time.Now()},
+		}, nil
+	}
+
+	controller.tick()
+	controller.tick()
+
+	if qState.AccumulatedTime != 10 {
+		t.Fatalf("两个同时运行的匹配进程应按单倍速率累计，预期 10 秒，实际 %d 秒", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_NoGamesRunning(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+
+	terminateCalls := 0
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminateCalls++
+		return nil
+	}
+
+	controller.tick()
+	controller.tick()
+
+	if qState.AccumulatedTime != 0 {
+		t.Fatalf("没有游戏进程时不应累计时间，实际 %d 秒", qState.AccumulatedTime)
+	}
+	if terminateCalls != 0 || n.firstCalls+n.finalCalls+n.limitCalls != 0 {
+		t.Fatal("没有游戏进程且未超限时不应终止进程或弹窗")
+	}
+}
+
+func TestControllerTick_ScanErrorSkipsTick(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return nil, errors.New("tasklist failed")
+	}
+
+	controller.tick()
+	if qState.AccumulatedTime != 0 {
+		t.Fatalf("扫描失败时不应累计时间，实际 %d 秒", qState.AccumulatedTime)
+	}
+}
+
+func TestController_SessionsSurviveRestart(t *testing.T) {
+	controller, mock, _, _ := createTestController(t)
+
+	gameStart := time.Date(2026, 2, 12, 8, 0, 0, 0, time.UTC)
+	firstSeen := gameStart.Add(time.Minute)
+	controller.SetClock(clock.Func(func() time.Time { return firstSeen }))
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: gameStart}}, nil
+	}
+
+	controller.tick()
+	if err := controller.saveState(); err != nil {
+		t.Fatalf("保存状态失败: %v", err)
+	}
+
+	loaded, err := quota.LoadFromFile(controller.config)
+	if err != nil {
+		t.Fatalf("加载状态失败: %v", err)
+	}
+	if len(loaded.GetSessions()) != 1 {
+		t.Fatalf("状态文件应包含1个活跃会话，实际 %v", loaded.GetSessions())
+	}
+
+	restarted := NewControllerWithDeps(controller.config, loaded, mock, nil)
+	restarted.SetClock(clock.Func(func() time.Time { return firstSeen.Add(time.Hour) }))
+	restarted.tick()
+
+	sessions := restarted.tracker.ActiveSessions()
+	if len(sessions) != 1 || !sessions[0].FirstSeen.Equal(firstSeen) {
+		t.Fatalf("重启后仍在运行的会话应保留原始开始时间，实际 %v", sessions)
+	}
+	if sessions[0].Duration() != time.Hour {
+		t.Errorf("会话时长应跨越重启累计，实际 %v", sessions[0].Duration())
+	}
+}
+
+func TestControllerTick_EscalationShrinksGrace(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Enforcement.Escalation = []int{60, 30, 0}
+
+	now := time.Now()
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	running := false
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		if !running {
+			return nil, nil
+		}
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: now}}, nil
+	}
+	terminateCalls := 0
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminateCalls++
+		running = false
+		return nil
+	}
+
+	// overrun 模拟一次超限：启动游戏并返回本次宽限时长，随后等待宽限结束、游戏被终止并退出
+	overrun := func() time.Duration {
+		t.Helper()
+		running = true
+		before := terminateCalls
+		controller.tick()
+		grace := controller.graceDeadline.Sub(now)
+		now = now.Add(grace)
+		if grace > 0 {
+			controller.tick()
+		}
+		if terminateCalls != before+1 {
+			t.Fatalf("宽限 %v 结束后应终止一次进程，实际 %d 次", grace, terminateCalls-before)
+		}
+		controller.tick()
+		return grace
+	}
+
+	qState.AddTime(120 * 60)
+	for i, expect := range []time.Duration{60 * time.Second, 30 * time.Second, 0, 0} {
+		if grace := overrun(); grace != expect {
+			t.Fatalf("第 %d 次超限宽限应为 %v，实际 %v", i+1, expect, grace)
+		}
+	}
+
+	if err := qState.Reset(); err != nil {
+		t.Fatalf("重置配额失败: %v", err)
+	}
+	qState.AddTime(120 * 60)
+	if grace := overrun(); grace != 60*time.Second {
+		t.Fatalf("配额重置后宽限应恢复为 60s，实际 %v", grace)
+	}
+}
+
+func TestControllerTick_HeartbeatCadence(t *testing.T) {
+	controller, mock, _, _ := createTestController(t)
+	controller.config.Logging.HeartbeatSeconds = 30
+
+	start := time.Now()
+	now := start
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: start}}, nil
+	}
+
+	readLoggedEvents(t, "game_running")
+	for i := 0; i < 13; i++ {
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+
+	beats := readLoggedEvents(t, "game_running")
+	if len(beats) != 2 {
+		t.Fatalf("60 秒内按 30 秒间隔应记录 2 次心跳，实际 %d 次", len(beats))
+	}
+	if beats[0].Process != "game.exe" || beats[0].Duration != 30000 || beats[1].Duration != 60000 {
+		t.Errorf("心跳应携带累计会话时长，实际 %+v", beats)
+	}
+}
+
+func TestControllerTick_SaveIntervalHonored(t *testing.T) {
+	controller, _, _, _ := createTestController(t)
+	controller.config.Controller.SaveIntervalSeconds = 30
+
+	now := time.Now()
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	controller.lastSaveTime = now
+
+	now = now.Add(25 * time.Second)
+	controller.tick()
+	if _, err := os.Stat(controller.config.StateFile); !os.IsNotExist(err) {
+		t.Fatalf("未到保存间隔时不应写入状态文件，err=%v", err)
+	}
+
+	now = now.Add(5 * time.Second)
+	controller.tick()
+	if _, err := os.Stat(controller.config.StateFile); err != nil {
+		t.Fatalf("到达保存间隔后应写入状态文件: %v", err)
+	}
+}
+
+func TestControllerTick_ResetSavesImmediately(t *testing.T) {
+	controller, _, _, qState := createTestController(t)
+
+	now := time.Now()
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	controller.lastSaveTime = now
+
+	qState.AddTime(600)
+	qState.NextResetTime = now.Add(-time.Second).Unix()
+	controller.tick()
+
+	loaded, err := quota.LoadFromFile(controller.config)
+	if err != nil {
+		t.Fatalf("配额重置后应立即保存状态: %v", err)
+	}
+	if loaded.AccumulatedTime != 0 {
+		t.Fatalf("保存的状态应为重置后的累计时间 0，实际 %d", loaded.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_ResetLogsDailySummary(t *testing.T) {
+	controller, _, n, qState := createTestController(t)
+	controller.config.Notifications.DailySummary = true
+	readLoggedEvents(t, "")
+
+	qState.AddTime(5400)
+	qState.GameSeconds = map[string]int64{"game.exe": 3600, "other.exe": 1800}
+	qState.LimitNotified = true
+	qState.RecordTermination()
+	qState.RecordTermination()
+	qState.NextResetTime = time.Now().Add(-time.Second).Unix()
+	controller.tick()
+
+	summaries := readLoggedEvents(t, "daily_summary")
+	if len(summaries) != 1 {
+		t.Fatalf("重置时应记录一条 daily_summary，实际 %d 条", len(summaries))
+	}
+	summary := summaries[0]
+	if summary.Duration != 5400*1000 {
+		t.Errorf("汇总的累计时间应为 5400 秒，实际 %d 毫秒", summary.Duration)
+	}
+	if summary.Games["game.exe"] != 3600 || summary.Games["other.exe"] != 1800 {
+		t.Errorf("汇总的各游戏时间不符: %v", summary.Games)
+	}
+	if !summary.LimitReached || summary.Terminations != 2 {
+		t.Errorf("汇总应记录已超限与 2 次终止，实际 limitReached=%v terminations=%d", summary.LimitReached, summary.Terminations)
+	}
+	if n.summaryCalls != 1 || n.lastSummaryMinutes != 90 {
+		t.Errorf("启用 dailySummary 时应弹出一次 90 分钟的汇总，实际 %d 次、%d 分钟", n.summaryCalls, n.lastSummaryMinutes)
+	}
+	if got := qState.Summary(); got.PlayedSeconds != 0 || got.Terminations != 0 || len(got.GameSeconds) != 0 {
+		t.Errorf("重置后新周期的汇总应清零，实际 %+v", got)
+	}
+}
+
+func TestControllerTick_DailySummaryNotificationDisabled(t *testing.T) {
+	controller, _, n, qState := createTestController(t)
+	readLoggedEvents(t, "")
+
+	qState.AddTime(600)
+	qState.NextResetTime = time.Now().Add(-time.Second).Unix()
+	controller.tick()
+
+	if got := readLoggedEvents(t, "daily_summary"); len(got) != 1 {
+		t.Errorf("未启用通知时仍应记录 daily_summary，实际 %d 条", len(got))
+	}
+	if n.summaryCalls != 0 {
+		t.Errorf("未启用 notifications.dailySummary 时不应弹出汇总，实际 %d 次", n.summaryCalls)
+	}
+}
+
+func TestControllerTick_OutsideAllowedWindowTerminates(t *testing.T) {
+	controller, mock, n, _ := createTestController(t)
+	controller.config.Timezone = "UTC"
+	controller.config.Days = map[string]config.DayRule{
+		"all": {AllowedWindows: []string{"07:00-21:00"}},
+	}
+
+	now := time.Date(2026, 2, 9, 20, 59, 0, 0, time.UTC)
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: now}}, nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		return nil
+	}
+
+	controller.tick()
+	if len(terminated) != 0 {
+		t.Fatalf("允许时段内不应终止进程，实际终止 %v", terminated)
+	}
+
+	now = now.Add(time.Minute)
+	controller.tick()
+	if len(terminated) != 1 || terminated[0] != 100 {
+		t.Fatalf("允许时段外应终止游戏进程，实际终止 %v", terminated)
+	}
+	if n.limitCalls != 0 {
+		t.Errorf("时段限制不应触发超限弹窗，实际 %d 次", n.limitCalls)
+	}
+	if want := time.Date(2026, 2, 10, 7, 0, 0, 0, time.UTC); n.scheduleCalls != 1 || !n.lastNextAllowed.Equal(want) {
+		t.Errorf("应弹出一次时段外通知并附上开放时间 %v，实际 %d 次、%v", want, n.scheduleCalls, n.lastNextAllowed)
+	}
+
+	now = now.Add(time.Minute)
+	controller.tick()
+	if n.scheduleCalls != 1 {
+		t.Errorf("同一段时段外时间只应通知一次，实际 %d 次", n.scheduleCalls)
+	}
+
+	// 回到允许时段后，下次进入时段外重新通知
+	now = time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	controller.tick()
+	now = time.Date(2026, 2, 10, 21, 0, 0, 0, time.UTC)
+	controller.tick()
+	if n.scheduleCalls != 2 {
+		t.Errorf("再次进入时段外应重新通知，实际 %d 次", n.scheduleCalls)
+	}
+}
+
+func TestControllerTick_TerminateReasonRecorded(t *testing.T) {
+	tests := []struct {
+		name    string
+		onLimit string
+		setup   func(c *Controller, qState *quota.QuotaState)
+		event   string
+		reason  string
+	}{
+		{
+			name:   "超限",
+			setup:  func(c *Controller, qState *quota.QuotaState) { qState.AddTime(120 * 60) },
+			event:  "termination_result",
+			reason: reasonQuota,
+		},
+		{
+			name: "时段外",
+			setup: func(c *Controller, qState *quota.QuotaState) {
+				c.config.Days = map[string]config.DayRule{"all": {AllowedWindows: []string{"07:00-21:00"}}}
+			},
+			event:  "termination_result",
+			reason: reasonCurfew,
+		},
+		{
+			name:    "时段外忽略挂起设置",
+			onLimit: config.ActionSuspend,
+			setup: func(c *Controller, qState *quota.QuotaState) {
+				c.config.Days = map[string]config.DayRule{"all": {AllowedWindows: []string{"07:00-21:00"}}}
+			},
+			event:  "termination_result",
+			reason: reasonCurfew,
+		},
+		{
+			name: "强制休息",
+			setup: func(c *Controller, qState *quota.QuotaState) {
+				c.breakUntil = c.now().Add(10 * time.Minute)
+			},
+			event:  "termination_result",
+			reason: reasonBreak,
+		},
+		{
+			name:    "强制休息挂起",
+			onLimit: config.ActionSuspend,
+			setup: func(c *Controller, qState *quota.QuotaState) {
+				c.breakUntil = c.now().Add(10 * time.Minute)
+			},
+			event:  "limit_action",
+			reason: reasonBreak,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, mock, _, qState := createTestController(t)
+			controller.config.Timezone = "UTC"
+			controller.config.Enforcement.OnLimit = tt.onLimit
+			controller.suspendProcess = func(pid int) error { return nil }
+
+			now := time.Date(2026, 2, 9, 22, 0, 0, 0, time.UTC)
+			controller.SetClock(clock.Func(func() time.Time { return now }))
+			mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+				return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: now}}, nil
+			}
+			tt.setup(controller, qState)
+
+			readLoggedEvents(t, "")
+			controller.tick()
+			events := readLoggedEvents(t, tt.event)
+			if len(events) != 1 {
+				t.Fatalf("应记录一条 %s 事件，实际 %d 条", tt.event, len(events))
+			}
+			if events[0].Reason != tt.reason {
+				t.Errorf("terminate_reason 应为 %q，实际 %q", tt.reason, events[0].Reason)
+			}
+		})
+	}
+}
+
+func TestControllerTick_PerGameTimeBuckets(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Games = []string{"Game.exe", "other.exe"}
+
+	start := time.Now()
+	running := []process.ProcessInfo{
+		{PID: 1, Name: "game.exe", StartTime: start},
+		{PID: 2, Name: "GAME.EXE", StartTime: start},
+	}
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return running, nil
+	}
+
+	controller.tick()
+	controller.tick()
+	running = append(running, process.ProcessInfo{PID: 3, Name: "other.exe", StartTime: start})
+	controller.tick()
+
+	status := controller.GetStatus()
+	if status.GameTimes["Game.exe"] != 15 || status.GameTimes["other.exe"] != 5 {
+		t.Fatalf("各游戏应分别累计（按配置名去重），实际 %v", status.GameTimes)
+	}
+	if qState.AccumulatedTime != 15 {
+		t.Errorf("总累计时间仍按墙钟计算，预期 15 秒，实际 %d", qState.AccumulatedTime)
+	}
+
+	if err := qState.Reset(); err != nil {
+		t.Fatalf("重置配额失败: %v", err)
+	}
+	if games := qState.GetGameSeconds(); len(games) != 0 {
+		t.Errorf("配额重置后各游戏时间应一起清空，实际 %v", games)
+	}
+}
+
+func TestControllerTick_TitleMatchedGameTime(t *testing.T) {
+	controller, mock, _, _ := createTestController(t)
+	controller.config.Games = []string{"game.exe", "Title: Elden Ring"}
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{
+			{PID: 7, Name: "start_protected_game.exe", Match: "title:Elden Ring", StartTime: time.Now()},
+		}, nil
+	}
+
+	controller.tick()
+
+	status := controller.GetStatus()
+	if status.GameTimes["title:Elden Ring"] != 5 {
+		t.Errorf("按窗口标题匹配的游戏应按配置项计时，实际 %v", status.GameTimes)
+	}
+	if never := controller.neverSeenGames(); len(never) != 1 || never[0] != "game.exe" {
+		t.Errorf("按窗口标题匹配到的配置项应视为出现过，实际 %v", never)
+	}
+}
+
+func TestControllerTick_GameStopDurationMatchesSession(t *testing.T) {
+	controller, mock, _, _ := createTestController(t)
+	readLoggedEvents(t, "") // 清空之前测试写入的日志
+
+	start := time.Date(2026, 2, 12, 20, 0, 0, 0, time.Local)
+	now := start
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	// 创建时间偶尔读取失败（零值），不应把一次会话拆成多次开始/结束
+	running := true
+	scans := 0
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		if !running {
+			return nil, nil
+		}
+		scans++
+		proc := process.ProcessInfo{PID: 42, Name: "game.exe", StartTime: start.Add(-time.Minute)}
+		if scans%3 == 0 {
+			proc.StartTime = time.Time{}
+		}
+		return []process.ProcessInfo{proc}, nil
+	}
+
+	const played = 10 * time.Minute
+	for ; !now.After(start.Add(played)); now = now.Add(5 * time.Second) {
+		controller.tick()
+	}
+	if starts := readLoggedEvents(t, "game_start"); len(starts) != 1 {
+		t.Fatalf("应只记录一次 game_start，实际 %d 次", len(starts))
+	}
+
+	running = false
+	controller.tick()
+	stops := readLoggedEvents(t, "game_stop")
+	if len(stops) != 1 {
+		t.Fatalf("应只记录一次 game_stop，实际 %d 次", len(stops))
+	}
+	got := time.Duration(stops[0].Duration) * time.Millisecond
+	if diff := got - played; diff < -5*time.Second || diff > 5*time.Second {
+		t.Errorf("game_stop 时长应约为 %v（误差不超过一个扫描周期），实际 %v", played, got)
+	}
+}
+
+func TestControllerTick_RestartWithinTickKeepsCommittedTime(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+
+	start := time.Now()
+	now := start
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	game := process.ProcessInfo{PID: 100, Name: "game.exe", StartTime: start}
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{game}, nil
+	}
+	for i := 0; i < 4; i++ {
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+	if qState.AccumulatedTime != 20 {
+		t.Fatalf("重启前应累计 20 秒，实际 %d", qState.AccumulatedTime)
+	}
+
+	// 两次扫描之间关闭并重新打开游戏：旧 PID 消失、新 PID 出现
+	game = process.ProcessInfo{PID: 200, Name: "game.exe", StartTime: now}
+	for i := 0; i < 3; i++ {
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+	if qState.AccumulatedTime != 35 {
+		t.Errorf("重启游戏不应重置或丢失已计入的时间，应继续累计到 35 秒，实际 %d", qState.AccumulatedTime)
+	}
+
+	saved, err := quota.LoadFromFile(controller.config)
+	if err != nil || saved == nil {
+		t.Fatalf("读取状态文件失败: %v", err)
+	}
+	if saved.AccumulatedTime < 20 {
+		t.Errorf("游戏会话结束时应立即保存已计入的时间，状态文件中为 %d 秒", saved.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_RepeatedRestartsStillCounted(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Tracking.MinSessionSeconds = 30
+
+	start := time.Now()
+	now := start
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	// 每 20 秒重启一次游戏，单个会话永远达不到最短会话时长
+	pid := 100
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: pid, Name: "game.exe", StartTime: start}}, nil
+	}
+	for i := 0; i < 12; i++ {
+		if i > 0 && i%4 == 0 {
+			pid++
+		}
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+
+	if qState.AccumulatedTime != 60 {
+		t.Errorf("连续游戏超过最短会话时长后应计入全部时间，预期 60 秒，实际 %d", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_TransientDropIsContinuousSession(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Tracking.MinSessionSeconds = 20
+	controller.tracker.SetStopDebounce(1)
+	readLoggedEvents(t, "") // 清空之前测试写入的日志
+
+	start := time.Now()
+	now := start
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	game := process.ProcessInfo{PID: 7, Name: "game.exe", StartTime: start}
+	// 第 3 次扫描时进程短暂消失（如加载画面），下一次扫描又出现
+	scans := [][]process.ProcessInfo{{game}, {game}, nil, {game}, {game}}
+	for _, current := range scans {
+		mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+			return current, nil
+		}
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+
+	if starts := readLoggedEvents(t, "game_start"); len(starts) != 1 {
+		t.Fatalf("缺失一个周期应视为同一会话，实际记录 %d 次 game_start", len(starts))
+	}
+	if qState.AccumulatedTime != 20 {
+		t.Errorf("短暂消失不应清空最短会话前暂存的时间，预期累计 20 秒，实际 %d", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_ShortSessionsNotCounted(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Tracking.MinSessionSeconds = 30
+
+	start := time.Now()
+	now := start
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	running := true
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		if !running {
+			return nil, nil
+		}
+		return []process.ProcessInfo{{PID: 100, Name: "game.exe", StartTime: start}}, nil
+	}
+
+	readLoggedEvents(t, "short_session_ignored")
+	// 20 秒的短会话
+	for i := 0; i < 5; i++ {
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+	running = false
+	controller.tick()
+
+	if qState.AccumulatedTime != 0 {
+		t.Fatalf("短于最短会话时长的会话不应计时，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+	if events := readLoggedEvents(t, "short_session_ignored"); len(events) != 1 {
+		t.Fatalf("短会话结束应记录一次 short_session_ignored，实际 %d 次", len(events))
+	}
+
+	// 达到最短时长后，之前暂存的时间一并计入
+	start = now
+	running = true
+	for i := 0; i < 7; i++ {
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+	if qState.AccumulatedTime != 35 {
+		t.Fatalf("达到最短会话时长后应计入全部时间，预期 35 秒，实际 %d 秒", qState.AccumulatedTime)
+	}
+}
+
+func TestControllerTick_MonitorModeNeverTerminates(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+	controller.config.Enforcement.Mode = config.ModeMonitor
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+	terminateCalls := 0
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminateCalls++
+		return nil
+	}
+
+	readLoggedEvents(t, "would_terminate")
+	qState.AddTime(120 * 60)
+	controller.tick()
+
+	if terminateCalls != 0 {
+		t.Fatalf("监控模式下不应终止进程，实际终止 %d 次", terminateCalls)
+	}
+	if n.limitCalls != 1 {
+		t.Errorf("监控模式下仍应照常弹出超限提醒，实际 %d 次", n.limitCalls)
+	}
+	events := readLoggedEvents(t, "would_terminate")
+	if len(events) != 1 || events[0].PID != 1234 {
+		t.Fatalf("应记录一次带 PID 的 would_terminate 事件，实际 %+v", events)
+	}
+}
+
+func TestControllerTerminate_KillCommand(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Enforcement.KillCommands = map[string]string{"game.exe": "launcher.exe --quit {{.PID}} --name {{.Name}}"}
+
+	failKill := false
+	runner := &sysexec.FakeRunner{Handler: func(name string, args ...string) ([]byte, error) {
+		if failKill {
+			return []byte("launcher not running"), errors.New("exit status 1")
+		}
+		return nil, nil
+	}}
+	controller.killRunner = runner
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+	var taskkilled []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		taskkilled = append(taskkilled, pid)
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	controller.tick()
+	want := []string{"launcher.exe", "--quit", "1234", "--name", "game.exe"}
+	if len(runner.Calls) != 1 || !slices.Equal(runner.Calls[0], want) {
+		t.Fatalf("应运行展开后的结束命令 %v，实际 %v", want, runner.Calls)
+	}
+	if len(taskkilled) != 0 {
+		t.Fatalf("结束命令成功时不应再使用 taskkill，实际终止 %v", taskkilled)
+	}
+
+	// 结束命令失败时回退到 taskkill
+	failKill = true
+	controller.tick()
+	if len(taskkilled) != 1 || taskkilled[0] != 1234 {
+		t.Fatalf("结束命令失败时应改用 taskkill 终止 PID 1234，实际 %v", taskkilled)
+	}
+}
+
+func TestControllerTerminate_NoKillCommandUsesTaskkill(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	runner := &sysexec.FakeRunner{}
+	controller.killRunner = runner
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+	var taskkilled []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		taskkilled = append(taskkilled, pid)
+		return nil
+	}
+
+	qState.AddTime(120 * 60)
+	controller.tick()
+	if len(runner.Calls) != 0 {
+		t.Errorf("未配置结束命令时不应运行自定义命令，实际 %v", runner.Calls)
+	}
+	if len(taskkilled) != 1 || taskkilled[0] != 1234 {
+		t.Errorf("未配置结束命令时应使用 taskkill 终止 PID 1234，实际 %v", taskkilled)
+	}
+}
+
+func TestControllerTick_SlowScanWarnsOnce(t *testing.T) {
+	controller, _, _, _ := createTestController(t)
+	controller.config.Controller.SlowScanPercent = 1 // 阈值 50 毫秒
+
+	slow := true
+	runner := &sysexec.FakeRunner{Handler: func(name string, args ...string) ([]byte, error) {
+		if slow {
+			time.Sleep(60 * time.Millisecond)
+		}
+		return []byte(`"explorer.exe","100","Console","1","50,000 K"` + "\r\n"), nil
+	}}
+	controller.scanner = process.NewScannerWithRunner(runner)
+
+	readLoggedEvents(t, "slow_scan")
+	controller.tick()
+	controller.tick()
+	events := readLoggedEvents(t, "slow_scan")
+	if len(events) != 1 {
+		t.Fatalf("连续过慢的扫描应只记录一次 slow_scan，实际 %d 次", len(events))
+	}
+	if events[0].Duration < 60 {
+		t.Errorf("slow_scan 应记录扫描耗时（毫秒），实际 %d", events[0].Duration)
+	}
+	if controller.metrics.lastScanMillis < 60 {
+		t.Errorf("指标应记录最近一次扫描耗时，实际 %d 毫秒", controller.metrics.lastScanMillis)
+	}
+
+	// 恢复正常后再次变慢，应重新记录
+	slow = false
+	controller.tick()
+	slow = true
+	controller.tick()
+	if events := readLoggedEvents(t, "slow_scan"); len(events) != 1 {
+		t.Errorf("扫描恢复后再次变慢应重新记录 slow_scan，实际 %d 次", len(events))
+	}
+}
+
+func TestControllerTick_ScanFailureKeepsLastKnownProcesses(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+
+	scanErr := errors.New("tasklist 失败")
+	failing := false
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		if failing {
+			return nil, scanErr
+		}
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		return nil
+	}
+
+	controller.tick()
+	accumulated := qState.AccumulatedTime
+
+	failing = true
+	readLoggedEvents(t, "scanner_degraded")
+	for i := 0; i < scannerDegradedAfter-1; i++ {
+		controller.tick()
+	}
+	if events := readLoggedEvents(t, "scanner_degraded"); len(events) != 0 {
+		t.Fatalf("连续失败未达到 %d 次时不应记录 scanner_degraded", scannerDegradedAfter)
+	}
+	if qState.AccumulatedTime != accumulated {
+		t.Errorf("扫描失败期间不应累计时间，实际从 %d 变为 %d", accumulated, qState.AccumulatedTime)
+	}
+
+	// 扫描仍失败时超限，应对上次扫描到的游戏进程执行终止
+	qState.AddTime(120 * 60)
+	controller.tick()
+	if events := readLoggedEvents(t, "scanner_degraded"); len(events) != 1 {
+		t.Fatalf("连续失败 %d 次应记录一次 scanner_degraded，实际 %d 次", scannerDegradedAfter, len(events))
+	}
+	if len(terminated) != 1 || terminated[0] != 1234 {
+		t.Fatalf("应终止上次扫描到的 PID 1234，实际 %v", terminated)
+	}
+
+	controller.tick()
+	if events := readLoggedEvents(t, "scanner_degraded"); len(events) != 0 {
+		t.Errorf("持续失败时不应重复记录 scanner_degraded，实际 %d 次", len(events))
+	}
+
+	failing = false
+	controller.tick()
+	if controller.scanFailures != 0 {
+		t.Errorf("扫描恢复后应清零失败计数，实际 %d", controller.scanFailures)
+	}
+}
+
+func TestControllerTick_SoftLimitBandWarnsWithoutTerminating(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+	controller.config.TimeLimit.SoftLimit = 90
+
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.Local)
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: now}}, nil
+	}
+	terminateCalls := 0
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminateCalls++
+		return nil
+	}
+
+	readLoggedEvents(t, "soft_limit_exceeded")
+	qState.AddTime(90 * 60)
+
+	// 软限制与硬限制之间：提醒逐次变密（10 分钟、5 分钟），但不终止
+	for i := 0; i <= 12*15; i++ {
+		controller.tick()
+		now = now.Add(5 * time.Second)
+	}
+
+	if terminateCalls != 0 {
+		t.Fatalf("软限制与硬限制之间不应终止游戏，实际终止 %d 次", terminateCalls)
+	}
+	if n.softCalls != 3 {
+		t.Errorf("15 分钟内应提醒 3 次（0、10、15 分钟），实际 %d 次", n.softCalls)
+	}
+	if events := readLoggedEvents(t, "soft_limit_exceeded"); len(events) != n.softCalls {
+		t.Errorf("每次提醒都应记录 soft_limit_exceeded，实际 %d 条", len(events))
+	}
+
+	// 达到硬限制后终止
+	qState.AddTime(30 * 60)
+	controller.tick()
+	if terminateCalls != 1 {
+		t.Fatalf("达到硬限制后应终止游戏，实际终止 %d 次", terminateCalls)
+	}
+}
+
+func TestControllerTick_QuietHoursSuppressNotificationsOnly(t *testing.T) {
+	controller, mock, n, qState := createTestController(t)
+	controller.config.Timezone = "UTC"
+	controller.config.Notifications.QuietHours = "22:00-07:00"
+	now := time.Date(2026, 2, 9, 23, 0, 0, 0, time.UTC)
+	controller.SetClock(clock.Func(func() time.Time { return now }))
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe"}}, nil
+	}
+	terminated := 0
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated++
+		return nil
+	}
+
+	qState.AddTime(int64((120 - 14) * 60))
+	controller.tick()
+	if n.firstCalls != 0 {
+		t.Fatalf("静默时段内不应弹出首次警告，实际 %d", n.firstCalls)
+	}
+
+	qState.AddTime(14 * 60)
+	controller.tick()
+	if n.limitCalls != 0 {
+		t.Fatalf("静默时段内不应弹出超限提醒，实际 %d", n.limitCalls)
+	}
+	if terminated == 0 {
+		t.Fatal("静默时段内仍应终止超限的游戏")
+	}
+}
+
+func TestControllerTick_GameInOtherSessionCountedAndTerminated(t *testing.T) {
+	controller, _, _, qState := createTestController(t)
+
+	// 以会话 0 服务身份运行的守护进程，扫描到孩子在会话 2 中运行的游戏
+	running := true
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			if name == "taskkill" {
+				running = false
+				return nil, nil
+			}
+			out := `"game-control.exe","50","Services","0","20,000 K"` + "\r\n"
+			if running {
+				out += `"game.exe","1234","","2","120,000 K"` + "\r\n"
+			}
+			return []byte(out), nil
+		},
+	}
+	controller.scanner = process.NewScannerWithRunner(fake)
+
+	qState.AddTime(120*60 - 5)
+	controller.tick()
+
+	if qState.AccumulatedTime != 120*60 {
+		t.Fatalf("其他会话中的游戏应照常计时，实际累计 %d 秒", qState.AccumulatedTime)
+	}
+	var killed bool
+	for _, call := range fake.Calls {
+		if strings.Join(call, " ") == "taskkill /F /PID 1234" {
+			killed = true
+		}
+	}
+	if !killed || running {
+		t.Fatalf("超限后应终止其他会话中的游戏，调用记录 %v", fake.Calls)
+	}
+}
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"github.com/yourusername/game-control/pkg/logger"
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+// earnTime 奖励应用运行时按 earn.apps 的比例赚取游戏时间（增加当天的每日限制，不超过 earn.maxMinutes），
+// 每赚到整分钟记录 time_earned。有游戏在运行、用户空闲或屏幕锁定时不计入；
+// 同时运行多个奖励应用时只按比例最优（ratio 最小）的一个计入
+func (c *Controller) earnTime(earnApps, gameProcesses []process.ProcessInfo, seconds int64) {
+	if len(earnApps) == 0 || len(gameProcesses) > 0 || c.userIdle() || c.screenIsLocked() {
+		return
+	}
+
+	var app string
+	best := 0
+	for _, proc := range earnApps {
+		for _, name := range c.config.EarnNames() {
+			if !proc.Matches(name) {
+				continue
+			}
+			if ratio := c.config.EarnRatio(name); best == 0 || ratio < best {
+				app, best = name, ratio
+			}
+		}
+	}
+	if app == "" {
+		return
+	}
+
+	before, after := c.quotaState.AddEarnTime(app, seconds)
+	if after > before {
+		logger.LogTimeEarned(app, after-before, after)
+	}
+}
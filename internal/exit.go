@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/logger"
+)
+
+// 守护进程退出原因，记录在退出记录与 daemon_exit 事件中
+const (
+	ExitSignal  = "signal"  // 收到 SIGINT/SIGTERM（包括 stop 命令）
+	ExitContext = "context" // 嵌入方取消了 ctx
+	ExitPanic   = "panic"   // 控制循环崩溃
+	ExitKilled  = "killed"  // 上次运行没有留下退出记录：被强制结束、断电或崩溃时未能记录
+)
+
+// ExitRecord 守护进程最近一次运行的启动与退出记录，保存在状态文件旁的 .exit 文件中
+type ExitRecord struct {
+	StartedAt time.Time `json:"startedAt"`           // 最近一次启动的时间
+	StoppedAt time.Time `json:"stoppedAt,omitempty"` // 最近一次记录到退出的时间，早于 StartedAt 表示本次运行尚未记录退出
+	Reason    string    `json:"reason,omitempty"`    // 退出原因（Exit* 常量）
+	Detail    string    `json:"detail,omitempty"`    // 补充说明，如 panic 的值
+}
+
+// Stopped 判断记录中最近一次启动的运行是否已记录退出
+func (r ExitRecord) Stopped() bool {
+	return !r.StoppedAt.IsZero() && !r.StoppedAt.Before(r.StartedAt)
+}
+
+// ExitRecordPath 返回守护进程退出记录的路径（状态文件旁的 .exit 文件）
+func ExitRecordPath(cfg *config.Config) string {
+	return cfg.StateFile + ".exit"
+}
+
+// ReadExitRecord 读取退出记录，守护进程从未运行过时返回包含 os.ErrNotExist 的错误
+func ReadExitRecord(cfg *config.Config) (ExitRecord, error) {
+	var record ExitRecord
+	data, err := os.ReadFile(ExitRecordPath(cfg))
+	if err != nil {
+		return record, fmt.Errorf("读取退出记录失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, fmt.Errorf("解析退出记录失败: %w", err)
+	}
+	return record, nil
+}
+
+// writeExitRecord 写入退出记录，失败时只记录警告
+func writeExitRecord(cfg *config.Config, record ExitRecord) {
+	data, err := json.Marshal(record)
+	if err == nil {
+		err = os.WriteFile(ExitRecordPath(cfg), data, 0644)
+	}
+	if err != nil {
+		logger.Warnf("写入退出记录失败: %v", err)
+	}
+}
+
+// markDaemonStarted 在退出记录中记下本次启动时间。上次运行没有留下退出记录时，
+// 先记录一次 killed 的 daemon_exit 事件，说明上次运行是被强制结束或崩溃的
+func markDaemonStarted(cfg *config.Config) {
+	record, err := ReadExitRecord(cfg)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warnf("%v", err)
+	}
+	if err == nil && !record.Stopped() {
+		// 退出时间未知，StoppedAt 留空
+		logger.LogDaemonExit(ExitKilled, "")
+		record = ExitRecord{Reason: ExitKilled}
+	}
+	record.StartedAt = time.Now()
+	writeExitRecord(cfg, record)
+}
+
+// recordDaemonExit 记录守护进程退出的时间与原因，并记录 daemon_exit 事件
+func recordDaemonExit(cfg *config.Config, reason, detail string) {
+	logger.LogDaemonExit(reason, detail)
+
+	record, err := ReadExitRecord(cfg)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warnf("%v", err)
+	}
+	record.StoppedAt = time.Now()
+	record.Reason = reason
+	record.Detail = detail
+	writeExitRecord(cfg, record)
+}
+
+// guardDaemonExit 由守护进程主循环直接 defer 调用：控制循环崩溃时记录 panic 为退出原因，随后重新抛出
+func guardDaemonExit(cfg *config.Config) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	logger.Errorf("控制循环崩溃: %v\n%s", r, debug.Stack())
+	recordDaemonExit(cfg, ExitPanic, fmt.Sprint(r))
+	panic(r)
+}
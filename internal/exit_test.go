@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/logger"
+)
+
+func TestControllerRunContext_RecordsCleanExit(t *testing.T) {
+	controller, _, _, _ := createTestController(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	readLoggedEvents(t, "")
+	go func() { done <- controller.RunContext(ctx) }()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("取消 ctx 后控制循环应退出")
+	}
+
+	record, err := ReadExitRecord(controller.config)
+	if err != nil {
+		t.Fatalf("退出时应写入退出记录: %v", err)
+	}
+	if !record.Stopped() || record.Reason != ExitContext || record.StartedAt.IsZero() {
+		t.Errorf("退出记录应为 context 且晚于启动时间，实际 %+v", record)
+	}
+	events := readLoggedEvents(t, "daemon_exit")
+	if len(events) != 1 || events[0].ExitReason != ExitContext || events[0].Level != logger.LevelInfo {
+		t.Errorf("应记录一次 daemon_exit 事件，实际 %+v", events)
+	}
+}
+
+func TestGuardDaemonExit_RecordsPanic(t *testing.T) {
+	controller, _, _, _ := createTestController(t)
+	cfg := controller.config
+	markDaemonStarted(cfg)
+	readLoggedEvents(t, "")
+
+	var repanicked any
+	func() {
+		defer func() { repanicked = recover() }()
+		defer guardDaemonExit(cfg)
+		panic("boom")
+	}()
+
+	if repanicked != "boom" {
+		t.Fatalf("记录后应重新抛出 panic，实际 %v", repanicked)
+	}
+	record, err := ReadExitRecord(cfg)
+	if err != nil {
+		t.Fatalf("读取退出记录失败: %v", err)
+	}
+	if !record.Stopped() || record.Reason != ExitPanic || record.Detail != "boom" {
+		t.Errorf("崩溃时应记录 panic 及其值，实际 %+v", record)
+	}
+	events := readLoggedEvents(t, "daemon_exit")
+	if len(events) != 1 || events[0].ExitReason != ExitPanic || events[0].Level != logger.LevelError {
+		t.Errorf("崩溃应记录为错误级别的 daemon_exit 事件，实际 %+v", events)
+	}
+}
+
+func TestMarkDaemonStarted_DetectsUnrecordedExit(t *testing.T) {
+	controller, _, _, _ := createTestController(t)
+	cfg := controller.config
+
+	markDaemonStarted(cfg)
+	readLoggedEvents(t, "")
+	// 上次运行被强制结束，没有留下退出记录
+	markDaemonStarted(cfg)
+
+	events := readLoggedEvents(t, "daemon_exit")
+	if len(events) != 1 || events[0].ExitReason != ExitKilled {
+		t.Fatalf("上次运行未记录退出时应记录 killed 事件，实际 %+v", events)
+	}
+	record, err := ReadExitRecord(cfg)
+	if err != nil {
+		t.Fatalf("读取退出记录失败: %v", err)
+	}
+	if record.Reason != ExitKilled || !record.StoppedAt.IsZero() || record.Stopped() {
+		t.Errorf("退出时间未知时应只记录原因，实际 %+v", record)
+	}
+}
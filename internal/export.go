@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/logger"
+)
+
+// remainingExport 导出给叠加层（OBS、Rainmeter 等）轮询的剩余时间
+type remainingExport struct {
+	RemainingMinutes int    `json:"remainingMinutes"`
+	DailyLimit       int    `json:"dailyLimit"`
+	NextReset        string `json:"nextReset"` // RFC 3339
+}
+
+// exportRemaining 将剩余时间写入 export.remainingFile。内容未变化时不写文件，
+// 写入失败只在首次记录警告，下个周期重试
+func (c *Controller) exportRemaining() {
+	path := c.config.Export.RemainingFile
+	if path == "" {
+		return
+	}
+
+	data, err := json.Marshal(remainingExport{
+		RemainingMinutes: c.quotaState.GetRemainingMinutes(),
+		DailyLimit:       int(c.config.RuleFor(c.now()).DailyLimit),
+		NextReset:        time.Unix(c.quotaState.NextResetTime, 0).Format(time.RFC3339),
+	})
+	if err != nil {
+		logger.Errorf("序列化剩余时间失败: %v", err)
+		return
+	}
+	data = append(data, '\n')
+	if bytes.Equal(data, c.lastExport) {
+		return
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		if !c.exportFailed {
+			logger.Warnf("写入剩余时间文件失败: %v", err)
+		}
+		c.exportFailed = true
+		return
+	}
+	c.exportFailed = false
+	c.lastExport = data
+}
+
+// writeFileAtomic 先写入同目录下的临时文件再重命名，读取方不会看到写了一半的内容
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("关闭临时文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("替换 %s 失败: %w", path, err)
+	}
+	return nil
+}
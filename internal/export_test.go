@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+func readRemainingExport(t *testing.T, path string) remainingExport {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取剩余时间文件失败: %v", err)
+	}
+	var export remainingExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("解析剩余时间文件失败: %v\n%s", err, data)
+	}
+	return export
+}
+
+func TestControllerTick_ExportsRemainingMinutes(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	path := filepath.Join(t.TempDir(), "remaining.json")
+	controller.config.Export.RemainingFile = path
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1234, Name: "game.exe"}}, nil
+	}
+
+	// 每分钟（12 个周期）检查一次文件是否跟随剩余时间变化
+	for minute := 1; minute <= 3; minute++ {
+		for i := 0; i < 12; i++ {
+			controller.tick()
+		}
+		export := readRemainingExport(t, path)
+		if export.RemainingMinutes != qState.GetRemainingMinutes() {
+			t.Fatalf("第 %d 分钟导出剩余 %d 分钟，实际剩余 %d 分钟", minute, export.RemainingMinutes, qState.GetRemainingMinutes())
+		}
+		if export.RemainingMinutes != 120-minute {
+			t.Fatalf("第 %d 分钟应剩余 %d 分钟，导出 %d", minute, 120-minute, export.RemainingMinutes)
+		}
+	}
+
+	export := readRemainingExport(t, path)
+	if export.DailyLimit != 120 {
+		t.Errorf("导出的每日限制应为 120，实际 %d", export.DailyLimit)
+	}
+	next, err := time.Parse(time.RFC3339, export.NextReset)
+	if err != nil || next.Unix() != qState.NextResetTime {
+		t.Errorf("导出的下次重置时间不正确: %q, %v", export.NextReset, err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("原子写入后不应残留临时文件，实际 %d 个文件", len(entries))
+	}
+}
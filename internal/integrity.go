@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/logger"
+)
+
+// fileFingerprint 文件的修改时间、大小与内容摘要
+type fileFingerprint struct {
+	modTime time.Time
+	size    int64
+	hash    string
+}
+
+// WatchConfigFile 记录已加载配置文件的摘要，运行期间被外部修改时记录 config_tampered 事件
+func (c *Controller) WatchConfigFile(path string) error {
+	fp, err := fingerprint(path)
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件摘要: %w", err)
+	}
+	c.configPath = path
+	c.configPrint = fp
+	return nil
+}
+
+// checkIntegrity 检查配置文件是否被外部修改，以及状态文件是否被删除或清空
+func (c *Controller) checkIntegrity() {
+	c.checkConfigFile()
+	c.checkStateFile()
+}
+
+// checkConfigFile 配置文件的修改时间或大小变化时重新计算摘要，内容不同则视为篡改
+func (c *Controller) checkConfigFile() {
+	if c.configPath == "" {
+		return
+	}
+
+	info, err := os.Stat(c.configPath)
+	if err == nil && info.ModTime().Equal(c.configPrint.modTime) && info.Size() == c.configPrint.size {
+		return
+	}
+
+	fp, err := fingerprint(c.configPath)
+	if err != nil {
+		fp = fileFingerprint{}
+	}
+	if fp.hash != c.configPrint.hash {
+		logger.LogConfigTampered(c.configPath)
+	}
+	// 只报告一次，之后以新内容为基准
+	c.configPrint = fp
+}
+
+// checkStateFile 状态文件已保存过却被删除或清空时，用内存中的状态重新写入
+func (c *Controller) checkStateFile() {
+	if !c.stateSaved {
+		return
+	}
+
+	info, err := os.Stat(c.config.StateFile)
+	if err == nil && info.Size() > 0 {
+		return
+	}
+
+	logger.LogStateTampered(c.config.StateFile, "被删除或清空，已用内存中的状态重写")
+	c.saveNow()
+}
+
+// fingerprint 计算文件指纹
+func fingerprint(path string) (fileFingerprint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileFingerprint{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fileFingerprint{}, err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fileFingerprint{}, err
+	}
+
+	return fileFingerprint{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		hash:    hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
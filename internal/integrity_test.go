@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/clock"
+)
+
+func TestCheckIntegrity_DetectsConfigChange(t *testing.T) {
+	controller, _, _, _ := createTestController(t)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("dailyLimit: 120\n"), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	if err := controller.WatchConfigFile(configPath); err != nil {
+		t.Fatalf("WatchConfigFile 失败: %v", err)
+	}
+
+	readLoggedEvents(t, "config_tampered")
+	controller.tick()
+	if events := readLoggedEvents(t, "config_tampered"); len(events) != 0 {
+		t.Fatalf("配置未修改时不应报告篡改，实际 %d 次", len(events))
+	}
+
+	if err := os.WriteFile(configPath, []byte("dailyLimit: 6000\n"), 0644); err != nil {
+		t.Fatalf("修改配置文件失败: %v", err)
+	}
+	controller.tick()
+	controller.tick()
+	if events := readLoggedEvents(t, "config_tampered"); len(events) != 1 {
+		t.Fatalf("配置被外部修改后应报告一次篡改，实际 %d 次", len(events))
+	}
+}
+
+func TestCheckIntegrity_RewritesDeletedState(t *testing.T) {
+	controller, _, _, qState := createTestController(t)
+
+	qState.AddTime(600)
+	controller.saveNow()
+	if err := os.Remove(controller.config.StateFile); err != nil {
+		t.Fatalf("删除状态文件失败: %v", err)
+	}
+
+	readLoggedEvents(t, "state_tampered")
+	controller.SetClock(clock.Func(func() time.Time { return controller.lastSaveTime }))
+	controller.tick()
+
+	if events := readLoggedEvents(t, "state_tampered"); len(events) != 1 {
+		t.Fatalf("状态文件被删除后应报告一次，实际 %d 次", len(events))
+	}
+	data, err := os.ReadFile(controller.config.StateFile)
+	if err != nil || len(data) == 0 {
+		t.Fatalf("状态文件应已用内存状态重写: %v", err)
+	}
+}
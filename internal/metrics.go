@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/logger"
+)
+
+// metrics 控制器运行指标，以 Prometheus 文本格式通过 /metrics 暴露
+type metrics struct {
+	mu                 sync.Mutex
+	accumulatedMinutes int
+	remainingMinutes   int
+	dailyLimit         int
+	activeGames        int
+	terminationsToday  int
+	lastScanMillis     int
+}
+
+// update 每次循环后更新配额相关指标
+func (m *metrics) update(accumulated, remaining, limit, activeGames int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accumulatedMinutes = accumulated
+	m.remainingMinutes = remaining
+	m.dailyLimit = limit
+	m.activeGames = activeGames
+}
+
+// recordTermination 记录一次成功终止的游戏进程
+func (m *metrics) recordTermination() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.terminationsToday++
+}
+
+// recordScan 记录最近一次进程扫描的耗时
+func (m *metrics) recordScan(took time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastScanMillis = int(took.Milliseconds())
+}
+
+// resetDaily 配额重置时清零当天计数
+func (m *metrics) resetDaily() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.terminationsToday = 0
+}
+
+// ServeHTTP 输出 Prometheus 文本格式（text/plain; version=0.0.4）
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	gauges := []struct {
+		name, help string
+		value      int
+	}{
+		{"gamecontrol_accumulated_minutes", "今日累计游戏时间（分钟）", m.accumulatedMinutes},
+		{"gamecontrol_remaining_minutes", "今日剩余游戏时间（分钟）", m.remainingMinutes},
+		{"gamecontrol_daily_limit_minutes", "今日生效的每日限制（分钟）", m.dailyLimit},
+		{"gamecontrol_active_games", "当前运行的游戏进程数", m.activeGames},
+		{"gamecontrol_terminations_today", "今日已终止的游戏进程数", m.terminationsToday},
+		{"gamecontrol_last_scan_milliseconds", "最近一次进程扫描的耗时（毫秒）", m.lastScanMillis},
+	}
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, g := range gauges {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", g.name, g.help, g.name, g.name, g.value)
+	}
+}
+
+// scanTimer 能报告最近一次扫描耗时的扫描器，由 process.Scanner 实现
+type scanTimer interface {
+	LastScanDuration() time.Duration
+}
+
+// scanDuration 返回 scanner 最近一次扫描的耗时，扫描器不支持计时时返回 0
+func scanDuration(scanner ProcessScanner) time.Duration {
+	if timer, ok := scanner.(scanTimer); ok {
+		return timer.LastScanDuration()
+	}
+	return 0
+}
+
+// checkScanDuration 记录最近一次扫描的耗时，过慢时记录 slow_scan
+func (c *Controller) checkScanDuration() {
+	took := scanDuration(c.scanner)
+	c.metrics.recordScan(took)
+	c.slowScan = reportSlowScan(c.config, took, c.slowScan)
+}
+
+// reportSlowScan 扫描耗时超过 controller.slowScanPercent 对应的阈值时记录 slow_scan，reported 表示此前已记录过，
+// 扫描恢复正常前只记录一次；返回新的 reported
+func reportSlowScan(cfg *config.Config, took time.Duration, reported bool) bool {
+	threshold := cfg.Controller.SlowScanThreshold(tickInterval)
+	if took <= threshold {
+		return false
+	}
+	if !reported {
+		logger.LogSlowScan(took, threshold)
+	}
+	return true
+}
+
+// startHTTP 按配置启动 HTTP 服务，未启用任何端点时返回 nil
+func (c *Controller) startHTTP() *http.Server {
+	if !c.config.HTTP.MetricsEnabled {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c.metrics)
+
+	server := &http.Server{
+		Addr:              c.config.HTTP.ListenAddr(),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("HTTP 服务启动失败: %v", err)
+		}
+	}()
+	logger.Infof("指标端点: http://%s/metrics", server.Addr)
+	return server
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+func TestMetricsHandlerExposesGauges(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1, Name: "game.exe", StartTime: time.Now()}}, nil
+	}
+	qState.AddTime(120 * 60)
+	controller.tick()
+
+	server := httptest.NewServer(controller.metrics)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("抓取指标失败: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	for _, want := range []string{
+		"gamecontrol_accumulated_minutes 120",
+		"gamecontrol_remaining_minutes 0",
+		"gamecontrol_daily_limit_minutes 120",
+		"gamecontrol_active_games 1",
+		"gamecontrol_terminations_today 1",
+		"# TYPE gamecontrol_active_games gauge",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("指标输出应包含 %q，实际:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"context"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/logger"
+	"github.com/yourusername/game-control/pkg/notifier"
+	"github.com/yourusername/game-control/pkg/process"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+// ownerScanner 只返回属于指定账户的游戏进程，供单个档案的控制器查询状态时使用
+type ownerScanner struct {
+	ProcessScanner
+	users []string
+}
+
+// FilterByOwner 包装 scanner，只返回所有者属于 users 的游戏进程。
+// scanner 需已开启所有者查询（process.Scanner.SetQueryOwners）
+func FilterByOwner(scanner ProcessScanner, users []string) ProcessScanner {
+	return ownerScanner{ProcessScanner: scanner, users: users}
+}
+
+func (s ownerScanner) FindGameProcesses(gameNames []string) ([]process.ProcessInfo, error) {
+	processes, err := s.ProcessScanner.FindGameProcesses(gameNames)
+	if err != nil {
+		return nil, err
+	}
+	return ownedBy(processes, s.users), nil
+}
+
+// ownedBy 筛选所有者属于 users 的进程
+func ownedBy(processes []process.ProcessInfo, users []string) []process.ProcessInfo {
+	owned := make([]process.ProcessInfo, 0, len(processes))
+	for _, proc := range processes {
+		if process.MatchesOwner(proc.Owner, users) {
+			owned = append(owned, proc)
+		}
+	}
+	return owned
+}
+
+// matchingGames 筛选匹配 gameNames 中任一项的进程（按映像名或窗口标题匹配项，不区分大小写）
+func matchingGames(processes []process.ProcessInfo, gameNames []string) []process.ProcessInfo {
+	matched := make([]process.ProcessInfo, 0, len(processes))
+	for _, proc := range processes {
+		for _, name := range gameNames {
+			if proc.Matches(name) {
+				matched = append(matched, proc)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// profileController 多档案模式中的一个档案
+type profileController struct {
+	name       string
+	users      []string
+	controller *Controller
+}
+
+// MultiController 在一个守护进程中管理多个档案：每个周期只扫描一次游戏进程，
+// 按进程所属账户分给各档案，各档案使用自己的配额状态独立计时与执行限制
+type MultiController struct {
+	config    *config.Config
+	scanner   ProcessScanner
+	gameNames []string
+	profiles  []profileController
+	slowScan  bool
+}
+
+// NewMultiController 为 cfg.Profiles 中的每个档案创建控制器，states 为按档案名索引的配额状态
+func NewMultiController(cfg *config.Config, states map[string]*quota.QuotaState) (*MultiController, error) {
+	scanner := defaultScanner(cfg)
+	scanner.SetQueryOwners(true)
+	return newMultiController(cfg, states, scanner, defaultNotifier(cfg))
+}
+
+func newMultiController(
+	cfg *config.Config,
+	states map[string]*quota.QuotaState,
+	scanner ProcessScanner,
+	n notifier.Notifier,
+) (*MultiController, error) {
+	m := &MultiController{config: cfg, scanner: scanner}
+
+	seen := make(map[string]bool)
+	for _, p := range cfg.Profiles {
+		pcfg, err := cfg.ForProfile(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		qState := states[p.Name]
+		if qState == nil {
+			if qState, err = quota.NewQuotaState(pcfg); err != nil {
+				return nil, err
+			}
+		}
+
+		controller := NewControllerWithDeps(pcfg, qState, FilterByOwner(scanner, p.Users), n)
+		m.profiles = append(m.profiles, profileController{name: p.Name, users: p.Users, controller: controller})
+
+		for _, name := range pcfg.GameNames() {
+			if key := strings.ToLower(name); !seen[key] {
+				seen[key] = true
+				m.gameNames = append(m.gameNames, name)
+			}
+		}
+	}
+	return m, nil
+}
+
+// WatchConfigFile 记录配置文件指纹，运行期间被修改时记录 config_tampered（只由第一个档案检查，避免重复记录）
+func (m *MultiController) WatchConfigFile(path string) error {
+	if len(m.profiles) == 0 {
+		return nil
+	}
+	return m.profiles[0].controller.WatchConfigFile(path)
+}
+
+// Run 运行多档案主控制循环，收到 SIGINT/SIGTERM 时保存状态并退出
+func (m *MultiController) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err := m.run(ctx, ExitSignal)
+	_ = logger.Close()
+	return err
+}
+
+// RunContext 运行多档案主控制循环，ctx 结束时保存所有档案的状态并返回（不处理信号，也不关闭日志）
+func (m *MultiController) RunContext(ctx context.Context) error {
+	return m.run(ctx, ExitContext)
+}
+
+// run 运行多档案主控制循环，退出原因记录在顶层配置的退出记录中（见 Controller.run）
+func (m *MultiController) run(ctx context.Context, stopReason string) error {
+	defer guardDaemonExit(m.config)
+	markDaemonStarted(m.config)
+
+	logger.Infof("游戏时间控制守护进程启动（%d 个档案）", len(m.profiles))
+	for _, p := range m.profiles {
+		logger.Infof("档案 %s: 账户 %v，每日时间限制 %d 分钟，游戏进程列表 %v",
+			p.name, p.users, p.controller.config.RuleFor(p.controller.now()).DailyLimit, p.controller.config.Games)
+	}
+	if m.config.HTTP.MetricsEnabled {
+		logger.Warnf("多档案模式暂不支持 /metrics 端点，已忽略 http.metricsEnabled")
+	}
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	relaunch, stopRelaunch := relaunchTicks(m.config)
+	defer stopRelaunch()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.tick()
+
+		case <-relaunch:
+			m.guardRelaunch()
+
+		case <-ctx.Done():
+			logger.Infof("正在关闭...")
+			m.cleanup()
+			recordDaemonExit(m.config, stopReason, "")
+			return nil
+		}
+	}
+}
+
+// tick 扫描一次游戏进程，按所有者与游戏列表分给各档案处理；不属于任何档案的进程不计时。
+// 禁止运行的进程不区分档案，检测到即终止
+func (m *MultiController) tick() {
+	snapshot, err := takeSnapshot(m.scanner, slices.Concat(m.gameNames, m.config.ProhibitedNames(), m.config.EarnNames()))
+	m.slowScan = reportSlowScan(m.config, scanDuration(m.scanner), m.slowScan)
+	if err != nil {
+		for _, p := range m.profiles {
+			p.locked(func(c *Controller) { c.process(nil, nil, err) })
+		}
+		return
+	}
+	gameProcesses, prohibited, earnApps := classify(snapshot, m.gameNames, m.config.ProhibitedNames(), m.config.EarnNames())
+	terminateProhibited(m.config, m.scanner, prohibited)
+	for _, p := range m.profiles {
+		owned := ownedBy(slices.Concat(gameProcesses, earnApps), p.users)
+		p.locked(func(c *Controller) {
+			c.process(matchingGames(owned, c.config.GameNames()), matchingGames(owned, c.config.EarnNames()), nil)
+		})
+	}
+}
+
+// locked 持有档案控制器的 mu 执行 f，与该控制器的 GetStatus 等外部调用串行，如同 Controller.tick
+func (p profileController) locked(f func(c *Controller)) {
+	p.controller.mu.Lock()
+	defer p.controller.mu.Unlock()
+	f(p.controller)
+}
+
+// guardRelaunch 有档案正在阻止重新启动时扫描一次游戏进程，按所有者分给各档案立即终止重新启动的游戏
+func (m *MultiController) guardRelaunch() {
+	blocking := false
+	for _, p := range m.profiles {
+		p.locked(func(c *Controller) { blocking = blocking || c.blockingRelaunch() })
+	}
+	if !blocking {
+		return
+	}
+
+	gameProcesses, err := m.scanner.FindGameProcesses(m.gameNames)
+	if err != nil {
+		logger.Debugf("检查重新启动的游戏进程失败: %v", err)
+		return
+	}
+	for _, p := range m.profiles {
+		p.locked(func(c *Controller) {
+			owned := matchingGames(ownedBy(gameProcesses, p.users), c.config.GameNames())
+			c.blockRelaunches(c.withoutPausedGames(owned))
+		})
+	}
+}
+
+// cleanup 保存所有档案的状态
+func (m *MultiController) cleanup() {
+	logger.Infof("正在保存状态...")
+	for _, p := range m.profiles {
+		if err := p.controller.saveState(); err != nil {
+			logger.Errorf("保存档案 %s 的状态失败: %v", p.name, err)
+		}
+	}
+
+	logger.Infof("游戏时间控制守护进程已关闭")
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/process"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+func createTestMultiController(t *testing.T) (*MultiController, *mockScanner, map[string]*quota.QuotaState) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		DailyLimit:     120,
+		ResetTime:      "08:00",
+		Games:          []string{"game.exe"},
+		FirstThreshold: 15,
+		FinalThreshold: 5,
+		StateFile:      filepath.Join(tempDir, "state.json"),
+		LogFile:        filepath.Join(tempDir, "test.log"),
+		Profiles: []config.ProfileConfig{
+			{Name: "alice", Users: []string{`PC\alice`}},
+			{Name: "bob", Users: []string{`PC\bob`}, Games: []string{"other.exe"}, DailyLimit: 60},
+		},
+	}
+
+	states := make(map[string]*quota.QuotaState)
+	for _, p := range cfg.Profiles {
+		pcfg, err := cfg.ForProfile(p.Name)
+		if err != nil {
+			t.Fatalf("ForProfile(%s) 失败: %v", p.Name, err)
+		}
+		qState, err := quota.NewQuotaState(pcfg)
+		if err != nil {
+			t.Fatalf("创建档案 %s 的配额状态失败: %v", p.Name, err)
+		}
+		states[p.Name] = qState
+	}
+
+	mock := &mockScanner{}
+	m, err := newMultiController(cfg, states, mock, &fakeNotifier{})
+	if err != nil {
+		t.Fatalf("newMultiController 失败: %v", err)
+	}
+	return m, mock, states
+}
+
+func TestMultiControllerTick_AttributesProcessesByOwner(t *testing.T) {
+	m, mock, states := createTestMultiController(t)
+
+	var scanned []string
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		scanned = games
+		return []process.ProcessInfo{
+			{PID: 1, Name: "game.exe", Owner: `PC\alice`},
+			{PID: 2, Name: "other.exe", Owner: `pc\BOB`},
+			{PID: 3, Name: "game.exe", Owner: `PC\bob`},    // 不在 bob 的游戏列表中
+			{PID: 4, Name: "other.exe", Owner: `PC\carol`}, // 不属于任何档案
+		}, nil
+	}
+
+	m.tick()
+
+	if len(scanned) != 2 {
+		t.Fatalf("应只扫描一次所有档案的游戏并集，实际 %v", scanned)
+	}
+	for _, p := range m.profiles {
+		seen := p.controller.lastGameProcesses
+		want := map[string]int{"alice": 1, "bob": 2}[p.name]
+		if len(seen) != 1 || seen[0].PID != want {
+			t.Errorf("档案 %s 应只看到 PID %d，实际 %v", p.name, want, seen)
+		}
+	}
+	for name, qState := range states {
+		if qState.AccumulatedTime == 0 {
+			t.Errorf("档案 %s 有游戏运行，应累计时间", name)
+		}
+	}
+}
+
+func TestMultiControllerTick_EnforcesOneProfileOnly(t *testing.T) {
+	m, mock, states := createTestMultiController(t)
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{
+			{PID: 10, Name: "game.exe", Owner: `PC\alice`, StartTime: time.Now()},
+			{PID: 20, Name: "other.exe", Owner: `PC\bob`, StartTime: time.Now()},
+		}, nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		return nil
+	}
+
+	states["bob"].AddTime(60 * 60)
+	m.tick()
+
+	if len(terminated) != 1 || terminated[0] != 20 {
+		t.Fatalf("应只终止超限档案 bob 的进程，实际 %v", terminated)
+	}
+	if states["alice"].IsLimitExceeded() {
+		t.Fatal("档案 alice 不应受 bob 超限影响")
+	}
+}
+
+func TestMultiControllerTick_HoldsProfileLock(t *testing.T) {
+	m, mock, states := createTestMultiController(t)
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{{PID: 1, Name: "game.exe", Owner: `PC\alice`}}, nil
+	}
+
+	// 控制命令、GetStatus 等持有档案控制器的 mu 时，tick 应等待而不是并发修改该档案的状态
+	alice := m.profiles[0].controller
+	alice.mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		m.tick()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("档案控制器被锁定时 tick 不应处理该档案")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if states["alice"].GetAccumulatedSeconds() != 0 {
+		t.Fatal("释放锁之前不应累计档案 alice 的时间")
+	}
+	alice.mu.Unlock()
+	<-done
+	if states["alice"].GetAccumulatedSeconds() == 0 {
+		t.Error("释放锁后应处理档案 alice")
+	}
+}
+
+func TestMultiControllerRunContext_CancelSavesAllProfiles(t *testing.T) {
+	m, _, states := createTestMultiController(t)
+	states["alice"].AddTime(60)
+	states["bob"].AddTime(120)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.RunContext(ctx); err != nil {
+		t.Fatalf("RunContext 应正常返回，实际 %v", err)
+	}
+
+	want := map[string]int64{"alice": 60, "bob": 120}
+	for _, p := range m.profiles {
+		saved, err := quota.LoadFromFile(p.controller.config)
+		if err != nil {
+			t.Fatalf("档案 %s 退出时应保存状态: %v", p.name, err)
+		}
+		if saved.AccumulatedTime != want[p.name] {
+			t.Errorf("档案 %s 保存的累计时间应为 %d 秒，实际 %d", p.name, want[p.name], saved.AccumulatedTime)
+		}
+	}
+}
+
+func TestMatchingGames_TitleMatch(t *testing.T) {
+	processes := []process.ProcessInfo{
+		{PID: 1, Name: "javaw.exe", Match: "title:Minecraft"},
+		{PID: 2, Name: "javaw.exe"},
+		{PID: 3, Name: "GAME.exe"},
+	}
+
+	matched := matchingGames(processes, []string{"game.exe", "title:minecraft"})
+	if len(matched) != 2 || matched[0].PID != 1 || matched[1].PID != 3 {
+		t.Errorf("应按映像名与窗口标题匹配项筛选，实际 %+v", matched)
+	}
+}
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"slices"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/logger"
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+// scanNames 返回每个周期要扫描的进程名：游戏、禁止运行的进程与奖励应用在同一次扫描中查找
+func scanNames(cfg *config.Config) []string {
+	return slices.Concat(cfg.GameNames(), cfg.ProhibitedNames(), cfg.EarnNames())
+}
+
+// refuseCritical 进程为系统关键进程或本程序时记录 refused_terminate_critical 并返回 true，调用方应跳过该进程
+func refuseCritical(proc process.ProcessInfo) bool {
+	err := process.CheckTerminable(proc)
+	if err == nil {
+		return false
+	}
+	logger.LogRefusedTerminateCritical(proc.Name, proc.PID, err)
+	return true
+}
+
+// terminateProhibited 终止禁止运行的进程，不受配额与允许时段影响（豁免 PID 与关键进程除外，监控模式下只记录）
+func terminateProhibited(cfg *config.Config, scanner ProcessScanner, prohibited []process.ProcessInfo) {
+	for _, proc := range prohibited {
+		if slices.Contains(cfg.Enforcement.ExemptPids, proc.PID) || refuseCritical(proc) {
+			continue
+		}
+		logger.LogProhibitedProcess(proc.Name, proc.PID)
+		if cfg.Enforcement.MonitorOnly() {
+			logger.LogWouldTerminate(proc.Name, proc.PID)
+			continue
+		}
+		if err := scanner.TerminateWithRetry(proc.PID, 3, 1*time.Second); err != nil {
+			logger.Errorf("终止禁止运行的进程失败 (PID: %d): %v", proc.PID, err)
+		}
+	}
+}
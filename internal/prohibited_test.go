@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+func TestControllerTick_TerminatesProhibitedProcesses(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Enforcement.Prohibited = []string{"RunAsDate.exe"}
+
+	var scanned []string
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		scanned = games
+		return []process.ProcessInfo{
+			{PID: 1, Name: "game.exe"},
+			{PID: 2, Name: "runasdate.exe"},
+		}, nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		return nil
+	}
+	readLoggedEvents(t, "prohibited_process")
+
+	controller.tick()
+
+	if len(scanned) != 2 {
+		t.Fatalf("应在同一次扫描中查找游戏与禁止运行的进程，实际 %v", scanned)
+	}
+	if len(terminated) != 1 || terminated[0] != 2 {
+		t.Fatalf("未超限时应只终止禁止运行的进程，实际 %v", terminated)
+	}
+	if qState.AccumulatedTime != 5 {
+		t.Errorf("只有游戏进程计时，应累计 5 秒，实际 %d", qState.AccumulatedTime)
+	}
+	if len(controller.lastGameProcesses) != 1 || controller.lastGameProcesses[0].PID != 1 {
+		t.Errorf("禁止运行的进程不应作为游戏进程跟踪，实际 %v", controller.lastGameProcesses)
+	}
+
+	events := readLoggedEvents(t, "prohibited_process")
+	if len(events) != 1 || events[0].PID != 2 {
+		t.Fatalf("应记录一次 prohibited_process 事件，实际 %+v", events)
+	}
+}
+
+func TestControllerTick_ProhibitedProcessMonitorMode(t *testing.T) {
+	controller, mock, _, _ := createTestController(t)
+	controller.config.Enforcement.Prohibited = []string{"taskkill-helper.exe"}
+	controller.config.Enforcement.Mode = config.ModeMonitor
+	controller.config.Enforcement.ExemptPids = []int{8}
+
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return []process.ProcessInfo{
+			{PID: 7, Name: "taskkill-helper.exe"},
+			{PID: 8, Name: "taskkill-helper.exe"},
+		}, nil
+	}
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		t.Fatalf("监控模式下不应终止进程 (PID: %d)", pid)
+		return nil
+	}
+	readLoggedEvents(t, "would_terminate")
+
+	controller.tick()
+
+	events := readLoggedEvents(t, "would_terminate")
+	if len(events) != 1 || events[0].PID != 7 {
+		t.Fatalf("监控模式下应只为非豁免进程记录 would_terminate，实际 %+v", events)
+	}
+}
+
+func TestControllerTick_RefusesToTerminateCriticalProcesses(t *testing.T) {
+	controller, mock, _, qState := createTestController(t)
+	controller.config.Games = []string{"game.exe", "explorer.exe"}
+	controller.config.Enforcement.Prohibited = []string{"svchost.exe"}
+
+	running := []process.ProcessInfo{
+		{PID: 1, Name: "game.exe"},
+		{PID: 2, Name: "Explorer.EXE"},
+		{PID: 3, Name: "svchost.exe"},
+	}
+	mock.findGameProcessesFunc = func(games []string) ([]process.ProcessInfo, error) {
+		return matchingGames(running, games), nil
+	}
+	var terminated []int
+	mock.terminateWithRetryFn = func(pid int, maxRetries int, retryDelay time.Duration) error {
+		terminated = append(terminated, pid)
+		return nil
+	}
+	readLoggedEvents(t, "refused_terminate_critical")
+
+	qState.AddTime(120 * 60)
+	controller.tick()
+
+	if len(terminated) != 1 || terminated[0] != 1 {
+		t.Fatalf("只应终止普通游戏进程，实际终止 %v", terminated)
+	}
+	events := readLoggedEvents(t, "refused_terminate_critical")
+	if len(events) != 2 || events[0].PID != 3 || events[1].PID != 2 {
+		t.Errorf("应为禁止运行列表与游戏列表中的关键进程各记录一次 refused_terminate_critical，实际 %+v", events)
+	}
+}
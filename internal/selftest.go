@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/clock"
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/process"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+// selfTestMaxTicks 自检最多执行的控制循环次数：每次计 5 秒，1 分钟的每日限制需要 12 次，其余为余量
+const selfTestMaxTicks = 30
+
+// SelfTestOptions 自检参数
+type SelfTestOptions struct {
+	GameName string         // 自检目标进程的映像名，作为唯一的游戏
+	PID      int            // 自检目标进程的 PID
+	Dir      string         // 存放自检状态文件的临时目录
+	Scanner  ProcessScanner // 为 nil 时使用真实的进程扫描与终止
+}
+
+// SelfTestStep 自检中的一步及其结果
+type SelfTestStep struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+// RunSelfTest 以加速的控制循环端到端验证扫描、计时与终止：将目标进程配置为唯一的游戏、每日限制 1 分钟，
+// 每次循环把时钟推进一个扫描间隔，依次检查进程被发现、时间被累计、达到限制后被终止。
+// 不弹出桌面提醒，也不读写正式的状态文件；返回的错误只表示无法开始自检
+func RunSelfTest(opts SelfTestOptions) ([]SelfTestStep, error) {
+	clk := clock.NewManual(time.Now())
+	cfg := selfTestConfig(opts, clk.Now())
+	qState, err := quota.NewQuotaStateWithClock(cfg, clk)
+	if err != nil {
+		return nil, fmt.Errorf("创建自检配额状态失败: %w", err)
+	}
+	scanner := opts.Scanner
+	if scanner == nil {
+		scanner = process.NewScanner()
+	}
+	n := &selfTestNotifier{}
+	c := NewControllerWithDeps(cfg, qState, scanner, n)
+	c.SetClock(clk)
+
+	var steps []SelfTestStep
+	for i := 0; i < selfTestMaxTicks; i++ {
+		if i > 0 {
+			clk.Advance(tickInterval)
+		}
+		c.tick()
+		if i == 0 {
+			found := hasPID(c.lastGameProcesses, opts.PID)
+			detail := fmt.Sprintf("%s (PID: %d)", opts.GameName, opts.PID)
+			if !found {
+				detail = i18n.T("selftest.notFound", opts.GameName, opts.PID)
+			}
+			steps = append(steps, SelfTestStep{Name: i18n.T("selftest.step.found"), OK: found, Detail: detail})
+			if !found {
+				return steps, nil
+			}
+		}
+		if qState.Summary().Terminations > 0 || n.accessDenied || n.terminationFailed {
+			break
+		}
+	}
+
+	seconds := qState.GetAccumulatedSeconds()
+	steps = append(steps,
+		SelfTestStep{Name: i18n.T("selftest.step.accrued"), OK: seconds > 0, Detail: i18n.T("selftest.accrued", seconds)},
+		SelfTestStep{Name: i18n.T("selftest.step.limit"), OK: qState.IsLimitExceeded(), Detail: i18n.T("selftest.limit", cfg.DailyLimit)},
+		selfTestTermination(scanner, opts, qState.Summary().Terminations, n),
+	)
+	return steps, nil
+}
+
+// selfTestConfig 自检使用的配置：只监控目标进程，每日限制 1 分钟、没有宽限期，重置时间与开始时间相隔 12 小时
+func selfTestConfig(opts SelfTestOptions, now time.Time) *config.Config {
+	return &config.Config{
+		DailyLimit: 1,
+		ResetTime:  now.Add(12 * time.Hour).Format("15:04"),
+		Games:      []string{opts.GameName},
+		StateFile:  filepath.Join(opts.Dir, "selftest-state.json"),
+		LogFile:    filepath.Join(opts.Dir, "selftest.log"),
+	}
+}
+
+// selfTestTermination 检查目标进程是否已被终止并且不再出现在扫描结果中
+func selfTestTermination(scanner ProcessScanner, opts SelfTestOptions, terminations int, n *selfTestNotifier) SelfTestStep {
+	step := SelfTestStep{Name: i18n.T("selftest.step.terminated")}
+	switch {
+	case n.accessDenied:
+		step.Detail = i18n.T("selftest.accessDenied")
+		return step
+	case n.terminationFailed:
+		step.Detail = i18n.T("selftest.terminationFailed")
+		return step
+	case terminations == 0:
+		step.Detail = i18n.T("selftest.notTerminated")
+		return step
+	}
+
+	processes, err := scanner.FindGameProcesses([]string{opts.GameName})
+	if err != nil {
+		step.Detail = i18n.T("selftest.rescanFailed", err)
+		return step
+	}
+	if hasPID(processes, opts.PID) {
+		step.Detail = i18n.T("selftest.stillRunning")
+		return step
+	}
+	step.OK = true
+	step.Detail = i18n.T("selftest.terminated", opts.PID)
+	return step
+}
+
+// hasPID 判断进程列表中是否有 pid
+func hasPID(processes []process.ProcessInfo, pid int) bool {
+	for _, proc := range processes {
+		if proc.PID == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// selfTestNotifier 自检期间代替桌面提醒，只记录与终止相关的通知
+type selfTestNotifier struct {
+	accessDenied      bool
+	terminationFailed bool
+}
+
+func (n *selfTestNotifier) NotifyFirstWarning(int) error          { return nil }
+func (n *selfTestNotifier) NotifyFinalWarning(int) error          { return nil }
+func (n *selfTestNotifier) NotifyLimitExceeded(time.Time) error   { return nil }
+func (n *selfTestNotifier) NotifySoftLimit(int, int) error        { return nil }
+func (n *selfTestNotifier) NotifyBreak(int, time.Time) error      { return nil }
+func (n *selfTestNotifier) NotifyOutsideSchedule(time.Time) error { return nil }
+func (n *selfTestNotifier) NotifyDailySummary(int, bool, int, map[string]int) error {
+	return nil
+}
+
+func (n *selfTestNotifier) NotifyTerminationFailed() error {
+	n.terminationFailed = true
+	return nil
+}
+
+func (n *selfTestNotifier) NotifyAccessDenied(string) error {
+	n.accessDenied = true
+	return nil
+}
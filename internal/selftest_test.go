@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+// selfTestScanner 模拟自检目标进程：被终止前一直出现在扫描结果中
+func selfTestScanner(name string, pid int, terminateErr error) *mockScanner {
+	alive := true
+	return &mockScanner{
+		findGameProcessesFunc: func([]string) ([]process.ProcessInfo, error) {
+			if !alive {
+				return nil, nil
+			}
+			return []process.ProcessInfo{{Name: name, PID: pid}}, nil
+		},
+		terminateWithRetryFn: func(int, int, time.Duration) error {
+			if terminateErr != nil {
+				return terminateErr
+			}
+			alive = false
+			return nil
+		},
+	}
+}
+
+func TestRunSelfTest_Passes(t *testing.T) {
+	steps, err := RunSelfTest(SelfTestOptions{
+		GameName: "gamectl-selftest.exe",
+		PID:      4242,
+		Dir:      t.TempDir(),
+		Scanner:  selfTestScanner("gamectl-selftest.exe", 4242, nil),
+	})
+	if err != nil {
+		t.Fatalf("RunSelfTest 失败: %v", err)
+	}
+	if len(steps) != 4 {
+		t.Fatalf("应有发现、计时、限制、终止 4 步，实际 %+v", steps)
+	}
+	for _, step := range steps {
+		if !step.OK {
+			t.Errorf("步骤 %s 应通过: %s", step.Name, step.Detail)
+		}
+	}
+}
+
+func TestRunSelfTest_ProcessNotFound(t *testing.T) {
+	steps, err := RunSelfTest(SelfTestOptions{
+		GameName: "gamectl-selftest.exe",
+		PID:      4242,
+		Dir:      t.TempDir(),
+		Scanner:  &mockScanner{},
+	})
+	if err != nil {
+		t.Fatalf("RunSelfTest 失败: %v", err)
+	}
+	if len(steps) != 1 || steps[0].OK {
+		t.Fatalf("未发现进程时应在第一步失败并停止，实际 %+v", steps)
+	}
+}
+
+func TestRunSelfTest_AccessDenied(t *testing.T) {
+	steps, err := RunSelfTest(SelfTestOptions{
+		GameName: "gamectl-selftest.exe",
+		PID:      4242,
+		Dir:      t.TempDir(),
+		Scanner:  selfTestScanner("gamectl-selftest.exe", 4242, process.ErrAccessDenied),
+	})
+	if err != nil {
+		t.Fatalf("RunSelfTest 失败: %v", err)
+	}
+	last := steps[len(steps)-1]
+	if last.Name != "终止进程" || last.OK {
+		t.Fatalf("权限不足时终止步骤应失败，实际 %+v", last)
+	}
+	if !steps[1].OK || !steps[2].OK {
+		t.Errorf("计时与达到限制应通过，实际 %+v", steps)
+	}
+}
@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+// snapshotScanner 能一次枚举进程、在快照上多次匹配的扫描器，由 process.Scanner 实现
+type snapshotScanner interface {
+	Snapshot() (*process.ProcessSnapshot, error)
+}
+
+// takeSnapshot 每个周期只枚举一次进程。扫描器不支持快照时（如测试替身或嵌入方提供的扫描器），
+// 按 names 查找一次并在结果上构造快照
+func takeSnapshot(scanner ProcessScanner, names []string) (*process.ProcessSnapshot, error) {
+	if s, ok := scanner.(snapshotScanner); ok {
+		return s.Snapshot()
+	}
+	processes, err := scanner.FindGameProcesses(names)
+	if err != nil {
+		return nil, err
+	}
+	return process.NewMatchedSnapshot(processes), nil
+}
+
+// classify 在同一份快照上分别匹配游戏、禁止运行的进程与奖励应用：
+// 同时是游戏与禁止运行的进程按禁止运行处理，同时是游戏与奖励应用的进程按游戏处理
+func classify(snapshot *process.ProcessSnapshot, gameNames, prohibitedNames, earnNames []string) (games, prohibited, earnApps []process.ProcessInfo) {
+	prohibited = snapshot.Find(prohibitedNames)
+	taken := make(map[int]bool, len(prohibited))
+	for _, proc := range prohibited {
+		taken[proc.PID] = true
+	}
+	for _, proc := range snapshot.Find(gameNames) {
+		if !taken[proc.PID] {
+			games = append(games, proc)
+			taken[proc.PID] = true
+		}
+	}
+	for _, proc := range snapshot.Find(earnNames) {
+		if !taken[proc.PID] {
+			earnApps = append(earnApps, proc)
+		}
+	}
+	return games, prohibited, earnApps
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/process"
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+func TestControllerTick_EnumeratesProcessesOnce(t *testing.T) {
+	controller, _, _, qState := createTestController(t)
+	controller.config.Enforcement.Mode = config.ModeMonitor
+	controller.config.Enforcement.Prohibited = []string{"RunAsDate.exe"}
+	controller.config.Earn = config.EarnConfig{Apps: []config.EarnApp{{Name: "anki.exe", Ratio: 2}}, MaxMinutes: 30}
+
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte(`"game.exe","1","Console","1","90,000 K"` + "\r\n" +
+				`"RunAsDate.exe","2","Console","1","5,000 K"` + "\r\n" +
+				`"anki.exe","3","Console","1","50,000 K"` + "\r\n"), nil
+		},
+	}
+	controller.scanner = process.NewScannerWithRunner(fake)
+	readLoggedEvents(t, "")
+
+	controller.tick()
+
+	if len(fake.Calls) != 1 || fake.Calls[0][0] != "tasklist" {
+		t.Fatalf("每个周期应只枚举一次进程，实际调用 %v", fake.Calls)
+	}
+	if len(controller.lastGameProcesses) != 1 || controller.lastGameProcesses[0].PID != 1 {
+		t.Errorf("游戏匹配应只得到 game.exe，实际 %+v", controller.lastGameProcesses)
+	}
+	if qState.AccumulatedTime != 5 {
+		t.Errorf("游戏进程应计时 5 秒，实际 %d", qState.AccumulatedTime)
+	}
+	if events := readLoggedEvents(t, "prohibited_process"); len(events) != 1 || events[0].PID != 2 {
+		t.Errorf("同一份快照中的禁止运行进程应被发现，实际 %+v", events)
+	}
+}
+
+func TestClassify_Precedence(t *testing.T) {
+	snapshot := process.NewMatchedSnapshot([]process.ProcessInfo{
+		{PID: 1, Name: "game.exe"},
+		{PID: 2, Name: "cheat.exe"},
+		{PID: 3, Name: "anki.exe"},
+	})
+
+	// cheat.exe 同时是游戏与禁止运行的进程，anki.exe 同时是游戏与奖励应用
+	games, prohibited, earnApps := classify(snapshot,
+		[]string{"game.exe", "cheat.exe", "anki.exe"}, []string{"cheat.exe"}, []string{"anki.exe"})
+
+	if len(prohibited) != 1 || prohibited[0].PID != 2 {
+		t.Errorf("同时是游戏的禁止运行进程应按禁止运行处理，实际 %+v", prohibited)
+	}
+	if len(games) != 2 || games[0].PID != 1 || games[1].PID != 3 {
+		t.Errorf("游戏应为 game.exe 与 anki.exe，实际 %+v", games)
+	}
+	if len(earnApps) != 0 {
+		t.Errorf("同时是游戏的奖励应用应按游戏处理，实际 %+v", earnApps)
+	}
+}
@@ -0,0 +1,113 @@
+// Package admin 提供家长密码的哈希与校验，用于保护停止守护进程等会解除限制的命令
+package admin
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 哈希格式：pbkdf2-sha256$<迭代次数>$<盐>$<派生密钥>，盐与密钥为无填充的 base64。
+// 标准库没有 bcrypt，这里使用 PBKDF2-HMAC-SHA256（RFC 8018），以免引入额外依赖
+const (
+	hashScheme = "pbkdf2-sha256"
+	iterations = 210000
+	saltLen    = 16
+	keyLen     = 32
+)
+
+var (
+	// ErrWrongPassword 密码与哈希不匹配
+	ErrWrongPassword = errors.New("密码错误")
+	// ErrEmptyPassword 密码为空
+	ErrEmptyPassword = errors.New("密码不能为空")
+)
+
+var encoding = base64.RawStdEncoding
+
+// HashPassword 使用随机盐生成密码哈希，结果可直接写入 admin.passwordHash
+func HashPassword(password string) (string, error) {
+	if password == "" {
+		return "", ErrEmptyPassword
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("生成随机盐失败: %w", err)
+	}
+	key := pbkdf2SHA256([]byte(password), salt, iterations, keyLen)
+	return fmt.Sprintf("%s$%d$%s$%s", hashScheme, iterations, encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// VerifyPassword 校验密码，不匹配时返回 ErrWrongPassword，哈希格式无效时返回其他错误
+func VerifyPassword(hash, password string) error {
+	iter, salt, want, err := parseHash(hash)
+	if err != nil {
+		return err
+	}
+	got := pbkdf2SHA256([]byte(password), salt, iter, len(want))
+	if subtle.ConstantTimeCompare(got, want) != 1 {
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+// CheckHash 检查哈希格式是否有效，用于配置校验
+func CheckHash(hash string) error {
+	_, _, _, err := parseHash(hash)
+	return err
+}
+
+func parseHash(hash string) (iter int, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != hashScheme {
+		return 0, nil, nil, fmt.Errorf("无效的密码哈希格式，请使用 set-password 命令生成")
+	}
+	if iter, err = strconv.Atoi(parts[1]); err != nil || iter <= 0 {
+		return 0, nil, nil, fmt.Errorf("无效的密码哈希迭代次数: %q", parts[1])
+	}
+	if salt, err = encoding.DecodeString(parts[2]); err != nil || len(salt) == 0 {
+		return 0, nil, nil, fmt.Errorf("无效的密码哈希盐值")
+	}
+	if key, err = encoding.DecodeString(parts[3]); err != nil || len(key) == 0 {
+		return 0, nil, nil, fmt.Errorf("无效的密码哈希值")
+	}
+	return iter, salt, key, nil
+}
+
+// pbkdf2SHA256 按 RFC 8018 计算 PBKDF2-HMAC-SHA256
+func pbkdf2SHA256(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	key := make([]byte, 0, blocks*hashLen)
+	var counter [4]byte
+	u := make([]byte, hashLen)
+	t := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter[:])
+		u = prf.Sum(u[:0])
+		copy(t, u)
+
+		for i := 1; i < iter; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestPBKDF2SHA256KnownVector(t *testing.T) {
+	// RFC 7914 第 11 节的 PBKDF2-HMAC-SHA256 测试向量
+	got := pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+		"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if hex.EncodeToString(got) != want {
+		t.Fatalf("PBKDF2 结果不正确: %x", got)
+	}
+}
+
+func TestVerifyPassword(t *testing.T) {
+	hash, err := HashPassword("parent-secret")
+	if err != nil {
+		t.Fatalf("HashPassword 失败: %v", err)
+	}
+
+	if err := VerifyPassword(hash, "parent-secret"); err != nil {
+		t.Fatalf("正确的密码应校验通过: %v", err)
+	}
+	if err := VerifyPassword(hash, "guess"); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("错误的密码应返回 ErrWrongPassword，实际 %v", err)
+	}
+	if err := VerifyPassword(hash, ""); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("空密码应返回 ErrWrongPassword，实际 %v", err)
+	}
+
+	other, _ := HashPassword("parent-secret")
+	if other == hash {
+		t.Fatal("相同密码的两次哈希应使用不同的盐")
+	}
+}
+
+func TestVerifyPassword_InvalidHash(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"$2a$10$bcrypthashvalue",
+		"pbkdf2-sha256$0$c2FsdA$a2V5",
+		"pbkdf2-sha256$1000$!!$a2V5",
+	} {
+		err := VerifyPassword(hash, "x")
+		if err == nil || errors.Is(err, ErrWrongPassword) {
+			t.Errorf("无效哈希 %q 应返回格式错误，实际 %v", hash, err)
+		}
+		if CheckHash(hash) == nil {
+			t.Errorf("CheckHash(%q) 应报错", hash)
+		}
+	}
+	if _, err := HashPassword(""); !errors.Is(err, ErrEmptyPassword) {
+		t.Fatalf("空密码不应生成哈希，实际 %v", err)
+	}
+}
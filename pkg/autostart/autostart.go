@@ -0,0 +1,38 @@
+package autostart
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+// TaskName 自启动任务名称（与 scripts/windows/*.bat 保持一致）
+const TaskName = "GameControlAutostart"
+
+// defaultRunner 执行 schtasks/systemctl 命令
+var defaultRunner sysexec.CommandRunner = sysexec.ExecRunner{}
+
+// InstallTask 安装开机/登录自启动任务，按当前平台选择实现
+func InstallTask(exePath, configPath string) error {
+	switch runtime.GOOS {
+	case "windows":
+		return installScheduledTask(defaultRunner, exePath, configPath)
+	case "linux":
+		return installSystemdUnit(defaultRunner, exePath, configPath)
+	default:
+		return fmt.Errorf("当前平台不支持自启动: %s", runtime.GOOS)
+	}
+}
+
+// RemoveTask 移除自启动任务，按当前平台选择实现
+func RemoveTask() error {
+	switch runtime.GOOS {
+	case "windows":
+		return removeScheduledTask(defaultRunner)
+	case "linux":
+		return removeSystemdUnit(defaultRunner)
+	default:
+		return fmt.Errorf("当前平台不支持自启动: %s", runtime.GOOS)
+	}
+}
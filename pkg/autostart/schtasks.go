@@ -0,0 +1,27 @@
+package autostart
+
+import (
+	"fmt"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+// installScheduledTask 通过 schtasks 创建登录时以最高权限运行的计划任务
+func installScheduledTask(runner sysexec.CommandRunner, exePath, configPath string) error {
+	taskCmd := fmt.Sprintf("\"%s\" start \"%s\"", exePath, configPath)
+	output, err := runner.Run("schtasks", "/Create", "/F", "/SC", "ONLOGON", "/RL", "HIGHEST",
+		"/TN", TaskName, "/TR", taskCmd)
+	if err != nil {
+		return fmt.Errorf("创建计划任务失败: %w, 输出: %s", err, string(output))
+	}
+	return nil
+}
+
+// removeScheduledTask 删除计划任务
+func removeScheduledTask(runner sysexec.CommandRunner) error {
+	output, err := runner.Run("schtasks", "/Delete", "/F", "/TN", TaskName)
+	if err != nil {
+		return fmt.Errorf("删除计划任务失败: %w, 输出: %s", err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package autostart
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+func TestInstallScheduledTask_Args(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	if err := installScheduledTask(fake, `C:\Game Control\game-control.exe`, `C:\Game Control\config.yaml`); err != nil {
+		t.Fatalf("installScheduledTask 失败: %v", err)
+	}
+
+	want := []string{
+		"schtasks", "/Create", "/F", "/SC", "ONLOGON", "/RL", "HIGHEST",
+		"/TN", TaskName, "/TR", `"C:\Game Control\game-control.exe" start "C:\Game Control\config.yaml"`,
+	}
+	if got := fake.Calls[0]; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("schtasks 参数不正确:\n实际 %q\n预期 %q", got, want)
+	}
+}
+
+func TestRemoveScheduledTask_Args(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	if err := removeScheduledTask(fake); err != nil {
+		t.Fatalf("removeScheduledTask 失败: %v", err)
+	}
+	if got := strings.Join(fake.Calls[0], " "); got != "schtasks /Delete /F /TN "+TaskName {
+		t.Errorf("schtasks 参数不正确: %s", got)
+	}
+}
+
+func TestScheduledTask_Error(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte("错误: 拒绝访问。"), errors.New("exit status 1")
+		},
+	}
+	err := removeScheduledTask(fake)
+	if err == nil || !strings.Contains(err.Error(), "拒绝访问") {
+		t.Fatalf("失败时错误应包含命令输出，实际 %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+// unitName systemd 单元名称
+const unitName = "game-control.service"
+
+// systemdScope 描述单元安装位置与 systemctl 调用方式
+type systemdScope struct {
+	dir      string   // 单元文件目录
+	ctlArgs  []string // systemctl 前置参数（用户单元为 --user）
+	wantedBy string   // [Install] 段的目标
+}
+
+// currentSystemdScope root 安装为系统单元，否则安装为用户单元
+func currentSystemdScope() (systemdScope, error) {
+	if os.Geteuid() == 0 {
+		return systemdScope{
+			dir:      "/etc/systemd/system",
+			wantedBy: "multi-user.target",
+		}, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return systemdScope{}, fmt.Errorf("无法定位用户配置目录: %w", err)
+	}
+	return systemdScope{
+		dir:      filepath.Join(configDir, "systemd", "user"),
+		ctlArgs:  []string{"--user"},
+		wantedBy: "default.target",
+	}, nil
+}
+
+// unitFileContent 生成 systemd 单元文件内容
+func unitFileContent(exePath, configPath, wantedBy string) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=Game time control daemon\n")
+	b.WriteString("After=network.target\n")
+	b.WriteString("\n")
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=simple\n")
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", quoteSystemdArg(filepath.Dir(configPath)))
+	fmt.Fprintf(&b, "ExecStart=%s start %s\n", quoteSystemdArg(exePath), quoteSystemdArg(configPath))
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("\n")
+	b.WriteString("[Install]\n")
+	fmt.Fprintf(&b, "WantedBy=%s\n", wantedBy)
+	return b.String()
+}
+
+// quoteSystemdArg 按 systemd 规则给参数加引号，转义反斜杠与双引号
+func quoteSystemdArg(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// installSystemdUnit 写入单元文件并启用
+func installSystemdUnit(runner sysexec.CommandRunner, exePath, configPath string) error {
+	scope, err := currentSystemdScope()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(scope.dir, 0755); err != nil {
+		return fmt.Errorf("无法创建单元目录: %w", err)
+	}
+
+	path := filepath.Join(scope.dir, unitName)
+	content := unitFileContent(exePath, configPath, scope.wantedBy)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("无法写入单元文件: %w", err)
+	}
+
+	if err := runSystemctl(runner, scope, "daemon-reload"); err != nil {
+		return err
+	}
+	return runSystemctl(runner, scope, "enable", "--now", unitName)
+}
+
+// removeSystemdUnit 停用并删除单元文件
+func removeSystemdUnit(runner sysexec.CommandRunner) error {
+	scope, err := currentSystemdScope()
+	if err != nil {
+		return err
+	}
+
+	if err := runSystemctl(runner, scope, "disable", "--now", unitName); err != nil {
+		return err
+	}
+
+	path := filepath.Join(scope.dir, unitName)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("无法删除单元文件: %w", err)
+	}
+
+	return runSystemctl(runner, scope, "daemon-reload")
+}
+
+func runSystemctl(runner sysexec.CommandRunner, scope systemdScope, args ...string) error {
+	fullArgs := append(append([]string{}, scope.ctlArgs...), args...)
+	output, err := runner.Run("systemctl", fullArgs...)
+	if err != nil {
+		return fmt.Errorf("执行 systemctl %s 失败: %w, 输出: %s",
+			strings.Join(fullArgs, " "), err, string(output))
+	}
+	return nil
+}
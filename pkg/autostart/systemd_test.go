@@ -0,0 +1,73 @@
+package autostart
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+func TestUnitFileContent(t *testing.T) {
+	content := unitFileContent("/opt/game control/game-control", "/etc/game-control/config.yaml", "default.target")
+
+	wantExec := `ExecStart="/opt/game control/game-control" start "/etc/game-control/config.yaml"`
+	if !strings.Contains(content, wantExec+"\n") {
+		t.Fatalf("单元文件应包含 %q，实际为:\n%s", wantExec, content)
+	}
+
+	for _, section := range []string{"[Unit]", "[Service]", "[Install]"} {
+		if !strings.Contains(content, section+"\n") {
+			t.Errorf("单元文件缺少段 %s", section)
+		}
+	}
+	if !strings.Contains(content, "WantedBy=default.target\n") {
+		t.Error("单元文件应包含 WantedBy=default.target")
+	}
+
+	// 除段标题与空行外，每一行都应为 key=value 形式
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		if line == "" || strings.HasPrefix(line, "[") {
+			continue
+		}
+		if !strings.Contains(line, "=") {
+			t.Errorf("无效的单元文件行: %q", line)
+		}
+	}
+}
+
+func TestQuoteSystemdArg(t *testing.T) {
+	tests := map[string]string{
+		"/usr/bin/game-control": `"/usr/bin/game-control"`,
+		`a"b`:                   `"a\"b"`,
+		`C:\path`:               `"C:\\path"`,
+	}
+	for input, want := range tests {
+		if got := quoteSystemdArg(input); got != want {
+			t.Errorf("quoteSystemdArg(%q) = %q，预期 %q", input, got, want)
+		}
+	}
+}
+
+func TestRunSystemctl_UserScope(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	scope := systemdScope{ctlArgs: []string{"--user"}}
+
+	if err := runSystemctl(fake, scope, "enable", "--now", unitName); err != nil {
+		t.Fatalf("runSystemctl 失败: %v", err)
+	}
+	if got := strings.Join(fake.Calls[0], " "); got != "systemctl --user enable --now "+unitName {
+		t.Errorf("systemctl 参数不正确: %s", got)
+	}
+	if len(scope.ctlArgs) != 1 {
+		t.Errorf("不应修改 scope.ctlArgs，实际 %v", scope.ctlArgs)
+	}
+
+	fake.Handler = func(name string, args ...string) ([]byte, error) {
+		return nil, errors.New("exit status 1")
+	}
+	err := runSystemctl(fake, systemdScope{}, "daemon-reload")
+	if err == nil || !strings.Contains(err.Error(), "systemctl daemon-reload") {
+		t.Errorf("失败时错误应包含执行的命令，实际 %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+// Package clock 提供可替换的时钟，使依赖当前时间的逻辑（每日重置、提醒阈值、计时）可以在测试中精确控制时间
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 当前时间的来源
+type Clock interface {
+	Now() time.Time
+	// Since 返回自 t 以来经过的时间，等价于 Now().Sub(t)
+	Since(t time.Time) time.Duration
+}
+
+// Real 使用系统时间的时钟
+type Real struct{}
+
+func (Real) Now() time.Time { return time.Now() }
+
+func (Real) Since(t time.Time) time.Duration { return time.Since(t) }
+
+// Func 将返回当前时间的函数适配为 Clock
+type Func func() time.Time
+
+func (f Func) Now() time.Time { return f() }
+
+func (f Func) Since(t time.Time) time.Duration { return f().Sub(t) }
+
+// ManualClock 手动拨动的时钟，时间只在调用 Set 或 Advance 时变化，用于测试。可并发使用
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManual 创建停在 now 的手动时钟
+func NewManual(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (m *ManualClock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+func (m *ManualClock) Since(t time.Time) time.Duration {
+	return m.Now().Sub(t)
+}
+
+// Set 将时钟拨到 t
+func (m *ManualClock) Set(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = t
+}
+
+// Advance 将时钟向前拨 d，返回拨动后的时间
+func (m *ManualClock) Advance(d time.Duration) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+	return m.now
+}
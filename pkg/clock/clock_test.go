@@ -0,0 +1,39 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2026, 2, 9, 7, 59, 0, 0, time.UTC)
+	c := NewManual(start)
+
+	if !c.Now().Equal(start) {
+		t.Fatalf("时钟应停在 %v，实际 %v", start, c.Now())
+	}
+	if got := c.Advance(90 * time.Second); !got.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("Advance 应返回拨动后的时间，实际 %v", got)
+	}
+	if got := c.Since(start); got != 90*time.Second {
+		t.Errorf("Since 应为 90s，实际 %v", got)
+	}
+
+	c.Set(start)
+	if !c.Now().Equal(start) {
+		t.Errorf("Set 后应回到 %v，实际 %v", start, c.Now())
+	}
+}
+
+func TestFunc(t *testing.T) {
+	now := time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC)
+	var c Clock = Func(func() time.Time { return now })
+
+	if got := c.Since(now.Add(-time.Minute)); got != time.Minute {
+		t.Errorf("Since 应按函数返回的时间计算，实际 %v", got)
+	}
+	now = now.Add(time.Hour)
+	if !c.Now().Equal(now) {
+		t.Errorf("Now 应每次调用函数，实际 %v", c.Now())
+	}
+}
@@ -1,28 +1,333 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/yourusername/game-control/pkg/admin"
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/notifier"
 )
 
+// CurrentVersion 当前配置结构版本，新增/迁移字段时递增
+const CurrentVersion = 1
+
 // Config 应用配置
 type Config struct {
-	DailyLimit     int      `yaml:"dailyLimit"`     // 每日游戏时间限制（分钟）
-	ResetTime      string   `yaml:"resetTime"`      // 格式: "08:00"
-	Games          []string `yaml:"games"`          // 游戏进程名称列表
-	FirstThreshold int      `yaml:"firstThreshold"` // 第一次警告阈值（分钟）
-	FinalThreshold int      `yaml:"finalThreshold"` // 最后警告阈值（分钟）
-	StateFile      string   `yaml:"stateFile"`      // 状态文件路径
-	LogFile        string   `yaml:"logFile"`        // 日志文件路径
+	Version        int      `yaml:"version"`    // 配置结构版本，缺省视为 0
+	DailyLimit     Minutes  `yaml:"dailyLimit"` // 每日游戏时间限制（分钟，也可写 "2h30m"）
+	ResetTime      string   `yaml:"resetTime"`  // 格式: "08:00"
+	Games          []string `yaml:"games"`      // 游戏进程名称列表
+	FirstThreshold Minutes  `yaml:"-"`          // 第一次警告阈值（分钟），加载时由 firstThreshold 换算
+	FinalThreshold Minutes  `yaml:"-"`          // 最后警告阈值（分钟），加载时由 finalThreshold 换算
+	StateFile      string   `yaml:"stateFile"`  // 状态文件路径
+	LogFile        string   `yaml:"logFile"`    // 日志文件路径
+	Timezone       string   `yaml:"timezone"`   // 重置时间所在时区（IANA 名称，如 "Asia/Shanghai"），为空时使用本地时区
+	Language       string   `yaml:"language"`   // 命令行输出、通知与事件日志的语言：zh（默认）| en
+
+	// CountForegroundOnly 仅在游戏窗口处于前台时累计时间，无法判断前台时回退为全部累计
+	CountForegroundOnly bool `yaml:"countForegroundOnly"`
+	// CountVisibleOnly 仅在至少一个游戏进程拥有可见且未最小化的窗口时累计时间，无法判断时回退为全部累计
+	CountVisibleOnly bool `yaml:"countVisibleOnly"`
+
+	Enforcement   EnforcementConfig   `yaml:"enforcement"`   // 超限执行策略
+	Idle          IdleConfig          `yaml:"idle"`          // 空闲检测
+	Logging       LoggingConfig       `yaml:"logging"`       // 日志输出
+	Controller    ControllerConfig    `yaml:"controller"`    // 控制循环
+	State         StateConfig         `yaml:"state"`         // 状态文件保护
+	Tracking      TrackingConfig      `yaml:"tracking"`      // 会话跟踪
+	HTTP          HTTPConfig          `yaml:"http"`          // HTTP 端点
+	Instance      InstanceConfig      `yaml:"instance"`      // 单实例锁
+	TimeLimit     TimeLimitConfig     `yaml:"timeLimit"`     // 软/硬限制
+	Export        ExportConfig        `yaml:"export"`        // 向外部工具导出状态
+	Admin         AdminConfig         `yaml:"admin"`         // 家长密码
+	Watchdog      WatchdogConfig      `yaml:"watchdog"`      // 守护进程看护
+	Notifications NotificationsConfig `yaml:"notifications"` // 桌面通知
+	Breaks        BreaksConfig        `yaml:"breaks"`        // 强制休息
+	Earn          EarnConfig          `yaml:"earn"`          // 奖励时间
+	Matching      MatchingConfig      `yaml:"matching"`      // 进程名匹配方式
+	Policy        PolicyConfig        `yaml:"policy"`        // 锁定策略
+
+	// FirstThresholdSetting/FinalThresholdSetting 配置文件中的警告阈值，可写分钟数、"15m" 或每日限制的百分比 "20%"
+	FirstThresholdSetting Threshold `yaml:"firstThreshold"`
+	FinalThresholdSetting Threshold `yaml:"finalThreshold"`
+	// Warnings 多级提醒阈值（写法同 firstThreshold），如 [30, 15, 5, 1]，设置后取代 firstThreshold/finalThreshold
+	Warnings []Threshold `yaml:"warnings,omitempty"`
+
+	// Days 按星期覆盖每日限制与允许时段，键为 monday..sunday、weekday、weekend 或 all
+	Days map[string]DayRule `yaml:"days"`
+
+	// Profiles 多档案：一个守护进程按进程所属账户为多个孩子分别计时，为空时所有游戏进程共用一份配额
+	Profiles []ProfileConfig `yaml:"profiles"`
+}
+
+// TimeLimitConfig 软/硬限制：超过软限制只提醒，超过硬限制才终止游戏
+type TimeLimitConfig struct {
+	SoftLimit Minutes `yaml:"softLimit"` // 软限制（分钟），0 表示不启用
+	HardLimit Minutes `yaml:"hardLimit"` // 硬限制（分钟），设置后取代顶层 dailyLimit
+}
+
+// NotificationsConfig 桌面通知配置
+type NotificationsConfig struct {
+	QuietHours string `yaml:"quietHours"` // 静默时段（HH:MM-HH:MM，可跨午夜），期间只记录日志不弹窗，限制照常执行
+	Sound      string `yaml:"sound"`      // 提醒与超限时播放的提示音：.wav 文件路径或系统声音名称（Asterisk、Beep、Exclamation、Hand、Question），空表示不播放
+	// TimeoutSeconds 单次弹窗命令（PowerShell / msg.exe）的超时秒数，超时后结束命令；0 表示使用默认值
+	TimeoutSeconds int `yaml:"timeoutSeconds"`
+	// DailySummary 每日重置时弹出前一天的汇总（累计时间、各游戏时间、是否超限、终止次数），daily_summary 事件总会记录
+	DailySummary bool `yaml:"dailySummary"`
+}
+
+// Timeout 返回单次弹窗命令的超时
+func (n NotificationsConfig) Timeout() time.Duration {
+	if n.TimeoutSeconds <= 0 {
+		return notifier.DefaultTimeout
+	}
+	return time.Duration(n.TimeoutSeconds) * time.Second
+}
+
+// WatchdogConfig 看护进程配置：启用后 start 会同时启动 watchdog 进程，守护进程消失时将其重新启动
+type WatchdogConfig struct {
+	Enabled         bool `yaml:"enabled"`         // 是否随 start 启动看护进程
+	IntervalSeconds int  `yaml:"intervalSeconds"` // 检查守护进程是否存活的间隔（秒），0 表示使用默认值
+}
+
+// DefaultWatchdogInterval 未配置时看护进程的检查间隔
+const DefaultWatchdogInterval = 10 * time.Second
+
+// Interval 返回看护进程的检查间隔
+func (w WatchdogConfig) Interval() time.Duration {
+	if w.IntervalSeconds <= 0 {
+		return DefaultWatchdogInterval
+	}
+	return time.Duration(w.IntervalSeconds) * time.Second
+}
+
+// AdminConfig 家长密码：设置后停止守护进程、移除自启动等命令需先验证密码
+type AdminConfig struct {
+	PasswordHash string `yaml:"passwordHash"` // set-password 命令生成的哈希，为空时不需要密码
+}
+
+// ExportConfig 向叠加层等外部工具导出状态
+type ExportConfig struct {
+	RemainingFile string `yaml:"remainingFile"` // 每个周期写入剩余时间的 JSON 文件，为空时不导出
+}
+
+// InstanceConfig 单实例锁配置
+type InstanceConfig struct {
+	LockDir          string `yaml:"lockDir"`          // 锁文件目录，为空时使用系统临时目录
+	StaleLockSeconds int    `yaml:"staleLockSeconds"` // 锁文件超过该秒数视为陈旧锁，0 表示默认 24 小时
+}
+
+// HTTPConfig HTTP 端点配置
+type HTTPConfig struct {
+	Listen         string `yaml:"listen"`         // 监听地址，为空时使用 DefaultHTTPListen
+	MetricsEnabled bool   `yaml:"metricsEnabled"` // 是否提供 Prometheus /metrics 端点
+}
+
+// DefaultHTTPListen 默认只监听本机
+const DefaultHTTPListen = "127.0.0.1:9477"
+
+// ListenAddr 返回 HTTP 监听地址
+func (h HTTPConfig) ListenAddr() string {
+	if h.Listen == "" {
+		return DefaultHTTPListen
+	}
+	return h.Listen
+}
+
+// TrackingConfig 会话跟踪配置
+type TrackingConfig struct {
+	MinSessionSeconds int `yaml:"minSessionSeconds"` // 短于该秒数的会话不计入游戏时间，0 表示全部计入
+	StopDebounceScans int `yaml:"stopDebounceScans"` // 游戏进程连续缺失超过该扫描次数才视为会话结束，0 表示缺失即结束
+	// MinCPUPercent 两次扫描之间至少有一个游戏进程的 CPU 占用率（按单个逻辑处理器计）达到该百分比才累计时间，
+	// 停在菜单或暂停画面的游戏不计时；0 表示不检测
+	MinCPUPercent float64 `yaml:"minCpuPercent"`
+}
+
+// StateConfig 状态文件保护配置，默认明文保存
+type StateConfig struct {
+	HMACKey string `yaml:"hmacKey"` // 非空时用 HMAC-SHA256 签名状态文件，加载时拒绝被修改的文件
+	Encrypt bool   `yaml:"encrypt"` // 使用由 hmacKey 派生的密钥以 AES-GCM 加密状态文件
+
+	// Shared 状态文件位于多台电脑共用的位置（网络共享、同步盘）时启用：
+	// 保存前加锁并重新读取文件，合并其他电脑累计的时间，使多台电脑共用一份每日配额
+	Shared    bool   `yaml:"shared"`
+	MachineID string `yaml:"machineId"` // 共享状态中本机的标识，为空时使用计算机名
+}
+
+// Machine 返回共享状态中本机的标识
+func (s StateConfig) Machine() string {
+	if s.MachineID != "" {
+		return s.MachineID
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "local"
+}
+
+// ControllerConfig 控制循环配置
+type ControllerConfig struct {
+	SaveIntervalSeconds int `yaml:"saveIntervalSeconds"` // 定期保存状态的间隔（秒），0 表示使用默认值
+	SlowScanPercent     int `yaml:"slowScanPercent"`     // 单次进程扫描耗时超过扫描间隔的该百分比时记录 slow_scan，0 表示使用默认值
+}
+
+// DefaultSaveInterval 未配置时定期保存状态的间隔
+const DefaultSaveInterval = time.Minute
+
+// SaveInterval 返回定期保存状态的间隔
+func (c ControllerConfig) SaveInterval() time.Duration {
+	if c.SaveIntervalSeconds <= 0 {
+		return DefaultSaveInterval
+	}
+	return time.Duration(c.SaveIntervalSeconds) * time.Second
+}
+
+// DefaultSlowScanPercent 未配置时判定扫描过慢的阈值（占扫描间隔的百分比）
+const DefaultSlowScanPercent = 50
+
+// SlowScanThreshold 返回扫描间隔为 interval 时判定扫描过慢的耗时阈值
+func (c ControllerConfig) SlowScanThreshold(interval time.Duration) time.Duration {
+	percent := c.SlowScanPercent
+	if percent <= 0 {
+		percent = DefaultSlowScanPercent
+	}
+	return interval * time.Duration(percent) / 100
+}
+
+// LoggingConfig 日志输出配置
+type LoggingConfig struct {
+	Level            string `yaml:"level"`            // 主日志最低级别：debug|info|warn|error，为空时为 info
+	Console          bool   `yaml:"console"`          // 写入日志文件的同时输出到控制台
+	EventsPath       string `yaml:"eventsPath"`       // 仅事件日志（JSON Lines）路径，为空时不单独输出
+	HeartbeatSeconds int    `yaml:"heartbeatSeconds"` // 游戏运行中心跳事件间隔（秒），0 表示使用默认值
+}
+
+// DefaultHeartbeatInterval 未配置时的 game_running 心跳间隔
+const DefaultHeartbeatInterval = 60 * time.Second
+
+// HeartbeatInterval 返回 game_running 心跳事件的间隔
+func (l LoggingConfig) HeartbeatInterval() time.Duration {
+	if l.HeartbeatSeconds <= 0 {
+		return DefaultHeartbeatInterval
+	}
+	return time.Duration(l.HeartbeatSeconds) * time.Second
+}
+
+// EnforcementConfig 超限执行策略
+type EnforcementConfig struct {
+	Mode         string   `yaml:"mode"`         // enforce（默认）终止游戏；monitor 只记录将要终止的进程，不实际终止
+	GraceSeconds int      `yaml:"graceSeconds"` // 超限后到终止游戏前的宽限时间（秒），0 表示立即终止
+	Escalation   []int    `yaml:"escalation"`   // 当天第 N 次超限时的宽限时间（秒），超出列表长度时沿用最后一项；为空时仅首次超限使用 graceSeconds
+	ExemptUsers  []string `yaml:"exemptUsers"`  // 豁免账户（Windows 账户名），其进程不计时也不终止
+	ExemptPids   []int    `yaml:"exemptPids"`   // 豁免进程 PID，永不终止
+	Prohibited   []string `yaml:"prohibited"`   // 禁止运行的进程（如修改系统时间、结束进程的工具），检测到即终止
+	OnLimit      string   `yaml:"onLimit"`      // 超过每日限制后的动作：terminate（默认）| suspend | lock | logoff
+	// BlockRelaunch 当天超限终止游戏后，重新启动的游戏不再给宽限期，每秒检查一次并立即终止（仅 onLimit 为 terminate 时生效）
+	BlockRelaunch bool `yaml:"blockRelaunch"`
+	// KillCommands 按游戏（键写法同 games）指定的结束命令模板，如 `launcher.exe --quit {{.PID}}`，
+	// 终止该游戏时代替 taskkill 运行；命令失败时仍回退到 taskkill
+	KillCommands map[string]string `yaml:"killCommands"`
+}
+
+// 执行模式
+const (
+	ModeEnforce = "enforce"
+	ModeMonitor = "monitor"
+)
+
+// 超限动作
+const (
+	ActionTerminate = "terminate" // 终止游戏进程
+	ActionSuspend   = "suspend"   // 挂起游戏进程，下次配额重置时恢复
+	ActionLock      = "lock"      // 锁定工作站
+	ActionLogoff    = "logoff"    // 注销当前用户
+)
+
+// MinSessionActionGrace lock / logoff 影响整个会话，每次超限至少留出该宽限时间并先发出提醒，便于保存进度
+const MinSessionActionGrace = time.Minute
+
+// LimitAction 返回超限动作（小写），未设置时为 terminate
+func (e EnforcementConfig) LimitAction() string {
+	if e.OnLimit == "" {
+		return ActionTerminate
+	}
+	return strings.ToLower(e.OnLimit)
+}
+
+// MonitorOnly 是否为只观察、不终止的模式
+func (e EnforcementConfig) MonitorOnly() bool {
+	return strings.EqualFold(e.Mode, ModeMonitor)
+}
+
+// GraceForHit 返回当天第 hit 次（从 1 开始）超限时的宽限时间；
+// 超限动作为 lock / logoff 时不少于 MinSessionActionGrace
+func (e EnforcementConfig) GraceForHit(hit int) time.Duration {
+	grace := e.configuredGrace(hit)
+	switch e.LimitAction() {
+	case ActionLock, ActionLogoff:
+		grace = max(grace, MinSessionActionGrace)
+	}
+	return grace
+}
+
+func (e EnforcementConfig) configuredGrace(hit int) time.Duration {
+	if len(e.Escalation) == 0 {
+		if hit == 1 {
+			return time.Duration(e.GraceSeconds) * time.Second
+		}
+		return 0
+	}
+
+	idx := hit - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(e.Escalation) {
+		idx = len(e.Escalation) - 1
+	}
+	return time.Duration(e.Escalation[idx]) * time.Second
+}
+
+// IdleConfig 空闲检测配置
+type IdleConfig struct {
+	PauseAfterSeconds int `yaml:"pauseAfterSeconds"` // 无键鼠输入超过该秒数后暂停计时，0 表示不检测
+}
+
+// BreaksConfig 强制休息：连续游戏达到上限后关闭游戏并要求休息一段时间，与每日限制同时生效
+type BreaksConfig struct {
+	MaxSessionMinutes Minutes `yaml:"maxSessionMinutes"` // 单次连续游戏上限（分钟），0 表示不启用
+	BreakMinutes      Minutes `yaml:"breakMinutes"`      // 休息时长（分钟）；停止游戏达到该时长也视为已休息，连续时间重新计算
+}
+
+// Enabled 是否启用强制休息
+func (b BreaksConfig) Enabled() bool {
+	return b.MaxSessionMinutes > 0
+}
+
+// MaxSession 返回单次连续游戏上限
+func (b BreaksConfig) MaxSession() time.Duration {
+	return time.Duration(b.MaxSessionMinutes) * time.Minute
+}
+
+// BreakDuration 返回休息时长
+func (b BreaksConfig) BreakDuration() time.Duration {
+	return time.Duration(b.BreakMinutes) * time.Minute
 }
 
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
+		Version:    CurrentVersion,
 		DailyLimit: 120, // 默认 2 小时
 		ResetTime:  "08:00",
 		Games: []string{
@@ -33,14 +338,31 @@ func DefaultConfig() *Config {
 		FinalThreshold: 5,  // 剩余 5 分钟时警告
 		StateFile:      "state.json",
 		LogFile:        "game-control.log",
+
+		FirstThresholdSetting: Threshold{Minutes: 15},
+		FinalThresholdSetting: Threshold{Minutes: 5},
 	}
 }
 
-// LoadFromFile 从文件加载配置
+// LoadFromFile 从文件加载配置，失败时返回的错误包含 ErrInvalid
 func LoadFromFile(path string) (*Config, error) {
+	config, err := loadFromFile(path)
+	return config, invalid(err)
+}
+
+func loadFromFile(path string) (*Config, error) {
+	path, err := ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	// 如果文件不存在，返回默认配置
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return DefaultConfig(), nil
+		config := DefaultConfig()
+		if err := config.finalize(); err != nil {
+			return nil, err
+		}
+		return config, nil
 	}
 
 	data, err := os.ReadFile(path)
@@ -48,16 +370,253 @@ func LoadFromFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("无法读取配置文件: %w", err)
 	}
 
+	config, err := decodeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := config.migrate(); err != nil {
+		return nil, err
+	}
+
+	if err := config.rebasePaths(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	if err := config.finalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// rebasePaths 将配置文件中的相对文件路径改为相对于配置文件所在目录，
+// 使自启动任务等工作目录不确定的场景下状态与日志仍写在配置文件旁边；绝对路径保持不变
+func (c *Config) rebasePaths(dir string) error {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	paths := []*string{&c.StateFile, &c.LogFile, &c.Logging.EventsPath, &c.Instance.LockDir, &c.Export.RemainingFile}
+	for i := range c.Profiles {
+		paths = append(paths, &c.Profiles[i].StateFile)
+	}
+	for _, p := range paths {
+		expanded, err := ExpandPath(*p)
+		if err != nil {
+			return err
+		}
+		if expanded != "" && !filepath.IsAbs(expanded) {
+			*p = filepath.Join(dir, expanded)
+		}
+	}
+	return nil
+}
+
+// finalize 加载后的统一处理：应用环境变量覆盖并展开文件路径
+func (c *Config) finalize() error {
+	// hardLimit 即每日限制的另一种写法，环境变量覆盖仍优先
+	if c.TimeLimit.HardLimit > 0 {
+		c.DailyLimit = c.TimeLimit.HardLimit
+	}
+	if err := c.ApplyEnvOverrides(); err != nil {
+		return err
+	}
+	c.resolveThresholds()
+
+	var err error
+	if c.StateFile, err = ExpandPath(c.StateFile); err != nil {
+		return err
+	}
+	if c.LogFile, err = ExpandPath(c.LogFile); err != nil {
+		return err
+	}
+	if c.Logging.EventsPath, err = ExpandPath(c.Logging.EventsPath); err != nil {
+		return err
+	}
+	if c.Instance.LockDir, err = ExpandPath(c.Instance.LockDir); err != nil {
+		return err
+	}
+	if c.Export.RemainingFile, err = ExpandPath(c.Export.RemainingFile); err != nil {
+		return err
+	}
+	if c.Notifications.Sound, err = ExpandPath(c.Notifications.Sound); err != nil {
+		return err
+	}
+	for i := range c.Profiles {
+		if c.Profiles[i].StateFile, err = ExpandPath(c.Profiles[i].StateFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpandPath 展开路径中的 "~"（用户主目录）与环境变量（$VAR、${VAR}，Windows 下还支持 %VAR%）
+func ExpandPath(path string) (string, error) {
+	if path == "" {
+		return path, nil
+	}
+
+	if runtime.GOOS == "windows" {
+		path = expandPercentEnv(path)
+	}
+	path = os.ExpandEnv(path)
+
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("无法展开路径 %q: %w", path, err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	return path, nil
+}
+
+// expandPercentEnv 展开 Windows 风格的 %VAR%，未定义的变量保持原样
+func expandPercentEnv(s string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '%')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start+1:], '%')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+
+		name := s[start+1 : end]
+		if value, ok := os.LookupEnv(name); ok && name != "" {
+			b.WriteString(s[:start])
+			b.WriteString(value)
+			s = s[end+1:]
+		} else {
+			// 不是已定义的变量，原样保留起始的 '%'，从结束 '%' 处继续匹配
+			b.WriteString(s[:end])
+			s = s[end:]
+		}
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// 环境变量覆盖项，优先级高于配置文件
+const (
+	EnvDailyLimit = "GAMECTL_DAILY_LIMIT"
+	EnvResetTime  = "GAMECTL_RESET_TIME"
+	EnvStateFile  = "GAMECTL_STATE_FILE"
+	EnvLogFile    = "GAMECTL_LOG_FILE"
+	EnvGames      = "GAMECTL_GAMES" // 逗号分隔
+	EnvTimezone   = "GAMECTL_TIMEZONE"
+	EnvLanguage   = "GAMECTL_LANG"
+)
+
+// ApplyEnvOverrides 使用环境变量覆盖配置值，未设置或为空的变量保持原值
+func (c *Config) ApplyEnvOverrides() error {
+	if v := strings.TrimSpace(os.Getenv(EnvDailyLimit)); v != "" {
+		limit, err := ParseMinutes(v)
+		if err != nil {
+			return fmt.Errorf("环境变量 %s: %w", EnvDailyLimit, err)
+		}
+		c.DailyLimit = limit
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvResetTime)); v != "" {
+		c.ResetTime = v
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvStateFile)); v != "" {
+		c.StateFile = v
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvLogFile)); v != "" {
+		c.LogFile = v
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvTimezone)); v != "" {
+		c.Timezone = v
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvLanguage)); v != "" {
+		c.Language = v
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvGames)); v != "" {
+		var games []string
+		for _, game := range strings.Split(v, ",") {
+			if game = strings.TrimSpace(game); game != "" {
+				games = append(games, game)
+			}
+		}
+		c.Games = games
+	}
+	return nil
+}
+
+// decodeConfig 严格解析 YAML，未知字段（多为拼写错误）直接报错
+func decodeConfig(data []byte) (*Config, error) {
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf("无法解析配置文件: %w", err)
 	}
-
 	return &config, nil
 }
 
-// Validate 验证配置
+// MigrateFile 加载配置并在版本较旧时将升级后的内容写回文件，返回是否发生了迁移
+func MigrateFile(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("无法读取配置文件: %w", err)
+	}
+
+	config, err := decodeConfig(data)
+	if err != nil {
+		return false, err
+	}
+
+	migrated, err := config.migrate()
+	if err != nil || !migrated {
+		return false, err
+	}
+
+	if err := config.SaveToFile(path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// migrate 将旧版本配置在内存中逐级升级到 CurrentVersion，返回是否发生了升级
+func (c *Config) migrate() (bool, error) {
+	if c.Version > CurrentVersion {
+		return false, fmt.Errorf("配置版本 %d 高于当前程序支持的版本 %d，请升级 game-control", c.Version, CurrentVersion)
+	}
+	if c.Version < 0 {
+		return false, fmt.Errorf("无效的配置版本: %d", c.Version)
+	}
+
+	from := c.Version
+	if c.Version == 0 {
+		c.migrateV0ToV1()
+	}
+
+	return c.Version != from, nil
+}
+
+// migrateV0ToV1 v0（无 version 字段）升级到 v1：补齐文件路径默认值
+func (c *Config) migrateV0ToV1() {
+	defaults := DefaultConfig()
+	if c.StateFile == "" {
+		c.StateFile = defaults.StateFile
+	}
+	if c.LogFile == "" {
+		c.LogFile = defaults.LogFile
+	}
+	c.Version = 1
+}
+
+// Validate 验证配置，失败时返回的错误包含 ErrInvalid
 func (c *Config) Validate() error {
+	return invalid(c.validate())
+}
+
+func (c *Config) validate() error {
 	// 验证每日时间限制
 	if c.DailyLimit <= 0 {
 		return fmt.Errorf("每日时间限制必须大于 0")
@@ -69,10 +628,30 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("重置时间格式无效，应为 HH:MM 格式: %w", err)
 	}
 
+	// 验证时区
+	if _, err := c.Location(); err != nil {
+		return err
+	}
+	if !i18n.Supported(c.Language) {
+		return fmt.Errorf("不支持的语言 %q，可选 zh|en", c.Language)
+	}
+
 	// 验证游戏列表
 	if len(c.Games) == 0 {
 		return fmt.Errorf("游戏进程列表不能为空")
 	}
+	if err := c.validateGames(); err != nil {
+		return err
+	}
+	if err := c.validateMatching(); err != nil {
+		return err
+	}
+	if err := c.validateKillCommands(); err != nil {
+		return err
+	}
+	if err := c.validateEarn(); err != nil {
+		return err
+	}
 
 	// 验证警告阈值
 	if c.FirstThreshold < 0 || c.FinalThreshold < 0 {
@@ -83,12 +662,148 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("最后警告阈值不能大于第一次警告阈值")
 	}
 
+	if c.FirstThreshold >= c.DailyLimit || c.FinalThreshold >= c.DailyLimit {
+		return fmt.Errorf("警告阈值必须小于每日时间限制 (%d 分钟)", c.DailyLimit)
+	}
+
+	// 验证软限制
+	if c.TimeLimit.SoftLimit < 0 {
+		return fmt.Errorf("软限制不能为负数")
+	}
+	if c.TimeLimit.SoftLimit > c.DailyLimit {
+		return fmt.Errorf("软限制 (%d 分钟) 不能大于硬限制 (%d 分钟)", c.TimeLimit.SoftLimit, c.DailyLimit)
+	}
+
+	// 验证日程
+	if err := c.validateDays(); err != nil {
+		return err
+	}
+
+	// 验证状态文件保护
+	if c.State.Encrypt && c.State.HMACKey == "" {
+		return fmt.Errorf("加密状态文件需要设置 state.hmacKey")
+	}
+
+	// 验证执行策略
+	switch strings.ToLower(c.Enforcement.Mode) {
+	case "", ModeEnforce, ModeMonitor:
+	default:
+		return fmt.Errorf("无效的执行模式 %q，可选 enforce|monitor", c.Enforcement.Mode)
+	}
+	switch c.Enforcement.LimitAction() {
+	case ActionTerminate, ActionSuspend, ActionLock, ActionLogoff:
+	default:
+		return fmt.Errorf("无效的超限动作 %q，可选 terminate|suspend|lock|logoff", c.Enforcement.OnLimit)
+	}
+	if c.Enforcement.GraceSeconds < 0 {
+		return fmt.Errorf("宽限时间不能为负数")
+	}
+	for _, seconds := range c.Enforcement.Escalation {
+		if seconds < 0 {
+			return fmt.Errorf("逐级宽限时间不能为负数: %d", seconds)
+		}
+	}
+
+	// 验证空闲检测
+	if c.Idle.PauseAfterSeconds < 0 {
+		return fmt.Errorf("空闲暂停阈值不能为负数")
+	}
+
+	if c.Breaks.MaxSessionMinutes < 0 || c.Breaks.BreakMinutes < 0 {
+		return fmt.Errorf("强制休息的连续游戏上限与休息时长不能为负数")
+	}
+	if c.Breaks.Enabled() && c.Breaks.BreakMinutes == 0 {
+		return fmt.Errorf("启用 breaks.maxSessionMinutes 时必须设置 breaks.breakMinutes")
+	}
+
+	switch strings.ToLower(c.Logging.Level) {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("无效的日志级别 %q，可选 debug|info|warn|error", c.Logging.Level)
+	}
+
+	if c.Logging.HeartbeatSeconds < 0 {
+		return fmt.Errorf("心跳间隔不能为负数")
+	}
+
+	if c.Tracking.MinSessionSeconds < 0 {
+		return fmt.Errorf("最短会话时长不能为负数")
+	}
+	if c.Tracking.StopDebounceScans < 0 {
+		return fmt.Errorf("会话结束防抖次数不能为负数")
+	}
+	if c.Tracking.MinCPUPercent < 0 {
+		return fmt.Errorf("最低 CPU 占用率不能为负数")
+	}
+
+	if c.Controller.SaveIntervalSeconds < 0 {
+		return fmt.Errorf("状态保存间隔不能为负数")
+	}
+	if c.Controller.SlowScanPercent < 0 || c.Controller.SlowScanPercent > 100 {
+		return fmt.Errorf("慢扫描阈值百分比必须在 0-100 之间")
+	}
+
+	if c.Instance.StaleLockSeconds < 0 {
+		return fmt.Errorf("陈旧锁判定时长不能为负数")
+	}
+
+	if c.Notifications.QuietHours != "" {
+		if _, err := parseWindow(c.Notifications.QuietHours); err != nil {
+			return fmt.Errorf("notifications.quietHours: %w", err)
+		}
+	}
+
+	if c.Notifications.TimeoutSeconds < 0 {
+		return fmt.Errorf("notifications.timeoutSeconds 不能为负数")
+	}
+
+	if sound := c.Notifications.Sound; sound != "" {
+		if _, ok := notifier.IsSystemSound(sound); !ok && !strings.EqualFold(filepath.Ext(sound), ".wav") {
+			return fmt.Errorf("notifications.sound 必须是 .wav 文件路径或系统声音名称（%s）: %q",
+				strings.Join(notifier.SystemSounds, "、"), sound)
+		}
+	}
+
+	if c.Watchdog.IntervalSeconds < 0 {
+		return fmt.Errorf("看护进程检查间隔不能为负数")
+	}
+
+	if c.Admin.PasswordHash != "" {
+		if err := admin.CheckHash(c.Admin.PasswordHash); err != nil {
+			return fmt.Errorf("admin.passwordHash: %w", err)
+		}
+	}
+
+	if err := c.validateProfiles(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Location 返回用于计算重置边界的时区，未配置时返回本地时区
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("无效的时区 %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
 // SaveToFile 保存配置到文件
 func (c *Config) SaveToFile(path string) error {
-	data, err := yaml.Marshal(c)
+	// 直接设置了分钟数的阈值（如代码中构造的配置）同样写入文件
+	out := *c
+	if out.FirstThresholdSetting == (Threshold{}) {
+		out.FirstThresholdSetting = Threshold{Minutes: c.FirstThreshold}
+	}
+	if out.FinalThresholdSetting == (Threshold{}) {
+		out.FinalThresholdSetting = Threshold{Minutes: c.FinalThreshold}
+	}
+	data, err := yaml.Marshal(&out)
 	if err != nil {
 		return fmt.Errorf("无法序列化配置: %w", err)
 	}
@@ -3,7 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/process"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -164,3 +168,579 @@ func TestSaveToFile(t *testing.T) {
 		t.Errorf("重新加载的配置不匹配，预期 %d，实际 %d", cfg.DailyLimit, loadedCfg.DailyLimit)
 	}
 }
+
+func TestValidate_InvalidTimezone(t *testing.T) {
+	cfg := &Config{
+		DailyLimit:     120,
+		ResetTime:      "08:00",
+		Games:          []string{"game.exe"},
+		FirstThreshold: 15,
+		FinalThreshold: 5,
+		Timezone:       "Mars/Olympus",
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("预期无效的时区应返回错误")
+	}
+}
+
+func TestLocation_DefaultsToLocal(t *testing.T) {
+	cfg := DefaultConfig()
+
+	loc, err := cfg.Location()
+	if err != nil {
+		t.Fatalf("未配置时区时不应报错: %v", err)
+	}
+	if loc != time.Local {
+		t.Errorf("未配置时区时应使用本地时区，实际为 %v", loc)
+	}
+}
+
+func TestLoadFromFile_MigratesV0Config(t *testing.T) {
+	yamlContent := `dailyLimit: 90
+resetTime: "07:30"
+games:
+  - "game.exe"
+firstThreshold: 10
+finalThreshold: 5`
+
+	dir := t.TempDir()
+	tempFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(tempFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	cfg, err := LoadFromFile(tempFile)
+	if err != nil {
+		t.Fatalf("加载 v0 配置失败: %v", err)
+	}
+
+	if cfg.Version != CurrentVersion {
+		t.Errorf("v0 配置应升级到版本 %d，实际为 %d", CurrentVersion, cfg.Version)
+	}
+	if cfg.StateFile != filepath.Join(dir, "state.json") || cfg.LogFile != filepath.Join(dir, "game-control.log") {
+		t.Errorf("v0 配置应补齐默认文件路径，实际 stateFile=%q logFile=%q", cfg.StateFile, cfg.LogFile)
+	}
+	if cfg.DailyLimit != 90 {
+		t.Errorf("迁移不应修改已有字段，每日限制应为90，实际为 %d", cfg.DailyLimit)
+	}
+}
+
+func TestLoadFromFile_FutureVersionRejected(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tempFile, []byte("version: 99\ndailyLimit: 60\n"), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	_, err := LoadFromFile(tempFile)
+	if err == nil || !strings.Contains(err.Error(), "请升级") {
+		t.Fatalf("未来版本配置应提示升级程序，实际错误: %v", err)
+	}
+}
+
+func TestMigrateFile_RewritesOldConfig(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tempFile, []byte("dailyLimit: 60\nresetTime: \"08:00\"\ngames: [\"game.exe\"]\n"), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	migrated, err := MigrateFile(tempFile)
+	if err != nil {
+		t.Fatalf("MigrateFile 失败: %v", err)
+	}
+	if !migrated {
+		t.Fatal("v0 配置应被迁移")
+	}
+
+	migrated, err = MigrateFile(tempFile)
+	if err != nil {
+		t.Fatalf("再次 MigrateFile 失败: %v", err)
+	}
+	if migrated {
+		t.Fatal("已是当前版本的配置不应再次迁移")
+	}
+}
+
+func TestLoadFromFile_UnknownFieldRejected(t *testing.T) {
+	yamlContent := `dailyLimit: 120
+timelimit: 60
+resetTime: "08:00"
+games:
+  - "game.exe"`
+
+	tempFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tempFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	_, err := LoadFromFile(tempFile)
+	if err == nil {
+		t.Fatal("包含未知字段的配置应返回错误")
+	}
+	if !strings.Contains(err.Error(), "timelimit") {
+		t.Errorf("错误信息应指出未知字段 timelimit，实际为: %v", err)
+	}
+}
+
+func TestLoadFromFile_KnownFieldsAccepted(t *testing.T) {
+	yamlContent := `version: 1
+dailyLimit: 120
+resetTime: "08:00"
+games:
+  - "game.exe"
+enforcement:
+  graceSeconds: 30`
+
+	tempFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tempFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	cfg, err := LoadFromFile(tempFile)
+	if err != nil {
+		t.Fatalf("拼写正确的配置应加载成功: %v", err)
+	}
+	if cfg.Enforcement.GraceSeconds != 30 {
+		t.Errorf("嵌套字段应正确解析，宽限时间应为30，实际为 %d", cfg.Enforcement.GraceSeconds)
+	}
+}
+
+func TestValidate_ThresholdNotBelowLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		first Minutes
+		final Minutes
+	}{
+		{name: "首次阈值等于限制", first: 120, final: 5},
+		{name: "首次阈值大于限制", first: 200, final: 5},
+		{name: "两个阈值都大于限制", first: 200, final: 150},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				DailyLimit:     120,
+				ResetTime:      "08:00",
+				Games:          []string{"game.exe"},
+				FirstThreshold: tt.first,
+				FinalThreshold: tt.final,
+			}
+
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("预期阈值不小于每日限制时返回错误")
+			}
+			if !strings.Contains(err.Error(), "小于每日时间限制") {
+				t.Errorf("错误信息不明确: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadFromFile_EnvOverridesTakePrecedence(t *testing.T) {
+	yamlContent := `dailyLimit: 180
+resetTime: "09:00"
+games:
+  - "game1.exe"
+stateFile: "file-state.json"`
+
+	tempFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tempFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	t.Setenv(EnvDailyLimit, "45")
+	t.Setenv(EnvResetTime, "06:30")
+	t.Setenv(EnvStateFile, "env-state.json")
+	t.Setenv(EnvGames, "a.exe, b.exe ,")
+
+	cfg, err := LoadFromFile(tempFile)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	if cfg.DailyLimit != 45 {
+		t.Errorf("每日限制应被环境变量覆盖为45，实际为 %d", cfg.DailyLimit)
+	}
+	if cfg.ResetTime != "06:30" {
+		t.Errorf("重置时间应被覆盖为06:30，实际为 %s", cfg.ResetTime)
+	}
+	if cfg.StateFile != "env-state.json" {
+		t.Errorf("状态文件应被覆盖为 env-state.json，实际为 %s", cfg.StateFile)
+	}
+	if len(cfg.Games) != 2 || cfg.Games[0] != "a.exe" || cfg.Games[1] != "b.exe" {
+		t.Errorf("游戏列表应被覆盖为 [a.exe b.exe]，实际为 %v", cfg.Games)
+	}
+}
+
+func TestLoadFromFile_EnvOverridesApplyToDefaults(t *testing.T) {
+	t.Setenv(EnvDailyLimit, "30")
+
+	cfg, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("加载默认配置失败: %v", err)
+	}
+	if cfg.DailyLimit != 30 {
+		t.Errorf("默认配置也应应用环境变量覆盖，实际每日限制为 %d", cfg.DailyLimit)
+	}
+}
+
+func TestLanguage_EnvOverrideAndValidation(t *testing.T) {
+	t.Setenv(EnvLanguage, "en")
+	cfg, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("加载默认配置失败: %v", err)
+	}
+	if cfg.Language != "en" {
+		t.Errorf("语言应被 %s 覆盖为 en，实际为 %q", EnvLanguage, cfg.Language)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Language = "fr"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期不支持的语言返回错误")
+	}
+}
+
+func TestLoadFromFile_InvalidEnvOverride(t *testing.T) {
+	t.Setenv(EnvDailyLimit, "two hours")
+
+	_, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil || !strings.Contains(err.Error(), EnvDailyLimit) {
+		t.Fatalf("非数字的每日限制应返回指明变量名的错误，实际为 %v", err)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("无法获取用户主目录: %v", err)
+	}
+	t.Setenv("GAMECTL_TEST_DIR", "/data/gc")
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "~/x", want: filepath.Join(home, "x")},
+		{input: "~", want: home},
+		{input: "$GAMECTL_TEST_DIR/x", want: "/data/gc/x"},
+		{input: "${GAMECTL_TEST_DIR}/state.json", want: "/data/gc/state.json"},
+		{input: "/abs/path/state.json", want: "/abs/path/state.json"},
+		{input: "relative/state.json", want: "relative/state.json"},
+		{input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandPath(tt.input)
+		if err != nil {
+			t.Fatalf("ExpandPath(%q) 失败: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q，预期 %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestExpandPercentEnv(t *testing.T) {
+	t.Setenv("GAMECTL_APPDATA", `C:\Users\kid\AppData`)
+
+	tests := map[string]string{
+		`%GAMECTL_APPDATA%\gc\state.json`: `C:\Users\kid\AppData\gc\state.json`,
+		`%UNDEFINED_GAMECTL_VAR%\x`:       `%UNDEFINED_GAMECTL_VAR%\x`,
+		`100%\%GAMECTL_APPDATA%`:          `100%\C:\Users\kid\AppData`,
+		`no-vars`:                         `no-vars`,
+	}
+	for input, want := range tests {
+		if got := expandPercentEnv(input); got != want {
+			t.Errorf("expandPercentEnv(%q) = %q，预期 %q", input, got, want)
+		}
+	}
+}
+
+func TestLoadFromFile_ExpandsFilePaths(t *testing.T) {
+	t.Setenv("GAMECTL_TEST_DIR", "/data/gc")
+	yamlContent := `dailyLimit: 120
+resetTime: "08:00"
+games: ["game.exe"]
+stateFile: "$GAMECTL_TEST_DIR/state.json"
+logFile: "/var/log/gc.log"
+instance:
+  lockDir: "${GAMECTL_TEST_DIR}/run"`
+
+	tempFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tempFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	cfg, err := LoadFromFile(tempFile)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if cfg.StateFile != "/data/gc/state.json" {
+		t.Errorf("状态文件路径应展开环境变量，实际为 %s", cfg.StateFile)
+	}
+	if cfg.LogFile != "/var/log/gc.log" {
+		t.Errorf("绝对路径应保持不变，实际为 %s", cfg.LogFile)
+	}
+	if cfg.Instance.LockDir != "/data/gc/run" {
+		t.Errorf("锁文件目录应展开环境变量，实际为 %s", cfg.Instance.LockDir)
+	}
+}
+
+func TestEnforcementGraceForHit(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    EnforcementConfig
+		hit    int
+		expect time.Duration
+	}{
+		{name: "未配置逐级时首次使用 graceSeconds", cfg: EnforcementConfig{GraceSeconds: 60}, hit: 1, expect: time.Minute},
+		{name: "未配置逐级时再次超限立即终止", cfg: EnforcementConfig{GraceSeconds: 60}, hit: 2, expect: 0},
+		{name: "逐级第二次", cfg: EnforcementConfig{Escalation: []int{300, 60, 0}}, hit: 2, expect: time.Minute},
+		{name: "超出列表沿用最后一项", cfg: EnforcementConfig{Escalation: []int{300, 60}}, hit: 5, expect: time.Minute},
+		{name: "锁定至少宽限一分钟", cfg: EnforcementConfig{OnLimit: "lock"}, hit: 1, expect: MinSessionActionGrace},
+		{name: "注销再次超限仍宽限", cfg: EnforcementConfig{OnLimit: "Logoff", GraceSeconds: 300}, hit: 2, expect: MinSessionActionGrace},
+		{name: "注销保留更长的宽限", cfg: EnforcementConfig{OnLimit: "logoff", GraceSeconds: 300}, hit: 1, expect: 5 * time.Minute},
+		{name: "挂起不强制宽限", cfg: EnforcementConfig{OnLimit: "suspend"}, hit: 1, expect: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.GraceForHit(tt.hit); got != tt.expect {
+				t.Errorf("预期宽限 %v，实际 %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestValidate_NegativeStopDebounce(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Tracking.StopDebounceScans = -1
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期会话结束防抖次数为负数时返回错误")
+	}
+}
+
+func TestValidate_OnLimit(t *testing.T) {
+	for _, action := range []string{"", "terminate", "SUSPEND", "lock", "logoff"} {
+		cfg := DefaultConfig()
+		cfg.Enforcement.OnLimit = action
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("超限动作 %q 应有效，实际 %v", action, err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Enforcement.OnLimit = "shutdown"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期无效的超限动作返回错误")
+	}
+}
+
+func TestValidate_Breaks(t *testing.T) {
+	tests := []struct {
+		name   string
+		breaks BreaksConfig
+		valid  bool
+	}{
+		{name: "未启用", breaks: BreaksConfig{}, valid: true},
+		{name: "启用", breaks: BreaksConfig{MaxSessionMinutes: 60, BreakMinutes: 15}, valid: true},
+		{name: "缺少休息时长", breaks: BreaksConfig{MaxSessionMinutes: 60}, valid: false},
+		{name: "负数", breaks: BreaksConfig{MaxSessionMinutes: -1, BreakMinutes: 15}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Breaks = tt.breaks
+			if err := cfg.Validate(); (err == nil) != tt.valid {
+				t.Errorf("强制休息配置 %+v 的校验结果不正确: %v", tt.breaks, err)
+			}
+		})
+	}
+}
+
+func TestControllerConfig_SlowScanThreshold(t *testing.T) {
+	if got := (ControllerConfig{}).SlowScanThreshold(5 * time.Second); got != 2500*time.Millisecond {
+		t.Errorf("未配置时阈值应为扫描间隔的 %d%%，实际 %v", DefaultSlowScanPercent, got)
+	}
+	if got := (ControllerConfig{SlowScanPercent: 80}).SlowScanThreshold(5 * time.Second); got != 4*time.Second {
+		t.Errorf("slowScanPercent 80 时阈值应为 4s，实际 %v", got)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Controller.SlowScanPercent = 101
+	if err := cfg.Validate(); err == nil {
+		t.Error("slowScanPercent 超过 100 时校验应失败")
+	}
+}
+
+func TestValidate_Earn(t *testing.T) {
+	tests := []struct {
+		name  string
+		earn  EarnConfig
+		valid bool
+	}{
+		{name: "未启用", earn: EarnConfig{}, valid: true},
+		{name: "启用", earn: EarnConfig{Apps: []EarnApp{{Name: "typing.exe", Ratio: 2}}, MaxMinutes: 30}, valid: true},
+		{name: "缺少上限", earn: EarnConfig{Apps: []EarnApp{{Name: "typing.exe", Ratio: 2}}}, valid: false},
+		{name: "比例无效", earn: EarnConfig{Apps: []EarnApp{{Name: "typing.exe"}}, MaxMinutes: 30}, valid: false},
+		{name: "缺少进程名", earn: EarnConfig{Apps: []EarnApp{{Ratio: 2}}, MaxMinutes: 30}, valid: false},
+		{name: "同时是游戏", earn: EarnConfig{Apps: []EarnApp{{Name: "GAME.exe", Ratio: 2}}, MaxMinutes: 30}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Games = []string{"game.exe"}
+			cfg.Earn = tt.earn
+			if err := cfg.Validate(); (err == nil) != tt.valid {
+				t.Errorf("奖励时间配置 %+v 的校验结果不正确: %v", tt.earn, err)
+			}
+		})
+	}
+}
+
+func TestValidate_KillCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands map[string]string
+		valid    bool
+	}{
+		{name: "未配置", commands: nil, valid: true},
+		{name: "占位符", commands: map[string]string{"steam.exe": "steam.exe -shutdown {{.PID}}"}, valid: true},
+		{name: "不是配置的游戏", commands: map[string]string{"other.exe": "other.exe --quit"}, valid: false},
+		{name: "未知字段", commands: map[string]string{"steam.exe": "steam.exe {{.Pid}}"}, valid: false},
+		{name: "空命令", commands: map[string]string{"steam.exe": " "}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Enforcement.KillCommands = tt.commands
+			if err := cfg.Validate(); (err == nil) != tt.valid {
+				t.Errorf("结束命令配置 %v 的校验结果不正确: %v", tt.commands, err)
+			}
+		})
+	}
+}
+
+func TestKillCommand(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Enforcement.KillCommands = map[string]string{"Steam.exe": "steam.exe -shutdown"}
+
+	if got := cfg.KillCommand(process.ProcessInfo{PID: 1, Name: "steam.exe"}); got != "steam.exe -shutdown" {
+		t.Errorf("应按不区分大小写的进程名找到结束命令，实际 %q", got)
+	}
+	if got := cfg.KillCommand(process.ProcessInfo{PID: 2, Name: "LeagueClient.exe"}); got != "" {
+		t.Errorf("未配置结束命令的游戏应返回空串，实际 %q", got)
+	}
+}
+
+func TestValidate_NegativeEscalation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Enforcement.Escalation = []int{60, -1}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期逐级宽限时间为负数时返回错误")
+	}
+}
+
+func TestValidate_NegativeStaleLock(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Instance.StaleLockSeconds = -1
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期陈旧锁判定时长为负数时返回错误")
+	}
+}
+
+func TestNotificationsConfig_Timeout(t *testing.T) {
+	if got := (NotificationsConfig{}).Timeout(); got != 10*time.Second {
+		t.Errorf("未配置时弹窗超时应为 10s，实际 %v", got)
+	}
+	if got := (NotificationsConfig{TimeoutSeconds: 30}).Timeout(); got != 30*time.Second {
+		t.Errorf("timeoutSeconds 30 时弹窗超时应为 30s，实际 %v", got)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Notifications.TimeoutSeconds = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("timeoutSeconds 为负数时校验应失败")
+	}
+}
+
+func TestLoadFromFile_HardLimitReplacesDailyLimit(t *testing.T) {
+	yamlContent := `dailyLimit: 120
+resetTime: "08:00"
+games: ["game.exe"]
+firstThreshold: 15
+finalThreshold: 5
+timeLimit:
+  softLimit: "1h30m"
+  hardLimit: "3h"`
+
+	tempFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tempFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	cfg, err := LoadFromFile(tempFile)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if cfg.DailyLimit != 180 {
+		t.Errorf("hardLimit 应取代 dailyLimit，实际为 %d", cfg.DailyLimit)
+	}
+	if cfg.TimeLimit.SoftLimit != 90 {
+		t.Errorf("softLimit 应为 90 分钟，实际为 %d", cfg.TimeLimit.SoftLimit)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("配置应有效: %v", err)
+	}
+}
+
+func TestValidate_SoftLimitAboveHardLimit(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TimeLimit.SoftLimit = cfg.DailyLimit + 1
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期软限制大于硬限制时返回错误")
+	}
+
+	cfg.TimeLimit.SoftLimit = cfg.DailyLimit
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("软限制等于硬限制时应有效: %v", err)
+	}
+}
+
+func TestLoadFromFile_RelativePathsFollowConfigDir(t *testing.T) {
+	yamlContent := `dailyLimit: 120
+resetTime: "08:00"
+games: ["game.exe"]
+stateFile: "state.json"
+logFile: "logs/game-control.log"
+logging:
+  eventsPath: "/var/log/events.jsonl"`
+
+	dir := filepath.Join(t.TempDir(), "profiles")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("无法创建目录: %v", err)
+	}
+	tempFile := filepath.Join(dir, "kid1.yaml")
+	if err := os.WriteFile(tempFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	cfg, err := LoadFromFile(tempFile)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if want := filepath.Join(dir, "state.json"); cfg.StateFile != want {
+		t.Errorf("相对状态文件路径应基于配置文件目录 %s，实际为 %s", want, cfg.StateFile)
+	}
+	if want := filepath.Join(dir, "logs", "game-control.log"); cfg.LogFile != want {
+		t.Errorf("相对日志路径应基于配置文件目录 %s，实际为 %s", want, cfg.LogFile)
+	}
+	if cfg.Logging.EventsPath != "/var/log/events.jsonl" {
+		t.Errorf("绝对路径应保持不变，实际为 %s", cfg.Logging.EventsPath)
+	}
+}
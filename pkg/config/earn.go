@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EarnConfig 奖励时间：运行指定的学习类应用可按比例赚取当天的额外游戏时间（增加当天的每日限制）
+type EarnConfig struct {
+	Apps       []EarnApp `yaml:"apps"`       // 可赚取时间的应用，为空表示不启用
+	MaxMinutes Minutes   `yaml:"maxMinutes"` // 每天最多赚取的游戏时间（分钟），启用时必须大于 0
+}
+
+// EarnApp 可赚取游戏时间的应用
+type EarnApp struct {
+	Name  string `yaml:"name"`  // 进程名，写法同 games
+	Ratio int    `yaml:"ratio"` // 运行该应用多少分钟奖励 1 分钟游戏时间，如 2 表示每 2 分钟奖励 1 分钟
+}
+
+// Enabled 是否启用奖励时间
+func (e EarnConfig) Enabled() bool {
+	return len(e.Apps) > 0
+}
+
+// EarnNames 返回规范化后的奖励应用进程名列表
+func (c *Config) EarnNames() []string {
+	names := make([]string, 0, len(c.Earn.Apps))
+	for _, app := range c.Earn.Apps {
+		names = append(names, c.matchName(app.Name))
+	}
+	return names
+}
+
+// EarnRatio 返回奖励应用（规范化后的进程名，不区分大小写）的奖励比例，未配置时返回 0
+func (c *Config) EarnRatio(name string) int {
+	for _, app := range c.Earn.Apps {
+		if strings.EqualFold(c.matchName(app.Name), name) {
+			return app.Ratio
+		}
+	}
+	return 0
+}
+
+// validateEarn 检查奖励应用的名称与比例，启用时必须设置每日上限，且应用不能同时是游戏
+func (c *Config) validateEarn() error {
+	if !c.Earn.Enabled() {
+		return nil
+	}
+	if c.Earn.MaxMinutes <= 0 {
+		return fmt.Errorf("启用 earn.apps 时 earn.maxMinutes 必须大于 0")
+	}
+	games := make(map[string]bool, len(c.Games))
+	for _, game := range c.GameNames() {
+		games[strings.ToLower(game)] = true
+	}
+	for i, app := range c.Earn.Apps {
+		name := c.matchName(app.Name)
+		if name == "" {
+			return fmt.Errorf("earn.apps 第 %d 项缺少进程名", i+1)
+		}
+		if app.Ratio < 1 {
+			return fmt.Errorf("earn.apps 中 %s 的 ratio 必须大于等于 1", app.Name)
+		}
+		if games[strings.ToLower(name)] {
+			return fmt.Errorf("earn.apps 中的 %s 同时出现在 games 中", app.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import "errors"
+
+// ErrInvalid 配置文件无法读取、解析或未通过校验，可用 errors.Is 判断
+var ErrInvalid = errors.New("配置无效")
+
+// invalidError 将错误标记为 ErrInvalid，错误文字保持不变
+type invalidError struct {
+	err error
+}
+
+func (e invalidError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidError) Unwrap() []error {
+	return []error{e.err, ErrInvalid}
+}
+
+// invalid 将非 nil 的 err 标记为 ErrInvalid
+func invalid(err error) error {
+	if err == nil || errors.Is(err, ErrInvalid) {
+		return err
+	}
+	return invalidError{err}
+}
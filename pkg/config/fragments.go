@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// appendedLists 合并配置片段时追加而不是覆盖的列表（按 YAML 键路径）：进程名与账户名列表，
+// 便于基础策略列出公共游戏、各孩子的片段再补充自己的游戏
+var appendedLists = map[string]bool{
+	"games":                   true,
+	"enforcement.prohibited":  true,
+	"enforcement.exemptUsers": true,
+	"enforcement.exemptPids":  true,
+}
+
+// Load 加载配置：path 是目录时按 LoadFromDir 合并其中的配置片段，否则按 LoadFromFile 加载单个文件；
+// 配置了 policy.url 时改为按 LoadPolicy 使用签名策略
+func Load(path string) (*Config, error) {
+	expanded, err := ExpandPath(path)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	var config *Config
+	if info, statErr := os.Stat(expanded); statErr == nil && info.IsDir() {
+		config, err = LoadFromDir(path)
+	} else {
+		config, err = LoadFromFile(path)
+	}
+	if err != nil || config.Policy.URL == "" {
+		return config, err
+	}
+	return LoadPolicy(config.Policy, path)
+}
+
+// LoadFromDir 按文件名字典序加载目录中的 *.yaml / *.yml 配置片段，依次合并后按单个配置文件处理
+// （相对路径相对于该目录），失败时返回的错误包含 ErrInvalid。合并规则：
+//   - 映射（如 enforcement、days）按键逐项合并，后面的片段只覆盖自己写出的键
+//   - 标量（如 dailyLimit、stateFile）由后面的片段覆盖
+//   - appendedLists 中的列表（games、enforcement.prohibited 等）依次追加，其余列表（如 escalation、profiles）整体覆盖
+//
+// 目录中没有配置片段时使用默认配置
+func LoadFromDir(dir string) (*Config, error) {
+	config, err := loadFromDir(dir)
+	return config, invalid(err)
+}
+
+func loadFromDir(dir string) (*Config, error) {
+	dir, err := ExpandPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取配置目录: %w", err)
+	}
+
+	var merged *yaml.Node
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		root, err := readFragment(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("配置片段 %s: %w", entry.Name(), err)
+		}
+		if root == nil {
+			continue
+		}
+		if merged == nil {
+			merged = root
+		} else {
+			merged = mergeNode(merged, root, "")
+		}
+	}
+
+	if merged == nil {
+		config := DefaultConfig()
+		if err := config.finalize(); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("合并配置片段失败: %w", err)
+	}
+	config, err := decodeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := config.migrate(); err != nil {
+		return nil, err
+	}
+	if err := config.rebasePaths(dir); err != nil {
+		return nil, err
+	}
+	if err := config.finalize(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// readFragment 读取并解析一个配置片段，返回顶层映射节点，空文件返回 nil。
+// 先按完整配置严格解码一次，未知字段在合并前就指出所在片段
+func readFragment(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取配置文件: %w", err)
+	}
+	if _, err := decodeConfig(data); err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("无法解析配置文件: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("顶层必须是键值映射")
+	}
+	return root, nil
+}
+
+// mergeNode 将 override 合并到 base 上并返回结果，path 为当前节点的键路径（如 "enforcement.prohibited"）
+func mergeNode(base, override *yaml.Node, path string) *yaml.Node {
+	switch {
+	case base.Kind == yaml.MappingNode && override.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(override.Content); i += 2 {
+			key, value := override.Content[i], override.Content[i+1]
+			child := key.Value
+			if path != "" {
+				child = path + "." + key.Value
+			}
+			if j := mappingIndex(base, key.Value); j >= 0 {
+				base.Content[j+1] = mergeNode(base.Content[j+1], value, child)
+			} else {
+				base.Content = append(base.Content, key, value)
+			}
+		}
+		return base
+	case base.Kind == yaml.SequenceNode && override.Kind == yaml.SequenceNode && appendedLists[path]:
+		base.Content = append(base.Content, override.Content...)
+		return base
+	default:
+		return override
+	}
+}
+
+// mappingIndex 返回映射节点中键 key 所在的下标，不存在时返回 -1
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
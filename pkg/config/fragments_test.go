@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFragment(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("写入配置片段失败: %v", err)
+	}
+}
+
+func TestLoadFromDir_MergesFragments(t *testing.T) {
+	dir := t.TempDir()
+	writeFragment(t, dir, "10-base.yaml", `
+dailyLimit: 120
+resetTime: "06:30"
+games: ["a.exe"]
+enforcement:
+  graceSeconds: 30
+  prohibited: ["x.exe"]
+  escalation: [300, 60]
+days:
+  weekend:
+    dailyLimit: 180
+`)
+	writeFragment(t, dir, "20-kid.yml", `
+dailyLimit: 90
+stateFile: kid.json
+games: ["b.exe"]
+enforcement:
+  onLimit: suspend
+  prohibited: ["y.exe"]
+  escalation: [0]
+days:
+  monday:
+    dailyLimit: 60
+`)
+	writeFragment(t, dir, "README.txt", "不是配置片段")
+
+	cfg, err := LoadFromDir(dir)
+	if err != nil {
+		t.Fatalf("LoadFromDir 失败: %v", err)
+	}
+
+	if cfg.DailyLimit != 90 {
+		t.Errorf("标量应由后面的片段覆盖，dailyLimit 实际 %d", cfg.DailyLimit)
+	}
+	if !slices.Equal(cfg.Games, []string{"a.exe", "b.exe"}) {
+		t.Errorf("games 应依次追加，实际 %v", cfg.Games)
+	}
+	if !slices.Equal(cfg.Enforcement.Prohibited, []string{"x.exe", "y.exe"}) {
+		t.Errorf("enforcement.prohibited 应依次追加，实际 %v", cfg.Enforcement.Prohibited)
+	}
+	if !slices.Equal(cfg.Enforcement.Escalation, []int{0}) {
+		t.Errorf("其余列表应整体覆盖，escalation 实际 %v", cfg.Enforcement.Escalation)
+	}
+	if cfg.Enforcement.GraceSeconds != 30 || cfg.Enforcement.OnLimit != ActionSuspend {
+		t.Errorf("映射应按键合并，enforcement 实际 %+v", cfg.Enforcement)
+	}
+	if cfg.Days["weekend"].DailyLimit != 180 || cfg.Days["monday"].DailyLimit != 60 {
+		t.Errorf("days 应按键合并，实际 %+v", cfg.Days)
+	}
+	if cfg.StateFile != filepath.Join(dir, "kid.json") {
+		t.Errorf("相对路径应相对于配置目录，stateFile 实际 %s", cfg.StateFile)
+	}
+	if cfg.ResetTime != "06:30" {
+		t.Errorf("后面的片段未设置的项应保留前面片段的值，resetTime 实际 %q", cfg.ResetTime)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("合并后的配置应通过校验: %v", err)
+	}
+}
+
+func TestLoadFromDir_UnknownFieldNamesFragment(t *testing.T) {
+	dir := t.TempDir()
+	writeFragment(t, dir, "10-base.yaml", "dailyLimit: 120\n")
+	writeFragment(t, dir, "20-typo.yaml", "dailyLimt: 90\n")
+
+	_, err := LoadFromDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "20-typo.yaml") {
+		t.Fatalf("未知字段应指出所在片段，实际 %v", err)
+	}
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("错误应包含 ErrInvalid，实际 %v", err)
+	}
+}
+
+func TestLoad_DirectoryOrFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFragment(t, dir, "base.yaml", "dailyLimit: 45\n")
+
+	fromDir, err := Load(dir)
+	if err != nil {
+		t.Fatalf("按目录加载失败: %v", err)
+	}
+	fromFile, err := Load(filepath.Join(dir, "base.yaml"))
+	if err != nil {
+		t.Fatalf("按文件加载失败: %v", err)
+	}
+	if fromDir.DailyLimit != 45 || fromFile.DailyLimit != 45 {
+		t.Errorf("目录与文件应加载出相同的配置，实际 %d / %d", fromDir.DailyLimit, fromFile.DailyLimit)
+	}
+}
+
+func TestLoadFromDir_EmptyDirUsesDefaults(t *testing.T) {
+	cfg, err := LoadFromDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadFromDir 失败: %v", err)
+	}
+	if cfg.DailyLimit != DefaultConfig().DailyLimit {
+		t.Errorf("空目录应使用默认配置，dailyLimit 实际 %d", cfg.DailyLimit)
+	}
+}
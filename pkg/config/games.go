@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+// NormalizeGameName 返回游戏名与 tasklist 映像名比较时使用的键：
+// 去掉目录部分（如 C:\Games\Game.exe → Game.exe），Windows 下缺少扩展名时补上 .exe。
+// 以 title: 开头的窗口标题匹配项只统一前缀写法，标题为空时返回空串
+func NormalizeGameName(name string) string {
+	return normalizeGameName(name, runtime.GOOS == "windows")
+}
+
+func normalizeGameName(name string, windows bool) string {
+	if pattern, ok := process.TitlePattern(name); ok {
+		if pattern == "" {
+			return ""
+		}
+		return process.TitlePrefix + pattern
+	}
+
+	name = strings.TrimSpace(name)
+	if idx := strings.LastIndexAny(name, `\/`); idx >= 0 {
+		name = name[idx+1:]
+	}
+	if name != "" && windows && path.Ext(name) == "" {
+		name += ".exe"
+	}
+	return name
+}
+
+// MatchingConfig 进程名匹配配置
+type MatchingConfig struct {
+	// Mode 配置的名称与进程映像名的比较方式（不区分大小写）：exact（默认）| contains | glob | regex，
+	// 对 games、enforcement.prohibited 与 earn.apps 一致生效；title: 项始终按窗口标题匹配
+	Mode string `yaml:"mode"`
+}
+
+// MatchMode 返回生效的匹配方式（小写），未配置时为 exact
+func (m MatchingConfig) MatchMode() string {
+	if m.Mode == "" {
+		return process.MatchExact
+	}
+	return strings.ToLower(m.Mode)
+}
+
+// shortPatternRunes contains 方式下短于该字符数的名称容易误匹配其他进程，验证时给出警告
+const shortPatternRunes = 4
+
+// matchName 返回配置中的名称用于匹配进程的写法：exact 方式下按 NormalizeGameName 规范化；
+// 其他方式下名称是模式，只去掉首尾空白（补 .exe 或去掉目录会改变模式的含义），title: 项照常规范化
+func (c *Config) matchName(name string) string {
+	if _, ok := process.TitlePattern(name); ok || c.Matching.MatchMode() == process.MatchExact {
+		return NormalizeGameName(name)
+	}
+	return strings.TrimSpace(name)
+}
+
+// GameNames 返回规范化后的游戏名列表，用于匹配进程；配置中的原始写法保持不变
+func (c *Config) GameNames() []string {
+	names := make([]string, 0, len(c.Games))
+	for _, game := range c.Games {
+		names = append(names, c.matchName(game))
+	}
+	return names
+}
+
+// ProhibitedNames 返回规范化后的禁止运行进程名列表
+func (c *Config) ProhibitedNames() []string {
+	names := make([]string, 0, len(c.Enforcement.Prohibited))
+	for _, name := range c.Enforcement.Prohibited {
+		names = append(names, c.matchName(name))
+	}
+	return names
+}
+
+// GameNameWarnings 列出写法会被规范化的游戏名（带目录或缺少 .exe）以及误写成系统关键进程的游戏名，便于用户修正配置
+func (c *Config) GameNameWarnings() []string {
+	var warnings []string
+	for _, game := range c.Games {
+		normalized := c.matchName(game)
+		if normalized != game {
+			warnings = append(warnings, fmt.Sprintf("游戏名 %q 将按 %q 匹配进程", game, normalized))
+		}
+		if process.IsCriticalName(normalized) {
+			warnings = append(warnings, fmt.Sprintf("游戏名 %q 是系统关键进程或本程序，超限时不会被终止", game))
+		}
+		if _, ok := process.TitlePattern(game); !ok && c.Matching.MatchMode() == process.MatchContains &&
+			utf8.RuneCountInString(normalized) < shortPatternRunes {
+			warnings = append(warnings, fmt.Sprintf("游戏名 %q 较短，按 contains 匹配可能误伤名称中包含它的其他进程", game))
+		}
+	}
+	return warnings
+}
+
+// validateGames 检查每个游戏名与禁止运行的进程名规范化后非空
+func (c *Config) validateGames() error {
+	for _, game := range c.Games {
+		if c.matchName(game) == "" {
+			if _, ok := process.TitlePattern(game); ok {
+				return fmt.Errorf("无效的游戏名 %q：缺少窗口标题", game)
+			}
+			return fmt.Errorf("无效的游戏名 %q：缺少进程映像名", game)
+		}
+	}
+	for _, name := range c.Enforcement.Prohibited {
+		if c.matchName(name) == "" {
+			return fmt.Errorf("无效的禁止运行进程名 %q：缺少进程映像名", name)
+		}
+	}
+	return nil
+}
+
+// validateMatching 检查匹配方式，并按该方式检查 games、enforcement.prohibited 与 earn.apps 中的名称
+func (c *Config) validateMatching() error {
+	mode := c.Matching.MatchMode()
+	if !slices.Contains(process.MatchModes, mode) {
+		return fmt.Errorf("无效的匹配方式 %q，可选 %s", c.Matching.Mode, strings.Join(process.MatchModes, "|"))
+	}
+	for _, name := range slices.Concat(c.GameNames(), c.ProhibitedNames(), c.EarnNames()) {
+		if _, ok := process.TitlePattern(name); ok {
+			continue
+		}
+		if err := process.ValidatePattern(mode, name); err != nil {
+			return fmt.Errorf("matching.mode 为 %s 时名称无效: %w", mode, err)
+		}
+	}
+	return nil
+}
+
+// KillCommand 返回 enforcement.killCommands 中与游戏进程匹配的结束命令模板，未配置时返回空串
+func (c *Config) KillCommand(proc process.ProcessInfo) string {
+	for name, command := range c.Enforcement.KillCommands {
+		if proc.Matches(c.matchName(name)) {
+			return command
+		}
+	}
+	return ""
+}
+
+// validateKillCommands 检查 enforcement.killCommands 的键是配置的游戏，且模板能按进程信息展开
+func (c *Config) validateKillCommands() error {
+	games := make(map[string]bool)
+	for _, game := range c.GameNames() {
+		games[strings.ToLower(game)] = true
+	}
+	for _, p := range c.Profiles {
+		for _, game := range p.Games {
+			games[strings.ToLower(c.matchName(game))] = true
+		}
+	}
+
+	for name, command := range c.Enforcement.KillCommands {
+		if !games[strings.ToLower(c.matchName(name))] {
+			return fmt.Errorf("enforcement.killCommands 中的 %s 不在 games 中", name)
+		}
+		if _, _, err := process.RenderKillCommand(command, process.ProcessInfo{PID: 1, Name: name}); err != nil {
+			return fmt.Errorf("enforcement.killCommands 中 %s 的结束命令无效: %w", name, err)
+		}
+	}
+	return nil
+}
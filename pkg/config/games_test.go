@@ -0,0 +1,128 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeGameName(t *testing.T) {
+	tests := []struct {
+		input   string
+		windows bool
+		expect  string
+	}{
+		{input: "Game.exe", windows: true, expect: "Game.exe"},
+		{input: "Game", windows: true, expect: "Game.exe"},
+		{input: "Game", windows: false, expect: "Game"},
+		{input: `C:\Games\Game.exe`, windows: true, expect: "Game.exe"},
+		{input: `C:\Games\Game`, windows: true, expect: "Game.exe"},
+		{input: "/opt/games/game.x86_64", windows: false, expect: "game.x86_64"},
+		{input: "  steam.exe  ", windows: true, expect: "steam.exe"},
+		{input: `C:\Games\`, windows: true, expect: ""},
+		{input: "Title: Elden Ring ", windows: true, expect: "title:Elden Ring"},
+		{input: "title:A/B", windows: true, expect: "title:A/B"},
+		{input: "title:  ", windows: true, expect: ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeGameName(tt.input, tt.windows); got != tt.expect {
+			t.Errorf("normalizeGameName(%q, windows=%v) = %q，预期 %q", tt.input, tt.windows, got, tt.expect)
+		}
+	}
+}
+
+func TestGameNameWarnings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Games = []string{"game.exe", `D:\Steam\steam.exe`}
+
+	warnings := cfg.GameNameWarnings()
+	if len(warnings) != 1 {
+		t.Fatalf("只有带目录的游戏名应产生警告，实际 %v", warnings)
+	}
+	if names := cfg.GameNames(); names[1] != "steam.exe" {
+		t.Errorf("应按映像名匹配，实际 %v", names)
+	}
+	if cfg.Games[1] != `D:\Steam\steam.exe` {
+		t.Errorf("配置中的原始写法应保持不变，实际 %q", cfg.Games[1])
+	}
+}
+
+func TestValidate_EmptyGameName(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Games = []string{"game.exe", `C:\Games\`}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期缺少映像名的游戏名返回错误")
+	}
+}
+
+func TestValidate_EmptyProhibitedName(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Enforcement.Prohibited = []string{" "}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期缺少映像名的禁止运行进程名返回错误")
+	}
+}
+
+func TestValidate_EmptyTitlePattern(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Games = []string{"game.exe", "title:"}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期缺少窗口标题的匹配项返回错误")
+	}
+}
+
+func TestGameNameWarnings_CriticalProcess(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Games = []string{"game.exe", "Explorer.exe"}
+
+	warnings := cfg.GameNameWarnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Explorer.exe") {
+		t.Errorf("误写成系统关键进程的游戏名应产生警告，实际 %v", warnings)
+	}
+}
+
+func TestValidate_MatchingMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		games      []string
+		prohibited []string
+		wantErr    bool
+	}{
+		{name: "默认精确匹配", games: []string{"game.exe"}},
+		{name: "大小写不敏感的方式名", mode: "Contains", games: []string{"valorant"}},
+		{name: "有效通配符", mode: "glob", games: []string{"valorant*.exe", "title:Elden Ring"}},
+		{name: "有效正则", mode: "regex", games: []string{`^game\d+\.exe$`}},
+		{name: "无效方式", mode: "fuzzy", games: []string{"game.exe"}, wantErr: true},
+		{name: "无效通配符", mode: "glob", games: []string{"game[.exe"}, wantErr: true},
+		{name: "无效正则", mode: "regex", games: []string{"game(.exe"}, wantErr: true},
+		{name: "禁止运行进程的无效正则", mode: "regex", games: []string{"game.exe"}, prohibited: []string{"*hack"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Matching.Mode = tt.mode
+			cfg.Games = tt.games
+			cfg.Enforcement.Prohibited = tt.prohibited
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() 错误 = %v，预期出错 %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGameNames_PatternsNotNormalized(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Matching.Mode = "contains"
+	cfg.Games = []string{" valorant ", "title: Elden Ring", "lol"}
+
+	names := cfg.GameNames()
+	if names[0] != "valorant" || names[1] != "title:Elden Ring" {
+		t.Errorf("非精确匹配时名称是模式，不应补 .exe，实际 %v", names)
+	}
+	warnings := strings.Join(cfg.GameNameWarnings(), "\n")
+	if !strings.Contains(warnings, `"lol"`) || strings.Contains(warnings, `"title: Elden Ring" 较短`) {
+		t.Errorf("contains 方式下过短的名称应产生警告，实际:\n%s", warnings)
+	}
+}
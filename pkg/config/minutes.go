@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Minutes 以分钟为单位的时长配置。
+// YAML 中既可以写整数分钟（90），也可以写时长字符串（"90m"、"1h30m"）。
+type Minutes int
+
+// UnmarshalYAML 解析整数分钟或时长字符串
+func (m *Minutes) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("第 %d 行: 分钟数必须是整数或时长字符串", value.Line)
+	}
+
+	parsed, err := ParseMinutes(value.Value)
+	if err != nil {
+		return fmt.Errorf("第 %d 行: %w", value.Line, err)
+	}
+	*m = parsed
+	return nil
+}
+
+// ParseMinutes 解析整数分钟（"90"）或 time.ParseDuration 格式的时长（"1h30m"）
+func ParseMinutes(s string) (Minutes, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		return Minutes(n), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("无效的分钟数 %q，应为整数分钟或如 \"2h30m\" 的时长", s)
+	}
+	if d%time.Minute != 0 {
+		return 0, fmt.Errorf("时长 %q 必须是整数分钟", s)
+	}
+	return Minutes(d / time.Minute), nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMinutesUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "分钟时长", input: `"90m"`},
+		{name: "小时与分钟", input: `"1h30m"`},
+		{name: "纯整数", input: `90`},
+		{name: "整数字符串", input: `"90"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Minutes
+			if err := yaml.Unmarshal([]byte(tt.input), &m); err != nil {
+				t.Fatalf("解析 %s 失败: %v", tt.input, err)
+			}
+			if m != 90 {
+				t.Errorf("%s 应解析为 90 分钟，实际为 %d", tt.input, m)
+			}
+		})
+	}
+
+	var m Minutes
+	if err := yaml.Unmarshal([]byte(`"1h"`), &m); err != nil || m != 60 {
+		t.Errorf("\"1h\" 应解析为 60 分钟，实际为 %d (err=%v)", m, err)
+	}
+}
+
+func TestMinutesUnmarshalYAML_Invalid(t *testing.T) {
+	for _, input := range []string{`"two hours"`, `"90s"`, `[1, 2]`} {
+		var m Minutes
+		if err := yaml.Unmarshal([]byte(input), &m); err == nil {
+			t.Errorf("%s 应解析失败", input)
+		}
+	}
+}
+
+func TestLoadFromFile_DurationLimits(t *testing.T) {
+	yamlContent := `dailyLimit: "2h30m"
+resetTime: "08:00"
+games: ["game.exe"]
+firstThreshold: "15m"
+finalThreshold: 5`
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if cfg.DailyLimit != 150 || cfg.FirstThreshold != 15 || cfg.FinalThreshold != 5 {
+		t.Errorf("时长配置解析错误: dailyLimit=%d firstThreshold=%d finalThreshold=%d",
+			cfg.DailyLimit, cfg.FirstThreshold, cfg.FinalThreshold)
+	}
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrPolicyUntrusted 锁定策略缺少签名、签名不匹配或无法解析，可用 errors.Is 判断
+var ErrPolicyUntrusted = errors.New("锁定策略未通过签名校验")
+
+// PolicyConfig 锁定策略（kiosk）：启动时获取经 Ed25519 签名的配置，校验通过后取代本地配置，
+// 用于共享电脑上防止本地修改限制。本地配置可被改写，要真正锁定应在构建时嵌入（见 README）
+type PolicyConfig struct {
+	URL       string `yaml:"url"`       // 签名策略的地址（http/https）或文件路径，为空时不启用
+	PublicKey string `yaml:"publicKey"` // 验证签名的 Ed25519 公钥（base64），由 policy-keygen 生成
+}
+
+// policyFetchTimeout 获取远程策略的超时
+const policyFetchTimeout = 15 * time.Second
+
+// maxPolicySize 策略文件大小上限
+const maxPolicySize = 1 << 20
+
+// signedPolicy 签名策略文件格式
+type signedPolicy struct {
+	Config    string `json:"config"`    // YAML 配置原文
+	Signature string `json:"signature"` // Config 原文的 Ed25519 签名（base64）
+}
+
+// GeneratePolicyKey 生成签名策略用的 Ed25519 密钥对，均为 base64 编码
+func GeneratePolicyKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("生成密钥失败: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv.Seed()), nil
+}
+
+// SignPolicy 用私钥（GeneratePolicyKey 返回的 base64 编码）签名 YAML 配置，返回签名策略文件内容。
+// 签名前先按完整配置严格解析，拒绝签发含未知字段的策略
+func SignPolicy(data []byte, privateKey string) ([]byte, error) {
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("无效的策略私钥")
+	}
+	if _, err := decodeConfig(data); err != nil {
+		return nil, err
+	}
+
+	signature := ed25519.Sign(ed25519.NewKeyFromSeed(seed), data)
+	return json.MarshalIndent(signedPolicy{
+		Config:    string(data),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	}, "", "  ")
+}
+
+// VerifyPolicy 用公钥校验签名策略文件，返回其中的 YAML 配置；校验失败时返回的错误包含 ErrPolicyUntrusted
+func VerifyPolicy(blob []byte, publicKey string) ([]byte, error) {
+	pub, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("无效的 policy.publicKey")
+	}
+
+	var policy signedPolicy
+	if err := json.Unmarshal(blob, &policy); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPolicyUntrusted, err)
+	}
+	if policy.Signature == "" {
+		return nil, fmt.Errorf("%w: 缺少签名", ErrPolicyUntrusted)
+	}
+	signature, err := base64.StdEncoding.DecodeString(policy.Signature)
+	if err != nil || !ed25519.Verify(pub, []byte(policy.Config), signature) {
+		return nil, fmt.Errorf("%w: 签名不匹配", ErrPolicyUntrusted)
+	}
+	return []byte(policy.Config), nil
+}
+
+// LoadPolicy 获取并校验签名策略，按其中的配置生效，忽略本地配置的其余内容；失败时返回的错误包含 ErrInvalid。
+// 策略中的相对路径相对于本地配置 configPath 所在目录（configPath 是目录时即该目录），状态与日志仍写在本机
+func LoadPolicy(policy PolicyConfig, configPath string) (*Config, error) {
+	config, err := loadPolicy(policy, configPath)
+	if err != nil {
+		return nil, invalid(fmt.Errorf("加载锁定策略 %s 失败: %w", policy.URL, err))
+	}
+	return config, nil
+}
+
+func loadPolicy(policy PolicyConfig, configPath string) (*Config, error) {
+	if policy.PublicKey == "" {
+		return nil, fmt.Errorf("启用锁定策略需要设置 policy.publicKey")
+	}
+	blob, err := fetchPolicy(policy.URL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := VerifyPolicy(blob, policy.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := decodeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := config.migrate(); err != nil {
+		return nil, err
+	}
+
+	dir, err := ExpandPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	if err := config.rebasePaths(dir); err != nil {
+		return nil, err
+	}
+	if err := config.finalize(); err != nil {
+		return nil, err
+	}
+	// 策略自身的 policy 段不生效，记录实际使用的来源
+	config.Policy = policy
+	return config, nil
+}
+
+// fetchPolicy 读取策略文件：http/https 地址通过网络获取，其余按本地路径读取
+func fetchPolicy(url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		path, err := ExpandPath(url)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("无法读取策略文件: %w", err)
+		}
+		return data, nil
+	}
+
+	client := &http.Client{Timeout: policyFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("获取策略失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取策略失败: HTTP %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPolicySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取策略失败: %w", err)
+	}
+	if len(data) > maxPolicySize {
+		return nil, fmt.Errorf("策略超过 %d 字节", maxPolicySize)
+	}
+	return data, nil
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPolicyYAML = "dailyLimit: 45\ngames: [kiosk.exe]\nstateFile: kiosk-state.json\n"
+
+// signTestPolicy 生成密钥对并签名 testPolicyYAML，返回公钥与签名策略
+func signTestPolicy(t *testing.T) (string, []byte) {
+	t.Helper()
+	publicKey, privateKey, err := GeneratePolicyKey()
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	blob, err := SignPolicy([]byte(testPolicyYAML), privateKey)
+	if err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	return publicKey, blob
+}
+
+func TestVerifyPolicy_Valid(t *testing.T) {
+	publicKey, blob := signTestPolicy(t)
+
+	data, err := VerifyPolicy(blob, publicKey)
+	if err != nil {
+		t.Fatalf("有效签名应通过校验: %v", err)
+	}
+	if string(data) != testPolicyYAML {
+		t.Errorf("返回的配置 = %q，预期 %q", data, testPolicyYAML)
+	}
+}
+
+func TestVerifyPolicy_Invalid(t *testing.T) {
+	publicKey, blob := signTestPolicy(t)
+	otherKey, _, err := GeneratePolicyKey()
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+
+	var policy signedPolicy
+	if err := json.Unmarshal(blob, &policy); err != nil {
+		t.Fatalf("解析策略失败: %v", err)
+	}
+	rewrite := func(mutate func(p *signedPolicy)) []byte {
+		p := policy
+		mutate(&p)
+		data, _ := json.Marshal(p)
+		return data
+	}
+
+	tests := []struct {
+		name string
+		blob []byte
+		key  string
+	}{
+		{"配置被修改", rewrite(func(p *signedPolicy) { p.Config = strings.Replace(p.Config, "45", "600", 1) }), publicKey},
+		{"未签名", rewrite(func(p *signedPolicy) { p.Signature = "" }), publicKey},
+		{"签名无法解码", rewrite(func(p *signedPolicy) { p.Signature = "not base64!" }), publicKey},
+		{"其他密钥", blob, otherKey},
+		{"不是签名策略", []byte(testPolicyYAML), publicKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyPolicy(tt.blob, tt.key); !errors.Is(err, ErrPolicyUntrusted) {
+				t.Errorf("应返回 ErrPolicyUntrusted，实际 %v", err)
+			}
+		})
+	}
+
+	if _, err := VerifyPolicy(blob, "short"); err == nil || errors.Is(err, ErrPolicyUntrusted) {
+		t.Errorf("无效公钥应返回配置错误，实际 %v", err)
+	}
+}
+
+func TestSignPolicy_RejectsUnknownFields(t *testing.T) {
+	_, privateKey, err := GeneratePolicyKey()
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	if _, err := SignPolicy([]byte("dailyLimt: 45\n"), privateKey); err == nil {
+		t.Error("含未知字段的配置不应签名")
+	}
+}
+
+func TestLoad_UsesSignedPolicy(t *testing.T) {
+	publicKey, blob := signTestPolicy(t)
+	dir := t.TempDir()
+	policyPath := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(policyPath, blob, 0644); err != nil {
+		t.Fatalf("写入策略失败: %v", err)
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	local := "dailyLimit: 600\ngames: [game.exe]\npolicy:\n  url: " + policyPath + "\n  publicKey: " + publicKey + "\n"
+	if err := os.WriteFile(configPath, []byte(local), 0644); err != nil {
+		t.Fatalf("写入配置失败: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("加载失败: %v", err)
+	}
+	if cfg.DailyLimit != 45 || len(cfg.Games) != 1 || cfg.Games[0] != "kiosk.exe" {
+		t.Errorf("应使用策略中的配置，实际 dailyLimit=%d games=%v", cfg.DailyLimit, cfg.Games)
+	}
+	if want := filepath.Join(dir, "kiosk-state.json"); cfg.StateFile != want {
+		t.Errorf("策略中的相对路径应相对于本地配置目录，实际 %s，预期 %s", cfg.StateFile, want)
+	}
+	if cfg.Policy.URL != policyPath {
+		t.Errorf("应记录使用的策略来源，实际 %q", cfg.Policy.URL)
+	}
+}
+
+func TestLoad_RejectsTamperedPolicy(t *testing.T) {
+	publicKey, blob := signTestPolicy(t)
+	tampered := strings.Replace(string(blob), "45", "600", 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(tampered))
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	local := "policy:\n  url: " + server.URL + "\n  publicKey: " + publicKey + "\n"
+	if err := os.WriteFile(configPath, []byte(local), 0644); err != nil {
+		t.Fatalf("写入配置失败: %v", err)
+	}
+
+	_, err := Load(configPath)
+	if !errors.Is(err, ErrPolicyUntrusted) || !errors.Is(err, ErrInvalid) {
+		t.Errorf("被篡改的策略应拒绝加载并视为配置错误，实际 %v", err)
+	}
+}
+
+func TestLoadPolicy_FetchesOverHTTP(t *testing.T) {
+	publicKey, blob := signTestPolicy(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(blob)
+	}))
+	defer server.Close()
+
+	cfg, err := LoadPolicy(PolicyConfig{URL: server.URL, PublicKey: publicKey}, t.TempDir())
+	if err != nil {
+		t.Fatalf("加载远程策略失败: %v", err)
+	}
+	if cfg.DailyLimit != 45 {
+		t.Errorf("dailyLimit = %d，预期 45", cfg.DailyLimit)
+	}
+
+	if _, err := LoadPolicy(PolicyConfig{URL: server.URL}, t.TempDir()); err == nil {
+		t.Error("未设置公钥时应拒绝加载")
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileConfig 档案（通常对应一个孩子）：按游戏进程所属的 Windows 账户区分，
+// 各自计时并执行限制；未设置的项沿用顶层配置
+type ProfileConfig struct {
+	Name       string   `yaml:"name"`       // 档案名称，用于 status --profile
+	Users      []string `yaml:"users"`      // 属于该档案的账户，可写 "PC\用户名" 或仅用户名
+	Games      []string `yaml:"games"`      // 游戏进程列表，为空时沿用顶层 games
+	DailyLimit Minutes  `yaml:"dailyLimit"` // 每日限制，0 表示沿用顶层 dailyLimit
+	StateFile  string   `yaml:"stateFile"`  // 状态文件，为空时为顶层状态文件旁的 state-<name>.json
+}
+
+// Profile 按名称（不区分大小写）查找档案
+func (c *Config) Profile(name string) (ProfileConfig, bool) {
+	for _, p := range c.Profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return ProfileConfig{}, false
+}
+
+// ForProfile 返回档案生效的配置：复制顶层配置并以档案中设置的项覆盖
+func (c *Config) ForProfile(name string) (*Config, error) {
+	p, ok := c.Profile(name)
+	if !ok {
+		return nil, fmt.Errorf("未知的档案: %s", name)
+	}
+
+	pc := *c
+	pc.Profiles = nil
+	if len(p.Games) > 0 {
+		pc.Games = p.Games
+	}
+	if p.DailyLimit > 0 {
+		pc.DailyLimit = p.DailyLimit
+		// 百分比阈值按档案自己的每日限制换算
+		pc.resolveThresholds()
+	}
+	pc.StateFile = p.StateFile
+	if pc.StateFile == "" {
+		pc.StateFile = filepath.Join(filepath.Dir(c.StateFile), "state-"+safeProfileName(p.Name)+".json")
+	}
+	if c.Export.RemainingFile != "" {
+		// 各档案导出到各自的文件：remaining.json -> remaining-<name>.json
+		ext := filepath.Ext(c.Export.RemainingFile)
+		base := strings.TrimSuffix(c.Export.RemainingFile, ext)
+		pc.Export.RemainingFile = base + "-" + safeProfileName(p.Name) + ext
+	}
+	return &pc, nil
+}
+
+// safeProfileName 将档案名转换为可用于文件名的形式
+func safeProfileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// validateProfiles 检查档案名称唯一、账户非空，且每个档案生效后的配置有效、状态文件互不相同
+func (c *Config) validateProfiles() error {
+	names := make(map[string]bool)
+	stateFiles := make(map[string]string)
+	for _, p := range c.Profiles {
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("档案名称不能为空")
+		}
+		key := strings.ToLower(p.Name)
+		if names[key] {
+			return fmt.Errorf("档案名称重复: %s", p.Name)
+		}
+		names[key] = true
+
+		if len(p.Users) == 0 {
+			return fmt.Errorf("档案 %s 未设置 users", p.Name)
+		}
+		if p.DailyLimit < 0 {
+			return fmt.Errorf("档案 %s 的每日时间限制不能为负数", p.Name)
+		}
+
+		pc, err := c.ForProfile(p.Name)
+		if err != nil {
+			return err
+		}
+		if err := pc.Validate(); err != nil {
+			return fmt.Errorf("档案 %s: %w", p.Name, err)
+		}
+		if other, ok := stateFiles[pc.StateFile]; ok {
+			return fmt.Errorf("档案 %s 与 %s 使用了相同的状态文件 %s", p.Name, other, pc.StateFile)
+		}
+		stateFiles[pc.StateFile] = p.Name
+	}
+	return nil
+}
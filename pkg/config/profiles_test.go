@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFromFile_Profiles(t *testing.T) {
+	yamlContent := `dailyLimit: 120
+resetTime: "08:00"
+games: ["game.exe"]
+firstThreshold: 15
+finalThreshold: 5
+stateFile: "state.json"
+export:
+  remainingFile: "remaining.json"
+profiles:
+  - name: alice
+    users: ["alice"]
+    dailyLimit: 60
+  - name: bob
+    users: ["PC\\bob"]
+    games: ["minecraft.exe"]
+    stateFile: "bob/state.json"`
+
+	dir := t.TempDir()
+	tempFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(tempFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+
+	cfg, err := LoadFromFile(tempFile)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("配置应有效: %v", err)
+	}
+
+	alice, err := cfg.ForProfile("Alice")
+	if err != nil {
+		t.Fatalf("ForProfile 失败: %v", err)
+	}
+	if alice.DailyLimit != 60 || alice.Games[0] != "game.exe" {
+		t.Errorf("alice 应使用自己的限制并沿用顶层游戏列表，实际 %d %v", alice.DailyLimit, alice.Games)
+	}
+	if want := filepath.Join(dir, "state-alice.json"); alice.StateFile != want {
+		t.Errorf("未设置状态文件时应为 %s，实际 %s", want, alice.StateFile)
+	}
+	if want := filepath.Join(dir, "remaining-alice.json"); alice.Export.RemainingFile != want {
+		t.Errorf("各档案应导出到各自的剩余时间文件 %s，实际 %s", want, alice.Export.RemainingFile)
+	}
+	if len(alice.Profiles) != 0 {
+		t.Error("档案配置不应再包含 profiles")
+	}
+
+	bob, _ := cfg.ForProfile("bob")
+	if bob.DailyLimit != 120 || bob.Games[0] != "minecraft.exe" {
+		t.Errorf("bob 应沿用顶层限制并使用自己的游戏列表，实际 %d %v", bob.DailyLimit, bob.Games)
+	}
+	if want := filepath.Join(dir, "bob", "state.json"); bob.StateFile != want {
+		t.Errorf("档案的相对状态文件路径应基于配置文件目录 %s，实际 %s", want, bob.StateFile)
+	}
+
+	if _, err := cfg.ForProfile("carol"); err == nil {
+		t.Error("未知档案应返回错误")
+	}
+}
+
+func TestValidate_Profiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []ProfileConfig
+	}{
+		{name: "名称为空", profiles: []ProfileConfig{{Users: []string{"a"}}}},
+		{name: "名称重复", profiles: []ProfileConfig{{Name: "a", Users: []string{"a"}}, {Name: "A", Users: []string{"b"}}}},
+		{name: "未设置账户", profiles: []ProfileConfig{{Name: "a"}}},
+		{name: "限制低于阈值", profiles: []ProfileConfig{{Name: "a", Users: []string{"a"}, DailyLimit: 10}}},
+		{name: "状态文件相同", profiles: []ProfileConfig{
+			{Name: "a", Users: []string{"a"}, StateFile: "s.json"},
+			{Name: "b", Users: []string{"b"}, StateFile: "s.json"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Profiles = tt.profiles
+			if err := cfg.Validate(); err == nil {
+				t.Fatal("预期档案配置无效")
+			}
+		})
+	}
+}
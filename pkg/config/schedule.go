@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DayRule 某一天的游戏规则，零值字段表示沿用更通用的规则
+type DayRule struct {
+	DailyLimit     Minutes  `yaml:"dailyLimit"`     // 当天的每日限制，0 表示沿用
+	AllowedWindows []string `yaml:"allowedWindows"` // 允许游戏的时段，格式 "HH:MM-HH:MM"，为空表示不限制
+}
+
+// 日程分组键，优先级：具体星期 > weekday/weekend > all
+const (
+	DayKeyAll     = "all"
+	DayKeyWeekday = "weekday"
+	DayKeyWeekend = "weekend"
+)
+
+// dayKey 返回星期对应的配置键，如 "monday"
+func dayKey(day time.Weekday) string {
+	return strings.ToLower(day.String())
+}
+
+// groupKey 返回星期所属的分组键
+func groupKey(day time.Weekday) string {
+	if day == time.Saturday || day == time.Sunday {
+		return DayKeyWeekend
+	}
+	return DayKeyWeekday
+}
+
+// isDayKey 判断是否为合法的日程键
+func isDayKey(key string) bool {
+	switch key {
+	case DayKeyAll, DayKeyWeekday, DayKeyWeekend:
+		return true
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if key == dayKey(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleFor 返回 t 所在日（按配置时区）生效的规则。
+// 各字段按 具体星期 > weekday/weekend > all > 顶层配置 的顺序取第一个设置的值。
+func (c *Config) RuleFor(t time.Time) DayRule {
+	rule := DayRule{DailyLimit: c.DailyLimit}
+	if len(c.Days) == 0 {
+		return rule
+	}
+
+	days := make(map[string]DayRule, len(c.Days))
+	for key, r := range c.Days {
+		days[strings.ToLower(key)] = r
+	}
+
+	day := c.localTime(t).Weekday()
+	for _, key := range []string{DayKeyAll, groupKey(day), dayKey(day)} {
+		r, ok := days[key]
+		if !ok {
+			continue
+		}
+		if r.DailyLimit > 0 {
+			rule.DailyLimit = r.DailyLimit
+		}
+		if len(r.AllowedWindows) > 0 {
+			rule.AllowedWindows = r.AllowedWindows
+		}
+	}
+	return rule
+}
+
+// AllowedAt 判断 t 是否处于当天允许游戏的时段内，未配置时段时总是允许
+func (c *Config) AllowedAt(t time.Time) bool {
+	rule := c.RuleFor(t)
+	if len(rule.AllowedWindows) == 0 {
+		return true
+	}
+
+	local := c.localTime(t)
+	minute := local.Hour()*60 + local.Minute()
+	for _, s := range rule.AllowedWindows {
+		w, err := parseWindow(s)
+		if err != nil {
+			continue
+		}
+		if w.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAllowed 返回 t 之后最近一次进入允许游戏时段的时刻（按配置时区），t 已在允许时段内时返回 t；
+// 一周内都没有允许的时段时返回零值
+func (c *Config) NextAllowed(t time.Time) time.Time {
+	if c.AllowedAt(t) {
+		return t
+	}
+
+	local := c.localTime(t)
+	for d := 0; d <= 7; d++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+d, 0, 0, 0, 0, local.Location())
+		// 候选时刻：当天零点（当天不限制时段时）与各时段的开始
+		starts := []time.Time{day}
+		for _, s := range c.RuleFor(day).AllowedWindows {
+			if w, err := parseWindow(s); err == nil {
+				starts = append(starts, day.Add(time.Duration(w.start)*time.Minute))
+			}
+		}
+
+		var next time.Time
+		for _, start := range starts {
+			if start.After(t) && c.AllowedAt(start) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+		if !next.IsZero() {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// QuietAt 判断 t 是否处于 notifications.quietHours 静默时段内，未配置或格式无效时返回 false
+func (c *Config) QuietAt(t time.Time) bool {
+	if c.Notifications.QuietHours == "" {
+		return false
+	}
+	w, err := parseWindow(c.Notifications.QuietHours)
+	if err != nil {
+		return false
+	}
+	local := c.localTime(t)
+	return w.contains(local.Hour()*60 + local.Minute())
+}
+
+// localTime 将 t 转换到配置时区，时区无效时使用本地时区
+func (c *Config) localTime(t time.Time) time.Time {
+	loc, err := c.Location()
+	if err != nil {
+		loc = time.Local
+	}
+	return t.In(loc)
+}
+
+// timeWindow 一天内的时段（分钟），end 小于 start 时表示跨越午夜
+type timeWindow struct {
+	start, end int
+}
+
+// contains 判断一天中的第 minute 分钟是否在时段内（含开始，不含结束）
+func (w timeWindow) contains(minute int) bool {
+	if w.start < w.end {
+		return minute >= w.start && minute < w.end
+	}
+	// 跨午夜，如 22:00-01:00 覆盖当天 22:00 之后与 01:00 之前
+	return minute >= w.start || minute < w.end
+}
+
+// parseWindow 解析 "HH:MM-HH:MM" 格式的时段
+func parseWindow(s string) (timeWindow, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return timeWindow{}, fmt.Errorf("无效的时段 %q，应为 HH:MM-HH:MM 格式", s)
+	}
+
+	var bounds [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return timeWindow{}, fmt.Errorf("无效的时段 %q，应为 HH:MM-HH:MM 格式", s)
+		}
+		bounds[i] = t.Hour()*60 + t.Minute()
+	}
+	if bounds[0] == bounds[1] {
+		return timeWindow{}, fmt.Errorf("时段 %q 的开始与结束不能相同", s)
+	}
+	return timeWindow{start: bounds[0], end: bounds[1]}, nil
+}
+
+// validateDays 验证日程配置
+func (c *Config) validateDays() error {
+	for key, rule := range c.Days {
+		if !isDayKey(strings.ToLower(key)) {
+			return fmt.Errorf("无效的日程键 %q，可选 monday..sunday、weekday、weekend、all", key)
+		}
+		if rule.DailyLimit < 0 {
+			return fmt.Errorf("日程 %s 的每日限制不能为负数", key)
+		}
+		if rule.DailyLimit > 0 && (c.FirstThreshold >= rule.DailyLimit || c.FinalThreshold >= rule.DailyLimit) {
+			return fmt.Errorf("警告阈值必须小于日程 %s 的每日时间限制 (%d 分钟)", key, rule.DailyLimit)
+		}
+		for _, window := range rule.AllowedWindows {
+			if _, err := parseWindow(window); err != nil {
+				return fmt.Errorf("日程 %s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SoftLimitFor 返回 t 所在日期生效的软限制，未启用时返回 0。
+// 日程把当天的每日限制调得比软限制更低时，软限制随之降低
+func (c *Config) SoftLimitFor(t time.Time) Minutes {
+	soft := c.TimeLimit.SoftLimit
+	if soft <= 0 {
+		return 0
+	}
+	if hard := c.RuleFor(t).DailyLimit; hard < soft {
+		return hard
+	}
+	return soft
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func scheduleConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.Timezone = "UTC"
+	cfg.Days = map[string]DayRule{
+		"all":     {AllowedWindows: []string{"07:00-21:00"}},
+		"weekend": {DailyLimit: 180},
+		"Friday":  {DailyLimit: 150, AllowedWindows: []string{"07:00-12:00", "10:00-23:00"}},
+		"sunday":  {AllowedWindows: []string{"22:00-01:00"}},
+	}
+	return cfg
+}
+
+func TestRuleFor_Precedence(t *testing.T) {
+	cfg := scheduleConfig()
+
+	tests := []struct {
+		name    string
+		date    time.Time
+		limit   Minutes
+		windows []string
+	}{
+		{name: "周一仅继承 all", date: time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC), limit: 120, windows: []string{"07:00-21:00"}},
+		{name: "周五具体星期覆盖", date: time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC), limit: 150, windows: []string{"07:00-12:00", "10:00-23:00"}},
+		{name: "周六使用周末限制与 all 时段", date: time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC), limit: 180, windows: []string{"07:00-21:00"}},
+		{name: "周日周末限制加具体时段", date: time.Date(2026, 2, 15, 12, 0, 0, 0, time.UTC), limit: 180, windows: []string{"22:00-01:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := cfg.RuleFor(tt.date)
+			if rule.DailyLimit != tt.limit {
+				t.Errorf("每日限制应为 %d，实际为 %d", tt.limit, rule.DailyLimit)
+			}
+			if strings.Join(rule.AllowedWindows, ",") != strings.Join(tt.windows, ",") {
+				t.Errorf("允许时段应为 %v，实际为 %v", tt.windows, rule.AllowedWindows)
+			}
+		})
+	}
+}
+
+func TestAllowedAt_Windows(t *testing.T) {
+	cfg := scheduleConfig()
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		expect bool
+	}{
+		{name: "周一时段内", at: time.Date(2026, 2, 9, 20, 59, 0, 0, time.UTC), expect: true},
+		{name: "周一时段结束", at: time.Date(2026, 2, 9, 21, 0, 0, 0, time.UTC), expect: false},
+		{name: "周五重叠时段", at: time.Date(2026, 2, 13, 11, 0, 0, 0, time.UTC), expect: true},
+		{name: "周五第二个时段", at: time.Date(2026, 2, 13, 22, 30, 0, 0, time.UTC), expect: true},
+		{name: "周五时段前", at: time.Date(2026, 2, 13, 6, 0, 0, 0, time.UTC), expect: false},
+		{name: "周日跨午夜时段晚间", at: time.Date(2026, 2, 15, 23, 0, 0, 0, time.UTC), expect: true},
+		{name: "周日跨午夜时段凌晨", at: time.Date(2026, 2, 15, 0, 30, 0, 0, time.UTC), expect: true},
+		{name: "周日白天", at: time.Date(2026, 2, 15, 12, 0, 0, 0, time.UTC), expect: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.AllowedAt(tt.at); got != tt.expect {
+				t.Errorf("AllowedAt(%v) 应为 %v，实际为 %v", tt.at, tt.expect, got)
+			}
+		})
+	}
+
+	if !DefaultConfig().AllowedAt(time.Now()) {
+		t.Error("未配置日程时应总是允许")
+	}
+}
+
+func TestNextAllowed(t *testing.T) {
+	cfg := scheduleConfig()
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		expect time.Time
+	}{
+		{name: "时段内返回当前时刻", at: time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC), expect: time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC)},
+		{name: "当天时段前", at: time.Date(2026, 2, 9, 6, 0, 0, 0, time.UTC), expect: time.Date(2026, 2, 9, 7, 0, 0, 0, time.UTC)},
+		{name: "当天时段后到次日", at: time.Date(2026, 2, 9, 21, 0, 0, 0, time.UTC), expect: time.Date(2026, 2, 10, 7, 0, 0, 0, time.UTC)},
+		{name: "周日跨午夜时段的凌晨部分", at: time.Date(2026, 2, 14, 21, 30, 0, 0, time.UTC), expect: time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "周日白天到晚间时段", at: time.Date(2026, 2, 15, 12, 0, 0, 0, time.UTC), expect: time.Date(2026, 2, 15, 22, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.NextAllowed(tt.at); !got.Equal(tt.expect) {
+				t.Errorf("NextAllowed(%v) 应为 %v，实际为 %v", tt.at, tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestValidate_Days(t *testing.T) {
+	tests := []struct {
+		name string
+		days map[string]DayRule
+	}{
+		{name: "未知键", days: map[string]DayRule{"holiday": {DailyLimit: 60}}},
+		{name: "时段格式错误", days: map[string]DayRule{"all": {AllowedWindows: []string{"7-21"}}}},
+		{name: "时段开始等于结束", days: map[string]DayRule{"all": {AllowedWindows: []string{"08:00-08:00"}}}},
+		{name: "限制不大于警告阈值", days: map[string]DayRule{"monday": {DailyLimit: 10}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Days = tt.days
+			if err := cfg.Validate(); err == nil {
+				t.Fatal("预期日程配置无效时返回错误")
+			}
+		})
+	}
+}
+
+func TestSoftLimitFor_CappedByDayLimit(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DailyLimit = 180
+	cfg.TimeLimit.SoftLimit = 120
+	cfg.Days = map[string]DayRule{"monday": {DailyLimit: 60}}
+
+	monday := time.Date(2026, 2, 9, 12, 0, 0, 0, time.Local)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	if got := cfg.SoftLimitFor(tuesday); got != 120 {
+		t.Errorf("周二软限制应为 120，实际 %d", got)
+	}
+	if got := cfg.SoftLimitFor(monday); got != 60 {
+		t.Errorf("当天限制低于软限制时软限制应随之降低，实际 %d", got)
+	}
+
+	cfg.TimeLimit.SoftLimit = 0
+	if got := cfg.SoftLimitFor(tuesday); got != 0 {
+		t.Errorf("未启用软限制时应返回 0，实际 %d", got)
+	}
+}
+
+func TestQuietAt(t *testing.T) {
+	day := func(hour, minute int) time.Time { return time.Date(2026, 2, 9, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		quiet  string
+		at     time.Time
+		expect bool
+	}{
+		{name: "白天时段内", quiet: "13:00-14:30", at: day(13, 0), expect: true},
+		{name: "白天时段结束", quiet: "13:00-14:30", at: day(14, 30), expect: false},
+		{name: "白天时段前", quiet: "13:00-14:30", at: day(12, 59), expect: false},
+		{name: "跨午夜晚间", quiet: "22:00-07:00", at: day(23, 15), expect: true},
+		{name: "跨午夜凌晨", quiet: "22:00-07:00", at: day(6, 59), expect: true},
+		{name: "跨午夜白天", quiet: "22:00-07:00", at: day(12, 0), expect: false},
+		{name: "未配置", quiet: "", at: day(23, 0), expect: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Timezone = "UTC"
+			cfg.Notifications.QuietHours = tt.quiet
+			if got := cfg.QuietAt(tt.at); got != tt.expect {
+				t.Errorf("QuietAt(%v) 在 %q 下应为 %v，实际为 %v", tt.at, tt.quiet, tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestValidate_QuietHours(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Notifications.QuietHours = "22:00"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("预期无效的静默时段返回错误")
+	}
+}
+
+func TestValidate_NotificationSound(t *testing.T) {
+	for _, sound := range []string{"", "Exclamation", "beep", `C:\Sounds\bell.wav`, "~/bell.WAV"} {
+		cfg := DefaultConfig()
+		cfg.Notifications.Sound = sound
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("提示音 %q 应有效，实际错误: %v", sound, err)
+		}
+	}
+	for _, sound := range []string{"Ding", `C:\Sounds\bell.mp3`} {
+		cfg := DefaultConfig()
+		cfg.Notifications.Sound = sound
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("提示音 %q 应返回错误", sound)
+		}
+	}
+}
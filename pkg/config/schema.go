@@ -0,0 +1,118 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/yourusername/game-control/pkg/process"
+)
+
+// durationPattern 时长字符串（time.ParseDuration 格式，如 "90m"、"1h30m"）
+const durationPattern = `^\s*\d+\s*$|^\s*(\d+(\.\d+)?(h|m|s|ms|us|µs|ns))+\s*$`
+
+// schemaTypes 自定义解析规则的类型在 YAML 中的写法
+var schemaTypes = map[reflect.Type]map[string]any{
+	reflect.TypeOf(Minutes(0)): {
+		"type":        []string{"integer", "string"},
+		"minimum":     0,
+		"pattern":     durationPattern,
+		"description": `整数分钟或时长字符串（如 "2h30m"）`,
+	},
+	reflect.TypeOf(Threshold{}): {
+		"type":        []string{"integer", "string"},
+		"minimum":     0,
+		"pattern":     durationPattern + `|^\s*\d{1,2}(\.\d+)?\s*%\s*$`,
+		"description": `整数分钟、时长字符串（如 "15m"）或每日限制的百分比（大于 0% 且小于 100%，如 "20%"）`,
+	},
+}
+
+// schemaRules 按 YAML 键路径补充的约束：取值范围、可选值与格式。列表元素的路径以 "[]" 结尾，映射的值以 ".*" 结尾；
+// 可选值为程序使用的小写写法（加载时不区分大小写），空字符串表示使用默认值
+var schemaRules = map[string]map[string]any{
+	"resetTime":                      {"pattern": `^\d{2}:\d{2}$`},
+	"language":                       {"enum": []string{"", "zh", "en"}},
+	"enforcement.mode":               {"enum": []string{"", ModeEnforce, ModeMonitor}},
+	"enforcement.onLimit":            {"enum": []string{"", ActionTerminate, ActionSuspend, ActionLock, ActionLogoff}},
+	"enforcement.graceSeconds":       {"minimum": 0},
+	"enforcement.escalation[]":       {"minimum": 0},
+	"logging.level":                  {"enum": []string{"", "debug", "info", "warn", "error"}},
+	"logging.heartbeatSeconds":       {"minimum": 0},
+	"idle.pauseAfterSeconds":         {"minimum": 0},
+	"controller.saveIntervalSeconds": {"minimum": 0},
+	"controller.slowScanPercent":     {"minimum": 0, "maximum": 100},
+	"tracking.minSessionSeconds":     {"minimum": 0},
+	"tracking.stopDebounceScans":     {"minimum": 0},
+	"tracking.minCpuPercent":         {"minimum": 0},
+	"instance.staleLockSeconds":      {"minimum": 0},
+	"watchdog.intervalSeconds":       {"minimum": 0},
+	"notifications.quietHours":       {"pattern": `^$|^\d{2}:\d{2}-\d{2}:\d{2}$`},
+	"notifications.timeoutSeconds":   {"minimum": 0},
+	"earn.apps[].ratio":              {"minimum": 1},
+	"matching.mode":                  {"enum": append([]string{""}, process.MatchModes...)},
+	"days": {"propertyNames": map[string]any{"enum": []string{
+		DayKeyAll, DayKeyWeekday, DayKeyWeekend,
+		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+	}}},
+	"days.*.allowedWindows[]": {"pattern": `^\d{2}:\d{2}-\d{2}:\d{2}$`},
+}
+
+// Schema 返回描述配置文件结构的 JSON Schema（draft 2020-12），供编辑器校验与自动补全。
+// 由 Config 的 yaml 标签反射生成，未知字段与加载时一样视为错误；跨字段的约束（如启用加密时必须设置 hmacKey）仍只由 Validate 检查
+func Schema() map[string]any {
+	schema := typeSchema(reflect.TypeOf(Config{}), "")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "game-control 配置"
+	return schema
+}
+
+// typeSchema 返回类型 t 的 schema，path 为其 YAML 键路径
+func typeSchema(t reflect.Type, path string) map[string]any {
+	schema := make(map[string]any)
+	if custom, ok := schemaTypes[t]; ok {
+		for k, v := range custom {
+			schema[k] = v
+		}
+	} else {
+		switch t.Kind() {
+		case reflect.Struct:
+			properties := make(map[string]any)
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+				if name == "-" || !field.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = strings.ToLower(field.Name)
+				}
+				child := name
+				if path != "" {
+					child = path + "." + name
+				}
+				properties[name] = typeSchema(field.Type, child)
+			}
+			schema["type"] = "object"
+			schema["properties"] = properties
+			schema["additionalProperties"] = false
+		case reflect.Slice:
+			schema["type"] = []string{"array", "null"}
+			schema["items"] = typeSchema(t.Elem(), path+"[]")
+		case reflect.Map:
+			schema["type"] = []string{"object", "null"}
+			schema["additionalProperties"] = typeSchema(t.Elem(), path+".*")
+		case reflect.String:
+			schema["type"] = "string"
+		case reflect.Bool:
+			schema["type"] = "boolean"
+		case reflect.Int, reflect.Int64, reflect.Int32:
+			schema["type"] = "integer"
+		case reflect.Float64, reflect.Float32:
+			schema["type"] = "number"
+		}
+	}
+
+	for k, v := range schemaRules[path] {
+		schema[k] = v
+	}
+	return schema
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configJSON 将配置按 YAML 键序列化为 JSON 再解析回来，即编辑器按 schema 校验时看到的数据
+func configJSON(t *testing.T, c *Config) any {
+	t.Helper()
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		t.Fatalf("序列化配置失败: %v", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("转换为 JSON 失败: %v", err)
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		t.Fatalf("解析 JSON 失败: %v", err)
+	}
+	return value
+}
+
+// validateSchema 按 schema 中用到的关键字（type、properties、additionalProperties、items、enum、
+// minimum、maximum、pattern、propertyNames）校验 JSON 值，返回所有不符合之处
+func validateSchema(schema map[string]any, value any, path string) []string {
+	// 先经过 JSON 往返，使 schema 中的 []string、int 等与 JSON 解析出的类型一致
+	raw, _ := json.Marshal(schema)
+	var s map[string]any
+	_ = json.Unmarshal(raw, &s)
+	return validateValue(s, value, path)
+}
+
+func validateValue(s map[string]any, value any, path string) []string {
+	var errs []string
+	fail := func(format string, args ...any) {
+		errs = append(errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types, ok := s["type"]; ok {
+		var allowed []string
+		switch v := types.(type) {
+		case string:
+			allowed = []string{v}
+		case []any:
+			for _, t := range v {
+				allowed = append(allowed, t.(string))
+			}
+		}
+		if !slices.Contains(allowed, jsonType(value)) &&
+			!(jsonType(value) == "integer" && slices.Contains(allowed, "number")) {
+			fail("类型 %s 不在 %v 中", jsonType(value), allowed)
+			return errs
+		}
+	}
+	if enum, ok := s["enum"].([]any); ok && !slices.Contains(enum, value) {
+		fail("%v 不在可选值 %v 中", value, enum)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if minimum, ok := s["minimum"].(float64); ok && v < minimum {
+			fail("%v 小于最小值 %v", v, minimum)
+		}
+		if maximum, ok := s["maximum"].(float64); ok && v > maximum {
+			fail("%v 大于最大值 %v", v, maximum)
+		}
+	case string:
+		if pattern, ok := s["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			fail("%q 不匹配 %s", v, pattern)
+		}
+	case []any:
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range v {
+				errs = append(errs, validateValue(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]any:
+		properties, _ := s["properties"].(map[string]any)
+		for key, item := range v {
+			child := strings.TrimPrefix(path+"."+key, ".")
+			if names, ok := s["propertyNames"].(map[string]any); ok {
+				errs = append(errs, validateValue(names, key, child)...)
+			}
+			if prop, ok := properties[key].(map[string]any); ok {
+				errs = append(errs, validateValue(prop, item, child)...)
+				continue
+			}
+			switch extra := s["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					errs = append(errs, child+": 未知字段")
+				}
+			case map[string]any:
+				errs = append(errs, validateValue(extra, item, child)...)
+			}
+		}
+	}
+	return errs
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return reflect.TypeOf(value).String()
+}
+
+func TestSchema_ValidatesDefaultConfig(t *testing.T) {
+	if errs := validateSchema(Schema(), configJSON(t, DefaultConfig()), ""); len(errs) > 0 {
+		t.Errorf("默认配置应符合 schema:\n%s", strings.Join(errs, "\n"))
+	}
+}
+
+func TestSchema_ValidatesFullConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FirstThresholdSetting = Threshold{Percent: 20}
+	cfg.Enforcement = EnforcementConfig{Mode: ModeMonitor, OnLimit: ActionSuspend, Escalation: []int{300, 60}}
+	cfg.Controller.SlowScanPercent = 80
+	cfg.Days = map[string]DayRule{"weekend": {DailyLimit: 180, AllowedWindows: []string{"09:00-21:00"}}}
+	cfg.Earn = EarnConfig{Apps: []EarnApp{{Name: "typing.exe", Ratio: 2}}, MaxMinutes: 30}
+	cfg.Profiles = []ProfileConfig{{Name: "kid", Users: []string{"kid"}}}
+
+	if errs := validateSchema(Schema(), configJSON(t, cfg), ""); len(errs) > 0 {
+		t.Errorf("完整配置应符合 schema:\n%s", strings.Join(errs, "\n"))
+	}
+}
+
+func TestSchema_RejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(doc map[string]any)
+		want   string
+	}{
+		{"未知字段", func(doc map[string]any) { doc["dailyLimt"] = 90 }, "dailyLimt"},
+		{"执行模式", func(doc map[string]any) { doc["enforcement"] = map[string]any{"mode": "strict"} }, "enforcement.mode"},
+		{"百分比范围", func(doc map[string]any) {
+			doc["controller"] = map[string]any{"slowScanPercent": 150.0}
+		}, "controller.slowScanPercent"},
+		{"阈值格式", func(doc map[string]any) { doc["firstThreshold"] = "soon" }, "firstThreshold"},
+		{"日程键", func(doc map[string]any) { doc["days"] = map[string]any{"funday": map[string]any{}} }, "days.funday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := configJSON(t, DefaultConfig()).(map[string]any)
+			tt.mutate(doc)
+			errs := validateSchema(Schema(), doc, "")
+			if !slices.ContainsFunc(errs, func(e string) bool { return strings.HasPrefix(e, tt.want) }) {
+				t.Errorf("应报告 %s，实际 %v", tt.want, errs)
+			}
+		})
+	}
+}
+
+func TestSchema_RulesMatchFields(t *testing.T) {
+	paths := make(map[string]bool)
+	var walk func(typ reflect.Type, path string)
+	walk = func(typ reflect.Type, path string) {
+		paths[path] = true
+		if _, custom := schemaTypes[typ]; custom {
+			return
+		}
+		switch typ.Kind() {
+		case reflect.Struct:
+			for i := 0; i < typ.NumField(); i++ {
+				name, _, _ := strings.Cut(typ.Field(i).Tag.Get("yaml"), ",")
+				if name != "-" && name != "" {
+					walk(typ.Field(i).Type, strings.TrimPrefix(path+"."+name, "."))
+				}
+			}
+		case reflect.Slice:
+			walk(typ.Elem(), path+"[]")
+		case reflect.Map:
+			walk(typ.Elem(), path+".*")
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+
+	for path := range schemaRules {
+		if !paths[path] {
+			t.Errorf("schemaRules 中的 %s 不对应任何配置项", path)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Threshold 配置文件中的警告阈值。
+// 除整数分钟（15）与时长字符串（"15m"）外，还可以写每日限制的百分比（"20%"），
+// 加载时按每日限制换算为分钟数，每日限制改变后阈值随之按比例调整。
+type Threshold struct {
+	Minutes Minutes // 绝对分钟数，Percent 非 0 时不使用
+	Percent float64 // 每日限制的百分比，0 表示使用 Minutes
+}
+
+// UnmarshalYAML 解析分钟数、时长字符串或百分比
+func (t *Threshold) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("第 %d 行: 警告阈值必须是分钟数、时长字符串或百分比", value.Line)
+	}
+
+	parsed, err := ParseThreshold(value.Value)
+	if err != nil {
+		return fmt.Errorf("第 %d 行: %w", value.Line, err)
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalYAML 百分比阈值保存为 "20%"，其余保存为整数分钟
+func (t Threshold) MarshalYAML() (interface{}, error) {
+	if t.Percent != 0 {
+		return strconv.FormatFloat(t.Percent, 'f', -1, 64) + "%", nil
+	}
+	return int(t.Minutes), nil
+}
+
+// ParseThreshold 解析百分比（"20%"），否则按 ParseMinutes 解析
+func ParseThreshold(s string) (Threshold, error) {
+	s = strings.TrimSpace(s)
+	if percent, ok := strings.CutSuffix(s, "%"); ok {
+		p, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil || p <= 0 || p >= 100 {
+			return Threshold{}, fmt.Errorf("无效的百分比阈值 %q，应在 0%% 到 100%% 之间", s)
+		}
+		return Threshold{Percent: p}, nil
+	}
+
+	m, err := ParseMinutes(s)
+	if err != nil {
+		return Threshold{}, err
+	}
+	return Threshold{Minutes: m}, nil
+}
+
+// Resolve 按每日限制换算为分钟数，百分比四舍五入到整分钟
+func (t Threshold) Resolve(limit Minutes) Minutes {
+	if t.Percent == 0 {
+		return t.Minutes
+	}
+	return Minutes(math.Round(float64(limit) * t.Percent / 100))
+}
+
+// resolveThresholds 按当前每日限制换算配置文件中的警告阈值。
+// 配置文件未设置的阈值（零值）保持 FirstThreshold/FinalThreshold 原值；
+// 设置了 warnings 时以其中最大、最小的阈值作为 FirstThreshold/FinalThreshold
+func (c *Config) resolveThresholds() {
+	if c.FirstThresholdSetting != (Threshold{}) {
+		c.FirstThreshold = c.FirstThresholdSetting.Resolve(c.DailyLimit)
+	}
+	if c.FinalThresholdSetting != (Threshold{}) {
+		c.FinalThreshold = c.FinalThresholdSetting.Resolve(c.DailyLimit)
+	}
+	if levels := c.WarningLevels(); len(c.Warnings) > 0 {
+		c.FirstThreshold = levels[0]
+		c.FinalThreshold = levels[len(levels)-1]
+	}
+}
+
+// WarningLevels 返回从大到小排列、去重后的提醒阈值（分钟）。
+// 设置了 warnings 时按当前每日限制换算，否则为 FirstThreshold 与 FinalThreshold；最后一项为最后提醒
+func (c *Config) WarningLevels() []Minutes {
+	var levels []Minutes
+	if len(c.Warnings) > 0 {
+		for _, t := range c.Warnings {
+			levels = append(levels, t.Resolve(c.DailyLimit))
+		}
+	} else {
+		levels = []Minutes{c.FirstThreshold, c.FinalThreshold}
+	}
+	slices.Sort(levels)
+	slices.Reverse(levels)
+	return slices.Compact(levels)
+}
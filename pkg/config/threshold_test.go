@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Threshold
+	}{
+		{input: "15", want: Threshold{Minutes: 15}},
+		{input: "15m", want: Threshold{Minutes: 15}},
+		{input: "20%", want: Threshold{Percent: 20}},
+		{input: " 12.5 % ", want: Threshold{Percent: 12.5}},
+	}
+	for _, tt := range tests {
+		got, err := ParseThreshold(tt.input)
+		if err != nil {
+			t.Errorf("%q 解析失败: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q 应解析为 %+v，实际 %+v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"0%", "100%", "abc%", "-5%"} {
+		if _, err := ParseThreshold(input); err == nil {
+			t.Errorf("%q 应解析失败", input)
+		}
+	}
+}
+
+func TestThresholdResolve(t *testing.T) {
+	percent := Threshold{Percent: 20}
+	if got := percent.Resolve(120); got != 24 {
+		t.Errorf("120 分钟的 20%% 应为 24 分钟，实际 %d", got)
+	}
+	if got := percent.Resolve(60); got != 12 {
+		t.Errorf("每日限制改为 60 分钟后应为 12 分钟，实际 %d", got)
+	}
+	if got := (Threshold{Minutes: 15}).Resolve(60); got != 15 {
+		t.Errorf("整数阈值不应随每日限制变化，实际 %d", got)
+	}
+}
+
+func loadThresholdConfig(t *testing.T, content string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("无法创建临时文件: %v", err)
+	}
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	return cfg
+}
+
+func TestLoadFromFile_PercentThresholds(t *testing.T) {
+	cfg := loadThresholdConfig(t, `dailyLimit: 120
+resetTime: "08:00"
+games: ["game.exe"]
+firstThreshold: "20%"
+finalThreshold: 5`)
+	if cfg.FirstThreshold != 24 || cfg.FinalThreshold != 5 {
+		t.Fatalf("阈值应换算为 24 与 5 分钟，实际 %d 与 %d", cfg.FirstThreshold, cfg.FinalThreshold)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("配置应有效: %v", err)
+	}
+
+	// 每日限制改变后重新加载，百分比阈值随之调整
+	cfg = loadThresholdConfig(t, `dailyLimit: 60
+resetTime: "08:00"
+games: ["game.exe"]
+firstThreshold: "20%"
+finalThreshold: "5%"`)
+	if cfg.FirstThreshold != 12 || cfg.FinalThreshold != 3 {
+		t.Fatalf("每日限制为 60 分钟时阈值应为 12 与 3 分钟，实际 %d 与 %d", cfg.FirstThreshold, cfg.FinalThreshold)
+	}
+
+	// 换算后仍需满足 final <= first < limit
+	cfg = loadThresholdConfig(t, `dailyLimit: 60
+resetTime: "08:00"
+games: ["game.exe"]
+firstThreshold: "10%"
+finalThreshold: 10`)
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("换算后最后警告阈值大于第一次警告阈值时应验证失败")
+	}
+}
+
+func TestForProfile_PercentThresholds(t *testing.T) {
+	cfg := loadThresholdConfig(t, `dailyLimit: 120
+resetTime: "08:00"
+games: ["game.exe"]
+firstThreshold: "20%"
+finalThreshold: 5
+profiles:
+  - name: alice
+    users: ["alice"]
+    dailyLimit: 60`)
+
+	alice, err := cfg.ForProfile("alice")
+	if err != nil {
+		t.Fatalf("ForProfile 失败: %v", err)
+	}
+	if alice.FirstThreshold != 12 || alice.FinalThreshold != 5 {
+		t.Errorf("档案的百分比阈值应按档案限制换算为 12 分钟，实际 %d 与 %d", alice.FirstThreshold, alice.FinalThreshold)
+	}
+}
+
+func TestLoadFromFile_WarningLadder(t *testing.T) {
+	cfg := loadThresholdConfig(t, `dailyLimit: 120
+resetTime: "08:00"
+games: ["game.exe"]
+warnings: [5, "30m", 1, "12.5%", 5]`)
+
+	want := []Minutes{30, 15, 5, 1}
+	if got := cfg.WarningLevels(); !slices.Equal(got, want) {
+		t.Fatalf("提醒阈值应从大到小排列并去重，期望 %v，实际 %v", want, got)
+	}
+	if cfg.FirstThreshold != 30 || cfg.FinalThreshold != 1 {
+		t.Errorf("设置 warnings 时首次/最后阈值应为其中最大与最小值，实际 %d 与 %d", cfg.FirstThreshold, cfg.FinalThreshold)
+	}
+}
+
+func TestWarningLevels_TwoFieldForm(t *testing.T) {
+	cfg := &Config{DailyLimit: 120, FirstThreshold: 15, FinalThreshold: 5}
+	if got := cfg.WarningLevels(); !slices.Equal(got, []Minutes{15, 5}) {
+		t.Errorf("未设置 warnings 时应使用 firstThreshold/finalThreshold，实际 %v", got)
+	}
+}
+
+func TestValidate_WarningLadderTooLarge(t *testing.T) {
+	cfg := loadThresholdConfig(t, `dailyLimit: 60
+resetTime: "08:00"
+games: ["game.exe"]
+warnings: [90, 15, 5]`)
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("大于等于每日限制的提醒阈值应验证失败")
+	}
+}
+
+func TestSaveToFile_KeepsPercentThreshold(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FirstThresholdSetting = Threshold{Percent: 20}
+	cfg.FinalThreshold = 3
+	cfg.FinalThresholdSetting = Threshold{}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := cfg.SaveToFile(path); err != nil {
+		t.Fatalf("保存配置失败: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取配置失败: %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("解析保存的配置失败: %v", err)
+	}
+	if raw["firstThreshold"] != "20%" || raw["finalThreshold"] != 3 {
+		t.Errorf("应保存百分比与分钟数阈值，实际 firstThreshold=%v finalThreshold=%v\n%s",
+			raw["firstThreshold"], raw["finalThreshold"], strings.TrimSpace(string(data)))
+	}
+}
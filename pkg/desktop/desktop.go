@@ -0,0 +1,7 @@
+// Package desktop 查询当前交互桌面的状态（前台窗口等），仅 Windows 提供真实实现。
+package desktop
+
+import "errors"
+
+// ErrUnsupported 当前平台不支持该桌面查询
+var ErrUnsupported = errors.New("desktop query not supported on this platform")
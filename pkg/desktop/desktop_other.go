@@ -0,0 +1,30 @@
+//go:build !windows
+
+package desktop
+
+import "time"
+
+// ForegroundPID 非 Windows 平台不支持
+func ForegroundPID() (int, error) {
+	return 0, ErrUnsupported
+}
+
+// IdleDuration 非 Windows 平台不支持
+func IdleDuration() (time.Duration, error) {
+	return 0, ErrUnsupported
+}
+
+// VisibleWindowPIDs 非 Windows 平台不支持
+func VisibleWindowPIDs() (map[int]bool, error) {
+	return nil, ErrUnsupported
+}
+
+// WindowTitles 非 Windows 平台不支持
+func WindowTitles() (map[int][]string, error) {
+	return nil, ErrUnsupported
+}
+
+// ScreenLocked 非 Windows 平台无法检测锁屏，始终视为未锁定
+func ScreenLocked() (bool, error) {
+	return false, nil
+}
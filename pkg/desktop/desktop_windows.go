@@ -0,0 +1,157 @@
+//go:build windows
+
+package desktop
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32                       = syscall.NewLazyDLL("user32.dll")
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procGetForegroundWindow      = user32.NewProc("GetForegroundWindow")
+	procGetWindowThreadProcessID = user32.NewProc("GetWindowThreadProcessId")
+	procGetLastInputInfo         = user32.NewProc("GetLastInputInfo")
+	procGetTickCount             = kernel32.NewProc("GetTickCount")
+	procEnumWindows              = user32.NewProc("EnumWindows")
+	procIsWindowVisible          = user32.NewProc("IsWindowVisible")
+	procIsIconic                 = user32.NewProc("IsIconic")
+	procGetWindowTextLengthW     = user32.NewProc("GetWindowTextLengthW")
+	procGetWindowTextW           = user32.NewProc("GetWindowTextW")
+	procOpenInputDesktop         = user32.NewProc("OpenInputDesktop")
+	procSwitchDesktop            = user32.NewProc("SwitchDesktop")
+	procCloseDesktop             = user32.NewProc("CloseDesktop")
+)
+
+// EnumWindows 的回调只创建一次：syscall.NewCallback 分配的回调槽位永不释放且总数有限（约 2000 个），
+// 每次枚举新建回调会让长期运行的守护进程在数小时后因 "too many callback functions" 无法恢复地崩溃。
+// 回调不捕获局部变量，每次枚举的处理函数经由 enumVisit 传入，enumMu 保证同一时间只有一次枚举
+var (
+	enumMu              sync.Mutex
+	enumVisit           func(hwnd uintptr)
+	enumWindowsCallback = syscall.NewCallback(func(hwnd uintptr, _ uintptr) uintptr {
+		enumVisit(hwnd)
+		return 1 // 继续枚举
+	})
+)
+
+// enumWindows 对每个顶层窗口调用 visit
+func enumWindows(visit func(hwnd uintptr)) error {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+	enumVisit = visit
+	defer func() { enumVisit = nil }()
+
+	if ret, _, err := procEnumWindows.Call(enumWindowsCallback, 0); ret == 0 {
+		return fmt.Errorf("EnumWindows 调用失败: %w", err)
+	}
+	return nil
+}
+
+// desktopSwitchDesktop 对应 Win32 DESKTOP_SWITCHDESKTOP 访问权限
+const desktopSwitchDesktop = 0x0100
+
+// lastInputInfo 对应 Win32 LASTINPUTINFO
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+// ForegroundPID 返回拥有当前前台窗口的进程 PID
+func ForegroundPID() (int, error) {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return 0, fmt.Errorf("没有前台窗口")
+	}
+
+	var pid uint32
+	procGetWindowThreadProcessID.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	if pid == 0 {
+		return 0, fmt.Errorf("无法获取前台窗口所属进程")
+	}
+	return int(pid), nil
+}
+
+// IdleDuration 返回距离最后一次键盘/鼠标输入的时长
+func IdleDuration() (time.Duration, error) {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	ret, _, err := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		return 0, fmt.Errorf("GetLastInputInfo 调用失败: %w", err)
+	}
+
+	tick, _, _ := procGetTickCount.Call()
+	// 两者均为 32 位毫秒计数，无符号减法可正确处理约 49.7 天的回绕
+	idleMillis := uint32(tick) - info.dwTime
+	return time.Duration(idleMillis) * time.Millisecond, nil
+}
+
+// VisibleWindowPIDs 返回拥有可见且未最小化的顶层窗口的进程 PID 集合
+func VisibleWindowPIDs() (map[int]bool, error) {
+	pids := make(map[int]bool)
+	err := enumWindows(func(hwnd uintptr) {
+		if visible, _, _ := procIsWindowVisible.Call(hwnd); visible == 0 {
+			return
+		}
+		if iconic, _, _ := procIsIconic.Call(hwnd); iconic != 0 {
+			return
+		}
+
+		var pid uint32
+		procGetWindowThreadProcessID.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+		if pid != 0 {
+			pids[int(pid)] = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pids, nil
+}
+
+// WindowTitles 返回每个进程的可见顶层窗口标题（按 PID 索引，跳过无标题的窗口）
+func WindowTitles() (map[int][]string, error) {
+	titles := make(map[int][]string)
+	err := enumWindows(func(hwnd uintptr) {
+		if visible, _, _ := procIsWindowVisible.Call(hwnd); visible == 0 {
+			return
+		}
+		length, _, _ := procGetWindowTextLengthW.Call(hwnd)
+		if length == 0 {
+			return
+		}
+
+		buf := make([]uint16, length+1)
+		n, _, _ := procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+		if n == 0 {
+			return
+		}
+
+		var pid uint32
+		procGetWindowThreadProcessID.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+		if pid != 0 {
+			titles[int(pid)] = append(titles[int(pid)], syscall.UTF16ToString(buf[:n]))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return titles, nil
+}
+
+// ScreenLocked 判断工作站是否已锁定。
+// 锁屏时输入桌面切换为 Winlogon 安全桌面，普通进程无法打开或切换到它，据此判断为锁定
+func ScreenLocked() (bool, error) {
+	desk, _, _ := procOpenInputDesktop.Call(0, 0, desktopSwitchDesktop)
+	if desk == 0 {
+		return true, nil
+	}
+	defer procCloseDesktop.Call(desk)
+
+	switched, _, _ := procSwitchDesktop.Call(desk)
+	return switched == 0, nil
+}
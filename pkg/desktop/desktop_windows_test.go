@@ -0,0 +1,22 @@
+//go:build windows
+
+package desktop
+
+import "testing"
+
+// 守护进程每次扫描都会枚举窗口，反复调用不能耗尽 syscall.NewCallback 的回调槽位（约 2000 个）
+func TestVisibleWindowPIDs_RepeatedCalls(t *testing.T) {
+	for i := 0; i < 2500; i++ {
+		if _, err := VisibleWindowPIDs(); err != nil {
+			t.Fatalf("第 %d 次调用 VisibleWindowPIDs 失败: %v", i+1, err)
+		}
+	}
+}
+
+func TestWindowTitles_RepeatedCalls(t *testing.T) {
+	for i := 0; i < 2500; i++ {
+		if _, err := WindowTitles(); err != nil {
+			t.Fatalf("第 %d 次调用 WindowTitles 失败: %v", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package gamecontrol 提供可嵌入的游戏时间控制引擎。
+// 自定义启动器或界面可直接使用 Engine 计时、查询状态并接收提醒回调，无需通过命令行或导入 internal 包。
+package gamecontrol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/game-control/internal"
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/logger"
+	"github.com/yourusername/game-control/pkg/process"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+// Scanner 引擎依赖的进程扫描能力，默认使用 tasklist/taskkill，嵌入方可替换为自己的实现
+type Scanner = internal.ProcessScanner
+
+// ProcessInfo 扫描到的进程信息
+type ProcessInfo = process.ProcessInfo
+
+// Status 当前配额与活跃游戏进程状态
+type Status = internal.StatusInfo
+
+// ActiveProcess 活跃游戏进程
+type ActiveProcess = internal.ActiveProcess
+
+// ErrAlreadyStarted 引擎已在运行时再次调用 Start
+var ErrAlreadyStarted = errors.New("引擎已在运行")
+
+// Options 引擎选项，零值使用默认设置
+type Options struct {
+	// Scanner 进程扫描器，为 nil 时使用默认实现（并跳过 enforcement.exemptUsers 的进程）
+	Scanner Scanner
+	// Hooks 提醒与超限回调；设置了任一回调时不再弹出默认的桌面提醒
+	Hooks Hooks
+	// ConfigPath 配置文件路径，非空时运行期间检查其是否被外部修改（config_tampered）
+	ConfigPath string
+}
+
+// Engine 游戏时间控制引擎，封装配置、配额状态与控制循环。
+// Status、AddTime 可在 Start 之后从任意 goroutine 调用
+type Engine struct {
+	cfg        *config.Config
+	state      *quota.QuotaState
+	controller *internal.Controller
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// New 按配置创建引擎并加载状态文件（损坏的状态文件会被隔离，见 LoadState）。
+// 全局日志尚未初始化时按配置初始化；输出语言按 cfg.Language 设置（全局生效）
+func New(cfg *config.Config, opts Options) (*Engine, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("配置验证失败: %w", err)
+	}
+	i18n.SetLanguage(cfg.Language)
+	if logger.LogHandle == nil {
+		if _, err := logger.NewLoggerWithOptions(logger.Options{
+			OutputPath: cfg.LogFile,
+			EventsPath: cfg.Logging.EventsPath,
+		}); err != nil {
+			return nil, fmt.Errorf("创建日志记录器失败: %w", err)
+		}
+	}
+
+	state, err := LoadState(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// opts.Scanner 为 nil 时由控制器使用按配置设置的默认扫描器
+	controller := internal.NewControllerWithDeps(cfg, state, opts.Scanner, opts.Hooks.notifier())
+	if opts.ConfigPath != "" {
+		if err := controller.WatchConfigFile(opts.ConfigPath); err != nil {
+			logger.Debugf("不检查配置文件修改: %v", err)
+		}
+	}
+
+	return &Engine{cfg: cfg, state: state, controller: controller}, nil
+}
+
+// Start 在后台启动控制循环并立即返回；ctx 结束或调用 Stop 时保存状态并停止
+func (e *Engine) Start(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cancel != nil {
+		return ErrAlreadyStarted
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- e.controller.RunContext(ctx) }()
+
+	e.cancel = cancel
+	e.done = done
+	return nil
+}
+
+// Stop 停止控制循环并等待状态保存完成，未启动时直接返回
+func (e *Engine) Stop() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cancel == nil {
+		return nil
+	}
+
+	e.cancel()
+	err := <-e.done
+	e.cancel = nil
+	e.done = nil
+	return err
+}
+
+// Status 返回当前状态（实时扫描一次游戏进程）
+func (e *Engine) Status() Status {
+	return e.controller.GetStatus()
+}
+
+// AddTime 将 d（按整秒）计入当天的游戏时间并立即保存，例如记录在其他设备上的游戏时间
+func (e *Engine) AddTime(d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("增加的时间不能为负数: %v", d)
+	}
+	e.state.AddTime(int64(d / time.Second))
+	if err := e.state.SaveToFile(); err != nil {
+		return fmt.Errorf("保存状态失败: %w", err)
+	}
+	return nil
+}
+
+// Config 返回引擎使用的配置
+func (e *Engine) Config() *config.Config {
+	return e.cfg
+}
@@ -0,0 +1,130 @@
+package gamecontrol
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+// fakeScanner 始终返回固定的游戏进程，记录终止调用
+type fakeScanner struct {
+	mu         sync.Mutex
+	processes  []ProcessInfo
+	terminated []int
+}
+
+func (f *fakeScanner) FindGameProcesses(gameNames []string) ([]ProcessInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ProcessInfo(nil), f.processes...), nil
+}
+
+func (f *fakeScanner) TerminateWithRetry(pid int, maxRetries int, retryDelay time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.terminated = append(f.terminated, pid)
+	return nil
+}
+
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Games = []string{"game.exe"}
+	cfg.StateFile = filepath.Join(dir, "state.json")
+	cfg.LogFile = filepath.Join(dir, "game-control.log")
+	return cfg
+}
+
+func TestEngine_StartStatusStop(t *testing.T) {
+	cfg := testConfig(t)
+	scanner := &fakeScanner{processes: []ProcessInfo{{PID: 1234, Name: "game.exe", StartTime: time.Now().Add(-time.Minute)}}}
+
+	engine, err := New(cfg, Options{Scanner: scanner})
+	if err != nil {
+		t.Fatalf("创建引擎失败: %v", err)
+	}
+	if err := engine.Start(context.Background()); err != nil {
+		t.Fatalf("启动失败: %v", err)
+	}
+	if err := engine.Start(context.Background()); !errors.Is(err, ErrAlreadyStarted) {
+		t.Fatalf("重复启动应返回 ErrAlreadyStarted，实际 %v", err)
+	}
+
+	if err := engine.AddTime(30 * time.Minute); err != nil {
+		t.Fatalf("AddTime 失败: %v", err)
+	}
+	if err := engine.AddTime(-time.Minute); err == nil {
+		t.Error("增加负数时间应返回错误")
+	}
+
+	status := engine.Status()
+	if status.AccumulatedTime != 30 || status.RemainingTime != 90 || status.DailyLimit != 120 {
+		t.Errorf("状态应为已用 30 分钟、剩余 90 分钟，实际 %+v", status)
+	}
+	if status.ActiveProcessCount != 1 || status.ActiveProcesses[0].PID != 1234 {
+		t.Errorf("应报告扫描器返回的游戏进程，实际 %+v", status.ActiveProcesses)
+	}
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("停止失败: %v", err)
+	}
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("重复停止应直接返回，实际 %v", err)
+	}
+
+	saved, err := quota.LoadFromFile(cfg)
+	if err != nil {
+		t.Fatalf("停止后应已保存状态: %v", err)
+	}
+	if saved.GetAccumulatedMinutes() != 30 {
+		t.Errorf("保存的累计时间应为 30 分钟，实际 %d", saved.GetAccumulatedMinutes())
+	}
+
+	// 停止后可再次启动，ctx 结束同样会停止控制循环
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("停止后再次启动失败: %v", err)
+	}
+	cancel()
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("ctx 结束后停止失败: %v", err)
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.DailyLimit = 0
+	if _, err := New(cfg, Options{Scanner: &fakeScanner{}}); err == nil {
+		t.Fatal("无效配置应返回错误")
+	}
+}
+
+func TestHooks(t *testing.T) {
+	if (Hooks{}).notifier() != nil {
+		t.Fatal("未设置回调时应使用默认桌面提醒")
+	}
+
+	var calls []string
+	n := Hooks{
+		OnFinalWarning:  func(remaining int) { calls = append(calls, "final") },
+		OnLimitExceeded: func() { calls = append(calls, "limit") },
+	}.notifier()
+	if n == nil {
+		t.Fatal("设置回调后应返回回调通知器")
+	}
+
+	_ = n.NotifyFirstWarning(15)
+	_ = n.NotifyFinalWarning(5)
+	_ = n.NotifyLimitExceeded(time.Time{})
+	_ = n.NotifyTerminationFailed()
+	if len(calls) != 2 || calls[0] != "final" || calls[1] != "limit" {
+		t.Fatalf("只应调用已设置的回调，实际 %v", calls)
+	}
+}
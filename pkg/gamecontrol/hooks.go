@@ -0,0 +1,102 @@
+package gamecontrol
+
+import (
+	"time"
+
+	"github.com/yourusername/game-control/pkg/notifier"
+)
+
+// Hooks 提醒与超限回调，在控制循环的 goroutine 中同步调用，未设置的回调忽略。
+// 与桌面提醒一样，每个提醒每天最多触发一次，静默时段（notifications.quietHours）内不触发
+type Hooks struct {
+	OnFirstWarning      func(remainingMinutes int)
+	OnFinalWarning      func(remainingMinutes int)
+	OnLimitExceeded     func()
+	OnSoftLimit         func(overMinutes, remainingMinutes int)
+	OnTerminationFailed func()
+	// OnAccessDenied 因权限不足无法终止游戏 game 时调用，守护进程运行期间每个游戏只调用一次
+	OnAccessDenied func(game string)
+	OnBreak        func(playedMinutes int, until time.Time)
+	// OnOutsideSchedule 不在允许的游戏时段内关闭游戏时调用，nextAllowed 为下次开放的时刻（一周内没有时零值）
+	OnOutsideSchedule func(nextAllowed time.Time)
+	// OnDailySummary 每日重置时的汇总，仅在 notifications.dailySummary 启用时调用
+	OnDailySummary func(playedMinutes int, limitReached bool, terminations int, gameMinutes map[string]int)
+}
+
+// notifier 返回调用回调的通知器；未设置任何回调时返回 nil，使用默认桌面提醒
+func (h Hooks) notifier() notifier.Notifier {
+	if h.OnFirstWarning == nil && h.OnFinalWarning == nil && h.OnLimitExceeded == nil &&
+		h.OnSoftLimit == nil && h.OnTerminationFailed == nil && h.OnAccessDenied == nil && h.OnBreak == nil && h.OnOutsideSchedule == nil &&
+		h.OnDailySummary == nil {
+		return nil
+	}
+	return hookNotifier{h}
+}
+
+// hookNotifier 将 notifier.Notifier 的调用转发给 Hooks
+type hookNotifier struct {
+	hooks Hooks
+}
+
+func (n hookNotifier) NotifyFirstWarning(remainingMinutes int) error {
+	if n.hooks.OnFirstWarning != nil {
+		n.hooks.OnFirstWarning(remainingMinutes)
+	}
+	return nil
+}
+
+func (n hookNotifier) NotifyFinalWarning(remainingMinutes int) error {
+	if n.hooks.OnFinalWarning != nil {
+		n.hooks.OnFinalWarning(remainingMinutes)
+	}
+	return nil
+}
+
+func (n hookNotifier) NotifyLimitExceeded(time.Time) error {
+	if n.hooks.OnLimitExceeded != nil {
+		n.hooks.OnLimitExceeded()
+	}
+	return nil
+}
+
+func (n hookNotifier) NotifySoftLimit(overMinutes, remainingMinutes int) error {
+	if n.hooks.OnSoftLimit != nil {
+		n.hooks.OnSoftLimit(overMinutes, remainingMinutes)
+	}
+	return nil
+}
+
+func (n hookNotifier) NotifyTerminationFailed() error {
+	if n.hooks.OnTerminationFailed != nil {
+		n.hooks.OnTerminationFailed()
+	}
+	return nil
+}
+
+func (n hookNotifier) NotifyAccessDenied(game string) error {
+	if n.hooks.OnAccessDenied != nil {
+		n.hooks.OnAccessDenied(game)
+	}
+	return nil
+}
+
+func (n hookNotifier) NotifyBreak(playedMinutes int, until time.Time) error {
+	if n.hooks.OnBreak != nil {
+		n.hooks.OnBreak(playedMinutes, until)
+	}
+	return nil
+}
+
+func (n hookNotifier) NotifyOutsideSchedule(nextAllowed time.Time) error {
+	if n.hooks.OnOutsideSchedule != nil {
+		n.hooks.OnOutsideSchedule(nextAllowed)
+	}
+	return nil
+}
+
+func (n hookNotifier) NotifyDailySummary(playedMinutes int, limitReached bool, terminations int, gameMinutes map[string]int) error {
+	if n.hooks.OnDailySummary != nil {
+		n.hooks.OnDailySummary(playedMinutes, limitReached, terminations, gameMinutes)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package gamecontrol
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/logger"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+// LoadState 加载配置对应的状态文件，文件不存在或不可用时返回新的状态。
+// 签名校验失败时记录 state_tampered；内容无法解析或无效时先将文件隔离为 <stateFile>.corrupt-<时间戳>；
+// 停止期间已过重置时间时立即重置并保存（见 resetIfDue）。需要先初始化全局日志
+func LoadState(cfg *config.Config) (*quota.QuotaState, error) {
+	loadedState, err := quota.LoadFromFile(cfg)
+	if errors.Is(err, quota.ErrStateTampered) {
+		logger.LogStateTampered(cfg.StateFile, "签名校验失败，使用新的状态")
+	}
+	if err == nil && loadedState != nil {
+		validateErr := loadedState.Validate()
+		if validateErr == nil {
+			resetIfDue(loadedState)
+			return loadedState, nil
+		}
+		logger.Warnf("状态验证失败，创建新状态: %v", validateErr)
+		err = fmt.Errorf("%w: %v", quota.ErrStateCorrupt, validateErr)
+	}
+	if errors.Is(err, quota.ErrStateCorrupt) {
+		if quarantined, qErr := quota.QuarantineStateFile(cfg.StateFile, time.Now()); qErr != nil {
+			logger.Warnf("%v", qErr)
+		} else {
+			logger.LogStateCorruptQuarantined(cfg.StateFile, quarantined)
+		}
+	}
+
+	qState, err := quota.NewQuotaState(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建配额状态失败: %w", err)
+	}
+	return qState, nil
+}
+
+// resetIfDue 守护进程停止期间已过重置时间时立即重置配额并保存，
+// 使文件中上一周期的累计时间与通知标记（如 limitNotified）不会沿用到当天，也不会被 status 等其他进程读到
+func resetIfDue(qState *quota.QuotaState) {
+	due, err := qState.ShouldReset()
+	if err != nil || !due {
+		return
+	}
+	summary := qState.Summary()
+	if err := qState.Reset(); err != nil {
+		logger.Errorf("重置配额失败: %v", err)
+		return
+	}
+	logger.LogDailySummary(summary.PlayedSeconds, summary.LimitReached, summary.Terminations, summary.GameSeconds)
+	logger.LogQuotaReset()
+	if err := qState.SaveToFile(); err != nil {
+		logger.Errorf("保存重置后的状态失败: %v", err)
+	}
+}
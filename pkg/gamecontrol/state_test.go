@@ -0,0 +1,88 @@
+package gamecontrol
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/logger"
+	"github.com/yourusername/game-control/pkg/quota"
+)
+
+func TestLoadState_QuarantinesCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := logger.NewLogger(filepath.Join(dir, "test.log")); err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.StateFile = filepath.Join(dir, "state.json")
+	corrupt := []byte(`{"accumulatedTime": 36`)
+	if err := os.WriteFile(cfg.StateFile, corrupt, 0644); err != nil {
+		t.Fatalf("写入损坏的状态文件失败: %v", err)
+	}
+
+	qState, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("LoadState 失败: %v", err)
+	}
+	if err := qState.Validate(); err != nil || qState.AccumulatedTime != 0 {
+		t.Fatalf("应创建新的有效状态，实际 %+v, %v", qState, err)
+	}
+
+	matches, _ := filepath.Glob(cfg.StateFile + ".corrupt-*")
+	if len(matches) != 1 {
+		t.Fatalf("应保留一个隔离的状态文件，实际 %v", matches)
+	}
+	data, _ := os.ReadFile(matches[0])
+	if string(data) != string(corrupt) {
+		t.Fatalf("隔离文件应保留原始内容，实际 %q", data)
+	}
+	if _, err := os.Stat(cfg.StateFile); !os.IsNotExist(err) {
+		t.Fatal("原状态文件应已被改名")
+	}
+}
+
+func TestLoadState_ResetsStaleStateOnStartup(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := logger.NewLogger(filepath.Join(dir, "test.log")); err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.StateFile = filepath.Join(dir, "state.json")
+	stale, err := quota.NewQuotaState(cfg)
+	if err != nil {
+		t.Fatalf("创建配额状态失败: %v", err)
+	}
+	// 守护进程停止期间错过了重置点：文件中仍是上一周期的累计时间与通知标记
+	stale.AccumulatedTime = 120 * 60
+	stale.WarningsNotified = []int{15, 5}
+	stale.LimitNotified = true
+	stale.LastResetTime = time.Now().Add(-25 * time.Hour).Unix()
+	stale.NextResetTime = time.Now().Add(-time.Hour).Unix()
+	if err := stale.SaveToFile(); err != nil {
+		t.Fatalf("保存状态失败: %v", err)
+	}
+
+	qState, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("LoadState 失败: %v", err)
+	}
+	if len(qState.WarningsNotified) != 0 || qState.LimitNotified || qState.AccumulatedTime != 0 {
+		t.Fatalf("启动时应重置上一周期的状态，实际 %+v", qState)
+	}
+	if due, _ := qState.ShouldReset(); due {
+		t.Error("重置后下次重置时间应在未来")
+	}
+
+	saved, err := quota.LoadFromFile(cfg)
+	if err != nil {
+		t.Fatalf("重新加载状态失败: %v", err)
+	}
+	if len(saved.WarningsNotified) != 0 || saved.LimitNotified || saved.AccumulatedTime != 0 {
+		t.Errorf("重置后应立即保存，文件中仍为 %+v", saved)
+	}
+}
@@ -0,0 +1,73 @@
+// Package i18n 提供面向用户的文字（命令行输出、通知、事件日志消息）的多语言版本，默认中文。
+// 文字按键集中在 messages.go 中，新增文字时先加中文，其他语言缺少的键回退到中文。
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// 支持的语言
+const (
+	ZH = "zh" // 中文（默认）
+	EN = "en" // 英文
+)
+
+// current 当前语言，守护进程各 goroutine 并发读取
+var current atomic.Value
+
+func init() {
+	current.Store(ZH)
+}
+
+// Supported 判断语言是否受支持（不区分大小写，空字符串视为默认中文）
+func Supported(lang string) bool {
+	_, ok := messages[normalize(lang)]
+	return ok
+}
+
+// SetLanguage 设置当前语言，不支持的语言回退为中文
+func SetLanguage(lang string) {
+	lang = normalize(lang)
+	if _, ok := messages[lang]; !ok {
+		lang = ZH
+	}
+	current.Store(lang)
+}
+
+// Language 返回当前语言
+func Language() string {
+	return current.Load().(string)
+}
+
+// T 返回当前语言下 key 对应的文字，有参数时按格式化字符串处理；
+// 当前语言缺少该键时回退到中文，中文也没有时返回 key 本身，便于发现遗漏
+func T(key string, args ...any) string {
+	return translate(Language(), key, args...)
+}
+
+func translate(lang, key string, args ...any) string {
+	format, ok := messages[lang][key]
+	if !ok {
+		if format, ok = messages[ZH][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// normalize 统一语言写法：小写，"zh-CN"、"en_US" 等只取主语言
+func normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if idx := strings.IndexAny(lang, "-_"); idx >= 0 {
+		lang = lang[:idx]
+	}
+	if lang == "" {
+		return ZH
+	}
+	return lang
+}
@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslate_English(t *testing.T) {
+	tests := []struct {
+		key    string
+		args   []any
+		expect string
+	}{
+		{key: "notify.limit.title", expect: "Game time used up"},
+		{key: "notify.first.message", args: []any{10}, expect: "Game time is running low: 10 minutes left."},
+		{key: "status.daemon.running", args: []any{42}, expect: "Daemon: running (PID: 42)"},
+		{key: "time.hoursMinutes", args: []any{1, 30}, expect: "1 h 30 min"},
+		{key: "cli.error", args: []any{"boom"}, expect: "Error: boom"},
+		{key: "cli.stop.daemonStopped", args: []any{7}, expect: "Daemon stopped (PID: 7)"},
+	}
+	for _, tt := range tests {
+		if got := translate(EN, tt.key, tt.args...); got != tt.expect {
+			t.Errorf("translate(en, %q) = %q，预期 %q", tt.key, got, tt.expect)
+		}
+	}
+}
+
+func TestTranslate_FallsBackToChinese(t *testing.T) {
+	messages[ZH]["test.onlyZh"] = "仅中文 %d"
+	defer delete(messages[ZH], "test.onlyZh")
+
+	if got := translate(EN, "test.onlyZh", 3); got != "仅中文 3" {
+		t.Errorf("英文缺少的键应回退到中文，实际 %q", got)
+	}
+	if got := translate(EN, "test.missing"); got != "test.missing" {
+		t.Errorf("两种语言都缺少的键应返回键本身，实际 %q", got)
+	}
+}
+
+func TestEnglishKeysExistInChinese(t *testing.T) {
+	for key := range messages[EN] {
+		if _, ok := messages[ZH][key]; !ok {
+			t.Errorf("英文键 %q 在中文中不存在，中文是回退语言，应包含全部键", key)
+		}
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	defer SetLanguage(ZH)
+
+	SetLanguage("en-US")
+	if Language() != EN {
+		t.Fatalf("en-US 应按 en 处理，实际 %q", Language())
+	}
+	if got := T("status.header"); !strings.Contains(got, "status") {
+		t.Errorf("切换为英文后应输出英文，实际 %q", got)
+	}
+
+	SetLanguage("fr")
+	if Language() != ZH {
+		t.Errorf("不支持的语言应回退为中文，实际 %q", Language())
+	}
+	if !Supported("") || !Supported("ZH") || Supported("fr") {
+		t.Error("Supported 判断不正确")
+	}
+}
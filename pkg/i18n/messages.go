@@ -0,0 +1,610 @@
+package i18n
+
+// messages 按语言索引的文字，值可以是 fmt 格式化字符串。
+// 键按使用位置分组：notify.* 桌面通知，status.* status 命令输出，cli.* 其他命令行输出与错误，selftest.* 自检步骤，event.* 事件日志消息，summary.* 每日汇总（通知与事件日志共用），reason.* 终止原因，time.* 时长显示
+var messages = map[string]map[string]string{
+	ZH: {
+		"notify.first.title":               "游戏时间提醒",
+		"notify.first.message":             "游戏剩余时间不足，当前还剩 %d 分钟。",
+		"notify.final.title":               "游戏时间最后提醒",
+		"notify.final.message":             "最后提醒：游戏剩余时间仅 %d 分钟。",
+		"notify.soft.title":                "游戏时间已超出建议时长",
+		"notify.soft.message":              "今日游戏时间已超出建议时长 %d 分钟，再玩 %d 分钟游戏将被强制关闭。",
+		"notify.limit.title":               "游戏时间已用尽",
+		"notify.limit.message":             "今日游戏时间已达上限，系统将终止游戏进程。",
+		"notify.limit.resume":              "游戏时间将于 %s 恢复。",
+		"notify.break.title":               "该休息了",
+		"notify.break.message":             "已连续游戏 %d 分钟，请休息一下，游戏将于 %s 后恢复。",
+		"notify.schedule.title":            "不在游戏时段内",
+		"notify.schedule.message":          "当前不在允许的游戏时段内，系统将终止游戏进程。",
+		"notify.schedule.resume":           "游戏将于 %s 开放。",
+		"notify.terminationFailed.title":   "无法关闭游戏",
+		"notify.terminationFailed.message": "无法关闭游戏进程。请以管理员身份运行 game-control。",
+		"notify.accessDenied.title":        "需要管理员权限",
+		"notify.accessDenied.message":      "没有权限关闭 %s（拒绝访问）。请以管理员身份运行 game-control 以关闭此游戏。",
+		"notify.summary.title":             "今日游戏汇总",
+		"notify.summary.message":           "今日共游戏 %d 分钟，%s，关闭游戏进程 %d 次。",
+		"notify.summary.games":             "\n各游戏：%s",
+		"notify.summary.game":              "%s %d 分钟",
+		"notify.summary.separator":         "、",
+
+		"status.header":          "=== 游戏时间控制状态 ===",
+		"status.daemon.stopped":  "守护进程: 未运行",
+		"status.daemon.running":  "守护进程: 运行中 (PID: %d)",
+		"status.daemon.since":    "守护进程: 运行中 (PID: %d，启动于 %s)",
+		"status.profile":         "--- 档案 %s（%s）---",
+		"status.accumulated":     "累计游戏时间: %s",
+		"status.remaining":       "剩余游戏时间: %s",
+		"status.dailyLimit":      "每日时间限制: %d 分钟",
+		"status.earned":          "今日赚取的奖励时间: %d 分钟",
+		"status.extension":       "今日临时延长: %d 分钟（下次重置时失效）",
+		"status.scanDuration":    "进程扫描耗时: %d 毫秒",
+		"status.gameTimes":       "今日各游戏时间:",
+		"status.machineTimes":    "今日各电脑时间（共享状态）:",
+		"status.pausedGames":     "今日已暂停限制的游戏: %s",
+		"status.activeProcesses": "活跃游戏进程: %d 个",
+		"status.noActive":        "当前没有活跃的游戏进程",
+		"status.nextReset":       "距离下次重置: %s",
+		"status.lastExit":        "守护进程上次于 %s 停止，原因: %s",
+		"status.lastExitUnknown": "守护进程上次运行未记录退出原因，可能被强制结束或崩溃",
+		"status.processLine":     "%s (PID: %d) 已运行 %s",
+		"status.unknown":         "未知",
+		"status.gameRunning":     "%s: 运行中 (PID: %s)",
+		"status.gameNotRunning":  "%s: 未运行",
+
+		"event.gameStart":                "游戏进程启动: %s",
+		"event.gameStop":                 "游戏进程停止: %s, 运行时长: %dms",
+		"event.gameRunning":              "游戏进程运行中: %s, 已运行: %dms",
+		"event.shortSessionIgnored":      "游戏会话过短，不计入游戏时间: %s, 运行时长: %dms",
+		"event.wouldTerminate":           "监控模式：本应终止游戏进程 %s (PID: %d)",
+		"event.stateCorrupt":             "状态文件 %s 已损坏，已改名为 %s 并使用新的状态",
+		"event.terminated":               "已终止 %d 个游戏进程",
+		"event.terminationFailed":        "终止游戏进程: 尝试 %d 个，成功 %d 个，失败 %d 个（PID: %v）",
+		"event.limitAction":              "已执行超限动作 %s",
+		"event.limitActionFailed":        "执行超限动作 %s 失败: %v",
+		"event.terminateReason":          "（原因：%s）",
+		"event.terminateAccessDenied":    "无权终止游戏进程 %s (PID: %d)：拒绝访问，请以管理员身份运行 game-control",
+		"event.daemonExit":               "守护进程退出，原因: %s",
+		"event.prohibitedProcess":        "检测到禁止运行的进程 %s (PID: %d)",
+		"event.relaunchBlocked":          "超限后重新启动的游戏进程 %s (PID: %d) 将被立即终止",
+		"event.refusedTerminateCritical": "拒绝终止进程 %s (PID: %d): %v",
+		"event.quotaReset":               "每日游戏时间配额已重置",
+		"event.dailySummary":             "每日汇总：共游戏 %d 分钟，%s，终止游戏进程 %d 个",
+		"event.limitExceeded":            "每日游戏时间限制已超限，终止游戏进程",
+		"event.softLimitExceeded":        "已超出软限制 %d 分钟，距硬限制剩余 %d 分钟",
+		"event.gamesNeverSeen":           "以下游戏进程从未被检测到，请检查进程名是否正确: %s",
+		"event.idlePaused":               "用户已空闲 %s，暂停计时",
+		"event.idleResumed":              "检测到用户输入，恢复计时",
+		"event.screenLocked":             "屏幕已锁定，暂停计时",
+		"event.screenUnlocked":           "屏幕已解锁，恢复计时",
+		"event.breakStarted":             "已连续游戏 %d 分钟，强制休息至 %s",
+		"event.breakEnded":               "强制休息结束",
+		"event.timeEarned":               "运行 %s 赚取游戏时间 %d 分钟，今日共赚取 %d 分钟",
+		"event.timeExtended":             "临时延长游戏时间 %d 分钟，今日共延长 %d 分钟（下次重置时失效）",
+		"event.configTampered":           "配置文件在运行期间被修改: %s",
+		"event.stateTampered":            "状态文件被篡改: %s（%s）",
+		"event.controlRejected":          "控制命令缺少签名、签名不符、已执行过或已过期，未执行: %s",
+		"event.scannerDegraded":          "进程扫描已连续失败 %d 次，沿用上次扫描结果: %v",
+		"event.slowScan":                 "进程扫描耗时 %d 毫秒，超过阈值 %d 毫秒，控制循环可能堆积；建议降低系统负载或调大 controller.slowScanPercent",
+
+		"summary.limitReached":    "已达到每日限制",
+		"summary.limitNotReached": "未达到每日限制",
+
+		"reason.quota":  "每日游戏时间已用尽",
+		"reason.curfew": "不在允许的游戏时段内",
+		"reason.break":  "强制休息",
+
+		"exit.signal":  "收到停止信号",
+		"exit.context": "被嵌入程序停止",
+		"exit.panic":   "程序崩溃",
+		"exit.killed":  "被强制结束或崩溃（未记录退出原因）",
+
+		"time.seconds":        "%d 秒",
+		"time.minutes":        "%d 分钟",
+		"time.hoursMinutes":   "%d 小时 %d 分钟",
+		"time.minutesSeconds": "%d 分 %d 秒",
+		"time.resumeLayout":   "1月2日 15:04",
+
+		"cli.error":                       "错误: %v",
+		"cli.warning":                     "警告: %s",
+		"cli.unknownCommand":              "未知命令: %s",
+		"cli.unknownFlag":                 "未知参数: %s",
+		"cli.extraArg":                    "多余的参数: %s",
+		"cli.configDir.missing":           "--config-dir 需要指定目录",
+		"cli.configDir.notDir":            "--config-dir %s 不是已存在的目录",
+		"cli.profile.missing":             "--profile 需要指定档案名称",
+		"cli.profile.unknown":             "未知的档案: %s",
+		"cli.profile.required":            "配置了多个档案，请使用 --profile 指定档案",
+		"cli.profile.error":               "档案 %s",
+		"cli.quietVerbose":                "--quiet 与 --verbose 不能同时使用",
+		"cli.devNullFailed":               "无法打开 %s",
+		"cli.loadConfigFailed":            "加载配置失败",
+		"cli.validateFailed":              "配置验证失败",
+		"cli.exePathFailed":               "获取程序路径失败",
+		"cli.absConfigFailed":             "解析配置路径失败",
+		"cli.schemaFailed":                "生成配置 schema 失败",
+		"cli.elevation.checkFailed":       "警告: 无法检测管理员权限: %v",
+		"cli.elevation.required":          "%s需要管理员权限",
+		"cli.elevation.warn":              "警告: 当前未以管理员权限运行，%s可能失败",
+		"cli.action.terminate":            "终止游戏进程",
+		"cli.action.installAutostart":     "安装自启动",
+		"cli.start.backgroundConflict":    "--background 与 --foreground 不能同时指定",
+		"cli.start.alreadyRunning":        "控制器已在运行",
+		"cli.start.lockFailed":            "获取单实例锁失败",
+		"cli.start.loggerFailed":          "创建日志记录器失败",
+		"cli.start.notElevated":           "当前未以管理员权限运行，超限时可能无法终止游戏进程",
+		"cli.start.watchdogFailed":        "启动看护进程失败: %v",
+		"cli.start.monitorMode":           "监控模式：只记录将要终止的游戏进程（would_terminate），不会实际终止",
+		"cli.start.multiFailed":           "创建多档案控制器失败",
+		"cli.start.noConfigWatch":         "不检查配置文件修改: %v",
+		"cli.background.alreadyRunning":   "控制器已在运行 (PID: %d)",
+		"cli.background.startFailed":      "启动后台进程失败",
+		"cli.background.started":          "守护进程已在后台启动 (PID: %d)",
+		"cli.background.exited":           "后台进程启动后立即退出，请查看日志文件或以 --foreground 运行排查",
+		"cli.background.timeout":          "后台进程在 %v 内未完成启动，请查看日志文件",
+		"cli.status.runStartFirst":        "，请先运行 start 命令",
+		"cli.status.loadFailed":           "加载状态失败",
+		"cli.status.checkResetFailed":     "检查重置状态失败",
+		"cli.status.resetFailed":          "重置配额失败",
+		"cli.status.saveFailed":           "保存重置状态失败",
+		"cli.daemon.notRunning":           "守护进程未运行",
+		"cli.daemon.readFailed":           "读取守护进程信息失败",
+		"cli.daemon.exited":               "守护进程未运行（锁文件记录的 PID %d 已退出）",
+		"cli.stop.watchdogFailed":         "停止看护进程失败",
+		"cli.stop.watchdogStopped":        "看护进程已停止 (PID: %d)",
+		"cli.stop.daemonStopped":          "守护进程已停止 (PID: %d)",
+		"cli.stop.timeout":                "已发送停止请求，但进程 (PID: %d) 在 10 秒内未退出",
+		"cli.stop.findFailed":             "查找守护进程失败",
+		"cli.stop.signalFailed":           "发送停止信号失败",
+		"cli.stop.requestFailed":          "请求守护进程退出失败（输出: %s，可使用 taskkill /F /PID %d 强制结束）",
+		"cli.validate.checkRunningSingle": "--check-running 只能用于单个配置文件",
+		"cli.validate.ok":                 "配置文件验证通过",
+		"cli.validate.dailyLimit":         "每日时间限制: %d 分钟",
+		"cli.validate.resetTime":          "重置时间: %s",
+		"cli.validate.timezone":           "时区: %s",
+		"cli.validate.games":              "游戏进程列表: %v",
+		"cli.validate.warningLevels":      "提醒阈值: %v 分钟",
+		"cli.validate.thresholds":         "警告阈值: %d 分钟 (第一次), %d 分钟 (最后)",
+		"cli.validate.scanFailed":         "扫描游戏进程失败",
+		"cli.validate.matchable":          "当前可匹配的游戏进程:",
+		"cli.validate.badPattern":         "无效的通配符 %s",
+		"cli.validate.noMatch":            "%s 没有匹配到任何配置文件",
+		"cli.validate.tableHeader":        "文件\t结果\t每日限制\t重置时间\t游戏数\t说明",
+		"cli.validate.rowFailed":          "%s\t失败\t-\t-\t-\t%s",
+		"cli.validate.rowPassed":          "%s\t通过\t%d 分钟\t%s\t%d\t%s",
+		"cli.validate.warningCount":       "%d 条警告",
+		"cli.validate.summaryFailed":      "%d/%d 个配置文件未通过验证",
+		"cli.validate.fixDir":             "--fix 只能用于配置文件，不能用于配置目录 %s",
+		"cli.validate.fixFailed":          "升级配置文件 %s 失败",
+		"cli.validate.fixed":              "已将配置文件 %s 升级到版本 %d",
+		"cli.autostart.installFailed":     "安装自启动失败",
+		"cli.autostart.installed":         "自启动已安装",
+		"cli.autostart.removeFailed":      "移除自启动失败",
+		"cli.autostart.removed":           "自启动已移除",
+		"cli.version.commit":              "提交: %s",
+		"cli.version.buildDate":           "构建时间: %s",
+		"cli.extend.missingMinutes":       "缺少延长的分钟数",
+		"cli.extend.invalidMinutes":       "无效的分钟数 %q",
+		"cli.extend.requested":            "已请求临时延长游戏时间 %d 分钟（下次重置时失效），守护进程将在下一个检查周期生效",
+		"cli.pause.missingGame":           "缺少游戏名",
+		"cli.pause.pauseRequested":        "已请求暂停对游戏 %s 的限制，守护进程将在下一个检查周期生效",
+		"cli.pause.resumeRequested":       "已请求恢复对游戏 %s 的限制，守护进程将在下一个检查周期生效",
+		"cli.logs.levelMissing":           "--level 需要指定级别",
+		"cli.logs.noLogFile":              "配置未指定日志文件",
+		"cli.logs.openFailed":             "无法打开日志文件",
+		"cli.logs.readFailed":             "读取日志失败",
+		"cli.password.missing":            "--password 需要指定密码",
+		"cli.password.prompt":             "请输入家长密码: ",
+		"cli.password.readFailed":         "读取密码失败",
+		"cli.password.verifyCurrent":      "配置中已设置家长密码，请先验证当前密码",
+		"cli.password.newPrompt":          "请输入新密码: ",
+		"cli.password.confirmPrompt":      "请再次输入新密码: ",
+		"cli.password.mismatch":           "两次输入的密码不一致",
+		"cli.password.writeConfig":        "请将以下内容写入配置文件 %s:\n\n",
+		"cli.policy.keygenUsage":          "用法: game-control policy-keygen <私钥文件>",
+		"cli.policy.writeKeyFailed":       "写入私钥失败",
+		"cli.policy.keyWritten":           "私钥已写入 %s，请妥善保管，不要放在受控电脑上",
+		"cli.policy.addToConfig":          "将以下内容加入受控电脑的配置:",
+		"cli.policy.keyFlagMissing":       "--key 需要指定私钥文件",
+		"cli.policy.keyMissing":           "缺少 --key 私钥文件",
+		"cli.policy.readConfigFailed":     "无法读取配置文件",
+		"cli.policy.readKeyFailed":        "无法读取私钥",
+		"cli.policy.signFailed":           "签名策略失败",
+		"cli.selftest.noArgs":             "selftest 不接受参数: %v",
+		"cli.selftest.windowsOnly":        "自检仅支持 Windows：进程扫描与终止依赖 tasklist/taskkill",
+		"cli.selftest.dirFailed":          "创建自检目录失败",
+		"cli.selftest.log":                "自检日志: %s",
+		"cli.selftest.started":            "已启动自检进程 %s (PID: %d)",
+		"cli.selftest.pass":               "通过",
+		"cli.selftest.fail":               "失败",
+		"cli.selftest.failed":             "自检未通过",
+		"cli.selftest.passed":             "自检通过：进程扫描、计时与终止均正常",
+		"cli.selftest.copyFailed":         "复制自检程序失败",
+		"cli.selftest.startFailed":        "启动自检进程失败",
+		"cli.uninstall.running":           "守护进程或看护进程正在运行 (PID: %d)，请先执行 stop",
+		"cli.uninstall.plan":              "将移除开机自启动，并清理以下文件:",
+		"cli.uninstall.planDelete":        "删除 %s",
+		"cli.uninstall.planArchive":       "归档 %s",
+		"cli.uninstall.notConfirmed":      "未确认卸载，请加上 --yes 重新执行",
+		"cli.uninstall.autostartFailed":   "移除自启动失败（可能未安装）: %v",
+		"cli.uninstall.autostartRemoved":  "已移除自启动",
+		"cli.uninstall.deleteFailed":      "删除 %s 失败: %v",
+		"cli.uninstall.deleted":           "已删除 %s",
+		"cli.uninstall.archiveDirFailed":  "创建归档目录失败",
+		"cli.uninstall.archiveFailed":     "归档 %s 失败: %v",
+		"cli.uninstall.archived":          "已归档 %s -> %s",
+		"cli.uninstall.incomplete":        "%d 个文件未能清理",
+		"cli.verbose.prefix":              "[详细] ",
+		"cli.verbose.config":              "配置: %s",
+		"cli.verbose.stateFile":           "状态文件: %s",
+		"cli.verbose.logFile":             "日志文件: %s",
+		"cli.verbose.eventsFile":          "事件日志: %s",
+		"cli.verbose.exportFile":          "剩余时间导出: %s",
+		"cli.verbose.hidden":              "<已隐藏>",
+		"cli.verbose.marshalFailed":       "无法序列化生效的配置: %v",
+		"cli.verbose.effective":           "生效的配置:\n%s",
+		"cli.watchdog.alreadyRunning":     "看护进程已在运行",
+		"cli.watchdog.lockFailed":         "获取看护进程锁失败",
+		"cli.watchdog.restarting":         "守护进程未运行，正在重新启动",
+		"cli.watchdog.restartFailed":      "重新启动守护进程失败: %v",
+		"cli.watchdog.startFailed":        "启动 %s 失败",
+		"selftest.step.found":             "发现进程",
+		"selftest.step.accrued":           "累计时间",
+		"selftest.step.limit":             "达到限制",
+		"selftest.step.terminated":        "终止进程",
+		"selftest.notFound":               "扫描结果中没有 %s (PID: %d)",
+		"selftest.accrued":                "累计 %d 秒",
+		"selftest.limit":                  "每日限制 %d 分钟",
+		"selftest.accessDenied":           "权限不足，请以管理员身份运行后重试",
+		"selftest.terminationFailed":      "终止失败，详情见自检日志",
+		"selftest.notTerminated":          "达到限制后没有终止目标进程",
+		"selftest.rescanFailed":           "终止后扫描失败: %v",
+		"selftest.stillRunning":           "报告已终止，但进程仍在运行",
+		"selftest.terminated":             "已终止 PID %d",
+
+		"cli.help": `游戏时间控制工具
+
+使用方法:
+  game-control [--quiet|--verbose] <command> [参数]
+
+可用命令:
+  start [config] [--require-admin] [--dry-run] [--background|--foreground]
+                                    启动游戏时间控制守护进程（--dry-run 只观察不终止，--background 脱离终端后台运行）
+  status [config] [--profile NAME]  查询当前游戏时间状态（多档案时可只看一个档案）
+  stop [config] [--password P]      停止使用该配置文件运行的守护进程及其看护进程（会先保存状态）
+  logs [config] [--follow] [--level L] [--json]  查看守护进程日志
+  validate [config...] [--check-running] [--fix]  验证配置文件，可选扫描当前可匹配的游戏进程；
+                                    --fix 先将旧版本的配置文件升级到当前版本并写回；
+                                    指定多个文件或通配符（如 "profiles/*.yaml"）时逐个校验并列表汇总
+  pause <game> [config] [--profile NAME]  暂停对单个游戏的限制（当天有效，不计时也不终止）
+  resume <game> [config] [--profile NAME] 恢复对单个游戏的限制
+  extend <minutes> [config] [--profile NAME]  临时延长当天的游戏时间（下次重置时失效，不累积）
+  watchdog [config]                 看护守护进程，消失时重新启动（watchdog.enabled 时由 start 自动启动）
+  install-autostart [config]        安装开机自启动（Windows 计划任务 / Linux systemd）
+  remove-autostart [config] [--password P]  移除开机自启动
+  uninstall [config] [--yes] [--archive]  移除自启动并删除锁、状态与日志文件（--archive 将状态与日志移入归档目录）
+  set-password [config]             生成家长密码哈希（admin.passwordHash）
+  policy-keygen <keyfile>           生成锁定策略的签名密钥，私钥写入 keyfile，输出 policy.publicKey
+  sign-policy <config> --key FILE   用私钥签名配置，将签名策略输出到标准输出（供 policy.url 使用）
+  selftest                          启动一个无害的进程作为游戏，验证本机能发现、计时并在达到限制后终止它
+  schema                            输出配置文件的 JSON Schema（供编辑器校验与自动补全）
+  version                           显示版本与构建信息
+  help                              显示此帮助信息
+
+说明:
+  - 默认配置文件路径: config.yaml
+  - 各命令均可用 --config-dir DIR 代替 [config]，按文件名顺序合并目录中的 *.yaml 配置片段
+  - 写在命令之前的 --quiet 不输出非错误信息（只看退出码），--verbose 向标准错误额外输出解析后的文件路径与生效的配置
+  - 需要管理员权限来终止游戏进程
+  - 配置了 admin.passwordHash 时，stop、remove-autostart、uninstall、pause、resume 与 extend 需要家长密码（未提供 --password 时提示输入）
+  - 进程监控仅支持 Windows 系统
+  - 默认在当前终端前台运行（--foreground），start --background 确认守护进程启动后即返回
+  - 退出码: 0 成功，1 其他错误，2 配置错误，3 已在运行，4 需要管理员权限，5 没有状态文件
+
+示例:
+  game-control start
+`,
+	},
+	EN: {
+		"notify.first.title":               "Game time reminder",
+		"notify.first.message":             "Game time is running low: %d minutes left.",
+		"notify.final.title":               "Final game time reminder",
+		"notify.final.message":             "Final reminder: only %d minutes of game time left.",
+		"notify.soft.title":                "Recommended game time exceeded",
+		"notify.soft.message":              "Today's game time is %d minutes over the recommended limit; games will be closed in %d minutes.",
+		"notify.limit.title":               "Game time used up",
+		"notify.limit.message":             "Today's game time limit has been reached; games will now be closed.",
+		"notify.limit.resume":              " Game time returns at %s.",
+		"notify.break.title":               "Time for a break",
+		"notify.break.message":             "You have played for %d minutes in a row. Please take a break; games are available again at %s.",
+		"notify.schedule.title":            "Outside game hours",
+		"notify.schedule.message":          "Games are not allowed right now; games will now be closed.",
+		"notify.schedule.resume":           " Games are closed until %s.",
+		"notify.terminationFailed.title":   "Unable to close game",
+		"notify.terminationFailed.message": "The game could not be closed. Please run game-control as administrator.",
+		"notify.accessDenied.title":        "Administrator rights required",
+		"notify.accessDenied.message":      "Access denied while closing %s. Run game-control as administrator to stop this game.",
+		"notify.summary.title":             "Daily game summary",
+		"notify.summary.message":           "Played %d minutes today, %s, %d game processes closed.",
+		"notify.summary.games":             "\nPer game: %s",
+		"notify.summary.game":              "%s %d min",
+		"notify.summary.separator":         ", ",
+
+		"status.header":          "=== Game time control status ===",
+		"status.daemon.stopped":  "Daemon: not running",
+		"status.daemon.running":  "Daemon: running (PID: %d)",
+		"status.daemon.since":    "Daemon: running (PID: %d, started at %s)",
+		"status.profile":         "--- Profile %s (%s) ---",
+		"status.accumulated":     "Time played: %s",
+		"status.remaining":       "Time remaining: %s",
+		"status.dailyLimit":      "Daily limit: %d minutes",
+		"status.earned":          "Bonus time earned today: %d minutes",
+		"status.extension":       "Temporary extension today: %d minutes (expires at the next reset)",
+		"status.scanDuration":    "Process scan took: %d ms",
+		"status.gameTimes":       "Time per game today:",
+		"status.machineTimes":    "Time per computer today (shared state):",
+		"status.pausedGames":     "Games with limits paused today: %s",
+		"status.activeProcesses": "Active game processes: %d",
+		"status.noActive":        "No active game processes",
+		"status.nextReset":       "Next reset in: %s",
+		"status.lastExit":        "Daemon last stopped at %s due to: %s",
+		"status.lastExitUnknown": "The last daemon run recorded no exit reason; it was probably killed or crashed",
+		"status.processLine":     "%s (PID: %d) running for %s",
+		"status.unknown":         "unknown",
+		"status.gameRunning":     "%s: running (PID: %s)",
+		"status.gameNotRunning":  "%s: not running",
+
+		"event.gameStart":                "Game process started: %s",
+		"event.gameStop":                 "Game process stopped: %s, duration: %dms",
+		"event.gameRunning":              "Game process running: %s, running for: %dms",
+		"event.shortSessionIgnored":      "Game session too short, not counted: %s, duration: %dms",
+		"event.wouldTerminate":           "Monitor mode: would have terminated game process %s (PID: %d)",
+		"event.stateCorrupt":             "State file %s is corrupt; renamed to %s and starting with a new state",
+		"event.terminated":               "Terminated %d game processes",
+		"event.terminationFailed":        "Terminating game processes: %d attempted, %d succeeded, %d failed (PID: %v)",
+		"event.limitAction":              "Executed limit action %s",
+		"event.limitActionFailed":        "Limit action %s failed: %v",
+		"event.terminateReason":          " (reason: %s)",
+		"event.terminateAccessDenied":    "Access denied terminating game process %s (PID: %d); run game-control as administrator",
+		"event.daemonExit":               "Daemon exited, reason: %s",
+		"event.prohibitedProcess":        "Prohibited process detected: %s (PID: %d)",
+		"event.relaunchBlocked":          "Game process %s (PID: %d) relaunched after the limit, terminating immediately",
+		"event.refusedTerminateCritical": "Refused to terminate process %s (PID: %d): %v",
+		"event.quotaReset":               "Daily game time quota has been reset",
+		"event.dailySummary":             "Daily summary: played %d minutes, %s, %d game processes terminated",
+		"event.limitExceeded":            "Daily game time limit exceeded, terminating game processes",
+		"event.softLimitExceeded":        "Soft limit exceeded by %d minutes, %d minutes left until the hard limit",
+		"event.gamesNeverSeen":           "These game processes have never been detected, please check the names: %s",
+		"event.idlePaused":               "User idle for %s, pausing the timer",
+		"event.idleResumed":              "User input detected, resuming the timer",
+		"event.screenLocked":             "Screen locked, pausing the timer",
+		"event.screenUnlocked":           "Screen unlocked, resuming the timer",
+		"event.breakStarted":             "Played for %d minutes in a row, mandatory break until %s",
+		"event.breakEnded":               "Mandatory break is over",
+		"event.timeEarned":               "Earned %[2]d minutes of game time by running %[1]s, %[3]d minutes earned today",
+		"event.timeExtended":             "Game time extended by %d minutes, %d minutes extended today (expires at the next reset)",
+		"event.configTampered":           "Config file was modified while running: %s",
+		"event.stateTampered":            "State file was tampered with: %s (%s)",
+		"event.controlRejected":          "Control command is unsigned, has a bad signature, was already run or has expired, and was not run: %s",
+		"event.scannerDegraded":          "Process scan failed %d times in a row, reusing the last result: %v",
+		"event.slowScan":                 "Process scan took %d ms, over the %d ms threshold; control ticks may pile up. Consider reducing system load or raising controller.slowScanPercent",
+
+		"summary.limitReached":    "daily limit reached",
+		"summary.limitNotReached": "daily limit not reached",
+
+		"reason.quota":  "daily limit reached",
+		"reason.curfew": "outside allowed hours",
+		"reason.break":  "mandatory break",
+
+		"exit.signal":  "stop signal received",
+		"exit.context": "stopped by the embedding program",
+		"exit.panic":   "crash",
+		"exit.killed":  "killed or crashed (no exit recorded)",
+
+		"time.seconds":        "%d s",
+		"time.minutes":        "%d min",
+		"time.hoursMinutes":   "%d h %d min",
+		"time.minutesSeconds": "%d min %d s",
+		"time.resumeLayout":   "Jan 2 15:04",
+
+		"cli.error":                       "Error: %v",
+		"cli.warning":                     "Warning: %s",
+		"cli.unknownCommand":              "Unknown command: %s",
+		"cli.unknownFlag":                 "Unknown flag: %s",
+		"cli.extraArg":                    "Unexpected argument: %s",
+		"cli.configDir.missing":           "--config-dir requires a directory",
+		"cli.configDir.notDir":            "--config-dir %s is not an existing directory",
+		"cli.profile.missing":             "--profile requires a profile name",
+		"cli.profile.unknown":             "Unknown profile: %s",
+		"cli.profile.required":            "Several profiles are configured; choose one with --profile",
+		"cli.profile.error":               "Profile %s",
+		"cli.quietVerbose":                "--quiet and --verbose cannot be used together",
+		"cli.devNullFailed":               "Cannot open %s",
+		"cli.loadConfigFailed":            "Failed to load config",
+		"cli.validateFailed":              "Config validation failed",
+		"cli.exePathFailed":               "Failed to get the program path",
+		"cli.absConfigFailed":             "Failed to resolve the config path",
+		"cli.schemaFailed":                "Failed to generate the config schema",
+		"cli.elevation.checkFailed":       "Warning: cannot check administrator privileges: %v",
+		"cli.elevation.required":          "%s requires administrator privileges",
+		"cli.elevation.warn":              "Warning: not running as administrator, %s may fail",
+		"cli.action.terminate":            "terminating game processes",
+		"cli.action.installAutostart":     "installing autostart",
+		"cli.start.backgroundConflict":    "--background and --foreground cannot be used together",
+		"cli.start.alreadyRunning":        "The controller is already running",
+		"cli.start.lockFailed":            "Failed to acquire the single-instance lock",
+		"cli.start.loggerFailed":          "Failed to create the logger",
+		"cli.start.notElevated":           "Not running as administrator; game processes may not be terminated when the limit is reached",
+		"cli.start.watchdogFailed":        "Failed to start the watchdog: %v",
+		"cli.start.monitorMode":           "Monitor mode: games that would be terminated are only logged (would_terminate), nothing is terminated",
+		"cli.start.multiFailed":           "Failed to create the multi-profile controller",
+		"cli.start.noConfigWatch":         "Not watching the config file for changes: %v",
+		"cli.background.alreadyRunning":   "The controller is already running (PID: %d)",
+		"cli.background.startFailed":      "Failed to start the background process",
+		"cli.background.started":          "Daemon started in the background (PID: %d)",
+		"cli.background.exited":           "The background process exited right after starting; check the log file or run with --foreground",
+		"cli.background.timeout":          "The background process did not finish starting within %v; check the log file",
+		"cli.status.runStartFirst":        ", run the start command first",
+		"cli.status.loadFailed":           "Failed to load state",
+		"cli.status.checkResetFailed":     "Failed to check the reset state",
+		"cli.status.resetFailed":          "Failed to reset the quota",
+		"cli.status.saveFailed":           "Failed to save the reset state",
+		"cli.daemon.notRunning":           "The daemon is not running",
+		"cli.daemon.readFailed":           "Failed to read daemon information",
+		"cli.daemon.exited":               "The daemon is not running (PID %d from the lock file has exited)",
+		"cli.stop.watchdogFailed":         "Failed to stop the watchdog",
+		"cli.stop.watchdogStopped":        "Watchdog stopped (PID: %d)",
+		"cli.stop.daemonStopped":          "Daemon stopped (PID: %d)",
+		"cli.stop.timeout":                "Stop requested, but the process (PID: %d) did not exit within 10 seconds",
+		"cli.stop.findFailed":             "Failed to find the daemon",
+		"cli.stop.signalFailed":           "Failed to send the stop signal",
+		"cli.stop.requestFailed":          "Failed to ask the daemon to exit (output: %s; use taskkill /F /PID %d to force it)",
+		"cli.validate.checkRunningSingle": "--check-running can only be used with a single config file",
+		"cli.validate.ok":                 "Config file is valid",
+		"cli.validate.dailyLimit":         "Daily limit: %d minutes",
+		"cli.validate.resetTime":          "Reset time: %s",
+		"cli.validate.timezone":           "Time zone: %s",
+		"cli.validate.games":              "Games: %v",
+		"cli.validate.warningLevels":      "Warning thresholds: %v minutes",
+		"cli.validate.thresholds":         "Warning thresholds: %d minutes (first), %d minutes (final)",
+		"cli.validate.scanFailed":         "Failed to scan game processes",
+		"cli.validate.matchable":          "Game processes that currently match:",
+		"cli.validate.badPattern":         "Invalid pattern %s",
+		"cli.validate.noMatch":            "%s matches no config files",
+		"cli.validate.tableHeader":        "File\tResult\tDaily limit\tReset time\tGames\tNote",
+		"cli.validate.rowFailed":          "%s\tfailed\t-\t-\t-\t%s",
+		"cli.validate.rowPassed":          "%s\tok\t%d min\t%s\t%d\t%s",
+		"cli.validate.warningCount":       "%d warnings",
+		"cli.validate.summaryFailed":      "%d/%d config files failed validation",
+		"cli.validate.fixDir":             "--fix only works on config files, not the config directory %s",
+		"cli.validate.fixFailed":          "Failed to upgrade config file %s",
+		"cli.validate.fixed":              "Upgraded config file %s to version %d",
+		"cli.autostart.installFailed":     "Failed to install autostart",
+		"cli.autostart.installed":         "Autostart installed",
+		"cli.autostart.removeFailed":      "Failed to remove autostart",
+		"cli.autostart.removed":           "Autostart removed",
+		"cli.version.commit":              "Commit: %s",
+		"cli.version.buildDate":           "Built: %s",
+		"cli.extend.missingMinutes":       "Missing the number of minutes to extend",
+		"cli.extend.invalidMinutes":       "Invalid number of minutes %q",
+		"cli.extend.requested":            "Requested a %d-minute extension (expires at the next reset); the daemon applies it on its next check",
+		"cli.pause.missingGame":           "Missing the game name",
+		"cli.pause.pauseRequested":        "Requested to pause limits for %s; the daemon applies it on its next check",
+		"cli.pause.resumeRequested":       "Requested to resume limits for %s; the daemon applies it on its next check",
+		"cli.logs.levelMissing":           "--level requires a level",
+		"cli.logs.noLogFile":              "The config does not set a log file",
+		"cli.logs.openFailed":             "Cannot open the log file",
+		"cli.logs.readFailed":             "Failed to read the log",
+		"cli.password.missing":            "--password requires a password",
+		"cli.password.prompt":             "Parent password: ",
+		"cli.password.readFailed":         "Failed to read the password",
+		"cli.password.verifyCurrent":      "A parent password is already set; enter the current password first",
+		"cli.password.newPrompt":          "New password: ",
+		"cli.password.confirmPrompt":      "Repeat the new password: ",
+		"cli.password.mismatch":           "The passwords do not match",
+		"cli.password.writeConfig":        "Add the following to the config file %s:\n\n",
+		"cli.policy.keygenUsage":          "Usage: game-control policy-keygen <private key file>",
+		"cli.policy.writeKeyFailed":       "Failed to write the private key",
+		"cli.policy.keyWritten":           "Private key written to %s; keep it safe and off the controlled computer",
+		"cli.policy.addToConfig":          "Add the following to the controlled computer's config:",
+		"cli.policy.keyFlagMissing":       "--key requires a private key file",
+		"cli.policy.keyMissing":           "Missing the --key private key file",
+		"cli.policy.readConfigFailed":     "Cannot read the config file",
+		"cli.policy.readKeyFailed":        "Cannot read the private key",
+		"cli.policy.signFailed":           "Failed to sign the policy",
+		"cli.selftest.noArgs":             "selftest takes no arguments: %v",
+		"cli.selftest.windowsOnly":        "selftest only runs on Windows: scanning and termination use tasklist/taskkill",
+		"cli.selftest.dirFailed":          "Failed to create the selftest directory",
+		"cli.selftest.log":                "Selftest log: %s",
+		"cli.selftest.started":            "Started selftest process %s (PID: %d)",
+		"cli.selftest.pass":               "PASS",
+		"cli.selftest.fail":               "FAIL",
+		"cli.selftest.failed":             "Selftest failed",
+		"cli.selftest.passed":             "Selftest passed: scanning, time tracking and termination all work",
+		"cli.selftest.copyFailed":         "Failed to copy the selftest program",
+		"cli.selftest.startFailed":        "Failed to start the selftest process",
+		"cli.uninstall.running":           "The daemon or watchdog is running (PID: %d); run stop first",
+		"cli.uninstall.plan":              "Autostart will be removed and these files cleaned up:",
+		"cli.uninstall.planDelete":        "delete %s",
+		"cli.uninstall.planArchive":       "archive %s",
+		"cli.uninstall.notConfirmed":      "Uninstall not confirmed; run again with --yes",
+		"cli.uninstall.autostartFailed":   "Failed to remove autostart (maybe not installed): %v",
+		"cli.uninstall.autostartRemoved":  "Autostart removed",
+		"cli.uninstall.deleteFailed":      "Failed to delete %s: %v",
+		"cli.uninstall.deleted":           "Deleted %s",
+		"cli.uninstall.archiveDirFailed":  "Failed to create the archive directory",
+		"cli.uninstall.archiveFailed":     "Failed to archive %s: %v",
+		"cli.uninstall.archived":          "Archived %s -> %s",
+		"cli.uninstall.incomplete":        "%d files could not be cleaned up",
+		"cli.verbose.prefix":              "[verbose] ",
+		"cli.verbose.config":              "Config: %s",
+		"cli.verbose.stateFile":           "State file: %s",
+		"cli.verbose.logFile":             "Log file: %s",
+		"cli.verbose.eventsFile":          "Event log: %s",
+		"cli.verbose.exportFile":          "Remaining time export: %s",
+		"cli.verbose.hidden":              "<hidden>",
+		"cli.verbose.marshalFailed":       "Cannot serialize the effective config: %v",
+		"cli.verbose.effective":           "Effective config:\n%s",
+		"cli.watchdog.alreadyRunning":     "The watchdog is already running",
+		"cli.watchdog.lockFailed":         "Failed to acquire the watchdog lock",
+		"cli.watchdog.restarting":         "The daemon is not running, restarting it",
+		"cli.watchdog.restartFailed":      "Failed to restart the daemon: %v",
+		"cli.watchdog.startFailed":        "Failed to start %s",
+		"selftest.step.found":             "find process",
+		"selftest.step.accrued":           "track time",
+		"selftest.step.limit":             "reach limit",
+		"selftest.step.terminated":        "terminate process",
+		"selftest.notFound":               "%s (PID: %d) is missing from the scan",
+		"selftest.accrued":                "%d seconds tracked",
+		"selftest.limit":                  "daily limit %d minutes",
+		"selftest.accessDenied":           "access denied; run as administrator and try again",
+		"selftest.terminationFailed":      "termination failed, see the selftest log",
+		"selftest.notTerminated":          "the target was not terminated after the limit was reached",
+		"selftest.rescanFailed":           "scan after termination failed: %v",
+		"selftest.stillRunning":           "reported as terminated but still running",
+		"selftest.terminated":             "terminated PID %d",
+
+		"cli.help": `Game time control tool
+
+Usage:
+  game-control [--quiet|--verbose] <command> [arguments]
+
+Commands:
+  start [config] [--require-admin] [--dry-run] [--background|--foreground]
+                                    Start the game time daemon (--dry-run observes without terminating, --background detaches from the terminal)
+  status [config] [--profile NAME]  Show today's game time (with profiles, optionally only one profile)
+  stop [config] [--password P]      Stop the daemon and its watchdog for this config (state is saved first)
+  logs [config] [--follow] [--level L] [--json]  Show the daemon log
+  validate [config...] [--check-running] [--fix]  Validate config files, optionally scanning for matching game processes;
+                                    --fix first upgrades old config files to the current version and writes them back;
+                                    several files or a pattern (such as "profiles/*.yaml") are checked one by one and summarized
+  pause <game> [config] [--profile NAME]  Pause limits for one game (today only, neither counted nor terminated)
+  resume <game> [config] [--profile NAME] Resume limits for one game
+  extend <minutes> [config] [--profile NAME]  Extend today's game time (expires at the next reset, does not carry over)
+  watchdog [config]                 Watch the daemon and restart it if it disappears (started by start when watchdog.enabled)
+  install-autostart [config]        Install autostart (Windows scheduled task / Linux systemd)
+  remove-autostart [config] [--password P]  Remove autostart
+  uninstall [config] [--yes] [--archive]  Remove autostart and delete lock, state and log files (--archive moves state and logs to an archive folder)
+  set-password [config]             Generate the parent password hash (admin.passwordHash)
+  policy-keygen <keyfile>           Generate a signing key for locked policies, write the private key to keyfile and print policy.publicKey
+  sign-policy <config> --key FILE   Sign a config with the private key and print the signed policy (for policy.url)
+  selftest                          Start a harmless process as a game and verify it is found, tracked and terminated at the limit
+  schema                            Print the JSON Schema of the config file (for editor validation and completion)
+  version                           Show version and build information
+  help                              Show this help
+
+Notes:
+  - Default config file: config.yaml
+  - Every command accepts --config-dir DIR instead of [config], merging the *.yaml fragments in DIR in file name order
+  - --quiet before the command prints nothing but errors (check the exit code); --verbose also prints resolved file paths and the effective config to stderr
+  - Administrator privileges are needed to terminate game processes
+  - With admin.passwordHash set, stop, remove-autostart, uninstall, pause, resume and extend need the parent password (prompted when --password is not given)
+  - Process monitoring only supports Windows
+  - Runs in the foreground by default (--foreground); start --background returns once the daemon has started
+  - Exit codes: 0 success, 1 other error, 2 config error, 3 already running, 4 administrator privileges required, 5 no state file
+
+Examples:
+  game-control start
+`,
+	},
+}
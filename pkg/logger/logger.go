@@ -3,11 +3,15 @@ package logger
 import (
 	"fmt"
 	"go.uber.org/zap/zapcore"
+	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/yourusername/game-control/pkg/i18n"
 )
 
 // LogLevel 日志级别
@@ -27,56 +31,154 @@ type LogEntry struct {
 	Message   string    `json:"message"`
 	Event     string    `json:"event,omitempty"`
 	Process   string    `json:"process,omitempty"`
+	PID       int       `json:"pid,omitempty"`
 	Duration  int64     `json:"duration,omitempty"` // 毫秒
+
+	// 仅 termination_result 事件：本轮终止成功与失败的 PID
+	Succeeded []int `json:"succeeded,omitempty"`
+	Failed    []int `json:"failed,omitempty"`
+
+	// 仅 termination_result、terminate_access_denied 与 limit_action 事件：处理游戏进程的原因（quota、curfew、break）
+	Reason string `json:"terminate_reason,omitempty"`
+
+	// 仅 daemon_exit 事件：守护进程退出的原因（signal、context、panic、killed）
+	ExitReason string `json:"exit_reason,omitempty"`
+
+	// 仅 daily_summary 事件：各游戏的累计时间（秒）、是否达到每日限制、终止的游戏进程数
+	Games        map[string]int64 `json:"games,omitempty"`
+	LimitReached bool             `json:"limitReached,omitempty"`
+	Terminations int              `json:"terminations,omitempty"`
+
+	// 仅 time_extended 事件：签名延长命令的随机数，每次授权各不相同
+	Nonce string `json:"nonce,omitempty"`
 }
 
 // Logger 日志记录器
 type Logger struct {
 	output *os.File
 	zap    *zap.Logger
+
+	// 仅事件日志（Event 非空的条目），未配置时为 nil
+	eventsOutput *os.File
+	events       *zap.Logger
+}
+
+// Options 日志记录器选项
+type Options struct {
+	OutputPath string   // 主日志路径，为空时输出到标准输出
+	EventsPath string   // 仅事件日志路径（JSON Lines），为空时不单独输出
+	Level      LogLevel // 主日志最低级别，为空时为 info；事件日志不受影响
+	Console    bool     // 主日志写入文件的同时输出到控制台
 }
 
+// consoleOutput 控制台输出目标，测试中可替换
+var consoleOutput io.Writer = os.Stdout
+
 var LogHandle *Logger
 var once sync.Once
 
 // NewLogger 创建新的日志记录器
 func NewLogger(outputPath string) (*Logger, error) {
-	once.Do(func() {
-		var output *os.File
-		var err error
-		if outputPath == "" {
-			output = os.Stdout
-		} else {
-			output, err = os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-			if err != nil {
-				panic(fmt.Sprintf("无法打开日志文件: %v", err))
-			}
-		}
+	return NewLoggerWithOptions(Options{OutputPath: outputPath})
+}
 
-		encoderCfg := zapcore.EncoderConfig{
-			TimeKey:        "timestamp",
-			LevelKey:       "level",
-			MessageKey:     "message",
-			EncodeLevel:    zapcore.LowercaseLevelEncoder,
-			EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeDuration: zapcore.MillisDurationEncoder,
-		}
-		core := zapcore.NewCore(
-			zapcore.NewJSONEncoder(encoderCfg),
-			zapcore.AddSync(output),
-			zapcore.DebugLevel,
-		)
-
-		LogHandle = &Logger{
-			output: output,
-			zap:    zap.New(core),
+// NewLoggerWithOptions 按选项创建全局日志记录器（进程内只初始化一次）
+func NewLoggerWithOptions(opts Options) (*Logger, error) {
+	once.Do(func() {
+		l, err := newLogger(opts)
+		if err != nil {
+			panic(err.Error())
 		}
+		LogHandle = l
 	})
 
 	return LogHandle, nil
 }
 
+// newLogger 创建独立的日志记录器实例
+func newLogger(opts Options) (*Logger, error) {
+	level := opts.Level
+	if level == "" {
+		level = LevelInfo
+	}
+	if _, ok := levelRank[level]; !ok {
+		return nil, fmt.Errorf("无效的日志级别 %q，可选 debug|info|warn|error", level)
+	}
+
+	output, err := openOutput(opts.OutputPath)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开日志文件: %w", err)
+	}
+
+	core := newJSONCore(output, zapLevel(level))
+	if opts.Console && output != os.Stdout {
+		// 控制台不需要 Sync（终端上 fsync 会报错），包装后 AddSync 使用空操作
+		console := newJSONCore(struct{ io.Writer }{consoleOutput}, zapLevel(level))
+		core = zapcore.NewTee(core, console)
+	}
+
+	l := &Logger{
+		output: output,
+		zap:    zap.New(core),
+	}
+
+	if opts.EventsPath != "" {
+		eventsOutput, err := openOutput(opts.EventsPath)
+		if err != nil {
+			_ = l.Close()
+			return nil, fmt.Errorf("无法打开事件日志文件: %w", err)
+		}
+		l.eventsOutput = eventsOutput
+		l.events = zap.New(newJSONCore(eventsOutput, zapcore.DebugLevel))
+	}
+
+	return l, nil
+}
+
+func openOutput(path string) (*os.File, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+}
+
+// newJSONCore 创建 JSON 编码的日志核心，字段名与 LogEntry 保持一致
+func newJSONCore(output io.Writer, level zapcore.LevelEnabler) zapcore.Core {
+	encoderCfg := zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "level",
+		MessageKey:     "message",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeDuration: zapcore.MillisDurationEncoder,
+	}
+	return zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderCfg),
+		zapcore.AddSync(output),
+		level,
+	)
+}
+
+// zapLevel 将日志级别转换为 zap 级别
+func zapLevel(level LogLevel) zapcore.Level {
+	switch level {
+	case LevelDebug:
+		return zapcore.DebugLevel
+	case LevelWarn:
+		return zapcore.WarnLevel
+	case LevelError:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// enabled 判断主日志是否会输出该级别，用于在格式化消息前提前丢弃
+func (l *Logger) enabled(level LogLevel) bool {
+	return l.zap.Core().Enabled(zapLevel(level))
+}
+
 func GetLogger() *Logger {
 	if LogHandle == nil {
 		panic("not init logger")
@@ -104,25 +206,190 @@ func Debugf(format string, args ...any) {
 	GetLogger().Debugf(format, args...)
 }
 
+// LogGameStart 使用全局单例记录游戏启动事件
+func LogGameStart(processName string) {
+	GetLogger().LogGameStart(processName)
+}
+
+// LogGameStop 使用全局单例记录游戏停止事件
+func LogGameStop(processName string, duration int64) {
+	GetLogger().LogGameStop(processName, duration)
+}
+
+// LogWouldTerminate 使用全局单例记录监控模式下本应终止的进程
+func LogWouldTerminate(processName string, pid int) {
+	GetLogger().LogWouldTerminate(processName, pid)
+}
+
+// LogDaemonExit 使用全局单例记录守护进程退出事件
+func LogDaemonExit(reason, detail string) {
+	GetLogger().LogDaemonExit(reason, detail)
+}
+
+// LogTerminateAccessDenied 使用全局单例记录因权限不足无法终止游戏进程事件
+func LogTerminateAccessDenied(reason, processName string, pid int) {
+	GetLogger().LogTerminateAccessDenied(reason, processName, pid)
+}
+
+// LogShortSessionIgnored 使用全局单例记录被忽略的短会话
+func LogShortSessionIgnored(processName string, duration int64) {
+	GetLogger().LogShortSessionIgnored(processName, duration)
+}
+
+// LogGameRunning 使用全局单例记录游戏运行中心跳事件
+func LogGameRunning(processName string, duration int64) {
+	GetLogger().LogGameRunning(processName, duration)
+}
+
 // LogQuotaReset 使用全局单例记录配额重置事件
 func LogQuotaReset() {
 	GetLogger().LogQuotaReset()
 }
 
+// LogDailySummary 使用全局单例记录每日汇总事件
+func LogDailySummary(playedSeconds int64, limitReached bool, terminations int, gameSeconds map[string]int64) {
+	GetLogger().LogDailySummary(playedSeconds, limitReached, terminations, gameSeconds)
+}
+
 // LogLimitExceeded 使用全局单例记录超限事件
 func LogLimitExceeded() {
 	GetLogger().LogLimitExceeded()
 }
 
+// LogSoftLimitExceeded 使用全局单例记录超过软限制事件
+func LogSoftLimitExceeded(overMinutes, remainingMinutes int) {
+	GetLogger().LogSoftLimitExceeded(overMinutes, remainingMinutes)
+}
+
+// LogGamesNeverSeen 使用全局单例记录从未出现过的游戏进程
+func LogGamesNeverSeen(names []string) {
+	GetLogger().LogGamesNeverSeen(names)
+}
+
+// LogIdlePaused 使用全局单例记录空闲暂停事件
+func LogIdlePaused(idle time.Duration) {
+	GetLogger().LogIdlePaused(idle)
+}
+
+// LogIdleResumed 使用全局单例记录空闲恢复事件
+func LogIdleResumed() {
+	GetLogger().LogIdleResumed()
+}
+
+// LogScreenLocked 使用全局单例记录锁屏暂停事件
+func LogScreenLocked() {
+	GetLogger().LogScreenLocked()
+}
+
+// LogScreenUnlocked 使用全局单例记录解锁恢复事件
+func LogScreenUnlocked() {
+	GetLogger().LogScreenUnlocked()
+}
+
+// LogBreakStarted 使用全局单例记录开始强制休息事件
+func LogBreakStarted(playedMinutes int, until time.Time) {
+	GetLogger().LogBreakStarted(playedMinutes, until)
+}
+
+// LogBreakEnded 使用全局单例记录强制休息结束事件
+func LogBreakEnded() {
+	GetLogger().LogBreakEnded()
+}
+
+// LogTimeEarned 使用全局单例记录赚取奖励时间事件
+func LogTimeEarned(app string, minutes, totalMinutes int) {
+	GetLogger().LogTimeEarned(app, minutes, totalMinutes)
+}
+
+// LogTimeExtended 使用全局单例记录临时延长游戏时间事件
+func LogTimeExtended(minutes, totalMinutes int, nonce string) {
+	GetLogger().LogTimeExtended(minutes, totalMinutes, nonce)
+}
+
+// LogControlRejected 使用全局单例记录拒绝执行控制命令事件
+func LogControlRejected(command string) {
+	GetLogger().LogControlRejected(command)
+}
+
+// LogConfigTampered 使用全局单例记录配置文件被外部修改事件
+func LogConfigTampered(path string) {
+	GetLogger().LogConfigTampered(path)
+}
+
+// LogStateTampered 使用全局单例记录状态文件被篡改事件
+func LogStateTampered(path, reason string) {
+	GetLogger().LogStateTampered(path, reason)
+}
+
+// LogScannerDegraded 使用全局单例记录进程扫描连续失败事件
+func LogScannerDegraded(failures int, err error) {
+	GetLogger().LogScannerDegraded(failures, err)
+}
+
+// LogSlowScan 使用全局单例记录进程扫描过慢事件
+func LogSlowScan(took, threshold time.Duration) {
+	GetLogger().LogSlowScan(took, threshold)
+}
+
+// LogLimitAction 使用全局单例记录超限动作的执行结果
+func LogLimitAction(reason, action string, pids []int, err error) {
+	GetLogger().LogLimitAction(reason, action, pids, err)
+}
+
+// LogRefusedTerminateCritical 使用全局单例记录拒绝终止关键进程
+func LogRefusedTerminateCritical(processName string, pid int, reason error) {
+	GetLogger().LogRefusedTerminateCritical(processName, pid, reason)
+}
+
+// LogProhibitedProcess 使用全局单例记录检测到禁止运行的进程
+func LogProhibitedProcess(processName string, pid int) {
+	GetLogger().LogProhibitedProcess(processName, pid)
+}
+
+// LogRelaunchBlocked 使用全局单例记录超限后重新启动的游戏进程
+func LogRelaunchBlocked(processName string, pid int) {
+	GetLogger().LogRelaunchBlocked(processName, pid)
+}
+
+// LogTerminationResult 使用全局单例记录一轮终止游戏进程的结果
+func LogTerminationResult(reason string, succeeded, failed []int) {
+	GetLogger().LogTerminationResult(reason, succeeded, failed)
+}
+
+// LogStateCorruptQuarantined 使用全局单例记录损坏的状态文件已被隔离
+func LogStateCorruptQuarantined(path, quarantined string) {
+	GetLogger().LogStateCorruptQuarantined(path, quarantined)
+}
+
+// Flush 同步全局单例日志器
+func Flush() error {
+	return GetLogger().Flush()
+}
+
 // Close 关闭全局单例日志器
 func Close() error {
 	return GetLogger().Close()
 }
 
+// Flush 将已写入的日志同步到磁盘，确保关键审计事件在进程被强制结束前落盘
+func (l *Logger) Flush() error {
+	var err error
+	if l.output != os.Stdout && l.output != os.Stderr {
+		err = l.zap.Sync()
+	}
+	if l.events != nil {
+		if syncErr := l.events.Sync(); err == nil {
+			err = syncErr
+		}
+	}
+	return err
+}
+
 // Close 关闭日志记录器
 func (l *Logger) Close() error {
-	if l != nil && l.zap != nil {
-		_ = l.zap.Sync()
+	_ = l.Flush()
+	if l.eventsOutput != nil {
+		_ = l.eventsOutput.Close()
 	}
 	if l.output != os.Stdout && l.output != os.Stderr {
 		return l.output.Close()
@@ -139,24 +406,61 @@ func (l *Logger) log(entry LogEntry) {
 	if entry.Process != "" {
 		fields = append(fields, zap.String("process", entry.Process))
 	}
+	if entry.PID > 0 {
+		fields = append(fields, zap.Int("pid", entry.PID))
+	}
 	if entry.Duration > 0 {
 		fields = append(fields, zap.Int64("duration", entry.Duration))
 	}
+	if len(entry.Succeeded) > 0 {
+		fields = append(fields, zap.Ints("succeeded", entry.Succeeded))
+	}
+	if len(entry.Failed) > 0 {
+		fields = append(fields, zap.Ints("failed", entry.Failed))
+	}
+	if entry.Reason != "" {
+		fields = append(fields, zap.String("terminate_reason", entry.Reason))
+	}
+	if entry.ExitReason != "" {
+		fields = append(fields, zap.String("exit_reason", entry.ExitReason))
+	}
+	if len(entry.Games) > 0 {
+		fields = append(fields, zap.Any("games", entry.Games))
+	}
+	if entry.LimitReached {
+		fields = append(fields, zap.Bool("limitReached", entry.LimitReached))
+	}
+	if entry.Terminations > 0 {
+		fields = append(fields, zap.Int("terminations", entry.Terminations))
+	}
+	if entry.Nonce != "" {
+		fields = append(fields, zap.String("nonce", entry.Nonce))
+	}
 
-	switch entry.Level {
+	write(l.zap, entry.Level, entry.Message, fields)
+	if l.events != nil && entry.Event != "" {
+		write(l.events, entry.Level, entry.Message, fields)
+	}
+}
+
+func write(z *zap.Logger, level LogLevel, message string, fields []zap.Field) {
+	switch level {
 	case LevelWarn:
-		l.zap.Warn(entry.Message, fields...)
+		z.Warn(message, fields...)
 	case LevelError:
-		l.zap.Error(entry.Message, fields...)
+		z.Error(message, fields...)
 	case LevelDebug:
-		l.zap.Debug(entry.Message, fields...)
+		z.Debug(message, fields...)
 	default:
-		l.zap.Info(entry.Message, fields...)
+		z.Info(message, fields...)
 	}
 }
 
 // Infof 记录信息日志
 func (l *Logger) Infof(format string, args ...any) {
+	if !l.enabled(LevelInfo) {
+		return
+	}
 	l.log(LogEntry{
 		Level:   LevelInfo,
 		Message: fmt.Sprintf(format, args...),
@@ -181,6 +485,9 @@ func (l *Logger) Errorf(format string, args ...any) {
 
 // Debugf 记录调试日志
 func (l *Logger) Debugf(format string, args ...any) {
+	if !l.enabled(LevelDebug) {
+		return
+	}
 	l.log(LogEntry{
 		Level:   LevelDebug,
 		Message: fmt.Sprintf(format, args...),
@@ -191,7 +498,7 @@ func (l *Logger) Debugf(format string, args ...any) {
 func (l *Logger) LogGameStart(processName string) {
 	l.log(LogEntry{
 		Level:   LevelInfo,
-		Message: fmt.Sprintf("游戏进程启动: %s", processName),
+		Message: i18n.T("event.gameStart", processName),
 		Event:   "game_start",
 		Process: processName,
 	})
@@ -201,27 +508,342 @@ func (l *Logger) LogGameStart(processName string) {
 func (l *Logger) LogGameStop(processName string, duration int64) {
 	l.log(LogEntry{
 		Level:    LevelInfo,
-		Message:  fmt.Sprintf("游戏进程停止: %s, 运行时长: %dms", processName, duration),
+		Message:  i18n.T("event.gameStop", processName, duration),
 		Event:    "game_stop",
 		Process:  processName,
 		Duration: duration,
 	})
 }
 
+// LogWouldTerminate 记录监控模式下本应终止、但实际未终止的进程
+func (l *Logger) LogWouldTerminate(processName string, pid int) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.wouldTerminate", processName, pid),
+		Event:   "would_terminate",
+		Process: processName,
+		PID:     pid,
+	})
+}
+
+// LogTerminateAccessDenied 记录因权限不足（未以管理员身份运行或受保护进程）无法终止游戏进程，reason 为终止原因
+func (l *Logger) LogTerminateAccessDenied(reason, processName string, pid int) {
+	l.log(LogEntry{
+		Level:   LevelError,
+		Message: i18n.T("event.terminateAccessDenied", processName, pid),
+		Event:   "terminate_access_denied",
+		Process: processName,
+		PID:     pid,
+		Reason:  reason,
+	})
+}
+
+// LogDaemonExit 记录守护进程退出及原因，detail 为补充说明（如 panic 的值）；崩溃与未记录的退出记为错误
+func (l *Logger) LogDaemonExit(reason, detail string) {
+	level := LevelInfo
+	if reason == "panic" || reason == "killed" {
+		level = LevelError
+	}
+	message := i18n.T("event.daemonExit", i18n.T("exit."+reason))
+	if detail != "" {
+		message += ": " + detail
+	}
+	l.log(LogEntry{
+		Level:      level,
+		Message:    message,
+		Event:      "daemon_exit",
+		ExitReason: reason,
+	})
+}
+
+// LogStateCorruptQuarantined 记录无法使用的状态文件已改名保留，随后以新状态启动
+func (l *Logger) LogStateCorruptQuarantined(path, quarantined string) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.stateCorrupt", path, quarantined),
+		Event:   "state_corrupt_quarantined",
+	})
+}
+
+// LogTerminationResult 汇总一轮执行限制时终止游戏进程的结果，reason 为终止原因（quota、curfew、break），有失败时记为错误
+func (l *Logger) LogTerminationResult(reason string, succeeded, failed []int) {
+	level := LevelInfo
+	message := i18n.T("event.terminated", len(succeeded))
+	if len(failed) > 0 {
+		level = LevelError
+		message = i18n.T("event.terminationFailed",
+			len(succeeded)+len(failed), len(succeeded), len(failed), failed)
+	}
+	l.log(LogEntry{
+		Level:     level,
+		Message:   message + reasonSuffix(reason),
+		Event:     "termination_result",
+		Succeeded: succeeded,
+		Failed:    failed,
+		Reason:    reason,
+	})
+}
+
+// LogLimitAction 记录 enforcement.onLimit 中 terminate 以外的动作（suspend / lock / logoff），
+// pids 为本次挂起的进程，reason 同 LogTerminationResult，执行失败时记为错误
+func (l *Logger) LogLimitAction(reason, action string, pids []int, err error) {
+	entry := LogEntry{
+		Level:     LevelWarn,
+		Message:   i18n.T("event.limitAction", action),
+		Event:     "limit_action",
+		Succeeded: pids,
+		Reason:    reason,
+	}
+	if err != nil {
+		entry.Level = LevelError
+		entry.Message = i18n.T("event.limitActionFailed", action, err)
+	}
+	entry.Message += reasonSuffix(reason)
+	l.log(entry)
+}
+
+// reasonSuffix 返回附在消息后的终止原因说明，reason 为空时返回空串
+func reasonSuffix(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return i18n.T("event.terminateReason", i18n.T("reason."+reason))
+}
+
+// LogRefusedTerminateCritical 记录因是系统关键进程或本程序而拒绝终止（或挂起）的进程
+func (l *Logger) LogRefusedTerminateCritical(processName string, pid int, reason error) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.refusedTerminateCritical", processName, pid, reason),
+		Event:   "refused_terminate_critical",
+		Process: processName,
+		PID:     pid,
+	})
+}
+
+// LogRelaunchBlocked 记录 enforcement.blockRelaunch 下超限后重新启动的游戏进程（随后将其立即终止）
+func (l *Logger) LogRelaunchBlocked(processName string, pid int) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.relaunchBlocked", processName, pid),
+		Event:   "relaunch_blocked",
+		Process: processName,
+		PID:     pid,
+	})
+}
+
+// LogProhibitedProcess 记录检测到 enforcement.prohibited 中的进程（随后将其终止）
+func (l *Logger) LogProhibitedProcess(processName string, pid int) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.prohibitedProcess", processName, pid),
+		Event:   "prohibited_process",
+		Process: processName,
+		PID:     pid,
+	})
+}
+
+// LogShortSessionIgnored 记录因短于最短会话时长而不计时的游戏会话结束
+func (l *Logger) LogShortSessionIgnored(processName string, duration int64) {
+	l.log(LogEntry{
+		Level:    LevelInfo,
+		Message:  i18n.T("event.shortSessionIgnored", processName, duration),
+		Event:    "short_session_ignored",
+		Process:  processName,
+		Duration: duration,
+	})
+}
+
+// LogGameRunning 记录游戏运行中心跳事件（调试级别，主要供事件日志离线还原会话时长）
+func (l *Logger) LogGameRunning(processName string, duration int64) {
+	l.log(LogEntry{
+		Level:    LevelDebug,
+		Message:  i18n.T("event.gameRunning", processName, duration),
+		Event:    "game_running",
+		Process:  processName,
+		Duration: duration,
+	})
+}
+
 // LogQuotaReset 记录配额重置事件
 func (l *Logger) LogQuotaReset() {
 	l.log(LogEntry{
 		Level:   LevelInfo,
-		Message: "每日游戏时间配额已重置",
+		Message: i18n.T("event.quotaReset"),
 		Event:   "quota_reset",
 	})
+	_ = l.Flush()
+}
+
+// LogDailySummary 记录每日重置前一个配额周期的汇总：累计游戏时间（秒）、是否达到每日限制、
+// 终止的游戏进程数以及各游戏的累计时间（秒）
+func (l *Logger) LogDailySummary(playedSeconds int64, limitReached bool, terminations int, gameSeconds map[string]int64) {
+	limit := i18n.T("summary.limitNotReached")
+	if limitReached {
+		limit = i18n.T("summary.limitReached")
+	}
+	l.log(LogEntry{
+		Level:        LevelInfo,
+		Message:      i18n.T("event.dailySummary", playedSeconds/60, limit, terminations),
+		Event:        "daily_summary",
+		Duration:     playedSeconds * 1000,
+		Games:        gameSeconds,
+		LimitReached: limitReached,
+		Terminations: terminations,
+	})
+	_ = l.Flush()
 }
 
 // LogLimitExceeded 记录时间限制超限事件
 func (l *Logger) LogLimitExceeded() {
 	l.log(LogEntry{
 		Level:   LevelWarn,
-		Message: "每日游戏时间限制已超限，终止游戏进程",
+		Message: i18n.T("event.limitExceeded"),
 		Event:   "limit_exceeded",
 	})
+	_ = l.Flush()
+}
+
+// LogSoftLimitExceeded 记录超过软限制的提醒事件，overMinutes 为超出软限制的分钟数
+func (l *Logger) LogSoftLimitExceeded(overMinutes, remainingMinutes int) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.softLimitExceeded", overMinutes, remainingMinutes),
+		Event:   "soft_limit_exceeded",
+	})
+}
+
+// LogGamesNeverSeen 记录配置中从未出现过的游戏进程名（可能是拼写错误）
+func (l *Logger) LogGamesNeverSeen(names []string) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.gamesNeverSeen", strings.Join(names, ", ")),
+		Event:   "game_never_seen",
+		Process: strings.Join(names, ","),
+	})
+}
+
+// LogIdlePaused 记录因用户空闲暂停计时事件
+func (l *Logger) LogIdlePaused(idle time.Duration) {
+	l.log(LogEntry{
+		Level:    LevelInfo,
+		Message:  i18n.T("event.idlePaused", idle.Round(time.Second)),
+		Event:    "idle_paused",
+		Duration: idle.Milliseconds(),
+	})
+}
+
+// LogIdleResumed 记录用户恢复输入、继续计时事件
+func (l *Logger) LogIdleResumed() {
+	l.log(LogEntry{
+		Level:   LevelInfo,
+		Message: i18n.T("event.idleResumed"),
+		Event:   "idle_resumed",
+	})
+}
+
+// LogScreenLocked 记录因工作站锁定暂停计时事件
+func (l *Logger) LogScreenLocked() {
+	l.log(LogEntry{
+		Level:   LevelInfo,
+		Message: i18n.T("event.screenLocked"),
+		Event:   "screen_locked",
+	})
+}
+
+// LogScreenUnlocked 记录工作站解锁、继续计时事件
+func (l *Logger) LogScreenUnlocked() {
+	l.log(LogEntry{
+		Level:   LevelInfo,
+		Message: i18n.T("event.screenUnlocked"),
+		Event:   "screen_unlocked",
+	})
+}
+
+// LogBreakStarted 记录连续游戏达到上限、开始强制休息的事件，until 为休息结束时间
+func (l *Logger) LogBreakStarted(playedMinutes int, until time.Time) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.breakStarted", playedMinutes, until.Format("15:04")),
+		Event:   "break_started",
+	})
+}
+
+// LogBreakEnded 记录强制休息结束的事件
+func (l *Logger) LogBreakEnded() {
+	l.log(LogEntry{
+		Level:   LevelInfo,
+		Message: i18n.T("event.breakEnded"),
+		Event:   "break_ended",
+	})
+}
+
+// LogTimeEarned 记录运行奖励应用赚取了 minutes 分钟游戏时间，totalMinutes 为当天累计奖励的分钟数
+func (l *Logger) LogTimeEarned(app string, minutes, totalMinutes int) {
+	l.log(LogEntry{
+		Level:   LevelInfo,
+		Message: i18n.T("event.timeEarned", app, minutes, totalMinutes),
+		Event:   "time_earned",
+		Process: app,
+	})
+}
+
+// LogTimeExtended 记录家长临时延长了 minutes 分钟游戏时间，totalMinutes 为当天共延长的分钟数，
+// nonce 为签名命令的随机数（未签名时为空），用于区分每一次授权
+func (l *Logger) LogTimeExtended(minutes, totalMinutes int, nonce string) {
+	l.log(LogEntry{
+		Level:   LevelInfo,
+		Message: i18n.T("event.timeExtended", minutes, totalMinutes),
+		Event:   "time_extended",
+		Nonce:   nonce,
+	})
+}
+
+// LogControlRejected 记录控制文件中缺少签名、签名不符、重放或已过期而未被执行的命令
+func (l *Logger) LogControlRejected(command string) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.controlRejected", command),
+		Event:   "control_rejected",
+	})
+	_ = l.Flush()
+}
+
+// LogConfigTampered 记录运行期间配置文件被外部修改的事件
+func (l *Logger) LogConfigTampered(path string) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.configTampered", path),
+		Event:   "config_tampered",
+	})
+	_ = l.Flush()
+}
+
+// LogStateTampered 记录状态文件被篡改的事件，reason 说明原因与处理方式
+func (l *Logger) LogStateTampered(path, reason string) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.stateTampered", path, reason),
+		Event:   "state_tampered",
+	})
+	_ = l.Flush()
+}
+
+// LogSlowScan 记录单次进程扫描耗时超过阈值的事件，Duration 为扫描耗时（毫秒）
+func (l *Logger) LogSlowScan(took, threshold time.Duration) {
+	l.log(LogEntry{
+		Level:    LevelWarn,
+		Message:  i18n.T("event.slowScan", took.Milliseconds(), threshold.Milliseconds()),
+		Event:    "slow_scan",
+		Duration: took.Milliseconds(),
+	})
+}
+
+// LogScannerDegraded 记录进程扫描连续失败的事件，此后控制器沿用最近一次成功扫描的结果执行限制
+func (l *Logger) LogScannerDegraded(failures int, err error) {
+	l.log(LogEntry{
+		Level:   LevelWarn,
+		Message: i18n.T("event.scannerDegraded", failures, err),
+		Event:   "scanner_degraded",
+	})
 }
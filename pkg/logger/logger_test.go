@@ -250,3 +250,169 @@ func TestLogEntryTimestamp(t *testing.T) {
 		t.Errorf("Timestamp %v is outside expected range [%v, %v]", entry.Timestamp, before, after)
 	}
 }
+
+func TestLogGamesNeverSeen(t *testing.T) {
+	resetLogFile(t)
+
+	testLogger.LogGamesNeverSeen([]string{"a.exe", "b.exe"})
+
+	data, err := os.ReadFile(testLogPath)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	if entry.Event != "game_never_seen" {
+		t.Errorf("Expected event to be 'game_never_seen', got %s", entry.Event)
+	}
+	if entry.Level != LevelWarn {
+		t.Errorf("Expected level to be %s, got %s", LevelWarn, entry.Level)
+	}
+	if entry.Process != "a.exe,b.exe" {
+		t.Errorf("Expected process to be 'a.exe,b.exe', got %s", entry.Process)
+	}
+}
+
+func TestEventsStreamOnlyContainsEvents(t *testing.T) {
+	dir := t.TempDir()
+	eventsPath := filepath.Join(dir, "events.jsonl")
+	l, err := newLogger(Options{
+		OutputPath: filepath.Join(dir, "main.log"),
+		EventsPath: eventsPath,
+	})
+	if err != nil {
+		t.Fatalf("newLogger() failed: %v", err)
+	}
+
+	l.Infof("plain message without event")
+	l.LogGameStart("game.exe")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(eventsPath)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 event line, got %d: %s", len(lines), data)
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if entry.Event != "game_start" || entry.Process != "game.exe" {
+		t.Errorf("Expected game_start event for game.exe, got %+v", entry)
+	}
+
+	mainData, err := os.ReadFile(filepath.Join(dir, "main.log"))
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	if !strings.Contains(string(mainData), "plain message without event") {
+		t.Error("Main log should still contain non-event messages")
+	}
+}
+
+func TestCriticalEventsFlushedWithoutClose(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "flush.log")
+	l, err := newLogger(Options{OutputPath: path, EventsPath: filepath.Join(dir, "events.jsonl")})
+	if err != nil {
+		t.Fatalf("newLogger() failed: %v", err)
+	}
+	defer l.Close()
+
+	l.LogLimitExceeded()
+	l.LogQuotaReset()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	for _, event := range []string{"limit_exceeded", "quota_reset"} {
+		if !strings.Contains(string(data), `"event":"`+event+`"`) {
+			t.Errorf("Expected %s to be on disk before Close, got %s", event, data)
+		}
+	}
+
+	if err := l.Flush(); err != nil {
+		t.Errorf("Flush() failed: %v", err)
+	}
+}
+
+func TestLoggerLevelFiltersMainLog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "warn.log")
+	eventsPath := filepath.Join(dir, "events.jsonl")
+	l, err := newLogger(Options{OutputPath: path, EventsPath: eventsPath, Level: LevelWarn})
+	if err != nil {
+		t.Fatalf("newLogger() failed: %v", err)
+	}
+
+	l.Infof("info should be dropped")
+	l.Warnf("warn should be kept")
+	l.LogGameStart("game.exe")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], "warn should be kept") {
+		t.Fatalf("Expected only the warn line, got %s", data)
+	}
+
+	events, err := os.ReadFile(eventsPath)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	if !strings.Contains(string(events), "game_start") {
+		t.Errorf("Events stream should not be filtered by level, got %s", events)
+	}
+}
+
+func TestNewLoggerRejectsUnknownLevel(t *testing.T) {
+	if _, err := newLogger(Options{OutputPath: filepath.Join(t.TempDir(), "x.log"), Level: "verbose"}); err == nil {
+		t.Fatal("Expected error for unknown level")
+	}
+}
+
+func TestConsoleTeeWritesBothSinks(t *testing.T) {
+	var console strings.Builder
+	orig := consoleOutput
+	consoleOutput = &console
+	defer func() { consoleOutput = orig }()
+
+	path := filepath.Join(t.TempDir(), "tee.log")
+	l, err := newLogger(Options{OutputPath: path, Console: true})
+	if err != nil {
+		t.Fatalf("newLogger() failed: %v", err)
+	}
+
+	l.Infof("tee message")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	if !strings.Contains(string(data), "tee message") {
+		t.Errorf("Expected message in file sink, got %s", data)
+	}
+	if !strings.Contains(console.String(), "tee message") {
+		t.Errorf("Expected message in console sink, got %s", console.String())
+	}
+}
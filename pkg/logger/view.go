@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// levelRank 日志级别从低到高的顺序
+var levelRank = map[LogLevel]int{
+	LevelDebug: 0,
+	LevelInfo:  1,
+	LevelWarn:  2,
+	LevelError: 3,
+}
+
+// ParseLevel 解析日志级别名称（不区分大小写）
+func ParseLevel(s string) (LogLevel, error) {
+	level := LogLevel(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := levelRank[level]; !ok {
+		return "", fmt.Errorf("无效的日志级别 %q，可选 debug|info|warn|error", s)
+	}
+	return level, nil
+}
+
+// AtLeast 判断级别是否不低于 min；未知级别总是视为满足
+func (l LogLevel) AtLeast(min LogLevel) bool {
+	rank, ok := levelRank[l]
+	if !ok {
+		return true
+	}
+	return rank >= levelRank[min]
+}
+
+// ParseEntry 解析一行 JSON 日志
+func ParseEntry(line []byte) (LogEntry, error) {
+	var entry LogEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return LogEntry{}, fmt.Errorf("无法解析日志行: %w", err)
+	}
+	return entry, nil
+}
+
+// FormatEntry 将日志条目格式化为便于阅读的单行文本，时间按 loc 显示
+func FormatEntry(entry LogEntry, loc *time.Location) string {
+	var b strings.Builder
+	b.WriteString(entry.Timestamp.In(loc).Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, " %-5s ", strings.ToUpper(string(entry.Level)))
+	b.WriteString(entry.Message)
+
+	var extras []string
+	if entry.Event != "" {
+		extras = append(extras, "event="+entry.Event)
+	}
+	if entry.Process != "" {
+		extras = append(extras, "process="+entry.Process)
+	}
+	if entry.Duration > 0 {
+		extras = append(extras, fmt.Sprintf("duration=%dms", entry.Duration))
+	}
+	if len(extras) > 0 {
+		b.WriteString(" [")
+		b.WriteString(strings.Join(extras, " "))
+		b.WriteString("]")
+	}
+	return b.String()
+}
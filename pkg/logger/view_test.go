@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAndFormatEntry(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "普通信息",
+			line: `{"level":"info","timestamp":"2026-02-12T08:00:05.123Z","message":"游戏时间控制守护进程启动"}`,
+			want: "2026-02-12 08:00:05 INFO  游戏时间控制守护进程启动",
+		},
+		{
+			name: "带事件字段",
+			line: `{"level":"info","timestamp":"2026-02-12T09:30:00Z","message":"游戏进程停止: game.exe, 运行时长: 60000ms","event":"game_stop","process":"game.exe","duration":60000}`,
+			want: "2026-02-12 09:30:00 INFO  游戏进程停止: game.exe, 运行时长: 60000ms [event=game_stop process=game.exe duration=60000ms]",
+		},
+		{
+			name: "警告",
+			line: `{"level":"warn","timestamp":"2026-02-12T10:00:00Z","message":"每日游戏时间限制已超限，终止游戏进程","event":"limit_exceeded"}`,
+			want: "2026-02-12 10:00:00 WARN  每日游戏时间限制已超限，终止游戏进程 [event=limit_exceeded]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := ParseEntry([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("ParseEntry 失败: %v", err)
+			}
+			if got := FormatEntry(entry, time.UTC); got != tt.want {
+				t.Errorf("格式化结果不匹配\n预期: %s\n实际: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatEntryUsesLocation(t *testing.T) {
+	entry, err := ParseEntry([]byte(`{"level":"info","timestamp":"2026-02-12T00:00:00Z","message":"m"}`))
+	if err != nil {
+		t.Fatalf("ParseEntry 失败: %v", err)
+	}
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	if got := FormatEntry(entry, shanghai); got != "2026-02-12 08:00:00 INFO  m" {
+		t.Errorf("时间应按指定时区显示，实际为 %s", got)
+	}
+}
+
+func TestParseEntryInvalid(t *testing.T) {
+	if _, err := ParseEntry([]byte("not json")); err == nil {
+		t.Fatal("非 JSON 行应返回错误")
+	}
+}
+
+func TestLevelAtLeast(t *testing.T) {
+	min, err := ParseLevel("WARN")
+	if err != nil {
+		t.Fatalf("ParseLevel 失败: %v", err)
+	}
+
+	if LevelInfo.AtLeast(min) {
+		t.Error("info 不应满足最低级别 warn")
+	}
+	if !LevelWarn.AtLeast(min) || !LevelError.AtLeast(min) {
+		t.Error("warn/error 应满足最低级别 warn")
+	}
+	if !LogLevel("fatal").AtLeast(min) {
+		t.Error("未知级别应总是显示")
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("无效级别应返回错误")
+	}
+}
@@ -0,0 +1,71 @@
+package notifier
+
+import "time"
+
+// asyncNotifier 在独立 goroutine 中弹窗，调用立即返回，弹窗命令卡住或等待用户关闭时不阻塞控制循环；
+// 失败时交给 onError 处理（通常是记录日志）
+type asyncNotifier struct {
+	Notifier
+	onError func(error)
+}
+
+// Async 包装 n，使每次通知都在后台执行
+func Async(n Notifier, onError func(error)) Notifier {
+	return &asyncNotifier{Notifier: n, onError: onError}
+}
+
+// send 在后台执行一次通知
+func (a *asyncNotifier) send(notify func() error) {
+	go func() {
+		if err := notify(); err != nil && a.onError != nil {
+			a.onError(err)
+		}
+	}()
+}
+
+func (a *asyncNotifier) NotifyFirstWarning(remainingMinutes int) error {
+	a.send(func() error { return a.Notifier.NotifyFirstWarning(remainingMinutes) })
+	return nil
+}
+
+func (a *asyncNotifier) NotifyFinalWarning(remainingMinutes int) error {
+	a.send(func() error { return a.Notifier.NotifyFinalWarning(remainingMinutes) })
+	return nil
+}
+
+func (a *asyncNotifier) NotifyLimitExceeded(nextReset time.Time) error {
+	a.send(func() error { return a.Notifier.NotifyLimitExceeded(nextReset) })
+	return nil
+}
+
+func (a *asyncNotifier) NotifySoftLimit(overMinutes, remainingMinutes int) error {
+	a.send(func() error { return a.Notifier.NotifySoftLimit(overMinutes, remainingMinutes) })
+	return nil
+}
+
+func (a *asyncNotifier) NotifyTerminationFailed() error {
+	a.send(a.Notifier.NotifyTerminationFailed)
+	return nil
+}
+
+func (a *asyncNotifier) NotifyBreak(playedMinutes int, until time.Time) error {
+	a.send(func() error { return a.Notifier.NotifyBreak(playedMinutes, until) })
+	return nil
+}
+
+func (a *asyncNotifier) NotifyAccessDenied(game string) error {
+	a.send(func() error { return a.Notifier.NotifyAccessDenied(game) })
+	return nil
+}
+
+func (a *asyncNotifier) NotifyOutsideSchedule(nextAllowed time.Time) error {
+	a.send(func() error { return a.Notifier.NotifyOutsideSchedule(nextAllowed) })
+	return nil
+}
+
+func (a *asyncNotifier) NotifyDailySummary(playedMinutes int, limitReached bool, terminations int, gameMinutes map[string]int) error {
+	a.send(func() error {
+		return a.Notifier.NotifyDailySummary(playedMinutes, limitReached, terminations, gameMinutes)
+	})
+	return nil
+}
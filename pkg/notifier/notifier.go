@@ -1,49 +1,191 @@
 package notifier
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
-	"runtime"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/sysexec"
 )
 
 type Notifier interface {
 	NotifyFirstWarning(remainingMinutes int) error
 	NotifyFinalWarning(remainingMinutes int) error
-	NotifyLimitExceeded() error
+	// NotifyLimitExceeded 超限通知，nextReset 为恢复游戏时间的时刻（按其时区显示），零值时只显示通用提示
+	NotifyLimitExceeded(nextReset time.Time) error
+	// NotifySoftLimit 超过软限制后的提醒，overMinutes 为超出软限制的分钟数，remainingMinutes 为距硬限制的剩余分钟数
+	NotifySoftLimit(overMinutes, remainingMinutes int) error
+	// NotifyTerminationFailed 超限、时段外或强制休息时未能终止游戏进程（通常是权限不足）
+	NotifyTerminationFailed() error
+	// NotifyAccessDenied 因权限不足（未以管理员身份运行或受保护进程）无法终止游戏 game，提示以管理员身份运行
+	NotifyAccessDenied(game string) error
+	// NotifyBreak 连续游戏 playedMinutes 分钟后开始强制休息，until 为休息结束时间（按其时区显示）
+	NotifyBreak(playedMinutes int, until time.Time) error
+	// NotifyOutsideSchedule 不在允许的游戏时段内关闭游戏的通知，nextAllowed 为下次开放的时刻（按其时区显示），零值时只显示通用提示
+	NotifyOutsideSchedule(nextAllowed time.Time) error
+	// NotifyDailySummary 每日重置时的汇总（notifications.dailySummary 启用时）：累计游戏分钟数、是否达到每日限制、
+	// 关闭的游戏进程数与各游戏的分钟数
+	NotifyDailySummary(playedMinutes int, limitReached bool, terminations int, gameMinutes map[string]int) error
+}
+
+// DefaultTimeout 未配置 notifications.timeoutSeconds 时单次弹窗命令的超时
+const DefaultTimeout = 10 * time.Second
+
+type WindowsNotifier struct {
+	runner  sysexec.CommandRunner
+	timeout time.Duration
 }
 
-type WindowsNotifier struct{}
+// NewNotifier 创建默认通知器，单次弹窗命令超过 timeout 即结束。以服务运行（会话 0）时 PowerShell 弹窗无法显示在用户桌面上，
+// 改为向活动控制台会话发送消息
+func NewNotifier(timeout time.Duration) Notifier {
+	if inServiceSession() {
+		n := NewSessionNotifier(sysexec.WindowsRunner{})
+		n.timeout = timeout
+		return n
+	}
+	n := NewWindowsNotifier(sysexec.WindowsRunner{})
+	n.timeout = timeout
+	return n
+}
 
-func NewNotifier() Notifier {
-	return &WindowsNotifier{}
+// NewWindowsNotifier 创建使用指定命令执行器弹窗的通知器（用于测试），超时为 DefaultTimeout
+func NewWindowsNotifier(runner sysexec.CommandRunner) *WindowsNotifier {
+	return &WindowsNotifier{runner: runner, timeout: DefaultTimeout}
+}
+
+// runWithRetry 在 timeout 内执行弹窗命令，失败时重试一次。超时不重试：卡住的 PowerShell 重试多半仍会卡住，
+// 而弹窗可能已经显示过
+func runWithRetry(runner sysexec.CommandRunner, timeout time.Duration, name string, args ...string) ([]byte, error) {
+	output, err := sysexec.RunTimeout(runner, timeout, name, args...)
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, sysexec.ErrUnsupportedPlatform) {
+		return output, err
+	}
+	return sysexec.RunTimeout(runner, timeout, name, args...)
 }
 
 func (n *WindowsNotifier) NotifyFirstWarning(remainingMinutes int) error {
-	msg := fmt.Sprintf("游戏剩余时间不足，当前还剩 %d 分钟。", remainingMinutes)
-	return showPopup("游戏时间提醒", msg)
+	return n.showPopup(firstWarning(remainingMinutes))
 }
 
 func (n *WindowsNotifier) NotifyFinalWarning(remainingMinutes int) error {
-	msg := fmt.Sprintf("最后提醒：游戏剩余时间仅 %d 分钟。", remainingMinutes)
-	return showPopup("游戏时间最后提醒", msg)
+	return n.showPopup(finalWarning(remainingMinutes))
+}
+
+func (n *WindowsNotifier) NotifyLimitExceeded(nextReset time.Time) error {
+	return n.showPopup(limitExceeded(nextReset))
+}
+
+func (n *WindowsNotifier) NotifySoftLimit(overMinutes, remainingMinutes int) error {
+	return n.showPopup(softLimit(overMinutes, remainingMinutes))
+}
+
+func (n *WindowsNotifier) NotifyTerminationFailed() error {
+	return n.showPopup(terminationFailed())
+}
+
+func (n *WindowsNotifier) NotifyAccessDenied(game string) error {
+	return n.showPopup(accessDenied(game))
+}
+
+func (n *WindowsNotifier) NotifyBreak(playedMinutes int, until time.Time) error {
+	return n.showPopup(breakStarted(playedMinutes, until))
+}
+
+func (n *WindowsNotifier) NotifyOutsideSchedule(nextAllowed time.Time) error {
+	return n.showPopup(outsideSchedule(nextAllowed))
+}
+
+func (n *WindowsNotifier) NotifyDailySummary(playedMinutes int, limitReached bool, terminations int, gameMinutes map[string]int) error {
+	return n.showPopup(dailySummary(playedMinutes, limitReached, terminations, gameMinutes))
+}
+
+// firstWarning 首次提醒的标题与内容
+func firstWarning(remainingMinutes int) (title, message string) {
+	return i18n.T("notify.first.title"), i18n.T("notify.first.message", remainingMinutes)
+}
+
+// finalWarning 最后提醒的标题与内容
+func finalWarning(remainingMinutes int) (title, message string) {
+	return i18n.T("notify.final.title"), i18n.T("notify.final.message", remainingMinutes)
+}
+
+// softLimit 软限制提醒的标题与内容
+func softLimit(overMinutes, remainingMinutes int) (title, message string) {
+	return i18n.T("notify.soft.title"), i18n.T("notify.soft.message", overMinutes, remainingMinutes)
+}
+
+// limitExceeded 超限通知的标题与内容，nextReset 非零时附上恢复游戏的时间
+func limitExceeded(nextReset time.Time) (title, message string) {
+	message = i18n.T("notify.limit.message")
+	if !nextReset.IsZero() {
+		message += i18n.T("notify.limit.resume", nextReset.Format(i18n.T("time.resumeLayout")))
+	}
+	return i18n.T("notify.limit.title"), message
+}
+
+// outsideSchedule 时段外通知的标题与内容，nextAllowed 非零时附上下次开放的时间
+func outsideSchedule(nextAllowed time.Time) (title, message string) {
+	message = i18n.T("notify.schedule.message")
+	if !nextAllowed.IsZero() {
+		message += i18n.T("notify.schedule.resume", nextAllowed.Format(i18n.T("time.resumeLayout")))
+	}
+	return i18n.T("notify.schedule.title"), message
 }
 
-func (n *WindowsNotifier) NotifyLimitExceeded() error {
-	return showPopup("游戏时间已用尽", "今日游戏时间已达上限，系统将终止游戏进程。")
+// breakStarted 强制休息通知的标题与内容
+func breakStarted(playedMinutes int, until time.Time) (title, message string) {
+	return i18n.T("notify.break.title"), i18n.T("notify.break.message", playedMinutes, until.Format(i18n.T("time.resumeLayout")))
 }
 
-func showPopup(title, message string) error {
-	if runtime.GOOS != "windows" {
-		return fmt.Errorf("桌面弹窗仅支持 Windows")
+// dailySummary 每日汇总通知的标题与内容，各游戏按时间从多到少列出
+func dailySummary(playedMinutes int, limitReached bool, terminations int, gameMinutes map[string]int) (title, message string) {
+	limit := i18n.T("summary.limitNotReached")
+	if limitReached {
+		limit = i18n.T("summary.limitReached")
+	}
+	message = i18n.T("notify.summary.message", playedMinutes, limit, terminations)
+
+	games := make([]string, 0, len(gameMinutes))
+	for game := range gameMinutes {
+		games = append(games, game)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if gameMinutes[games[i]] != gameMinutes[games[j]] {
+			return gameMinutes[games[i]] > gameMinutes[games[j]]
+		}
+		return games[i] < games[j]
+	})
+	items := make([]string, len(games))
+	for i, game := range games {
+		items[i] = i18n.T("notify.summary.game", game, gameMinutes[game])
+	}
+	if len(items) > 0 {
+		message += i18n.T("notify.summary.games", strings.Join(items, i18n.T("notify.summary.separator")))
 	}
+	return i18n.T("notify.summary.title"), message
+}
+
+// terminationFailed 终止失败通知的标题与内容
+func terminationFailed() (title, message string) {
+	return i18n.T("notify.terminationFailed.title"), i18n.T("notify.terminationFailed.message")
+}
+
+// accessDenied 权限不足无法终止游戏时通知的标题与内容
+func accessDenied(game string) (title, message string) {
+	return i18n.T("notify.accessDenied.title"), i18n.T("notify.accessDenied.message", game)
+}
 
+func (n *WindowsNotifier) showPopup(title, message string) error {
 	title = escapeSingleQuotes(title)
 	message = escapeSingleQuotes(message)
 	script := fmt.Sprintf("Add-Type -AssemblyName System.Windows.Forms; [System.Windows.Forms.MessageBox]::Show('%s','%s') | Out-Null", message, title)
 
-	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
-	output, err := cmd.CombinedOutput()
+	output, err := runWithRetry(n.runner, n.timeout, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
 	if err != nil {
 		return fmt.Errorf("弹窗通知失败: %w, 输出: %s", err, string(output))
 	}
@@ -0,0 +1,218 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/i18n"
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+func TestWindowsNotifier_PowerShellArgs(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.NotifyFirstWarning(15); err != nil {
+		t.Fatalf("NotifyFirstWarning 失败: %v", err)
+	}
+	if len(fake.Calls) != 1 {
+		t.Fatalf("应执行 1 次命令，实际 %d 次", len(fake.Calls))
+	}
+
+	call := fake.Calls[0]
+	if call[0] != "powershell" || strings.Join(call[1:4], " ") != "-NoProfile -NonInteractive -Command" {
+		t.Fatalf("powershell 参数不正确: %v", call)
+	}
+	script := call[len(call)-1]
+	if !strings.Contains(script, "MessageBox]::Show('游戏剩余时间不足，当前还剩 15 分钟。','游戏时间提醒')") {
+		t.Errorf("弹窗脚本不正确: %s", script)
+	}
+}
+
+func TestWindowsNotifier_EscapesQuotes(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.showPopup("it's", "don't"); err != nil {
+		t.Fatalf("showPopup 失败: %v", err)
+	}
+	script := fake.Calls[0][len(fake.Calls[0])-1]
+	if !strings.Contains(script, "Show('don''t','it''s')") {
+		t.Errorf("单引号应被转义，实际脚本: %s", script)
+	}
+}
+
+func TestWindowsNotifier_Error(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return nil, sysexec.ErrUnsupportedPlatform
+		},
+	}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.NotifyLimitExceeded(time.Time{}); !errors.Is(err, sysexec.ErrUnsupportedPlatform) {
+		t.Fatalf("应返回命令执行错误，实际 %v", err)
+	}
+}
+
+func TestNotificationText_English(t *testing.T) {
+	i18n.SetLanguage(i18n.EN)
+	defer i18n.SetLanguage(i18n.ZH)
+
+	title, message := limitExceeded(time.Time{})
+	if title != "Game time used up" || !strings.Contains(message, "limit has been reached") {
+		t.Errorf("英文环境下通知应为英文，实际 %q / %q", title, message)
+	}
+	if _, message = limitExceeded(time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)); !strings.HasSuffix(message, "Game time returns at Mar 9 08:00.") {
+		t.Errorf("英文超限通知应包含恢复时间，实际 %q", message)
+	}
+}
+
+func TestWindowsNotifier_LimitExceededShowsResumeTime(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.NotifyLimitExceeded(time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("NotifyLimitExceeded 失败: %v", err)
+	}
+	script := fake.Calls[0][len(fake.Calls[0])-1]
+	if !strings.Contains(script, "系统将终止游戏进程。游戏时间将于 3月9日 08:00 恢复。") {
+		t.Errorf("超限通知应包含恢复时间，实际脚本: %s", script)
+	}
+
+	if err := n.NotifyLimitExceeded(time.Time{}); err != nil {
+		t.Fatalf("NotifyLimitExceeded 失败: %v", err)
+	}
+	script = fake.Calls[1][len(fake.Calls[1])-1]
+	if strings.Contains(script, "恢复") {
+		t.Errorf("未提供重置时间时应只显示通用提示，实际脚本: %s", script)
+	}
+}
+
+func TestWindowsNotifier_Break(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.NotifyBreak(60, time.Date(2026, 3, 9, 20, 15, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("NotifyBreak 失败: %v", err)
+	}
+	script := fake.Calls[0][len(fake.Calls[0])-1]
+	if !strings.Contains(script, "已连续游戏 60 分钟，请休息一下，游戏将于 3月9日 20:15 后恢复。") {
+		t.Errorf("强制休息通知内容不正确，实际脚本: %s", script)
+	}
+}
+
+func TestWindowsNotifier_OutsideSchedule(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.NotifyOutsideSchedule(time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("NotifyOutsideSchedule 失败: %v", err)
+	}
+	script := fake.Calls[0][len(fake.Calls[0])-1]
+	if !strings.Contains(script, "不在游戏时段内") || !strings.Contains(script, "游戏将于 3月10日 07:00 开放。") {
+		t.Errorf("时段外通知应说明开放时间，实际脚本: %s", script)
+	}
+	if strings.Contains(script, "游戏时间已用尽") {
+		t.Errorf("时段外通知不应提示时间用尽，实际脚本: %s", script)
+	}
+}
+
+func TestWindowsNotifier_AccessDenied(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.NotifyAccessDenied("game.exe"); err != nil {
+		t.Fatalf("NotifyAccessDenied 失败: %v", err)
+	}
+	script := fake.Calls[0][len(fake.Calls[0])-1]
+	if !strings.Contains(script, "没有权限关闭 game.exe") || !strings.Contains(script, "请以管理员身份运行 game-control") {
+		t.Errorf("权限不足通知应说明游戏并提示以管理员身份运行，实际脚本: %s", script)
+	}
+}
+
+func TestWindowsNotifier_DailySummary(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.NotifyDailySummary(90, true, 2, map[string]int{"b.exe": 30, "a.exe": 60}); err != nil {
+		t.Fatalf("NotifyDailySummary 失败: %v", err)
+	}
+	script := fake.Calls[0][len(fake.Calls[0])-1]
+	if !strings.Contains(script, "今日共游戏 90 分钟，已达到每日限制，关闭游戏进程 2 次。\n各游戏：a.exe 60 分钟、b.exe 30 分钟") {
+		t.Errorf("每日汇总通知内容不正确，实际脚本: %s", script)
+	}
+}
+
+func TestWindowsNotifier_TimeoutCancelsHangingPopup(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		ContextHandler: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	n := NewWindowsNotifier(fake)
+	n.timeout = 50 * time.Millisecond
+
+	err := n.NotifyFirstWarning(15)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PowerShell 卡住时应返回超时错误，实际 %v", err)
+	}
+	if len(fake.Calls) != 1 {
+		t.Errorf("超时不应重试，实际执行 %d 次", len(fake.Calls))
+	}
+}
+
+func TestWindowsNotifier_RetriesTransientFailure(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	fake.Handler = func(name string, args ...string) ([]byte, error) {
+		if len(fake.Calls) == 1 {
+			return nil, errors.New("无法启动 PowerShell")
+		}
+		return nil, nil
+	}
+	n := NewWindowsNotifier(fake)
+
+	if err := n.NotifyFirstWarning(15); err != nil {
+		t.Fatalf("重试成功后不应返回错误: %v", err)
+	}
+	if len(fake.Calls) != 2 {
+		t.Errorf("失败后应重试一次，实际执行 %d 次", len(fake.Calls))
+	}
+}
+
+func TestAsync_DoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	errs := make(chan error, 1)
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			<-release
+			return nil, errors.New("拒绝访问")
+		},
+	}
+	n := Async(NewSessionNotifier(fake), func(err error) { errs <- err })
+
+	done := make(chan struct{})
+	go func() {
+		n.NotifyLimitExceeded(time.Time{})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("后台通知不应阻塞调用方")
+	}
+
+	close(release)
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("通知失败时应交给 onError")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("通知失败时应调用 onError")
+	}
+}
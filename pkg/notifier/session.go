@@ -0,0 +1,107 @@
+package notifier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+// sessionMessageSeconds msg.exe 消息框自动关闭前的显示时长
+const sessionMessageSeconds = 300
+
+// SessionNotifier 通过 msg.exe 向活动控制台会话发送消息，
+// 用于以 Windows 服务运行（会话 0）时仍能提醒已登录的用户
+type SessionNotifier struct {
+	runner  sysexec.CommandRunner
+	timeout time.Duration
+}
+
+// NewSessionNotifier 创建使用指定命令执行器的会话通知器，超时为 DefaultTimeout
+func NewSessionNotifier(runner sysexec.CommandRunner) *SessionNotifier {
+	return &SessionNotifier{runner: runner, timeout: DefaultTimeout}
+}
+
+func (n *SessionNotifier) NotifyFirstWarning(remainingMinutes int) error {
+	return n.send(firstWarning(remainingMinutes))
+}
+
+func (n *SessionNotifier) NotifyFinalWarning(remainingMinutes int) error {
+	return n.send(finalWarning(remainingMinutes))
+}
+
+func (n *SessionNotifier) NotifyLimitExceeded(nextReset time.Time) error {
+	return n.send(limitExceeded(nextReset))
+}
+
+func (n *SessionNotifier) NotifyBreak(playedMinutes int, until time.Time) error {
+	return n.send(breakStarted(playedMinutes, until))
+}
+
+func (n *SessionNotifier) NotifyOutsideSchedule(nextAllowed time.Time) error {
+	return n.send(outsideSchedule(nextAllowed))
+}
+
+func (n *SessionNotifier) NotifySoftLimit(overMinutes, remainingMinutes int) error {
+	return n.send(softLimit(overMinutes, remainingMinutes))
+}
+
+func (n *SessionNotifier) NotifyTerminationFailed() error {
+	return n.send(terminationFailed())
+}
+
+func (n *SessionNotifier) NotifyAccessDenied(game string) error {
+	return n.send(accessDenied(game))
+}
+
+func (n *SessionNotifier) NotifyDailySummary(playedMinutes int, limitReached bool, terminations int, gameMinutes map[string]int) error {
+	return n.send(dailySummary(playedMinutes, limitReached, terminations, gameMinutes))
+}
+
+// send 查询活动控制台会话并向其发送消息
+func (n *SessionNotifier) send(title, message string) error {
+	sessionID, err := n.activeSessionID()
+	if err != nil {
+		return err
+	}
+
+	output, err := runWithRetry(n.runner, n.timeout, "msg", strconv.Itoa(sessionID),
+		fmt.Sprintf("/TIME:%d", sessionMessageSeconds), title+"\n\n"+message)
+	if err != nil {
+		return fmt.Errorf("向会话 %d 发送消息失败: %w, 输出: %s", sessionID, err, string(output))
+	}
+	return nil
+}
+
+// activeSessionID 通过 query session 查询活动控制台会话 ID
+func (n *SessionNotifier) activeSessionID() (int, error) {
+	output, err := sysexec.RunTimeout(n.runner, n.timeout, "query", "session")
+	if err != nil {
+		return 0, fmt.Errorf("查询用户会话失败: %w", err)
+	}
+	return parseConsoleSession(string(output))
+}
+
+// parseConsoleSession 从 query session 输出中找出控制台会话 ID。
+// 状态列会随系统语言变化，因此按会话名 console 识别，并要求已有用户登录。
+//
+//	 SESSIONNAME       USERNAME                 ID  STATE   TYPE        DEVICE
+//	 services                                    0  Disc
+//	>console           kid                       1  Active
+func parseConsoleSession(output string) (int, error) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), ">"))
+		if len(fields) < 3 || !strings.EqualFold(fields[0], "console") {
+			continue
+		}
+		// 有用户登录时依次为会话名、用户名、ID
+		id, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		return id, nil
+	}
+	return 0, fmt.Errorf("没有已登录用户的控制台会话")
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package notifier
+
+// inServiceSession 非 Windows 平台没有服务会话隔离
+func inServiceSession() bool {
+	return false
+}
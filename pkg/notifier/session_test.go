@@ -0,0 +1,94 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+const querySessionOutput = ` SESSIONNAME       USERNAME                 ID  STATE   TYPE        DEVICE
+ services                                    0  Disc
+>console           kid                       1  Active
+ rdp-tcp                                 65536  Listen
+`
+
+func TestParseConsoleSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantID  int
+		wantErr bool
+	}{
+		{name: "控制台已登录", output: querySessionOutput, wantID: 1},
+		{name: "中文系统", output: " 会话名            用户名                   ID  状态    类型        设备\r\n services                                    0  断开\r\n console           孩子                      2  运行中\r\n", wantID: 2},
+		{name: "控制台无人登录", output: " services      0  Disc\n console       1  Conn\n", wantErr: true},
+		{name: "空输出", output: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := parseConsoleSession(tt.output)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("预期解析失败，实际得到会话 %d", id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if id != tt.wantID {
+				t.Errorf("会话 ID 应为 %d，实际 %d", tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestSessionNotifier_SendsToConsoleSession(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			if name == "query" {
+				return []byte(querySessionOutput), nil
+			}
+			return nil, nil
+		},
+	}
+	n := NewSessionNotifier(fake)
+
+	if err := n.NotifyFinalWarning(5); err != nil {
+		t.Fatalf("NotifyFinalWarning 失败: %v", err)
+	}
+	if len(fake.Calls) != 2 {
+		t.Fatalf("应先查询会话再发送消息，实际调用 %v", fake.Calls)
+	}
+	if got := strings.Join(fake.Calls[0], " "); got != "query session" {
+		t.Errorf("会话查询命令不正确: %s", got)
+	}
+
+	msg := fake.Calls[1]
+	if len(msg) != 4 || msg[0] != "msg" || msg[1] != "1" || msg[2] != "/TIME:300" {
+		t.Fatalf("msg 参数不正确: %q", msg)
+	}
+	if msg[3] != "游戏时间最后提醒\n\n最后提醒：游戏剩余时间仅 5 分钟。" {
+		t.Errorf("消息内容不正确: %q", msg[3])
+	}
+}
+
+func TestSessionNotifier_QueryFailure(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return nil, errors.New("拒绝访问")
+		},
+	}
+	n := NewSessionNotifier(fake)
+
+	if err := n.NotifyLimitExceeded(time.Time{}); err == nil {
+		t.Fatal("查询会话失败时应返回错误")
+	}
+	if len(fake.Calls) != 1 {
+		t.Errorf("查询会话失败时不应发送消息，实际调用 %v", fake.Calls)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package notifier
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                 = syscall.NewLazyDLL("kernel32.dll")
+	procProcessIdToSessionId = kernel32.NewProc("ProcessIdToSessionId")
+)
+
+// inServiceSession 判断当前进程是否运行在会话 0（Windows 服务所在的隔离会话）
+func inServiceSession() bool {
+	var sessionID uint32
+	ret, _, _ := procProcessIdToSessionId.Call(
+		uintptr(syscall.Getpid()),
+		uintptr(unsafe.Pointer(&sessionID)),
+	)
+	return ret != 0 && sessionID == 0
+}
@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+// SystemSounds notifications.sound 可用的系统声音名称（对应 System.Media.SystemSounds，不区分大小写）
+var SystemSounds = []string{"Asterisk", "Beep", "Exclamation", "Hand", "Question"}
+
+// IsSystemSound 判断 sound 是否为系统声音名称，是则返回规范写法
+func IsSystemSound(sound string) (string, bool) {
+	for _, name := range SystemSounds {
+		if strings.EqualFold(name, sound) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// soundNotifier 在提醒与超限通知弹出的同时播放提示音。
+// 播放在后台进行，不阻塞控制循环，失败时交给 onError 处理（通常是记录日志）
+type soundNotifier struct {
+	Notifier
+	sound   string
+	runner  sysexec.CommandRunner
+	onError func(error)
+}
+
+// WithSound 包装 n，在首次/最后提醒、软限制提醒、超限、时段外与强制休息通知时播放 sound（.wav 文件路径或系统声音名称）；
+// sound 为空时原样返回 n
+func WithSound(n Notifier, sound string, onError func(error)) Notifier {
+	if sound == "" {
+		return n
+	}
+	return &soundNotifier{Notifier: n, sound: sound, runner: sysexec.WindowsRunner{}, onError: onError}
+}
+
+func (s *soundNotifier) NotifyFirstWarning(remainingMinutes int) error {
+	go s.play()
+	return s.Notifier.NotifyFirstWarning(remainingMinutes)
+}
+
+func (s *soundNotifier) NotifyFinalWarning(remainingMinutes int) error {
+	go s.play()
+	return s.Notifier.NotifyFinalWarning(remainingMinutes)
+}
+
+func (s *soundNotifier) NotifyLimitExceeded(nextReset time.Time) error {
+	go s.play()
+	return s.Notifier.NotifyLimitExceeded(nextReset)
+}
+
+func (s *soundNotifier) NotifySoftLimit(overMinutes, remainingMinutes int) error {
+	go s.play()
+	return s.Notifier.NotifySoftLimit(overMinutes, remainingMinutes)
+}
+
+func (s *soundNotifier) NotifyBreak(playedMinutes int, until time.Time) error {
+	go s.play()
+	return s.Notifier.NotifyBreak(playedMinutes, until)
+}
+
+func (s *soundNotifier) NotifyOutsideSchedule(nextAllowed time.Time) error {
+	go s.play()
+	return s.Notifier.NotifyOutsideSchedule(nextAllowed)
+}
+
+// play 通过 PowerShell 同步播放提示音（在调用方的 goroutine 中阻塞到播放结束）
+func (s *soundNotifier) play() {
+	output, err := s.runner.Run("powershell", "-NoProfile", "-NonInteractive", "-Command", soundScript(s.sound))
+	if err != nil && s.onError != nil {
+		s.onError(fmt.Errorf("播放提示音 %s 失败: %w, 输出: %s", s.sound, err, string(output)))
+	}
+}
+
+// soundScript 返回播放 sound 的 PowerShell 脚本：系统声音名称使用 SystemSounds，其余按 .wav 文件路径播放
+func soundScript(sound string) string {
+	if name, ok := IsSystemSound(sound); ok {
+		return fmt.Sprintf("[System.Media.SystemSounds]::%s.Play(); Start-Sleep -Milliseconds 1500", name)
+	}
+	path := escapeSingleQuotes(filepath.FromSlash(sound))
+	return fmt.Sprintf("(New-Object Media.SoundPlayer '%s').PlaySync()", path)
+}
@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+func TestSoundScript(t *testing.T) {
+	tests := []struct {
+		sound  string
+		expect string
+	}{
+		{sound: "exclamation", expect: "[System.Media.SystemSounds]::Exclamation.Play()"},
+		{sound: "Asterisk", expect: "[System.Media.SystemSounds]::Asterisk.Play()"},
+		{sound: `C:\Sounds\bell.wav`, expect: `(New-Object Media.SoundPlayer 'C:\Sounds\bell.wav').PlaySync()`},
+		{sound: `C:\Kid's\bell.wav`, expect: `(New-Object Media.SoundPlayer 'C:\Kid''s\bell.wav').PlaySync()`},
+	}
+
+	for _, tt := range tests {
+		if script := soundScript(tt.sound); !strings.Contains(script, tt.expect) {
+			t.Errorf("soundScript(%q) 应包含 %q，实际 %q", tt.sound, tt.expect, script)
+		}
+	}
+}
+
+func TestSoundNotifier_PlaysOnWarning(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	base := &sysexec.FakeRunner{}
+	played := make(chan struct{}, 1)
+	fake.Handler = func(name string, args ...string) ([]byte, error) {
+		played <- struct{}{}
+		return nil, nil
+	}
+	n := &soundNotifier{Notifier: NewWindowsNotifier(base), sound: "Beep", runner: fake}
+
+	if err := n.NotifyFinalWarning(5); err != nil {
+		t.Fatalf("NotifyFinalWarning 失败: %v", err)
+	}
+	<-played
+	if len(base.Calls) != 1 {
+		t.Fatalf("应仍然弹出 1 次通知，实际 %d 次", len(base.Calls))
+	}
+	call := fake.Calls[0]
+	if call[0] != "powershell" || strings.Join(call[1:4], " ") != "-NoProfile -NonInteractive -Command" {
+		t.Fatalf("powershell 参数不正确: %v", call)
+	}
+	if !strings.Contains(call[len(call)-1], "SystemSounds]::Beep.Play()") {
+		t.Errorf("播放脚本不正确: %s", call[len(call)-1])
+	}
+}
+
+func TestSoundNotifier_ReportsError(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return nil, sysexec.ErrUnsupportedPlatform
+		},
+	}
+	var got error
+	n := &soundNotifier{sound: "Hand", runner: fake, onError: func(err error) { got = err }}
+
+	n.play()
+	if !errors.Is(got, sysexec.ErrUnsupportedPlatform) {
+		t.Fatalf("播放失败应交给 onError，实际 %v", got)
+	}
+}
+
+func TestWithSound_EmptyReturnsBase(t *testing.T) {
+	base := NewWindowsNotifier(&sysexec.FakeRunner{})
+	if n := WithSound(base, "", nil); n != Notifier(base) {
+		t.Error("未配置提示音时应原样返回通知器")
+	}
+}
@@ -0,0 +1,18 @@
+package privilege
+
+import "errors"
+
+// ErrNotElevated 当前进程不具备管理员/root 权限
+var ErrNotElevated = errors.New("not running with administrator/root privileges")
+
+// Require 在当前进程未提权时返回 ErrNotElevated
+func Require() error {
+	elevated, err := IsElevated()
+	if err != nil {
+		return err
+	}
+	if !elevated {
+		return ErrNotElevated
+	}
+	return nil
+}
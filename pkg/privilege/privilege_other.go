@@ -0,0 +1,13 @@
+//go:build !windows
+
+package privilege
+
+import "os"
+
+// geteuid 便于测试替换
+var geteuid = os.Geteuid
+
+// IsElevated 检查当前进程是否以 root 运行
+func IsElevated() (bool, error) {
+	return geteuid() == 0, nil
+}
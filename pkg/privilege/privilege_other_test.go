@@ -0,0 +1,47 @@
+//go:build !windows
+
+package privilege
+
+import (
+	"errors"
+	"testing"
+)
+
+func stubEUID(t *testing.T, uid int) {
+	t.Helper()
+	orig := geteuid
+	geteuid = func() int { return uid }
+	t.Cleanup(func() { geteuid = orig })
+}
+
+func TestIsElevated_NonRoot(t *testing.T) {
+	stubEUID(t, 1000)
+
+	elevated, err := IsElevated()
+	if err != nil {
+		t.Fatalf("IsElevated 失败: %v", err)
+	}
+	if elevated {
+		t.Fatal("非 root 用户不应视为已提权")
+	}
+}
+
+func TestIsElevated_Root(t *testing.T) {
+	stubEUID(t, 0)
+
+	elevated, err := IsElevated()
+	if err != nil {
+		t.Fatalf("IsElevated 失败: %v", err)
+	}
+	if !elevated {
+		t.Fatal("root 用户应视为已提权")
+	}
+}
+
+func TestRequire_NonRoot(t *testing.T) {
+	stubEUID(t, 1000)
+
+	if err := Require(); !errors.Is(err, ErrNotElevated) {
+		t.Fatalf("非 root 用户应返回 ErrNotElevated，实际为 %v", err)
+	}
+}
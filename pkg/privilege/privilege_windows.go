@@ -0,0 +1,36 @@
+//go:build windows
+
+package privilege
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// tokenElevation TOKEN_INFORMATION_CLASS 中的 TokenElevation
+const tokenElevation = 20
+
+// IsElevated 检查当前进程令牌是否已提升为管理员
+func IsElevated() (bool, error) {
+	proc, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return false, fmt.Errorf("获取当前进程句柄失败: %w", err)
+	}
+
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(proc, syscall.TOKEN_QUERY, &token); err != nil {
+		return false, fmt.Errorf("打开进程令牌失败: %w", err)
+	}
+	defer token.Close()
+
+	var elevation uint32
+	var returned uint32
+	err = syscall.GetTokenInformation(token, tokenElevation,
+		(*byte)(unsafe.Pointer(&elevation)), uint32(unsafe.Sizeof(elevation)), &returned)
+	if err != nil {
+		return false, fmt.Errorf("查询令牌提权状态失败: %w", err)
+	}
+
+	return elevation != 0, nil
+}
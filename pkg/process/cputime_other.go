@@ -0,0 +1,13 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"time"
+)
+
+// CPUTime 非 Windows 平台不支持读取进程 CPU 时间
+func CPUTime(pid int) (time.Duration, error) {
+	return 0, fmt.Errorf("当前只支持 Windows 平台")
+}
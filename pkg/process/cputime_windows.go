@@ -0,0 +1,29 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+)
+
+// CPUTime 返回进程累计占用的 CPU 时间（内核态与用户态之和）
+func CPUTime(pid int) (time.Duration, error) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return 0, fmt.Errorf("打开进程失败 (PID: %d): %w", pid, err)
+	}
+	defer syscall.CloseHandle(h)
+
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return 0, fmt.Errorf("读取进程时间失败 (PID: %d): %w", pid, err)
+	}
+	return filetimeDuration(kernel) + filetimeDuration(user), nil
+}
+
+// filetimeDuration 将表示时长的 FILETIME（100 纳秒为单位）转换为 time.Duration
+func filetimeDuration(ft syscall.Filetime) time.Duration {
+	return time.Duration(int64(ft.HighDateTime)<<32|int64(ft.LowDateTime)) * 100
+}
@@ -0,0 +1,54 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrCriticalProcess 拒绝终止系统关键进程或守护进程自身
+var ErrCriticalProcess = errors.New("拒绝终止关键进程")
+
+// ErrAccessDenied 权限不足，无法终止进程（需要以管理员身份运行）
+var ErrAccessDenied = errors.New("拒绝访问")
+
+// criticalProcesses 永不终止的 Windows 关键进程（小写映像名）。
+// games 或 enforcement.prohibited 误写成这些进程时，终止它们会导致桌面或整个系统崩溃
+var criticalProcesses = map[string]bool{
+	"system":       true,
+	"smss.exe":     true,
+	"csrss.exe":    true,
+	"wininit.exe":  true,
+	"winlogon.exe": true,
+	"services.exe": true,
+	"lsass.exe":    true,
+	"svchost.exe":  true,
+	"dwm.exe":      true,
+	"explorer.exe": true,
+}
+
+// IsCriticalName 判断映像名是否为系统关键进程或本程序（game-control 的守护进程、看护进程与命令行），不区分大小写
+func IsCriticalName(name string) bool {
+	name = strings.ToLower(name)
+	if criticalProcesses[name] {
+		return true
+	}
+	if exe, err := os.Executable(); err == nil && strings.EqualFold(filepath.Base(exe), name) {
+		return true
+	}
+	return false
+}
+
+// CheckTerminable 检查进程能否被终止：系统关键进程、本程序与当前进程一律拒绝，返回包含 ErrCriticalProcess 的说明错误
+func CheckTerminable(proc ProcessInfo) error {
+	if proc.PID == os.Getpid() {
+		return fmt.Errorf("%w: PID %d 是守护进程自身", ErrCriticalProcess, proc.PID)
+	}
+	if IsCriticalName(proc.Name) {
+		return fmt.Errorf("%w: %s (PID: %d) 是系统关键进程或本程序，请检查 games 与 enforcement.prohibited 配置",
+			ErrCriticalProcess, proc.Name, proc.PID)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+func TestCheckTerminable(t *testing.T) {
+	tests := []struct {
+		proc    ProcessInfo
+		refused bool
+	}{
+		{proc: ProcessInfo{PID: 100, Name: "explorer.exe"}, refused: true},
+		{proc: ProcessInfo{PID: 101, Name: "SVCHOST.EXE"}, refused: true},
+		{proc: ProcessInfo{PID: 102, Name: "winlogon.exe"}, refused: true},
+		{proc: ProcessInfo{PID: os.Getpid(), Name: "game.exe"}, refused: true},
+		{proc: ProcessInfo{PID: 103, Name: "game.exe"}, refused: false},
+	}
+	for _, tt := range tests {
+		err := CheckTerminable(tt.proc)
+		if tt.refused && !errors.Is(err, ErrCriticalProcess) {
+			t.Errorf("%s (PID: %d) 应被拒绝终止，实际 %v", tt.proc.Name, tt.proc.PID, err)
+		}
+		if !tt.refused && err != nil {
+			t.Errorf("%s 不是关键进程，不应被拒绝: %v", tt.proc.Name, err)
+		}
+	}
+}
+
+func TestTerminateProcess_RefusesSelf(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	scanner := NewScannerWithRunner(fake)
+
+	err := scanner.TerminateWithRetry(os.Getpid(), 3, 0)
+	if !errors.Is(err, ErrCriticalProcess) {
+		t.Fatalf("终止守护进程自身应返回 ErrCriticalProcess，实际 %v", err)
+	}
+	if len(fake.Calls) != 0 {
+		t.Errorf("拒绝终止时不应执行 taskkill，实际 %v", fake.Calls)
+	}
+}
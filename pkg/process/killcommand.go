@@ -0,0 +1,66 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// RenderKillCommand 按进程信息展开自定义结束命令模板，返回命令名与参数。
+// 模板先按空白拆分为参数（双引号包围的部分不拆分，引号本身去掉），再以 text/template 分别展开，
+// 可用 {{.PID}}、{{.Name}}、{{.Owner}}、{{.SessionID}} 等 ProcessInfo 字段，
+// 因此展开后含空格的值（如进程名）仍是一个参数
+func RenderKillCommand(command string, proc ProcessInfo) (string, []string, error) {
+	fields, err := splitCommandLine(command)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("结束命令为空")
+	}
+
+	args := make([]string, 0, len(fields))
+	for _, field := range fields {
+		tmpl, err := template.New("killCommand").Option("missingkey=error").Parse(field)
+		if err != nil {
+			return "", nil, fmt.Errorf("解析结束命令模板 %q 失败: %w", field, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, proc); err != nil {
+			return "", nil, fmt.Errorf("展开结束命令模板 %q 失败: %w", field, err)
+		}
+		args = append(args, b.String())
+	}
+	return args[0], args[1:], nil
+}
+
+// splitCommandLine 按空白拆分命令行，双引号包围的部分（可为空）作为一个参数的一部分
+func splitCommandLine(line string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	inQuotes, inField := false, false
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			inField = true
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteRune(r)
+			inField = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("结束命令 %q 中的引号不成对", line)
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
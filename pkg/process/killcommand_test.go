@@ -0,0 +1,57 @@
+package process
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRenderKillCommand(t *testing.T) {
+	proc := ProcessInfo{PID: 4321, Name: "My Game.exe", SessionID: 2}
+
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+	}{
+		{
+			name:    "占位符",
+			command: `launcher.exe --quit {{.PID}}`,
+			want:    []string{"launcher.exe", "--quit", "4321"},
+		},
+		{
+			name:    "展开后含空格仍为一个参数",
+			command: `taskkill /IM {{.Name}} /F`,
+			want:    []string{"taskkill", "/IM", "My Game.exe", "/F"},
+		},
+		{
+			name:    "引号包围的路径与拼接",
+			command: `"C:\Program Files\Launcher\launcher.exe" --session={{.SessionID}} ""`,
+			want:    []string{`C:\Program Files\Launcher\launcher.exe`, "--session=2", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, err := RenderKillCommand(tt.command, proc)
+			if err != nil {
+				t.Fatalf("展开失败: %v", err)
+			}
+			if got := append([]string{name}, args...); !slices.Equal(got, tt.want) {
+				t.Errorf("RenderKillCommand(%q) = %q，预期 %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderKillCommand_Errors(t *testing.T) {
+	for _, command := range []string{
+		"",
+		`launcher.exe "--quit`,
+		`launcher.exe {{.PID`,
+		`launcher.exe {{.Pid}}`,
+	} {
+		if _, _, err := RenderKillCommand(command, ProcessInfo{PID: 1}); err == nil {
+			t.Errorf("RenderKillCommand(%q) 应返回错误", command)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package process
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// 进程名匹配方式（matching.mode）：配置中的名称如何与扫描到的映像名比较，均不区分大小写。
+// title: 窗口标题匹配项不受影响，始终按标题子串匹配
+const (
+	MatchExact    = "exact"    // 映像名完全相同（默认）
+	MatchContains = "contains" // 映像名包含配置的名称
+	MatchGlob     = "glob"     // 通配符，如 "valorant*.exe"，语法同 path.Match
+	MatchRegex    = "regex"    // 正则表达式（RE2），在映像名中查找，需要整体匹配时用 ^...$
+)
+
+// MatchModes 可用的匹配方式
+var MatchModes = []string{MatchExact, MatchContains, MatchGlob, MatchRegex}
+
+// nameMatcher 判断映像名是否匹配某个配置项
+type nameMatcher func(name string) bool
+
+// compileMatcher 按匹配方式编译配置项，mode 为空时按 exact 处理
+func compileMatcher(mode, pattern string) (nameMatcher, error) {
+	switch mode {
+	case "", MatchExact:
+		return func(name string) bool { return strings.EqualFold(name, pattern) }, nil
+	case MatchContains:
+		lower := strings.ToLower(pattern)
+		return func(name string) bool { return strings.Contains(strings.ToLower(name), lower) }, nil
+	case MatchGlob:
+		lower := strings.ToLower(pattern)
+		if _, err := path.Match(lower, ""); err != nil {
+			return nil, fmt.Errorf("无效的通配符 %q: %w", pattern, err)
+		}
+		return func(name string) bool {
+			ok, _ := path.Match(lower, strings.ToLower(name))
+			return ok
+		}, nil
+	case MatchRegex:
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("无效的正则表达式 %q: %w", pattern, err)
+		}
+		return re.MatchString, nil
+	default:
+		return nil, fmt.Errorf("无效的匹配方式 %q，可选 %s", mode, strings.Join(MatchModes, "、"))
+	}
+}
+
+// ValidatePattern 检查 pattern 在匹配方式 mode 下是否有效
+func ValidatePattern(mode, pattern string) error {
+	_, err := compileMatcher(mode, pattern)
+	return err
+}
+
+// matchPattern 返回 names 中第一个按 s.matchMode 匹配映像名 name 的配置项，没有时返回空串。
+// 编译结果按配置项缓存，无效的配置项不匹配任何进程（加载配置时已校验）
+func (s *Scanner) matchPattern(name string, names []string) string {
+	for _, pattern := range names {
+		matcher, ok := s.matchers[pattern]
+		if !ok {
+			matcher, _ = compileMatcher(s.matchMode, pattern)
+			if s.matchers == nil {
+				s.matchers = make(map[string]nameMatcher)
+			}
+			s.matchers[pattern] = matcher
+		}
+		if matcher != nil && matcher(name) {
+			return pattern
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package process
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+// matchFixture 各匹配方式共用的进程列表
+const matchFixture = `"System","4","Services","0","100 K"` + "\r\n" +
+	`"VALORANT-Win64-Shipping.exe","100","Console","1","2,000,000 K"` + "\r\n" +
+	`"RiotClientServices.exe","101","Console","1","300,000 K"` + "\r\n" +
+	`"valorant.exe","102","Console","1","90,000 K"` + "\r\n" +
+	`"Minecraft.Windows.exe","200","Console","1","800,000 K"` + "\r\n" +
+	`"notepad.exe","300","Console","1","5,000 K"` + "\r\n"
+
+func TestFindGameProcesses_MatchModes(t *testing.T) {
+	tests := []struct {
+		mode  string
+		names []string
+		want  []int
+	}{
+		{mode: "", names: []string{"valorant.exe"}, want: []int{102}},
+		{mode: MatchExact, names: []string{"valorant"}, want: nil},
+		{mode: MatchContains, names: []string{"valorant"}, want: []int{100, 102}},
+		{mode: MatchContains, names: []string{"MINECRAFT"}, want: []int{200}},
+		{mode: MatchGlob, names: []string{"valorant*.exe"}, want: []int{100, 102}},
+		{mode: MatchGlob, names: []string{"riot?lient*"}, want: []int{101}},
+		{mode: MatchRegex, names: []string{`^valorant-win64-.*\.exe$`}, want: []int{100}},
+		{mode: MatchRegex, names: []string{`riot|minecraft`}, want: []int{101, 200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+" "+tt.names[0], func(t *testing.T) {
+			scanner := NewScannerWithRunner(&sysexec.FakeRunner{
+				Handler: func(name string, args ...string) ([]byte, error) { return []byte(matchFixture), nil },
+			})
+			scanner.SetMatchMode(tt.mode)
+
+			processes, err := scanner.FindGameProcesses(tt.names)
+			if err != nil {
+				t.Fatalf("FindGameProcesses 失败: %v", err)
+			}
+			var pids []int
+			for _, proc := range processes {
+				pids = append(pids, proc.PID)
+				if !proc.Matches(tt.names[0]) {
+					t.Errorf("匹配到的进程 %s 应能按配置项 %q 归属，实际 Match=%q", proc.Name, tt.names[0], proc.Match)
+				}
+			}
+			if !slices.Equal(pids, tt.want) {
+				t.Errorf("匹配方式 %q 下 %v 应匹配 %v，实际 %v", tt.mode, tt.names, tt.want, pids)
+			}
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	valid := []struct{ mode, pattern string }{
+		{MatchExact, "game.exe"},
+		{MatchContains, "valorant"},
+		{MatchGlob, "game[0-9].exe"},
+		{MatchRegex, `^game\d+\.exe$`},
+	}
+	for _, tt := range valid {
+		if err := ValidatePattern(tt.mode, tt.pattern); err != nil {
+			t.Errorf("%s 方式下 %q 应有效，实际错误: %v", tt.mode, tt.pattern, err)
+		}
+	}
+
+	invalid := []struct{ mode, pattern string }{
+		{MatchGlob, "game[.exe"},
+		{MatchRegex, "game(.exe"},
+		{"fuzzy", "game.exe"},
+	}
+	for _, tt := range invalid {
+		if err := ValidatePattern(tt.mode, tt.pattern); err == nil {
+			t.Errorf("%s 方式下 %q 应返回错误", tt.mode, tt.pattern)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package process
 
 import (
+	"errors"
 	"fmt"
-	"os/exec"
-	"runtime"
+	"os"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/yourusername/game-control/pkg/desktop"
+	"github.com/yourusername/game-control/pkg/sysexec"
 )
 
 // ProcessInfo 进程信息
@@ -13,35 +17,152 @@ type ProcessInfo struct {
 	PID       int       `json:"pid"`
 	Name      string    `json:"name"`
 	StartTime time.Time `json:"startTime"`
+	Owner     string    `json:"owner,omitempty"` // 所属账户（如 "PC\kid"），仅在启用所有者查询时填充
+	SessionID int       `json:"sessionId"`       // 所在的 Windows 会话编号（0 为服务会话）
+	Match     string    `json:"match,omitempty"` // 按窗口标题或 exact 以外的匹配方式命中时为命中的配置项（如 "title:Elden Ring"、"valorant"）
+}
+
+// ProcessKey 唯一标识一个进程实例。
+// PID 可能被系统复用，结合创建时间才能区分前后两个不同的进程。
+// 创建时间记为 Unix 毫秒（未知时为 0）而不是 time.Time：作为 map 键时 time.Time 会比较时区指针，
+// 扫描得到的本地时间与从状态文件恢复的 UTC 时间即使是同一时刻也不相等
+type ProcessKey struct {
+	PID         int
+	StartMillis int64
+}
+
+// Key 返回进程实例标识
+func (p ProcessInfo) Key() ProcessKey {
+	key := ProcessKey{PID: p.PID}
+	if !p.StartTime.IsZero() {
+		key.StartMillis = p.StartTime.UnixMilli()
+	}
+	return key
+}
+
+// diffProcesses 比较两次扫描结果，返回新出现与已消失的进程。
+// 同一 PID 的创建时间变化视为旧进程退出、新进程启动。
+func diffProcesses(prev, curr map[ProcessKey]ProcessInfo) (started, stopped []ProcessInfo) {
+	for key, proc := range curr {
+		if _, ok := prev[key]; !ok {
+			started = append(started, proc)
+		}
+	}
+	for key, proc := range prev {
+		if _, ok := curr[key]; !ok {
+			stopped = append(stopped, proc)
+		}
+	}
+	sortByPID(started)
+	sortByPID(stopped)
+	return started, stopped
 }
 
+func sortByPID(processes []ProcessInfo) {
+	sort.Slice(processes, func(i, j int) bool {
+		if processes[i].PID != processes[j].PID {
+			return processes[i].PID < processes[j].PID
+		}
+		return processes[i].StartTime.Before(processes[j].StartTime)
+	})
+}
+
+func toProcessMap(processes []ProcessInfo) map[ProcessKey]ProcessInfo {
+	m := make(map[ProcessKey]ProcessInfo, len(processes))
+	for _, proc := range processes {
+		m[proc.Key()] = proc
+	}
+	return m
+}
+
+const (
+	// scanAttempts tasklist 单次扫描的最大尝试次数
+	scanAttempts = 3
+	// scanRetryDelay 首次重试前的等待时间，之后每次翻倍
+	scanRetryDelay = 200 * time.Millisecond
+)
+
 // Scanner 进程扫描器
 type Scanner struct {
-	lastProcesses map[int]ProcessInfo // 上次扫描的进程
+	lastProcesses map[ProcessKey]ProcessInfo // 上次扫描的进程
+	exemptUsers   []string                   // 豁免账户，其进程不视为游戏进程
+	queryOwners   bool                       // 是否查询进程所有者（多档案按账户区分进程时需要）
+
+	// matchMode 进程名匹配方式（MatchExact 等），为空时为 exact；matchers 按配置项缓存编译结果
+	matchMode string
+	matchers  map[string]nameMatcher
+
+	// runner 执行 tasklist/taskkill 命令
+	runner sysexec.CommandRunner
+	// retryDelay 扫描失败后首次重试的等待时间
+	retryDelay time.Duration
+	// windowTitles 枚举各进程的窗口标题，仅在配置了 title: 匹配项时调用
+	windowTitles func() (map[int][]string, error)
+	// lastScanDuration 最近一次 ScanProcesses 的耗时（含重试）
+	lastScanDuration time.Duration
 }
 
 // NewScanner 创建新的进程扫描器
 func NewScanner() *Scanner {
+	return NewScannerWithRunner(sysexec.WindowsRunner{})
+}
+
+// NewScannerWithRunner 创建使用指定命令执行器的进程扫描器（用于测试）
+func NewScannerWithRunner(runner sysexec.CommandRunner) *Scanner {
 	return &Scanner{
-		lastProcesses: make(map[int]ProcessInfo),
+		lastProcesses: make(map[ProcessKey]ProcessInfo),
+		runner:        runner,
+		retryDelay:    scanRetryDelay,
+		windowTitles:  desktop.WindowTitles,
 	}
 }
 
-// ScanProcesses 扫描当前运行的进程
+// ScanProcesses 扫描当前运行的进程。
+// tasklist 在系统高负载或被杀毒软件拦截时偶尔会失败，失败后按指数退避重试，最多尝试 scanAttempts 次。
+//
+// 会话说明：不带 /FI 的 tasklist 会列出所有会话的进程，因此孩子通过“切换用户”离开后，
+// 仍在其（已断开的）会话中运行的游戏照常被扫描和计时，不只限于守护进程自己的会话。
+// 以普通用户身份运行时，其他用户进程的所有者显示为 N/A，taskkill 也会因权限不足失败；
+// 需要跨会话执行限制时，应以管理员或 SYSTEM 身份运行（如会话 0 中的服务或最高权限计划任务）。
 func (s *Scanner) ScanProcesses() ([]ProcessInfo, error) {
-	if runtime.GOOS != "windows" {
-		return nil, fmt.Errorf("当前只支持 Windows 平台")
+	// 使用 tasklist 命令获取进程列表，需要所有者时使用 /v 输出详细列
+	args := []string{"/fo", "csv", "/nh"}
+	if s.queryOwners || len(s.exemptUsers) > 0 {
+		args = append(args, "/v")
 	}
 
-	// 使用 tasklist 命令获取进程列表
-	cmd := exec.Command("tasklist", "/fo", "csv", "/nh")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("执行 tasklist 命令失败: %w", err)
+	start := time.Now()
+	defer func() { s.lastScanDuration = time.Since(start) }()
+
+	delay := s.retryDelay
+	var err error
+	for attempt := 1; attempt <= scanAttempts; attempt++ {
+		var output []byte
+		output, err = s.runner.Run("tasklist", args...)
+		if err == nil {
+			return parseTasklistOutput(string(output)), nil
+		}
+		if errors.Is(err, sysexec.ErrUnsupportedPlatform) {
+			return nil, err
+		}
+		if attempt < scanAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
 	}
 
-	// 解析输出
-	lines := strings.Split(string(output), "\n")
+	return nil, fmt.Errorf("执行 tasklist 命令失败（已尝试 %d 次）: %w", scanAttempts, err)
+}
+
+// LastScanDuration 返回最近一次 ScanProcesses 的耗时（含失败重试的等待），尚未扫描时为 0
+func (s *Scanner) LastScanDuration() time.Duration {
+	return s.lastScanDuration
+}
+
+// parseTasklistOutput 解析 tasklist CSV 输出。
+// 第 3、4 列为会话名与会话编号（已断开的会话名为空），详细模式（/v）下第 7 列为所属账户，不可用时为 "N/A"。
+func parseTasklistOutput(output string) []ProcessInfo {
+	lines := strings.Split(output, "\n")
 	processes := make([]ProcessInfo, 0)
 
 	for _, line := range lines {
@@ -65,14 +186,23 @@ func (s *Scanner) ScanProcesses() ([]ProcessInfo, error) {
 			continue
 		}
 
-		processes = append(processes, ProcessInfo{
-			PID:       pid,
-			Name:      name,
-			StartTime: time.Now(), // 这里简化处理，实际可以从进程创建时间获取
-		})
+		// StartTime 由 FindGameProcesses 按需从系统读取，全量扫描时不填充
+		info := ProcessInfo{
+			PID:  pid,
+			Name: name,
+		}
+		if len(fields) >= 4 {
+			fmt.Sscanf(strings.TrimSpace(fields[3]), "%d", &info.SessionID)
+		}
+		if len(fields) >= 7 {
+			if owner := strings.TrimSpace(fields[6]); owner != "N/A" {
+				info.Owner = owner
+			}
+		}
+		processes = append(processes, info)
 	}
 
-	return processes, nil
+	return processes
 }
 
 // parseCSVLine 解析 CSV 行（处理带引号的字段）
@@ -104,43 +234,119 @@ func parseCSVLine(line string) []string {
 	return fields
 }
 
-// FindGameProcesses 查找游戏进程
+// GetNewProcesses 返回相对上次记录新出现的进程
+func (s *Scanner) GetNewProcesses(current []ProcessInfo) []ProcessInfo {
+	started, _ := diffProcesses(s.lastProcesses, toProcessMap(current))
+	return started
+}
+
+// GetStoppedProcesses 返回上次记录中已不在 current 里的进程
+func (s *Scanner) GetStoppedProcesses(current []ProcessInfo) []ProcessInfo {
+	_, stopped := diffProcesses(s.lastProcesses, toProcessMap(current))
+	return stopped
+}
+
+// UpdateLastProcesses 记录本次扫描结果，作为下次比较的基准
+func (s *Scanner) UpdateLastProcesses(current []ProcessInfo) {
+	s.lastProcesses = toProcessMap(current)
+}
+
+// SetExemptUsers 设置豁免账户，非空时扫描会额外查询进程所有者
+func (s *Scanner) SetExemptUsers(users []string) {
+	s.exemptUsers = users
+}
+
+// SetMatchMode 设置配置的进程名与映像名的匹配方式（MatchExact 等）
+func (s *Scanner) SetMatchMode(mode string) {
+	s.matchMode = mode
+	s.matchers = nil
+}
+
+// SetQueryOwners 设置是否查询进程所有者（填充 ProcessInfo.Owner）
+func (s *Scanner) SetQueryOwners(query bool) {
+	s.queryOwners = query
+}
+
+// FindGameProcesses 查找游戏进程（跳过豁免账户的进程）。
+// 映像名先精确匹配（不区分大小写），未命中时按 SetMatchMode 设置的方式匹配，命中的配置项记录在 Match 中；
+// gameNames 中以 title: 开头的项按窗口标题匹配：只在存在这类项且有进程未按映像名命中时才枚举一次窗口，
+// 无法枚举窗口时（如非 Windows 平台）这类项不匹配任何进程。
+// 需要对同一次扫描做多组匹配时使用 Snapshot
 func (s *Scanner) FindGameProcesses(gameNames []string) ([]ProcessInfo, error) {
-	allProcesses, err := s.ScanProcesses()
+	snapshot, err := s.Snapshot()
 	if err != nil {
 		return nil, err
 	}
+	return snapshot.Find(gameNames), nil
+}
 
-	gameProcesses := make([]ProcessInfo, 0)
-	for _, proc := range allProcesses {
-		for _, gameName := range gameNames {
-			// 精确匹配（不区分大小写）
-			if strings.EqualFold(proc.Name, gameName) {
-				gameProcesses = append(gameProcesses, proc)
-				break
-			}
+// filterExemptOwners 移除属于豁免账户的进程
+func filterExemptOwners(processes []ProcessInfo, exemptUsers []string) []ProcessInfo {
+	if len(exemptUsers) == 0 {
+		return processes
+	}
+
+	filtered := make([]ProcessInfo, 0, len(processes))
+	for _, proc := range processes {
+		if !IsExemptOwner(proc.Owner, exemptUsers) {
+			filtered = append(filtered, proc)
 		}
 	}
+	return filtered
+}
 
-	return gameProcesses, nil
+// IsExemptOwner 判断进程所有者是否在豁免列表中
+func IsExemptOwner(owner string, exemptUsers []string) bool {
+	return MatchesOwner(owner, exemptUsers)
 }
 
-// TerminateProcess 终止进程
+// MatchesOwner 判断进程所有者是否在账户列表中。
+// 列表项可写完整的 "域\用户" 或仅写用户名，比较不区分大小写；所有者未知时不匹配。
+func MatchesOwner(owner string, users []string) bool {
+	if owner == "" {
+		return false
+	}
+	user := owner
+	if idx := strings.LastIndex(owner, `\`); idx >= 0 {
+		user = owner[idx+1:]
+	}
+	for _, u := range users {
+		if strings.EqualFold(u, owner) || strings.EqualFold(u, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// TerminateProcess 终止进程。taskkill /PID 不区分会话，以管理员或 SYSTEM 身份运行时可终止其他会话中的进程。
+// 拒绝终止当前进程（返回 ErrCriticalProcess）；按名称拒绝关键进程见 CheckTerminable
 func (s *Scanner) TerminateProcess(pid int) error {
-	if runtime.GOOS != "windows" {
-		return fmt.Errorf("当前只支持 Windows 平台")
+	if err := CheckTerminable(ProcessInfo{PID: pid}); err != nil {
+		return err
 	}
 
 	// 使用 taskkill 命令终止进程
-	cmd := exec.Command("taskkill", "/F", "/PID", fmt.Sprintf("%d", pid))
-	output, err := cmd.CombinedOutput()
+	output, err := s.runner.Run("taskkill", "/F", "/PID", fmt.Sprintf("%d", pid))
 	if err != nil {
+		if isAccessDenied(err, output) {
+			return fmt.Errorf("终止进程失败 (PID: %d): %w: %w, 输出: %s", pid, ErrAccessDenied, err, string(output))
+		}
 		return fmt.Errorf("终止进程失败 (PID: %d): %w, 输出: %s", pid, err, string(output))
 	}
 
 	return nil
 }
 
+// isAccessDenied 判断 taskkill 失败是否因为权限不足（未以管理员身份运行，或目标是受保护进程）。
+// taskkill 的错误输出随系统语言变化，中英文都要识别
+func isAccessDenied(err error, output []byte) bool {
+	if errors.Is(err, os.ErrPermission) {
+		return true
+	}
+	text := strings.ToLower(string(output))
+	return strings.Contains(text, "access is denied") || strings.Contains(text, "拒绝访问")
+}
+
 // CheckProcessRunning 检查指定 PID 的进程是否正在运行
 func (s *Scanner) CheckProcessRunning(pid int) (bool, error) {
 	processes, err := s.ScanProcesses()
@@ -162,6 +368,10 @@ func (s *Scanner) TerminateWithRetry(pid int, maxRetries int, retryDelay time.Du
 	var lastErr error
 	for i := 0; i < maxRetries; i++ {
 		err := s.TerminateProcess(pid)
+		if errors.Is(err, ErrCriticalProcess) || errors.Is(err, ErrAccessDenied) {
+			// 重试不会改变权限，直接返回
+			return err
+		}
 		if err == nil {
 			// 验证进程是否真正终止
 			time.Sleep(100 * time.Millisecond)
@@ -175,3 +385,10 @@ func (s *Scanner) TerminateWithRetry(pid int, maxRetries int, retryDelay time.Du
 	}
 	return fmt.Errorf("进程终止失败 (PID: %d)，已重试 %d 次: %w", pid, maxRetries, lastErr)
 }
+
+// RunWithRetry 带重试的进程终止
+//
+// Deprecated: 使用 TerminateWithRetry。
+func (s *Scanner) RunWithRetry(pid int, maxRetries int, retryDelay time.Duration) error {
+	return s.TerminateWithRetry(pid, maxRetries, retryDelay)
+}
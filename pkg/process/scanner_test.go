@@ -1,10 +1,13 @@
 package process
 
 import (
+	"errors"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
 )
 
 func TestNewScanner(t *testing.T) {
@@ -180,3 +183,403 @@ func TestFindGameProcesses_CaseInsensitive(t *testing.T) {
 	// 不要求一定找到，因为cmd.exe可能不在运行
 	_ = found
 }
+
+func TestParseTasklistOutput_Verbose(t *testing.T) {
+	output := `"game.exe","1234","Console","1","120,000 K","Running","PC\kid","0:01:02","Game Window"` + "\r\n" +
+		`"System","4","Services","0","100 K","Unknown","N/A","0:10:00","N/A"` + "\r\n" +
+		`"short.exe","77","Console","1","1 K"` + "\r\n"
+
+	processes := parseTasklistOutput(output)
+	if len(processes) != 3 {
+		t.Fatalf("预期解析出3个进程，实际 %d", len(processes))
+	}
+	if processes[0].Owner != `PC\kid` {
+		t.Errorf("game.exe 所有者应为 PC\\kid，实际为 %q", processes[0].Owner)
+	}
+	if processes[1].Owner != "" {
+		t.Errorf("N/A 所有者应解析为空，实际为 %q", processes[1].Owner)
+	}
+	if processes[2].Owner != "" || processes[2].PID != 77 {
+		t.Errorf("非详细模式行应无所有者，实际为 %+v", processes[2])
+	}
+}
+
+func TestFilterExemptOwners(t *testing.T) {
+	processes := []ProcessInfo{
+		{PID: 1, Name: "game.exe", Owner: `PC\Parent`},
+		{PID: 2, Name: "game.exe", Owner: `PC\kid`},
+		{PID: 3, Name: "game.exe", Owner: ""},
+		{PID: 4, Name: "game.exe", Owner: `OTHER\admin`},
+	}
+
+	filtered := filterExemptOwners(processes, []string{"parent", `other\ADMIN`})
+	if len(filtered) != 2 {
+		t.Fatalf("预期保留2个进程，实际 %d", len(filtered))
+	}
+	if filtered[0].PID != 2 || filtered[1].PID != 3 {
+		t.Errorf("过滤结果不正确: %+v", filtered)
+	}
+
+	if got := filterExemptOwners(processes, nil); len(got) != len(processes) {
+		t.Errorf("无豁免账户时不应过滤，实际剩余 %d", len(got))
+	}
+}
+
+func TestDiffProcesses_PIDReuse(t *testing.T) {
+	t1 := time.Date(2026, 2, 12, 8, 0, 0, 0, time.UTC)
+	t2 := t1.Add(3 * time.Second)
+
+	oldGame := ProcessInfo{PID: 100, Name: "game.exe", StartTime: t1}
+	reused := ProcessInfo{PID: 100, Name: "notepad.exe", StartTime: t2}
+	steady := ProcessInfo{PID: 200, Name: "game.exe", StartTime: t1}
+
+	prev := map[ProcessKey]ProcessInfo{oldGame.Key(): oldGame, steady.Key(): steady}
+	curr := map[ProcessKey]ProcessInfo{reused.Key(): reused, steady.Key(): steady}
+
+	started, stopped := diffProcesses(prev, curr)
+	if len(started) != 1 || started[0].StartTime != t2 {
+		t.Fatalf("复用 PID 的新进程应视为新启动，实际 started=%+v", started)
+	}
+	if len(stopped) != 1 || stopped[0].StartTime != t1 {
+		t.Fatalf("被复用 PID 的旧进程应视为已停止，实际 stopped=%+v", stopped)
+	}
+}
+
+func TestProcessKey_UnknownStartTime(t *testing.T) {
+	a := ProcessInfo{PID: 100, Name: "game.exe"}
+	b := ProcessInfo{PID: 100, Name: "game.exe"}
+	if a.Key() != b.Key() {
+		t.Fatal("创建时间未知时应只按 PID 识别进程")
+	}
+}
+
+func TestScannerProcessDiff(t *testing.T) {
+	scanner := NewScanner()
+	start := time.Date(2026, 2, 12, 8, 0, 0, 0, time.UTC)
+
+	first := []ProcessInfo{
+		{PID: 1, Name: "a.exe", StartTime: start},
+		{PID: 2, Name: "b.exe", StartTime: start},
+	}
+
+	newProcs := scanner.GetNewProcesses(first)
+	if len(newProcs) != 2 || newProcs[0].PID != 1 || newProcs[1].PID != 2 {
+		t.Fatalf("首次扫描时所有进程都应为新进程，实际 %+v", newProcs)
+	}
+	if stopped := scanner.GetStoppedProcesses(first); len(stopped) != 0 {
+		t.Fatalf("首次扫描时不应有已停止进程，实际 %+v", stopped)
+	}
+	scanner.UpdateLastProcesses(first)
+
+	second := []ProcessInfo{
+		{PID: 2, Name: "b.exe", StartTime: start},
+		{PID: 3, Name: "c.exe", StartTime: start.Add(time.Minute)},
+	}
+
+	newProcs = scanner.GetNewProcesses(second)
+	if len(newProcs) != 1 || newProcs[0].PID != 3 {
+		t.Errorf("应只检测到新进程 PID 3，实际 %+v", newProcs)
+	}
+	stopped := scanner.GetStoppedProcesses(second)
+	if len(stopped) != 1 || stopped[0].PID != 1 {
+		t.Errorf("应只检测到已停止进程 PID 1，实际 %+v", stopped)
+	}
+
+	scanner.UpdateLastProcesses(second)
+	if newProcs := scanner.GetNewProcesses(second); len(newProcs) != 0 {
+		t.Errorf("更新基准后相同扫描不应有新进程，实际 %+v", newProcs)
+	}
+}
+
+// failingRunner 前 len(errs) 次调用依次返回 errs 中的错误，之后返回 output
+func failingRunner(output string, errs ...error) *sysexec.FakeRunner {
+	fake := &sysexec.FakeRunner{}
+	fake.Handler = func(name string, args ...string) ([]byte, error) {
+		if n := len(fake.Calls); n <= len(errs) {
+			return nil, errs[n-1]
+		}
+		return []byte(output), nil
+	}
+	return fake
+}
+
+func TestScanProcesses_RecordsDuration(t *testing.T) {
+	fake := &sysexec.FakeRunner{Handler: func(name string, args ...string) ([]byte, error) {
+		time.Sleep(20 * time.Millisecond)
+		return []byte(`"game.exe","1234","Console","1","120,000 K"` + "\r\n"), nil
+	}}
+	scanner := NewScannerWithRunner(fake)
+	if scanner.LastScanDuration() != 0 {
+		t.Errorf("尚未扫描时耗时应为 0，实际 %v", scanner.LastScanDuration())
+	}
+
+	if _, err := scanner.ScanProcesses(); err != nil {
+		t.Fatalf("扫描失败: %v", err)
+	}
+	if took := scanner.LastScanDuration(); took < 20*time.Millisecond {
+		t.Errorf("应记录扫描耗时，实际 %v", took)
+	}
+}
+
+func TestScanProcesses_RetriesTransientFailure(t *testing.T) {
+	fake := failingRunner(`"game.exe","1234","Console","1","120,000 K"`+"\r\n",
+		errors.New("拒绝访问"), errors.New("超时"))
+	scanner := NewScannerWithRunner(fake)
+	scanner.retryDelay = time.Millisecond
+
+	processes, err := scanner.ScanProcesses()
+	if err != nil {
+		t.Fatalf("重试后应扫描成功，实际错误: %v", err)
+	}
+	if len(fake.Calls) != 3 {
+		t.Errorf("应调用 tasklist 3 次，实际 %d 次", len(fake.Calls))
+	}
+	if len(processes) != 1 || processes[0].PID != 1234 {
+		t.Errorf("解析结果不正确: %+v", processes)
+	}
+}
+
+func TestScanProcesses_GivesUpAfterMaxAttempts(t *testing.T) {
+	cause := errors.New("拒绝访问")
+	fake := failingRunner("", cause, cause, cause, cause)
+	scanner := NewScannerWithRunner(fake)
+	scanner.retryDelay = time.Millisecond
+
+	_, err := scanner.ScanProcesses()
+	if !errors.Is(err, cause) {
+		t.Fatalf("应返回最后一次失败原因，实际 %v", err)
+	}
+	if len(fake.Calls) != scanAttempts {
+		t.Errorf("应尝试 %d 次，实际 %d 次", scanAttempts, len(fake.Calls))
+	}
+}
+
+func TestScanProcesses_UnsupportedPlatformNotRetried(t *testing.T) {
+	fake := failingRunner("", sysexec.ErrUnsupportedPlatform)
+	scanner := NewScannerWithRunner(fake)
+
+	if _, err := scanner.ScanProcesses(); !errors.Is(err, sysexec.ErrUnsupportedPlatform) {
+		t.Fatalf("应返回平台不支持错误，实际 %v", err)
+	}
+	if len(fake.Calls) != 1 {
+		t.Errorf("平台不支持时不应重试，实际调用 %d 次", len(fake.Calls))
+	}
+}
+
+func TestScanProcesses_TasklistArgs(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	scanner := NewScannerWithRunner(fake)
+
+	if _, err := scanner.ScanProcesses(); err != nil {
+		t.Fatalf("ScanProcesses 失败: %v", err)
+	}
+	scanner.SetExemptUsers([]string{"parent"})
+	if _, err := scanner.ScanProcesses(); err != nil {
+		t.Fatalf("ScanProcesses 失败: %v", err)
+	}
+
+	if got := strings.Join(fake.Calls[0], " "); got != "tasklist /fo csv /nh" {
+		t.Errorf("默认 tasklist 参数不正确: %s", got)
+	}
+	if got := strings.Join(fake.Calls[1], " "); got != "tasklist /fo csv /nh /v" {
+		t.Errorf("配置豁免账户时应使用 /v，实际: %s", got)
+	}
+}
+
+func TestFindGameProcesses_FakeTasklist(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte(`"System","4","Services","0","100 K"` + "\r\n" +
+				`"Game.EXE","1234","Console","1","120,000 K"` + "\r\n" +
+				`"notepad.exe","88","Console","1","5,000 K"` + "\r\n"), nil
+		},
+	}
+	scanner := NewScannerWithRunner(fake)
+
+	processes, err := scanner.FindGameProcesses([]string{"game.exe"})
+	if err != nil {
+		t.Fatalf("FindGameProcesses 失败: %v", err)
+	}
+	if len(processes) != 1 || processes[0].PID != 1234 {
+		t.Fatalf("应只匹配到 PID 1234，实际 %+v", processes)
+	}
+}
+
+func TestTerminateProcess_TaskkillArgs(t *testing.T) {
+	fake := &sysexec.FakeRunner{}
+	scanner := NewScannerWithRunner(fake)
+
+	if err := scanner.TerminateProcess(4321); err != nil {
+		t.Fatalf("TerminateProcess 失败: %v", err)
+	}
+	if got := strings.Join(fake.Calls[0], " "); got != "taskkill /F /PID 4321" {
+		t.Errorf("taskkill 参数不正确: %s", got)
+	}
+
+	fake.Handler = func(name string, args ...string) ([]byte, error) {
+		return nil, errors.New("拒绝访问")
+	}
+	if err := scanner.TerminateProcess(4321); err == nil || !strings.Contains(err.Error(), "4321") {
+		t.Errorf("taskkill 失败时应返回包含 PID 的错误，实际 %v", err)
+	}
+}
+
+func TestTerminateWithRetry_AccessDeniedNotRetried(t *testing.T) {
+	outputs := []string{
+		"ERROR: The process with PID 4321 could not be terminated.\r\nReason: Access is denied.\r\n",
+		"错误: 无法终止 PID 为 4321 的进程。\r\n原因: 拒绝访问。\r\n",
+	}
+	for _, output := range outputs {
+		fake := &sysexec.FakeRunner{
+			Handler: func(name string, args ...string) ([]byte, error) {
+				return []byte(output), errors.New("exit status 1")
+			},
+		}
+		scanner := NewScannerWithRunner(fake)
+
+		err := scanner.TerminateWithRetry(4321, 3, time.Millisecond)
+		if !errors.Is(err, ErrAccessDenied) {
+			t.Fatalf("输出 %q 应识别为拒绝访问，实际 %v", output, err)
+		}
+		if len(fake.Calls) != 1 {
+			t.Errorf("拒绝访问时不应重试，实际执行 %d 次", len(fake.Calls))
+		}
+	}
+
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte("ERROR: The process \"4321\" not found."), errors.New("exit status 128")
+		},
+	}
+	if err := NewScannerWithRunner(fake).TerminateProcess(4321); errors.Is(err, ErrAccessDenied) {
+		t.Errorf("其他失败不应识别为拒绝访问，实际 %v", err)
+	}
+}
+
+func TestFindGameProcesses_OtherSession(t *testing.T) {
+	// 孩子切换用户后，其会话处于断开状态（会话名为空），游戏仍在会话 2 中运行
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte(`"System","4","Services","0","100 K"` + "\r\n" +
+				`"explorer.exe","300","Console","1","50,000 K"` + "\r\n" +
+				`"game.exe","1234","","2","120,000 K"` + "\r\n"), nil
+		},
+	}
+	scanner := NewScannerWithRunner(fake)
+
+	processes, err := scanner.FindGameProcesses([]string{"game.exe"})
+	if err != nil {
+		t.Fatalf("FindGameProcesses 失败: %v", err)
+	}
+	if len(processes) != 1 || processes[0].PID != 1234 || processes[0].SessionID != 2 {
+		t.Fatalf("应匹配到会话 2 中的 PID 1234，实际 %+v", processes)
+	}
+
+	if err := scanner.TerminateProcess(1234); err != nil {
+		t.Fatalf("TerminateProcess 失败: %v", err)
+	}
+	last := fake.Calls[len(fake.Calls)-1]
+	if got := strings.Join(last, " "); got != "taskkill /F /PID 1234" {
+		t.Errorf("终止其他会话的进程时不应按会话过滤，实际: %s", got)
+	}
+}
+
+func TestFindGameProcesses_TitleMatch(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte(`"steam.exe","10","Console","1","90,000 K"` + "\r\n" +
+				`"start_protected_game.exe","20","Console","1","900,000 K"` + "\r\n" +
+				`"javaw.exe","30","Console","1","500,000 K"` + "\r\n" +
+				`"javaw.exe","40","Console","1","200,000 K"` + "\r\n"), nil
+		},
+	}
+	scanner := NewScannerWithRunner(fake)
+	calls := 0
+	scanner.windowTitles = func() (map[int][]string, error) {
+		calls++
+		return map[int][]string{
+			10: {"Steam"},
+			20: {"ELDEN RING™"},
+			30: {"Minecraft 1.20.1"},
+			40: {"Eclipse IDE"},
+		}, nil
+	}
+
+	processes, err := scanner.FindGameProcesses([]string{"steam.exe", "title:Elden Ring", "Title: minecraft"})
+	if err != nil {
+		t.Fatalf("FindGameProcesses 失败: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("每次扫描最多枚举一次窗口，实际 %d 次", calls)
+	}
+	if len(processes) != 3 {
+		t.Fatalf("应匹配到 3 个进程，实际 %+v", processes)
+	}
+	if processes[0].PID != 10 || processes[0].Match != "" {
+		t.Errorf("按映像名匹配的进程不应记录标题匹配项，实际 %+v", processes[0])
+	}
+	if processes[1].PID != 20 || processes[1].Match != "title:Elden Ring" {
+		t.Errorf("应按窗口标题匹配 Elden Ring（不区分大小写），实际 %+v", processes[1])
+	}
+	if processes[2].PID != 30 || !processes[2].Matches("Title: minecraft") {
+		t.Errorf("应按窗口标题匹配 minecraft，实际 %+v", processes[2])
+	}
+}
+
+func TestFindGameProcesses_NoTitlePatternsSkipsEnumeration(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte(`"Game.exe","1234","Console","1","120,000 K"` + "\r\n"), nil
+		},
+	}
+	scanner := NewScannerWithRunner(fake)
+	scanner.windowTitles = func() (map[int][]string, error) {
+		t.Fatal("未配置 title: 项时不应枚举窗口")
+		return nil, nil
+	}
+
+	if _, err := scanner.FindGameProcesses([]string{"game.exe"}); err != nil {
+		t.Fatalf("FindGameProcesses 失败: %v", err)
+	}
+}
+
+func TestFindGameProcesses_TitleEnumerationFails(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte(`"Game.exe","1234","Console","1","120,000 K"` + "\r\n" +
+				`"javaw.exe","30","Console","1","500,000 K"` + "\r\n"), nil
+		},
+	}
+	scanner := NewScannerWithRunner(fake)
+	scanner.windowTitles = func() (map[int][]string, error) {
+		return nil, errors.New("不支持")
+	}
+
+	processes, err := scanner.FindGameProcesses([]string{"game.exe", "title:Minecraft"})
+	if err != nil {
+		t.Fatalf("枚举窗口失败不应导致扫描失败: %v", err)
+	}
+	if len(processes) != 1 || processes[0].PID != 1234 {
+		t.Errorf("无法枚举窗口时应只按映像名匹配，实际 %+v", processes)
+	}
+}
+
+func TestTitlePattern(t *testing.T) {
+	tests := []struct {
+		input   string
+		pattern string
+		ok      bool
+	}{
+		{input: "title:Elden Ring", pattern: "Elden Ring", ok: true},
+		{input: "  TITLE:  Minecraft ", pattern: "Minecraft", ok: true},
+		{input: "title:", pattern: "", ok: true},
+		{input: "game.exe", ok: false},
+		{input: "tit", ok: false},
+	}
+	for _, tt := range tests {
+		pattern, ok := TitlePattern(tt.input)
+		if pattern != tt.pattern || ok != tt.ok {
+			t.Errorf("TitlePattern(%q) = %q, %v，预期 %q, %v", tt.input, pattern, ok, tt.pattern, tt.ok)
+		}
+	}
+}
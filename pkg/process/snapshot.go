@@ -0,0 +1,111 @@
+package process
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// ProcessSnapshot 一次进程枚举（tasklist）的结果。每个检查周期只枚举一次进程，
+// 游戏、禁止运行的进程与奖励应用等各项匹配都在同一份快照上进行；
+// 窗口标题只在首次需要按标题匹配时枚举一次，进程创建时间在进程首次被匹配到时读取，之后的匹配直接复用
+type ProcessSnapshot struct {
+	TakenAt time.Time // 枚举进程的时间
+
+	processes []ProcessInfo // 快照中的进程（已去掉豁免账户的进程）
+	// scanner 提供匹配方式与窗口枚举；为 nil 时快照由已匹配过的进程构造，按 ProcessInfo.Matches 比较
+	scanner *Scanner
+
+	windowTitles map[int][]string
+	titlesLoaded bool
+	startTimes   map[int]time.Time
+}
+
+// NewMatchedSnapshot 由已匹配过的进程（如其他 FindGameProcesses 实现的结果）构造快照，
+// Find 按 ProcessInfo.Matches 比较，不再枚举窗口或读取创建时间
+func NewMatchedSnapshot(processes []ProcessInfo) *ProcessSnapshot {
+	return &ProcessSnapshot{TakenAt: time.Now(), processes: processes}
+}
+
+// Snapshot 枚举一次进程并返回快照，供多个匹配项共用
+func (s *Scanner) Snapshot() (*ProcessSnapshot, error) {
+	allProcesses, err := s.ScanProcesses()
+	if err != nil {
+		return nil, err
+	}
+	return &ProcessSnapshot{
+		TakenAt:   time.Now(),
+		processes: filterExemptOwners(allProcesses, s.exemptUsers),
+		scanner:   s,
+	}, nil
+}
+
+// Processes 返回快照中的全部进程
+func (p *ProcessSnapshot) Processes() []ProcessInfo {
+	return p.processes
+}
+
+// Find 返回快照中匹配 names 任一项的进程，匹配规则同 Scanner.FindGameProcesses；
+// 每次调用返回独立的结果，Match 记录的是 names 中命中的项
+func (p *ProcessSnapshot) Find(names []string) []ProcessInfo {
+	matched := make([]ProcessInfo, 0)
+	if len(names) == 0 {
+		return matched
+	}
+	if p.scanner == nil {
+		for _, proc := range p.processes {
+			if slices.ContainsFunc(names, proc.Matches) {
+				matched = append(matched, proc)
+			}
+		}
+		return matched
+	}
+
+	imageNames, titles := splitTitlePatterns(names)
+	matchMode := p.scanner.matchMode
+	for _, proc := range p.processes {
+		// 精确匹配（不区分大小写）
+		ok := slices.ContainsFunc(imageNames, func(name string) bool {
+			return strings.EqualFold(proc.Name, name)
+		})
+		if !ok && matchMode != "" && matchMode != MatchExact {
+			proc.Match = p.scanner.matchPattern(proc.Name, imageNames)
+			ok = proc.Match != ""
+		}
+		if !ok && len(titles) > 0 {
+			proc.Match = matchTitle(p.titles()[proc.PID], titles)
+			ok = proc.Match != ""
+		}
+		if !ok {
+			continue
+		}
+		proc.StartTime = p.startTime(proc.PID)
+		matched = append(matched, proc)
+	}
+	return matched
+}
+
+// titles 返回各进程的窗口标题，首次调用时枚举窗口；无法枚举时（如非 Windows 平台）为空
+func (p *ProcessSnapshot) titles() map[int][]string {
+	if !p.titlesLoaded {
+		p.titlesLoaded = true
+		p.windowTitles, _ = p.scanner.windowTitles()
+	}
+	return p.windowTitles
+}
+
+// startTime 返回进程的创建时间，首次查询时从系统读取；未知时为零值
+func (p *ProcessSnapshot) startTime(pid int) time.Time {
+	if started, ok := p.startTimes[pid]; ok {
+		return started
+	}
+	started, err := processStartTime(pid)
+	if err != nil {
+		started = time.Time{}
+	}
+	if p.startTimes == nil {
+		p.startTimes = make(map[int]time.Time)
+	}
+	p.startTimes[pid] = started
+	return started
+}
@@ -0,0 +1,85 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+func TestSnapshot_SharedAcrossMatchers(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte(`"Game.exe","10","Console","1","90,000 K"` + "\r\n" +
+				`"RunAsDate.exe","20","Console","1","5,000 K"` + "\r\n" +
+				`"javaw.exe","30","Console","1","500,000 K"` + "\r\n" +
+				`"Anki.exe","40","Console","1","50,000 K"` + "\r\n"), nil
+		},
+	}
+	scanner := NewScannerWithRunner(fake)
+	titleCalls := 0
+	scanner.windowTitles = func() (map[int][]string, error) {
+		titleCalls++
+		return map[int][]string{30: {"Minecraft 1.20.1"}}, nil
+	}
+
+	snapshot, err := scanner.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot 失败: %v", err)
+	}
+	games := snapshot.Find([]string{"game.exe", "title:Minecraft"})
+	prohibited := snapshot.Find([]string{"runasdate.exe", "title:Cheat Engine"})
+	earn := snapshot.Find([]string{"anki.exe"})
+
+	if len(fake.Calls) != 1 {
+		t.Errorf("多组匹配应共用一次 tasklist，实际调用 %d 次", len(fake.Calls))
+	}
+	if titleCalls != 1 {
+		t.Errorf("多组标题匹配应只枚举一次窗口，实际 %d 次", titleCalls)
+	}
+	if len(games) != 2 || games[0].PID != 10 || games[1].PID != 30 || games[1].Match != "title:Minecraft" {
+		t.Errorf("应匹配到 Game.exe 与按标题匹配的 javaw.exe，实际 %+v", games)
+	}
+	if len(prohibited) != 1 || prohibited[0].PID != 20 {
+		t.Errorf("应匹配到禁止运行的 RunAsDate.exe，实际 %+v", prohibited)
+	}
+	if len(earn) != 1 || earn[0].PID != 40 {
+		t.Errorf("应匹配到奖励应用 Anki.exe，实际 %+v", earn)
+	}
+	if len(snapshot.Processes()) != 4 {
+		t.Errorf("快照应包含全部 4 个进程，实际 %d", len(snapshot.Processes()))
+	}
+}
+
+func TestSnapshot_ExcludesExemptOwners(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte(`"Game.exe","10","Console","1","90,000 K","Running","PC\parent","0:00:01","N/A"` + "\r\n" +
+				`"Game.exe","20","Console","1","90,000 K","Running","PC\kid","0:00:01","N/A"` + "\r\n"), nil
+		},
+	}
+	scanner := NewScannerWithRunner(fake)
+	scanner.SetExemptUsers([]string{"parent"})
+
+	snapshot, err := scanner.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot 失败: %v", err)
+	}
+	if games := snapshot.Find([]string{"game.exe"}); len(games) != 1 || games[0].PID != 20 {
+		t.Errorf("豁免账户的进程不应出现在快照中，实际 %+v", games)
+	}
+}
+
+func TestNewMatchedSnapshot(t *testing.T) {
+	snapshot := NewMatchedSnapshot([]ProcessInfo{
+		{PID: 1, Name: "game.exe"},
+		{PID: 2, Name: "javaw.exe", Match: "title:Minecraft"},
+		{PID: 3, Name: "anki.exe"},
+	})
+
+	if games := snapshot.Find([]string{"GAME.exe", "title:minecraft"}); len(games) != 2 {
+		t.Errorf("已匹配的快照应按映像名与 Match 比较，实际 %+v", games)
+	}
+	if none := snapshot.Find(nil); len(none) != 0 {
+		t.Errorf("没有匹配项时不应返回进程，实际 %+v", none)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"time"
+)
+
+// processStartTime 非 Windows 平台不支持读取进程创建时间
+func processStartTime(pid int) (time.Time, error) {
+	return time.Time{}, fmt.Errorf("当前只支持 Windows 平台")
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+)
+
+// processQueryLimitedInformation PROCESS_QUERY_LIMITED_INFORMATION 访问权限
+const processQueryLimitedInformation = 0x1000
+
+// processStartTime 读取进程创建时间
+func processStartTime(pid int) (time.Time, error) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("打开进程失败 (PID: %d): %w", pid, err)
+	}
+	defer syscall.CloseHandle(h)
+
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return time.Time{}, fmt.Errorf("读取进程时间失败 (PID: %d): %w", pid, err)
+	}
+	return time.Unix(0, creation.Nanoseconds()), nil
+}
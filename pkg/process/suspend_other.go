@@ -0,0 +1,15 @@
+//go:build !windows
+
+package process
+
+import "github.com/yourusername/game-control/pkg/sysexec"
+
+// SuspendProcess 非 Windows 平台不支持
+func SuspendProcess(pid int) error {
+	return sysexec.ErrUnsupportedPlatform
+}
+
+// ResumeProcess 非 Windows 平台不支持
+func ResumeProcess(pid int) error {
+	return sysexec.ErrUnsupportedPlatform
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// processSuspendResume PROCESS_SUSPEND_RESUME 访问权限
+const processSuspendResume = 0x0800
+
+var (
+	ntdll                = syscall.NewLazyDLL("ntdll.dll")
+	procNtSuspendProcess = ntdll.NewProc("NtSuspendProcess")
+	procNtResumeProcess  = ntdll.NewProc("NtResumeProcess")
+)
+
+// SuspendProcess 挂起进程的所有线程（游戏画面冻结但不丢失进度），可用 ResumeProcess 恢复
+func SuspendProcess(pid int) error {
+	return callWithProcess(pid, procNtSuspendProcess, "挂起")
+}
+
+// ResumeProcess 恢复被 SuspendProcess 挂起的进程
+func ResumeProcess(pid int) error {
+	return callWithProcess(pid, procNtResumeProcess, "恢复")
+}
+
+func callWithProcess(pid int, proc *syscall.LazyProc, action string) error {
+	h, err := syscall.OpenProcess(processSuspendResume, false, uint32(pid))
+	if err != nil {
+		return fmt.Errorf("打开进程失败 (PID: %d): %w", pid, err)
+	}
+	defer syscall.CloseHandle(h)
+
+	// NTSTATUS 为 0 表示成功
+	if status, _, _ := proc.Call(uintptr(h)); status != 0 {
+		return fmt.Errorf("%s进程失败 (PID: %d): NTSTATUS 0x%08X", action, pid, uint32(status))
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package process
+
+import "strings"
+
+// TitlePrefix 游戏名以该前缀开头时按窗口标题匹配进程（如 "title:Elden Ring"），
+// 用于通过 Steam/Epic 启动、进程名是通用宿主（如 javaw.exe、UnityPlayer）的游戏
+const TitlePrefix = "title:"
+
+// TitlePattern 判断 name 是否为窗口标题匹配项，是则返回去掉前缀与首尾空白的标题片段（前缀不区分大小写）
+func TitlePattern(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if len(name) < len(TitlePrefix) || !strings.EqualFold(name[:len(TitlePrefix)], TitlePrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(name[len(TitlePrefix):]), true
+}
+
+// Matches 判断进程是否匹配配置中的游戏名：按映像名精确匹配，或是按窗口标题、匹配方式命中的该项（均不区分大小写）
+func (p ProcessInfo) Matches(name string) bool {
+	return strings.EqualFold(p.Name, name) || (p.Match != "" && strings.EqualFold(p.Match, name))
+}
+
+// splitTitlePatterns 将游戏名列表拆分为映像名与窗口标题匹配项
+func splitTitlePatterns(gameNames []string) (names, titles []string) {
+	for _, name := range gameNames {
+		if _, ok := TitlePattern(name); ok {
+			titles = append(titles, name)
+		} else {
+			names = append(names, name)
+		}
+	}
+	return names, titles
+}
+
+// matchTitle 返回 titles 中第一个出现在窗口标题 windowTitles 里的匹配项（子串匹配，不区分大小写），没有时返回空串
+func matchTitle(windowTitles []string, titles []string) string {
+	for _, entry := range titles {
+		pattern, _ := TitlePattern(entry)
+		if pattern == "" {
+			continue
+		}
+		for _, title := range windowTitles {
+			if strings.Contains(strings.ToLower(title), strings.ToLower(pattern)) {
+				return entry
+			}
+		}
+	}
+	return ""
+}
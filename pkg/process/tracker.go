@@ -0,0 +1,152 @@
+package process
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/clock"
+)
+
+// Session 一次游戏进程会话
+type Session struct {
+	PID       int       `json:"pid"`
+	Name      string    `json:"name"`
+	StartTime time.Time `json:"startTime"` // 进程创建时间，未知时为零值
+	FirstSeen time.Time `json:"firstSeen"` // 首次扫描到的时间
+	LastSeen  time.Time `json:"lastSeen"`  // 最近一次扫描到的时间
+
+	// missed 最近一次扫描到之后连续缺失的扫描次数
+	missed int
+}
+
+// Key 返回会话对应的进程实例标识
+func (s Session) Key() ProcessKey {
+	return ProcessInfo{PID: s.PID, StartTime: s.StartTime}.Key()
+}
+
+// Duration 返回会话从首次到最近一次被扫描到的时长
+func (s Session) Duration() time.Duration {
+	return s.LastSeen.Sub(s.FirstSeen)
+}
+
+// ProcessTracker 跟踪游戏进程会话的开始与结束
+type ProcessTracker struct {
+	sessions map[ProcessKey]*Session
+	// stopDebounce 进程连续缺失超过该扫描次数才结束会话，用于容忍加载画面等导致的短暂消失
+	stopDebounce int
+	// clock 扫描时间的来源，会话的首次与最近一次扫描到的时间取自该时钟
+	clock clock.Clock
+}
+
+// NewProcessTracker 创建进程会话跟踪器
+func NewProcessTracker() *ProcessTracker {
+	return &ProcessTracker{
+		sessions: make(map[ProcessKey]*Session),
+		clock:    clock.Real{},
+	}
+}
+
+// SetStopDebounce 设置会话结束防抖：进程连续缺失超过 scans 次扫描才视为结束，0 表示缺失即结束。
+// 防抖期间会话仍视为活跃，重新出现时继续原会话，结束时的时长截止到最后一次扫描到的时间
+func (t *ProcessTracker) SetStopDebounce(scans int) {
+	t.stopDebounce = scans
+}
+
+// SetClock 替换时钟（默认使用系统时间），用于测试
+func (t *ProcessTracker) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+// Update 用本次扫描结果更新会话（扫描时间取当前时钟），返回新开始与已结束的会话
+func (t *ProcessTracker) Update(current []ProcessInfo) (started, stopped []Session) {
+	now := t.clock.Now()
+	seen := make(map[ProcessKey]bool, len(current))
+	for _, proc := range current {
+		key := proc.Key()
+		if session, ok := t.lookup(proc); ok {
+			seen[session.Key()] = true
+			session.LastSeen = now
+			session.missed = 0
+			continue
+		}
+		seen[key] = true
+
+		session := &Session{
+			PID:       proc.PID,
+			Name:      proc.Name,
+			StartTime: proc.StartTime,
+			FirstSeen: now,
+			LastSeen:  now,
+		}
+		t.sessions[key] = session
+		started = append(started, *session)
+	}
+
+	for key, session := range t.sessions {
+		if seen[key] {
+			continue
+		}
+		if session.missed < t.stopDebounce {
+			session.missed++
+			continue
+		}
+		stopped = append(stopped, *session)
+		delete(t.sessions, key)
+	}
+
+	sortSessions(started)
+	sortSessions(stopped)
+	return started, stopped
+}
+
+// lookup 查找进程所属的会话。
+// 创建时间偶尔读取失败（权限不足、进程正在退出）时为零值，此时按 PID 与已有会话合并，
+// 避免同一进程因键变化被记为一次结束加一次开始；之后读到创建时间时补全到会话上
+func (t *ProcessTracker) lookup(proc ProcessInfo) (*Session, bool) {
+	if session, ok := t.sessions[proc.Key()]; ok {
+		return session, true
+	}
+	for key, session := range t.sessions {
+		if session.PID != proc.PID {
+			continue
+		}
+		if proc.StartTime.IsZero() {
+			return session, true
+		}
+		if session.StartTime.IsZero() {
+			delete(t.sessions, key)
+			session.StartTime = proc.StartTime
+			t.sessions[session.Key()] = session
+			return session, true
+		}
+	}
+	return nil, false
+}
+
+// ActiveSessions 返回当前活跃会话（按 PID 排序）
+func (t *ProcessTracker) ActiveSessions() []Session {
+	sessions := make([]Session, 0, len(t.sessions))
+	for _, session := range t.sessions {
+		sessions = append(sessions, *session)
+	}
+	sortSessions(sessions)
+	return sessions
+}
+
+// Restore 恢复之前保存的会话（如守护进程重启前的活跃会话）。
+// 恢复的会话会在下一次 Update 中与实际运行的进程对账，已不存在的会话按结束处理。
+func (t *ProcessTracker) Restore(sessions []Session) {
+	for _, session := range sessions {
+		s := session
+		t.sessions[s.Key()] = &s
+	}
+}
+
+func sortSessions(sessions []Session) {
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].PID != sessions[j].PID {
+			return sessions[i].PID < sessions[j].PID
+		}
+		return sessions[i].StartTime.Before(sessions[j].StartTime)
+	})
+}
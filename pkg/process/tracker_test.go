@@ -0,0 +1,167 @@
+package process
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/clock"
+)
+
+// newTestTracker 返回使用手动时钟的跟踪器，时钟停在 base
+func newTestTracker(base time.Time) (*ProcessTracker, *clock.ManualClock) {
+	clk := clock.NewManual(base)
+	tracker := NewProcessTracker()
+	tracker.SetClock(clk)
+	return tracker, clk
+}
+
+func TestProcessTracker_StartAndStop(t *testing.T) {
+	base := time.Date(2026, 2, 12, 8, 0, 0, 0, time.UTC)
+	tracker, clk := newTestTracker(base)
+	game := ProcessInfo{PID: 100, Name: "game.exe", StartTime: base.Add(-time.Minute)}
+
+	started, stopped := tracker.Update([]ProcessInfo{game})
+	if len(started) != 1 || len(stopped) != 0 {
+		t.Fatalf("首次出现应开始会话，started=%v stopped=%v", started, stopped)
+	}
+
+	clk.Advance(5 * time.Second)
+	started, stopped = tracker.Update([]ProcessInfo{game})
+	if len(started) != 0 || len(stopped) != 0 {
+		t.Fatalf("持续运行不应产生开始/结束，started=%v stopped=%v", started, stopped)
+	}
+
+	clk.Advance(5 * time.Second)
+	_, stopped = tracker.Update(nil)
+	if len(stopped) != 1 {
+		t.Fatalf("进程消失应结束会话，实际 %v", stopped)
+	}
+	if stopped[0].Duration() != 5*time.Second {
+		t.Errorf("会话时长应为首次到最后一次扫描到的间隔 5s，实际 %v", stopped[0].Duration())
+	}
+	if len(tracker.ActiveSessions()) != 0 {
+		t.Error("会话结束后不应再处于活跃状态")
+	}
+}
+
+func TestProcessTracker_RestoreReconciles(t *testing.T) {
+	base := time.Date(2026, 2, 12, 8, 0, 0, 0, time.UTC)
+	stillRunning := Session{PID: 100, Name: "game.exe", StartTime: base, FirstSeen: base, LastSeen: base.Add(time.Minute)}
+	gone := Session{PID: 200, Name: "game.exe", StartTime: base, FirstSeen: base, LastSeen: base.Add(time.Minute)}
+	// PID 200 已被另一个进程复用
+	reused := ProcessInfo{PID: 200, Name: "game.exe", StartTime: base.Add(time.Hour)}
+
+	tracker, _ := newTestTracker(base.Add(2 * time.Hour))
+	tracker.Restore([]Session{stillRunning, gone})
+
+	started, stopped := tracker.Update([]ProcessInfo{
+		{PID: 100, Name: "game.exe", StartTime: base},
+		reused,
+	})
+
+	if len(stopped) != 1 || stopped[0].PID != 200 || !stopped[0].StartTime.Equal(base) {
+		t.Fatalf("已不存在的恢复会话应结束，实际 %v", stopped)
+	}
+	if len(started) != 1 || !started[0].StartTime.Equal(reused.StartTime) {
+		t.Fatalf("复用 PID 的新进程应开始新会话，实际 %v", started)
+	}
+
+	active := tracker.ActiveSessions()
+	if len(active) != 2 || !active[0].FirstSeen.Equal(base) {
+		t.Fatalf("仍在运行的恢复会话应保留原始首次检测时间，实际 %v", active)
+	}
+}
+
+func TestProcessTracker_RestoreFromStateJSON(t *testing.T) {
+	// 扫描得到的创建时间由 time.Unix 构造（本地时区），状态文件中的会话经 JSON 解码后时区可能不同
+	live := []time.Time{
+		time.Unix(1770883200, 123456789),
+		time.Unix(1770883200, 123456789).In(time.FixedZone("UTC+8", 8*3600)),
+	}
+	for _, start := range live {
+		game := ProcessInfo{PID: 100, Name: "game.exe", StartTime: start}
+
+		first, _ := newTestTracker(start.Add(time.Minute))
+		first.Update([]ProcessInfo{game})
+		data, err := json.Marshal(first.ActiveSessions())
+		if err != nil {
+			t.Fatalf("序列化会话失败: %v", err)
+		}
+		var saved []Session
+		if err := json.Unmarshal(data, &saved); err != nil {
+			t.Fatalf("解析会话失败: %v", err)
+		}
+
+		tracker, _ := newTestTracker(start.Add(time.Hour))
+		tracker.Restore(saved)
+		started, stopped := tracker.Update([]ProcessInfo{game})
+		if len(started) != 0 || len(stopped) != 0 {
+			t.Fatalf("恢复的会话应与仍在运行的进程匹配（%v），started=%v stopped=%v", start.Location(), started, stopped)
+		}
+		if active := tracker.ActiveSessions(); len(active) != 1 || !active[0].FirstSeen.Equal(start.Add(time.Minute)) {
+			t.Errorf("恢复的会话应保留原始首次检测时间，实际 %v", active)
+		}
+	}
+}
+
+func TestProcessTracker_MergesUnknownStartTime(t *testing.T) {
+	base := time.Date(2026, 2, 12, 8, 0, 0, 0, time.UTC)
+	tracker, clk := newTestTracker(base)
+	known := ProcessInfo{PID: 100, Name: "game.exe", StartTime: base.Add(-time.Minute)}
+	unknown := ProcessInfo{PID: 100, Name: "game.exe"}
+
+	tracker.Update([]ProcessInfo{unknown})
+	for i, proc := range []ProcessInfo{known, unknown, known} {
+		clk.Advance(5 * time.Second)
+		started, stopped := tracker.Update([]ProcessInfo{proc})
+		if len(started) != 0 || len(stopped) != 0 {
+			t.Fatalf("第 %d 次扫描：创建时间时有时无不应拆分会话，started=%v stopped=%v", i+1, started, stopped)
+		}
+	}
+
+	active := tracker.ActiveSessions()
+	if len(active) != 1 || !active[0].StartTime.Equal(known.StartTime) {
+		t.Fatalf("应只有一个补全了创建时间的会话，实际 %v", active)
+	}
+
+	clk.Set(base.Add(time.Minute))
+	_, stopped := tracker.Update(nil)
+	if len(stopped) != 1 || stopped[0].Duration() != 15*time.Second {
+		t.Errorf("会话时长应为首次到最后一次扫描到的间隔 15s，实际 %v", stopped)
+	}
+}
+
+func TestProcessTracker_StopDebounce(t *testing.T) {
+	base := time.Date(2026, 2, 12, 8, 0, 0, 0, time.UTC)
+	tracker, clk := newTestTracker(base)
+	tracker.SetStopDebounce(2)
+	game := ProcessInfo{PID: 100, Name: "game.exe", StartTime: base.Add(-time.Minute)}
+
+	scans := [][]ProcessInfo{
+		{game}, // 0s
+		nil,    // 5s 短暂消失（加载画面）
+		{game}, // 10s 重新出现，继续原会话
+		nil,    // 15s
+		nil,    // 20s
+	}
+	for i, current := range scans {
+		clk.Set(base.Add(time.Duration(i) * 5 * time.Second))
+		started, stopped := tracker.Update(current)
+		if i > 0 && (len(started) != 0 || len(stopped) != 0) {
+			t.Fatalf("第 %d 次扫描：缺失未超过防抖次数不应产生开始/结束，started=%v stopped=%v", i, started, stopped)
+		}
+	}
+	if len(tracker.ActiveSessions()) != 1 {
+		t.Fatal("防抖期间会话应仍处于活跃状态")
+	}
+
+	clk.Set(base.Add(25 * time.Second))
+	_, stopped := tracker.Update(nil)
+	if len(stopped) != 1 {
+		t.Fatalf("连续缺失超过防抖次数应结束会话，实际 %v", stopped)
+	}
+	if stopped[0].Duration() != 10*time.Second {
+		t.Errorf("会话时长应截止到最后一次扫描到的时间（10s），实际 %v", stopped[0].Duration())
+	}
+}
@@ -3,8 +3,12 @@ package quota
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/yourusername/game-control/pkg/clock"
 	"github.com/yourusername/game-control/pkg/config"
+	"github.com/yourusername/game-control/pkg/process"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,42 +17,89 @@ import (
 type QuotaState struct {
 	mu  sync.Mutex
 	cfg *config.Config
+	// clock 当前时间的来源，nil 时使用系统时间
+	clock clock.Clock
 
-	AccumulatedTime      int64 `json:"accumulatedTime"`      // 累计游戏时间（秒）
-	LastResetTime        int64 `json:"lastResetTime"`        // 上次重置时间（Unix 时间戳）
-	NextResetTime        int64 `json:"nextResetTime"`        // 下次重置时间（Unix 时间戳）
-	FirstWarningNotified bool  `json:"firstWarningNotified"` // 首次警告是否已提示
-	FinalWarningNotified bool  `json:"finalWarningNotified"` // 最后警告是否已提示
-	LimitNotified        bool  `json:"limitNotified"`        // 超限是否已提示
+	AccumulatedTime int64 `json:"accumulatedTime"` // 累计游戏时间（秒）
+	LastResetTime   int64 `json:"lastResetTime"`   // 上次重置时间（Unix 时间戳）
+	NextResetTime   int64 `json:"nextResetTime"`   // 下次重置时间（Unix 时间戳）
+	LimitNotified   bool  `json:"limitNotified"`   // 超限是否已提示
+	LimitHits       int   `json:"limitHits"`       // 当天超限被执行的次数，用于逐级缩短宽限期
+	Terminations    int   `json:"terminations"`    // 当天成功终止的游戏进程数，用于每日汇总
+
+	// WarningsNotified 当天已提示过的提醒阈值（分钟），每个阈值每天只提示一次
+	WarningsNotified []int `json:"warningsNotified,omitempty"`
+
+	// GameSeconds 当天各游戏的累计时间（秒），键为配置中的游戏名
+	GameSeconds map[string]int64 `json:"gameSeconds,omitempty"`
+
+	// Sessions 保存时仍在运行的游戏会话，守护进程重启后据此恢复会话开始时间
+	Sessions []process.Session `json:"sessions,omitempty"`
+
+	// PausedGames 当天暂停限制的游戏（规范化后的进程名），这些游戏既不计时也不会被终止
+	PausedGames []string `json:"pausedGames,omitempty"`
+
+	// ExtensionMinutes 当天家长临时延长的游戏时间（分钟），与每日限制分开记录，下次重置时失效、不累积到次日
+	ExtensionMinutes int `json:"extensionMinutes,omitempty"`
+
+	// EarnSeconds 当天各奖励应用（earn.apps）计入的运行时间（秒），据此计算奖励的游戏时间
+	EarnSeconds map[string]int64 `json:"earnSeconds,omitempty"`
+
+	// ControlNonces 当前重置周期内已执行的签名控制命令的随机数，用于拒绝重放；下次重置时清空
+	ControlNonces []string `json:"controlNonces,omitempty"`
+
+	// Machines 共享状态（state.shared）下当天各电脑累计的时间（秒），键为 state.machineId，保存时据此合并
+	Machines map[string]int64 `json:"machines,omitempty"`
 }
 
 // NewQuotaState 创建新的配额状态
 func NewQuotaState(cfg *config.Config) (*QuotaState, error) {
-	now := time.Now()
-
-	// 解析重置时间
-	resetTimeParsed, err := time.Parse("15:04", cfg.ResetTime)
-	if err != nil {
-		return nil, fmt.Errorf("无效的重置时间格式: %w", err)
-	}
+	return NewQuotaStateWithClock(cfg, clock.Real{})
+}
 
-	// 计算下次重置时间
-	nextReset := time.Date(now.Year(), now.Month(), now.Day(),
-		resetTimeParsed.Hour(), resetTimeParsed.Minute(), 0, 0, now.Location())
+// NewQuotaStateWithClock 使用指定时钟创建新的配额状态，重置时间与当天规则均按该时钟计算
+func NewQuotaStateWithClock(cfg *config.Config, c clock.Clock) (*QuotaState, error) {
+	now := c.Now()
 
-	// 如果今天的重置时间已过，则设置为明天
-	if now.After(nextReset) {
-		nextReset = nextReset.Add(24 * time.Hour)
+	nextReset, err := nextResetAfter(cfg, now)
+	if err != nil {
+		return nil, err
 	}
 
 	return &QuotaState{
 		cfg:             cfg,
+		clock:           c,
 		AccumulatedTime: 0,
 		LastResetTime:   now.Unix(),
 		NextResetTime:   nextReset.Unix(),
 	}, nil
 }
 
+// SetClock 替换时钟（如从文件加载的状态），之后的重置判断与当天规则均按该时钟计算
+func (q *QuotaState) SetClock(c clock.Clock) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.clock = c
+}
+
+// Clock 返回状态使用的时钟，未设置时为系统时间
+func (q *QuotaState) Clock() clock.Clock {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.clock == nil {
+		return clock.Real{}
+	}
+	return q.clock
+}
+
+// now 返回当前时间，调用方需持有锁
+func (q *QuotaState) now() time.Time {
+	if q.clock == nil {
+		return time.Now()
+	}
+	return q.clock.Now()
+}
+
 // GetAccumulatedMinutes 获取累计游戏时间（分钟）
 func (q *QuotaState) GetAccumulatedMinutes() int {
 	q.mu.Lock()
@@ -56,25 +107,53 @@ func (q *QuotaState) GetAccumulatedMinutes() int {
 	return int(q.AccumulatedTime / 60)
 }
 
+// GetAccumulatedSeconds 获取累计游戏时间（秒）
+func (q *QuotaState) GetAccumulatedSeconds() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.AccumulatedTime
+}
+
+// GetRemainingSeconds 获取剩余可用时间（秒）
+func (q *QuotaState) GetRemainingSeconds() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	remaining := int64(q.dailyLimit())*60 - q.AccumulatedTime
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 // GetRemainingMinutes 获取剩余可用时间（分钟）
 func (q *QuotaState) GetRemainingMinutes() int {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	accumulated := int(q.AccumulatedTime / 60)
-	remaining := q.cfg.DailyLimit - accumulated
+	remaining := q.dailyLimit() - accumulated
 	if remaining < 0 {
 		return 0
 	}
 	return remaining
 }
 
-// IsLimitExceeded 检查是否超过时间限制
+// IsLimitExceeded 检查是否超过时间限制（硬限制，超过后终止游戏）
 func (q *QuotaState) IsLimitExceeded() bool {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	return int(q.AccumulatedTime/60) >= q.cfg.DailyLimit
+	return q.AccumulatedTime >= int64(q.dailyLimit())*60
+}
+
+// IsSoftLimitExceeded 检查是否达到软限制（只提醒，不终止），未启用软限制时返回 false
+func (q *QuotaState) IsSoftLimitExceeded() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	soft := int(q.cfg.SoftLimitFor(q.now()))
+	return soft > 0 && q.AccumulatedTime >= int64(soft)*60
 }
 
 // AddTime 增加累计时间（秒）
@@ -82,6 +161,33 @@ func (q *QuotaState) AddTime(seconds int64) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	q.AccumulatedTime += seconds
+	if q.cfg.State.Shared {
+		if q.Machines == nil {
+			q.Machines = make(map[string]int64)
+		}
+		q.Machines[q.cfg.State.Machine()] += seconds
+	}
+}
+
+// AddGameTime 增加某个游戏当天的累计时间（秒）
+func (q *QuotaState) AddGameTime(game string, seconds int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.GameSeconds == nil {
+		q.GameSeconds = make(map[string]int64)
+	}
+	q.GameSeconds[game] += seconds
+}
+
+// GetGameSeconds 返回当天各游戏累计时间（秒）的副本
+func (q *QuotaState) GetGameSeconds() map[string]int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	games := make(map[string]int64, len(q.GameSeconds))
+	for game, seconds := range q.GameSeconds {
+		games[game] = seconds
+	}
+	return games
 }
 
 // ShouldReset 检查是否应该重置配额
@@ -90,7 +196,7 @@ func (q *QuotaState) ShouldReset() (bool, error) {
 	defer q.mu.Unlock()
 
 	// 使用已存储的下次重置时间
-	return time.Now().After(time.Unix(q.NextResetTime, 0)), nil
+	return q.now().After(time.Unix(q.NextResetTime, 0)), nil
 }
 
 // Reset 重置配额
@@ -98,49 +204,99 @@ func (q *QuotaState) Reset() error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	now := time.Now()
+	now := q.now()
 	q.AccumulatedTime = 0
 	q.LastResetTime = now.Unix()
-	q.FirstWarningNotified = false
-	q.FinalWarningNotified = false
+	q.WarningsNotified = nil
 	q.LimitNotified = false
+	q.LimitHits = 0
+	q.Terminations = 0
+	q.GameSeconds = nil
+	q.PausedGames = nil
+	q.ExtensionMinutes = 0
+	q.EarnSeconds = nil
+	q.ControlNonces = nil
+	q.Machines = nil
+
+	// 从当前时间重新计算下次重置时间，守护进程停止期间错过多个重置点时也直接落在未来最近的一个
+	nextReset, err := nextResetAfter(q.cfg, now)
+	if err != nil {
+		return err
+	}
+
+	q.NextResetTime = nextReset.Unix()
+
+	return nil
+}
+
+// NextResetAfter 返回 now 之后的下一个重置边界（按配置时区），即 now 所在重置周期的结束时间
+func NextResetAfter(cfg *config.Config, now time.Time) (time.Time, error) {
+	return nextResetAfter(cfg, now)
+}
+
+// nextResetAfter 计算 now 之后的下一个重置边界（按配置时区）
+func nextResetAfter(cfg *config.Config, now time.Time) (time.Time, error) {
+	resetTimeParsed, err := time.Parse("15:04", cfg.ResetTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的重置时间格式: %w", err)
+	}
 
-	// 重新计算下次重置时间
-	resetTimeParsed, err := time.Parse("15:04", q.cfg.ResetTime)
+	loc, err := cfg.Location()
 	if err != nil {
-		return fmt.Errorf("无效的重置时间格式: %w", err)
+		return time.Time{}, err
 	}
 
-	nextReset := time.Date(now.Year(), now.Month(), now.Day(),
-		resetTimeParsed.Hour(), resetTimeParsed.Minute(), 0, 0, now.Location())
+	local := now.In(loc)
+	nextReset := time.Date(local.Year(), local.Month(), local.Day(),
+		resetTimeParsed.Hour(), resetTimeParsed.Minute(), 0, 0, loc)
 
-	// 如果今天的重置时间已过，则设置为明天
-	if now.After(nextReset) {
-		nextReset = nextReset.Add(24 * time.Hour)
+	// 如果今天的重置时间已过（恰好在重置时刻也算已过，否则下一次扫描会立即再重置一次），则设置为明天
+	if !now.Before(nextReset) {
+		nextReset = nextReset.AddDate(0, 0, 1)
 	}
 
-	q.NextResetTime = nextReset.Unix()
+	return nextReset, nil
+}
 
-	return nil
+// NextReset 返回下次重置的时间点
+func (q *QuotaState) NextReset() time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return time.Unix(q.NextResetTime, 0)
 }
 
-// TimeUntilNextReset 获取距离下次重置的时间
+// TimeUntilNextReset 获取距离下次重置的时间（按状态的时钟计算）
 func (q *QuotaState) TimeUntilNextReset() time.Duration {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	return time.Until(time.Unix(q.NextResetTime, 0))
+	return time.Unix(q.NextResetTime, 0).Sub(q.now())
 }
 
-// SaveToFile 保存状态到文件
+// SaveToFile 保存状态到文件。
+// 启用 state.shared 时先对状态文件加锁并合并文件中其他电脑的累计时间，合并结果同时更新到内存
 func (q *QuotaState) SaveToFile() error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	if q.cfg.State.Shared {
+		unlock, err := lockStateFile(q.cfg.StateFile)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		q.mergeFromFile()
+	}
+
 	data, err := json.MarshalIndent(q, "", "  ")
 	if err != nil {
 		return fmt.Errorf("无法序列化状态: %w", err)
 	}
 
+	data, err = sealState(q.cfg.State, data)
+	if err != nil {
+		return fmt.Errorf("无法签名状态: %w", err)
+	}
+
 	if err := os.WriteFile(q.cfg.StateFile, data, 0644); err != nil {
 		return fmt.Errorf("无法写入状态文件: %w", err)
 	}
@@ -148,12 +304,11 @@ func (q *QuotaState) SaveToFile() error {
 	return nil
 }
 
-// LoadFromFile 从文件加载状态
+// LoadFromFile 从文件加载状态，文件不存在时返回包含 ErrNoState 的错误
 func LoadFromFile(cfg *config.Config) (*QuotaState, error) {
 	path := cfg.StateFile
-	// 如果文件不存在，返回错误
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil, fmt.Errorf("状态文件不存在: %s", path)
+		return nil, fmt.Errorf("%w: %s", ErrNoState, path)
 	}
 
 	data, err := os.ReadFile(path)
@@ -161,15 +316,62 @@ func LoadFromFile(cfg *config.Config) (*QuotaState, error) {
 		return nil, fmt.Errorf("无法读取状态文件: %w", err)
 	}
 
+	data, err = openState(cfg.State, data)
+	if err != nil {
+		return nil, err
+	}
+
 	var state QuotaState
 	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("无法解析状态文件: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrStateCorrupt, err)
 	}
 	state.cfg = cfg
+	state.migrateWarningFlags(data)
+	state.reconcileNextReset()
 
 	return &state, nil
 }
 
+// reconcileNextReset 按当前配置从上次重置时间重新推算下次重置时间。
+// 启动时只在真正越过了上次重置之后的第一个边界时才重置：守护进程短暂停止后重启不会丢失当天的累计时间，
+// 停止期间错过的重置仍会执行；修改了 resetTime 或时区后，文件中按旧配置保存的时间也不会造成误判
+func (q *QuotaState) reconcileNextReset() {
+	if q.LastResetTime <= 0 {
+		return
+	}
+	nextReset, err := nextResetAfter(q.cfg, time.Unix(q.LastResetTime, 0))
+	if err != nil {
+		return
+	}
+	q.NextResetTime = nextReset.Unix()
+}
+
+// migrateWarningFlags 将旧版本状态文件中的首次/最后提醒标记转换为已提示的阈值
+func (q *QuotaState) migrateWarningFlags(data []byte) {
+	var legacy struct {
+		First bool `json:"firstWarningNotified"`
+		Final bool `json:"finalWarningNotified"`
+	}
+	if q.WarningsNotified != nil || json.Unmarshal(data, &legacy) != nil {
+		return
+	}
+	if legacy.First {
+		q.WarningsNotified = append(q.WarningsNotified, int(q.cfg.FirstThreshold))
+	}
+	if legacy.Final && q.cfg.FinalThreshold != q.cfg.FirstThreshold {
+		q.WarningsNotified = append(q.WarningsNotified, int(q.cfg.FinalThreshold))
+	}
+}
+
+// QuarantineStateFile 将无法使用的状态文件改名为 <path>.corrupt-<时间戳> 保留下来，返回新路径
+func QuarantineStateFile(path string, now time.Time) (string, error) {
+	quarantined := path + ".corrupt-" + now.Format("20060102-150405")
+	if err := os.Rename(path, quarantined); err != nil {
+		return "", fmt.Errorf("隔离损坏的状态文件失败: %w", err)
+	}
+	return quarantined, nil
+}
+
 // Validate 验证状态完整性
 func (q *QuotaState) Validate() error {
 	if q.AccumulatedTime < 0 {
@@ -187,33 +389,96 @@ func (q *QuotaState) Validate() error {
 	return nil
 }
 
-// ConsumeWarningNotifications 检查并消费警告阈值，确保每个阈值每天只触发一次
-func (q *QuotaState) ConsumeWarningNotifications() (first, final bool) {
+// dailyLimit 返回当天生效的每日限制（分钟，含奖励时间与临时延长），调用方需持有锁
+func (q *QuotaState) dailyLimit() int {
+	return int(q.cfg.RuleFor(q.now()).DailyLimit) + q.earnedMinutes() + q.ExtensionMinutes
+}
+
+// AddExtension 临时延长当天的游戏时间 minutes 分钟（下次重置时失效），返回当天共延长的分钟数。
+// 清除超限通知标记与延长后尚未到达的提醒阈值，延长的时间用完时再次提醒
+func (q *QuotaState) AddExtension(minutes int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.ExtensionMinutes += minutes
+	remaining := q.dailyLimit() - int(q.AccumulatedTime/60)
+	q.WarningsNotified = slices.DeleteFunc(q.WarningsNotified, func(threshold int) bool {
+		return threshold < remaining
+	})
+	q.LimitNotified = false
+	return q.ExtensionMinutes
+}
+
+// GetExtensionMinutes 返回当天临时延长的游戏时间（分钟）
+func (q *QuotaState) GetExtensionMinutes() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.ExtensionMinutes
+}
+
+// AddEarnTime 记录奖励应用 app 运行了 seconds 秒，返回记录前后当天奖励的游戏时间（分钟）
+func (q *QuotaState) AddEarnTime(app string, seconds int64) (before, after int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	before = q.earnedMinutes()
+	if q.EarnSeconds == nil {
+		q.EarnSeconds = make(map[string]int64)
+	}
+	q.EarnSeconds[app] += seconds
+	return before, q.earnedMinutes()
+}
+
+// GetEarnedMinutes 返回当天奖励的游戏时间（分钟）
+func (q *QuotaState) GetEarnedMinutes() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.earnedMinutes()
+}
+
+// earnedMinutes 按各奖励应用的运行时间与比例计算当天奖励的游戏时间（分钟），不超过 earn.maxMinutes；调用方需持有锁
+func (q *QuotaState) earnedMinutes() int {
+	var earned int64
+	for app, seconds := range q.EarnSeconds {
+		if ratio := q.cfg.EarnRatio(app); ratio > 0 {
+			earned += seconds / int64(ratio)
+		}
+	}
+	return min(int(earned/60), int(q.cfg.Earn.MaxMinutes))
+}
+
+// ConsumeWarningNotifications 检查并消费提醒阈值，返回刚到达的阈值（分钟），确保每个阈值每天只触发一次。
+// 一次越过多个阈值时只返回最小的一个，越过的较大阈值一并标记为已提示
+func (q *QuotaState) ConsumeWarningNotifications() (threshold int, crossed bool) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	accumulated := int(q.AccumulatedTime / 60)
-	remaining := q.cfg.DailyLimit - accumulated
+	remaining := q.dailyLimit() - accumulated
 	if remaining < 0 {
 		remaining = 0
 	}
 
-	if remaining <= q.cfg.FinalThreshold {
-		if !q.FinalWarningNotified {
-			q.FinalWarningNotified = true
-			final = true
+	// 阈值从大到小排列，最后一个满足的即为刚到达的最小阈值
+	var reached []int
+	for _, level := range q.cfg.WarningLevels() {
+		if remaining <= int(level) {
+			reached = append(reached, int(level))
 		}
-		return
+	}
+	if len(reached) == 0 {
+		return 0, false
 	}
 
-	if remaining <= q.cfg.FirstThreshold && remaining > q.cfg.FinalThreshold {
-		if !q.FirstWarningNotified {
-			q.FirstWarningNotified = true
-			first = true
+	threshold = reached[len(reached)-1]
+	if slices.Contains(q.WarningsNotified, threshold) {
+		return 0, false
+	}
+	for _, level := range reached {
+		if !slices.Contains(q.WarningsNotified, level) {
+			q.WarningsNotified = append(q.WarningsNotified, level)
 		}
 	}
-
-	return
+	return threshold, true
 }
 
 // ConsumeLimitNotification 检查并消费超限通知，确保每天只触发一次
@@ -221,7 +486,7 @@ func (q *QuotaState) ConsumeLimitNotification() bool {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	if int(q.AccumulatedTime/60) < q.cfg.DailyLimit {
+	if int(q.AccumulatedTime/60) < q.dailyLimit() {
 		return false
 	}
 	if q.LimitNotified {
@@ -230,3 +495,124 @@ func (q *QuotaState) ConsumeLimitNotification() bool {
 	q.LimitNotified = true
 	return true
 }
+
+// RecordLimitHit 记录一次超限执行并返回当天的累计次数（从 1 开始）
+func (q *QuotaState) RecordLimitHit() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.LimitHits++
+	return q.LimitHits
+}
+
+// RecordTermination 记录一次成功终止的游戏进程
+func (q *QuotaState) RecordTermination() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.Terminations++
+}
+
+// DailySummary 一个配额周期的汇总，在每日重置前由 Summary 生成
+type DailySummary struct {
+	PlayedSeconds int64            // 累计游戏时间（秒）
+	LimitReached  bool             // 是否达到每日限制（已执行超限处理）
+	Terminations  int              // 成功终止的游戏进程数
+	GameSeconds   map[string]int64 // 各游戏的累计时间（秒）
+}
+
+// Summary 返回当前配额周期的汇总
+func (q *QuotaState) Summary() DailySummary {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	games := make(map[string]int64, len(q.GameSeconds))
+	for game, seconds := range q.GameSeconds {
+		games[game] = seconds
+	}
+	return DailySummary{
+		PlayedSeconds: q.AccumulatedTime,
+		LimitReached:  q.LimitNotified,
+		Terminations:  q.Terminations,
+		GameSeconds:   games,
+	}
+}
+
+// PauseGame 暂停对某个游戏的限制，返回 false 表示该游戏已处于暂停状态
+func (q *QuotaState) PauseGame(game string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pausedIndex(game) >= 0 {
+		return false
+	}
+	q.PausedGames = append(q.PausedGames, game)
+	return true
+}
+
+// ResumeGame 恢复对某个游戏的限制，返回 false 表示该游戏未被暂停
+func (q *QuotaState) ResumeGame(game string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.pausedIndex(game)
+	if i < 0 {
+		return false
+	}
+	q.PausedGames = append(q.PausedGames[:i:i], q.PausedGames[i+1:]...)
+	return true
+}
+
+// IsGamePaused 判断某个游戏是否暂停了限制（不区分大小写）
+func (q *QuotaState) IsGamePaused(game string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pausedIndex(game) >= 0
+}
+
+// GetPausedGames 返回暂停限制的游戏列表副本
+func (q *QuotaState) GetPausedGames() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]string(nil), q.PausedGames...)
+}
+
+func (q *QuotaState) pausedIndex(game string) int {
+	for i, paused := range q.PausedGames {
+		if strings.EqualFold(paused, game) {
+			return i
+		}
+	}
+	return -1
+}
+
+// UseControlNonce 记录一条签名控制命令的随机数，返回 false 表示本周期内已执行过该随机数（重放）
+func (q *QuotaState) UseControlNonce(nonce string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if slices.Contains(q.ControlNonces, nonce) {
+		return false
+	}
+	q.ControlNonces = append(q.ControlNonces, nonce)
+	return true
+}
+
+// SetSessions 记录当前活跃的游戏会话，随下次保存写入状态文件
+func (q *QuotaState) SetSessions(sessions []process.Session) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.Sessions = sessions
+}
+
+// GetMachineSeconds 返回共享状态下当天各电脑累计时间（秒）的副本
+func (q *QuotaState) GetMachineSeconds() map[string]int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	machines := make(map[string]int64, len(q.Machines))
+	for machine, seconds := range q.Machines {
+		machines[machine] = seconds
+	}
+	return machines
+}
+
+// GetSessions 返回状态文件中保存的游戏会话
+func (q *QuotaState) GetSessions() []process.Session {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]process.Session(nil), q.Sessions...)
+}
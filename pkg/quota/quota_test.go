@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/yourusername/game-control/pkg/clock"
 	"github.com/yourusername/game-control/pkg/config"
 )
 
@@ -38,16 +40,19 @@ func TestResetClearsNotificationFlags(t *testing.T) {
 	cfg := createTestConfig(t)
 	state, _ := NewQuotaState(cfg)
 
-	state.FirstWarningNotified = true
-	state.FinalWarningNotified = true
+	state.WarningsNotified = []int{15, 5}
 	state.LimitNotified = true
+	state.RecordLimitHit()
 
 	if err := state.Reset(); err != nil {
 		t.Fatalf("Reset 失败: %v", err)
 	}
-	if state.FirstWarningNotified || state.FinalWarningNotified || state.LimitNotified {
+	if len(state.WarningsNotified) != 0 || state.LimitNotified {
 		t.Fatal("Reset 后通知去重标记应清空")
 	}
+	if state.LimitHits != 0 {
+		t.Fatalf("Reset 后超限次数应清零，实际为 %d", state.LimitHits)
+	}
 }
 
 func TestConsumeWarningNotificationsOnce(t *testing.T) {
@@ -55,14 +60,13 @@ func TestConsumeWarningNotificationsOnce(t *testing.T) {
 	state, _ := NewQuotaState(cfg)
 
 	state.AddTime(int64((120 - 14) * 60))
-	first, final := state.ConsumeWarningNotifications()
-	if !first || final {
-		t.Fatalf("剩余14分钟应触发首次警告，first=%v final=%v", first, final)
+	threshold, crossed := state.ConsumeWarningNotifications()
+	if !crossed || threshold != 15 {
+		t.Fatalf("剩余14分钟应触发 15 分钟的首次警告，threshold=%d crossed=%v", threshold, crossed)
 	}
 
-	first, final = state.ConsumeWarningNotifications()
-	if first || final {
-		t.Fatalf("同一阈值重复检查不应重复触发，first=%v final=%v", first, final)
+	if _, crossed = state.ConsumeWarningNotifications(); crossed {
+		t.Fatal("同一阈值重复检查不应重复触发")
 	}
 }
 
@@ -71,17 +75,73 @@ func TestConsumeFinalWarningOnce(t *testing.T) {
 	state, _ := NewQuotaState(cfg)
 
 	state.AddTime(int64((120 - 4) * 60))
-	first, final := state.ConsumeWarningNotifications()
-	if first || !final {
-		t.Fatalf("剩余4分钟应触发最后警告，first=%v final=%v", first, final)
+	threshold, crossed := state.ConsumeWarningNotifications()
+	if !crossed || threshold != 5 {
+		t.Fatalf("剩余4分钟应触发 5 分钟的最后警告，threshold=%d crossed=%v", threshold, crossed)
 	}
 
-	_, final = state.ConsumeWarningNotifications()
-	if final {
+	if _, crossed = state.ConsumeWarningNotifications(); crossed {
 		t.Fatal("最后警告应只触发一次")
 	}
 }
 
+func TestConsumeWarningNotifications_Ladder(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Warnings = []config.Threshold{{Minutes: 5}, {Minutes: 30}, {Minutes: 1}, {Minutes: 15}}
+	state, _ := NewQuotaState(cfg)
+
+	var fired []int
+	// 每次增加 30 秒，从剩余 120 分钟一直玩到超限
+	for i := 0; i < 240; i++ {
+		state.AddTime(30)
+		if threshold, crossed := state.ConsumeWarningNotifications(); crossed {
+			if remaining := state.GetRemainingMinutes(); remaining > threshold {
+				t.Errorf("剩余 %d 分钟时不应触发 %d 分钟的提醒", remaining, threshold)
+			}
+			fired = append(fired, threshold)
+		}
+	}
+
+	if want := []int{30, 15, 5, 1}; !slices.Equal(fired, want) {
+		t.Fatalf("每个阈值应按从大到小各触发一次，期望 %v，实际 %v", want, fired)
+	}
+}
+
+func TestConsumeWarningNotifications_SkipsPassedThresholds(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Warnings = []config.Threshold{{Minutes: 30}, {Minutes: 15}, {Minutes: 5}, {Minutes: 1}}
+	state, _ := NewQuotaState(cfg)
+
+	// 守护进程停止期间越过了 30 与 15 分钟两个阈值
+	state.AddTime(int64((120 - 10) * 60))
+	if threshold, crossed := state.ConsumeWarningNotifications(); !crossed || threshold != 15 {
+		t.Fatalf("一次越过多个阈值时应只触发最小的 15 分钟，threshold=%d crossed=%v", threshold, crossed)
+	}
+	state.AddTime(60)
+	if _, crossed := state.ConsumeWarningNotifications(); crossed {
+		t.Fatal("已越过的较大阈值不应再触发")
+	}
+}
+
+func TestAddExtension_RearmsWarnings(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Warnings = []config.Threshold{{Minutes: 30}, {Minutes: 15}, {Minutes: 5}, {Minutes: 1}}
+	state, _ := NewQuotaState(cfg)
+
+	state.AddTime(int64((120 - 4) * 60))
+	state.ConsumeWarningNotifications()
+
+	// 延长 20 分钟后剩余 24 分钟：30 分钟的提醒已过，15、5、1 分钟重新生效
+	state.AddExtension(20)
+	if want := []int{30}; !slices.Equal(state.WarningsNotified, want) {
+		t.Fatalf("延长后只应保留仍已越过的阈值，期望 %v，实际 %v", want, state.WarningsNotified)
+	}
+	state.AddTime(10 * 60)
+	if threshold, crossed := state.ConsumeWarningNotifications(); !crossed || threshold != 15 {
+		t.Fatalf("延长的时间用到剩余 14 分钟时应再次触发 15 分钟的提醒，threshold=%d crossed=%v", threshold, crossed)
+	}
+}
+
 func TestConsumeLimitNotificationOnce(t *testing.T) {
 	cfg := createTestConfig(t)
 	state, _ := NewQuotaState(cfg)
@@ -100,7 +160,7 @@ func TestSaveAndLoadCompatibility(t *testing.T) {
 	state, _ := NewQuotaState(cfg)
 
 	state.AddTime(1800)
-	state.FirstWarningNotified = true
+	state.WarningsNotified = []int{15}
 	if err := state.SaveToFile(); err != nil {
 		t.Fatalf("SaveToFile 失败: %v", err)
 	}
@@ -112,8 +172,8 @@ func TestSaveAndLoadCompatibility(t *testing.T) {
 	if loaded.GetAccumulatedMinutes() != 30 {
 		t.Fatalf("加载后累计时间应为30分钟，实际 %d", loaded.GetAccumulatedMinutes())
 	}
-	if !loaded.FirstWarningNotified {
-		t.Fatal("应保留已触发的首次警告标记")
+	if !slices.Equal(loaded.WarningsNotified, []int{15}) {
+		t.Fatal("应保留已触发的提醒阈值")
 	}
 }
 
@@ -133,7 +193,418 @@ func TestLoadOldStateWithoutFlags(t *testing.T) {
 	if err != nil {
 		t.Fatalf("加载旧状态失败: %v", err)
 	}
-	if loaded.FirstWarningNotified || loaded.FinalWarningNotified || loaded.LimitNotified {
+	if len(loaded.WarningsNotified) != 0 || loaded.LimitNotified {
 		t.Fatal("旧状态加载后新增标记字段应默认 false")
 	}
 }
+
+func TestLoadFromFile_MigratesLegacyWarningFlags(t *testing.T) {
+	cfg := createTestConfig(t)
+	state, _ := NewQuotaState(cfg)
+	data, _ := json.Marshal(map[string]any{
+		"accumulatedTime":      state.AccumulatedTime,
+		"lastResetTime":        state.LastResetTime,
+		"nextResetTime":        state.NextResetTime,
+		"firstWarningNotified": true,
+		"finalWarningNotified": false,
+	})
+	if err := os.WriteFile(cfg.StateFile, data, 0644); err != nil {
+		t.Fatalf("写入状态文件失败: %v", err)
+	}
+
+	loaded, err := LoadFromFile(cfg)
+	if err != nil {
+		t.Fatalf("LoadFromFile 失败: %v", err)
+	}
+	if want := []int{15}; !slices.Equal(loaded.WarningsNotified, want) {
+		t.Errorf("旧版本的首次提醒标记应转换为 15 分钟阈值，实际 %v", loaded.WarningsNotified)
+	}
+}
+
+func TestNextResetUsesConfiguredTimezone(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Timezone = "Asia/Shanghai"
+
+	state, err := NewQuotaState(cfg)
+	if err != nil {
+		t.Fatalf("NewQuotaState 失败: %v", err)
+	}
+
+	// Asia/Shanghai 的 08:00 对应 UTC 00:00
+	next := time.Unix(state.NextResetTime, 0).UTC()
+	if next.Hour() != 0 || next.Minute() != 0 || next.Second() != 0 {
+		t.Fatalf("下次重置应落在 UTC 00:00，实际为 %v", next)
+	}
+	if until := time.Until(next); until <= 0 || until > 24*time.Hour {
+		t.Fatalf("下次重置应在未来 24 小时内，实际距今 %v", until)
+	}
+}
+
+func TestNextResetAfterFixedInstant(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Timezone = "Asia/Shanghai"
+
+	// UTC 2026-01-01 01:00 即上海 09:00，已过当天 08:00，应落在次日 UTC 00:00
+	now := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+	next, err := nextResetAfter(cfg, now)
+	if err != nil {
+		t.Fatalf("nextResetAfter 失败: %v", err)
+	}
+
+	want := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Fatalf("下次重置应为 %v，实际为 %v", want, next.UTC())
+	}
+}
+
+func TestNextResetAtExactBoundary(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Timezone = "UTC"
+
+	// 恰好在重置时刻创建状态，下次重置应为次日而不是当前时刻
+	clk := clock.NewManual(time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC))
+	state, err := NewQuotaStateWithClock(cfg, clk)
+	if err != nil {
+		t.Fatalf("NewQuotaStateWithClock 失败: %v", err)
+	}
+	clk.Advance(5 * time.Second)
+	if shouldReset, _ := state.ShouldReset(); shouldReset {
+		t.Fatalf("重置时刻创建的状态不应在 5 秒后再次重置，下次重置为 %v", time.Unix(state.NextResetTime, 0).UTC())
+	}
+}
+
+func TestResetAfterSeveralMissedBoundaries(t *testing.T) {
+	cfg := createTestConfig(t)
+	state, _ := NewQuotaState(cfg)
+
+	// 守护进程停止了三天：下次重置时间停留在三天前
+	state.AccumulatedTime = 90 * 60
+	state.NextResetTime = time.Now().AddDate(0, 0, -3).Unix()
+
+	shouldReset, err := state.ShouldReset()
+	if err != nil || !shouldReset {
+		t.Fatalf("错过重置时间后应需要重置，实际 %v, %v", shouldReset, err)
+	}
+	if err := state.Reset(); err != nil {
+		t.Fatalf("Reset 失败: %v", err)
+	}
+
+	next := time.Unix(state.NextResetTime, 0)
+	if until := time.Until(next); until <= 0 || until > 24*time.Hour {
+		t.Fatalf("一次重置后下次重置应落在未来 24 小时内，实际距今 %v", until)
+	}
+	if shouldReset, _ := state.ShouldReset(); shouldReset {
+		t.Fatal("重置后不应再次需要重置")
+	}
+	if state.AccumulatedTime != 0 {
+		t.Errorf("重置后累计时间应清零，实际 %d", state.AccumulatedTime)
+	}
+}
+
+func TestResetBoundary_ManualClock(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Timezone = "UTC"
+	cfg.Days = map[string]config.DayRule{"saturday": {DailyLimit: 180}}
+
+	// 周五 07:59:59，距重置还有 1 秒
+	clk := clock.NewManual(time.Date(2026, 2, 13, 7, 59, 59, 0, time.UTC))
+	state, err := NewQuotaStateWithClock(cfg, clk)
+	if err != nil {
+		t.Fatalf("NewQuotaStateWithClock 失败: %v", err)
+	}
+	if want := time.Date(2026, 2, 13, 8, 0, 0, 0, time.UTC); state.NextResetTime != want.Unix() {
+		t.Fatalf("下次重置应为 %v，实际 %v", want, time.Unix(state.NextResetTime, 0).UTC())
+	}
+	state.AddTime(100 * 60)
+
+	clk.Advance(time.Second)
+	if shouldReset, _ := state.ShouldReset(); shouldReset {
+		t.Fatal("恰好到达重置时间时还不应重置")
+	}
+	clk.Advance(time.Second)
+	if shouldReset, _ := state.ShouldReset(); !shouldReset {
+		t.Fatal("越过重置时间后应需要重置")
+	}
+	if err := state.Reset(); err != nil {
+		t.Fatalf("Reset 失败: %v", err)
+	}
+	if again, _ := state.ShouldReset(); again {
+		t.Fatal("重置后不应立即再次需要重置")
+	}
+	if state.LastResetTime != clk.Now().Unix() || state.NextResetTime != time.Date(2026, 2, 14, 8, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("重置时间应按时钟计算，实际上次 %v，下次 %v",
+			time.Unix(state.LastResetTime, 0).UTC(), time.Unix(state.NextResetTime, 0).UTC())
+	}
+	if got := state.GetRemainingMinutes(); got != 120 {
+		t.Errorf("周五应按默认限制剩余 120 分钟，实际 %d", got)
+	}
+
+	// 拨到周六：当天规则按时钟所在的日期生效
+	clk.Set(time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC))
+	if got := state.GetRemainingMinutes(); got != 180 {
+		t.Errorf("周六应按周六限制剩余 180 分钟，实际 %d", got)
+	}
+}
+
+func TestTimeUntilNextReset_ManualClock(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Timezone = "UTC"
+
+	// 距 08:00 重置还有 2 小时
+	clk := clock.NewManual(time.Date(2026, 2, 9, 6, 0, 0, 0, time.UTC))
+	state, err := NewQuotaStateWithClock(cfg, clk)
+	if err != nil {
+		t.Fatalf("NewQuotaStateWithClock 失败: %v", err)
+	}
+	if got := state.TimeUntilNextReset(); got != 2*time.Hour {
+		t.Fatalf("距下次重置应为 2h，实际 %v", got)
+	}
+	clk.Advance(90 * time.Minute)
+	if got := state.TimeUntilNextReset(); got != 30*time.Minute {
+		t.Errorf("时钟前进 90 分钟后距下次重置应为 30m，实际 %v", got)
+	}
+}
+
+func TestSoftAndHardLimit(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.TimeLimit.SoftLimit = 90
+	state, _ := NewQuotaState(cfg)
+
+	state.AddTime(89 * 60)
+	if state.IsSoftLimitExceeded() {
+		t.Fatal("未达到软限制时不应视为超过软限制")
+	}
+
+	state.AddTime(60)
+	if !state.IsSoftLimitExceeded() {
+		t.Fatal("达到软限制时应视为超过软限制")
+	}
+	if state.IsLimitExceeded() {
+		t.Fatal("软限制与硬限制之间不应视为超限")
+	}
+
+	state.AddTime(30 * 60)
+	if !state.IsLimitExceeded() {
+		t.Fatal("达到硬限制时应视为超限")
+	}
+}
+
+func TestSoftLimitDisabled(t *testing.T) {
+	cfg := createTestConfig(t)
+	state, _ := NewQuotaState(cfg)
+
+	state.AddTime(200 * 60)
+	if state.IsSoftLimitExceeded() {
+		t.Fatal("未配置软限制时不应视为超过软限制")
+	}
+}
+
+func TestLimitBoundaryInSeconds(t *testing.T) {
+	cfg := createTestConfig(t)
+	state, _ := NewQuotaState(cfg)
+
+	state.AddTime(119*60 + 59)
+	if state.IsLimitExceeded() {
+		t.Fatal("119 分 59 秒时不应视为超限")
+	}
+	if got := state.GetRemainingSeconds(); got != 1 {
+		t.Fatalf("119 分 59 秒时应剩余 1 秒，实际 %d", got)
+	}
+	if got := state.GetAccumulatedSeconds(); got != 119*60+59 {
+		t.Fatalf("累计秒数不正确: %d", got)
+	}
+
+	state.AddTime(1)
+	if !state.IsLimitExceeded() {
+		t.Fatal("120 分 0 秒时应视为超限")
+	}
+	if got := state.GetRemainingSeconds(); got != 0 {
+		t.Fatalf("达到限制后应剩余 0 秒，实际 %d", got)
+	}
+
+	state.AddTime(30)
+	if got := state.GetRemainingSeconds(); got != 0 {
+		t.Fatalf("超过限制后剩余秒数不应为负，实际 %d", got)
+	}
+}
+
+func TestPausedGamesClearedOnReset(t *testing.T) {
+	cfg := createTestConfig(t)
+	state, _ := NewQuotaState(cfg)
+
+	if !state.PauseGame("edu.exe") || state.PauseGame("EDU.exe") {
+		t.Fatal("同一游戏（不区分大小写）只应暂停一次")
+	}
+	if !state.IsGamePaused("Edu.EXE") || state.IsGamePaused("game.exe") {
+		t.Fatal("只有被暂停的游戏应视为暂停")
+	}
+	if err := state.Reset(); err != nil {
+		t.Fatalf("Reset 失败: %v", err)
+	}
+	if len(state.GetPausedGames()) != 0 {
+		t.Fatal("每日重置后应清空暂停的游戏")
+	}
+	if state.ResumeGame("edu.exe") {
+		t.Fatal("未暂停的游戏恢复时应返回 false")
+	}
+}
+
+func TestLoadFromFile_WarmStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		nextReset time.Duration // 重置边界相对现在的偏移
+		wantReset bool
+	}{
+		// 保存后短暂停止又重启：重置边界还没到，当天的累计时间应保留
+		{name: "边界在 5 分钟后", nextReset: 5 * time.Minute},
+		// 停止期间错过了重置边界
+		{name: "边界在 1 小时前", nextReset: -time.Hour, wantReset: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t)
+			cfg.ResetTime = time.Now().Add(tt.nextReset).Format("15:04")
+			state, err := NewQuotaState(cfg)
+			if err != nil {
+				t.Fatalf("NewQuotaState 失败: %v", err)
+			}
+			// 上次重置在上一个边界之后不久，文件中的下次重置时间与配置一致
+			boundary := time.Unix(state.NextResetTime, 0)
+			if tt.wantReset {
+				boundary = boundary.AddDate(0, 0, -1)
+			}
+			state.AccumulatedTime = 30 * 60
+			state.LastResetTime = boundary.Add(-23 * time.Hour).Unix()
+			state.NextResetTime = boundary.Unix()
+			if err := state.SaveToFile(); err != nil {
+				t.Fatalf("SaveToFile 失败: %v", err)
+			}
+
+			loaded, err := LoadFromFile(cfg)
+			if err != nil {
+				t.Fatalf("LoadFromFile 失败: %v", err)
+			}
+			if loaded.NextResetTime != boundary.Unix() {
+				t.Errorf("加载后的下次重置应为 %v，实际 %v", boundary, time.Unix(loaded.NextResetTime, 0))
+			}
+			shouldReset, err := loaded.ShouldReset()
+			if err != nil {
+				t.Fatalf("ShouldReset 失败: %v", err)
+			}
+			if shouldReset != tt.wantReset {
+				t.Errorf("启动时是否重置应为 %v，实际 %v", tt.wantReset, shouldReset)
+			}
+		})
+	}
+}
+
+func TestLoadFromFile_ReconcilesNextReset(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Timezone = "UTC"
+	now := time.Now().UTC()
+	// 上次重置发生在 08:00 之后的第一个检查周期
+	boundary := time.Date(now.Year(), now.Month(), now.Day(), 8, 0, 0, 0, time.UTC)
+	if now.Before(boundary) {
+		boundary = boundary.AddDate(0, 0, -1)
+	}
+	lastReset := boundary.Add(5 * time.Second)
+
+	state, err := NewQuotaState(cfg)
+	if err != nil {
+		t.Fatalf("NewQuotaState 失败: %v", err)
+	}
+	// 文件中的下次重置时间刚刚过去，但与上次重置之后的边界不一致（如按旧的 resetTime 保存）
+	state.AccumulatedTime = 45 * 60
+	state.LastResetTime = lastReset.Unix()
+	state.NextResetTime = now.Add(-10 * time.Second).Unix()
+	if err := state.SaveToFile(); err != nil {
+		t.Fatalf("SaveToFile 失败: %v", err)
+	}
+
+	loaded, err := LoadFromFile(cfg)
+	if err != nil {
+		t.Fatalf("LoadFromFile 失败: %v", err)
+	}
+	if want := boundary.AddDate(0, 0, 1); loaded.NextResetTime != want.Unix() {
+		t.Errorf("下次重置应按上次重置时间推算为 %v，实际 %v", want, time.Unix(loaded.NextResetTime, 0).UTC())
+	}
+	if shouldReset, _ := loaded.ShouldReset(); shouldReset {
+		t.Error("没有越过真正的重置边界，加载后不应重置")
+	}
+}
+
+func TestAddEarnTime_RatioAndCap(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Earn = config.EarnConfig{
+		Apps:       []config.EarnApp{{Name: "typing.exe", Ratio: 2}, {Name: "math.exe", Ratio: 3}},
+		MaxMinutes: 3,
+	}
+	state, _ := NewQuotaState(cfg)
+
+	// 每 2 分钟奖励 1 分钟：运行 1 分钟不足以赚到整分钟
+	if before, after := state.AddEarnTime("typing.exe", 60); before != 0 || after != 0 {
+		t.Fatalf("运行 1 分钟不应赚到整分钟，实际 %d -> %d", before, after)
+	}
+	if before, after := state.AddEarnTime("typing.exe", 60); before != 0 || after != 1 {
+		t.Fatalf("运行 2 分钟应赚到 1 分钟，实际 %d -> %d", before, after)
+	}
+	// 各应用按各自比例累加：math.exe 运行 3 分钟再奖励 1 分钟
+	if _, after := state.AddEarnTime("math.exe", 180); after != 2 {
+		t.Fatalf("按各应用比例累加后应为 2 分钟，实际 %d", after)
+	}
+	if got := state.GetRemainingMinutes(); got != 122 {
+		t.Errorf("奖励时间应计入剩余时间，实际剩余 %d 分钟", got)
+	}
+
+	if _, after := state.AddEarnTime("typing.exe", 3600); after != 3 {
+		t.Fatalf("奖励时间不应超过 earn.maxMinutes，实际 %d", after)
+	}
+	state.AddTime(123 * 60)
+	if !state.IsLimitExceeded() {
+		t.Error("用完每日限制与奖励时间后应超限")
+	}
+
+	if err := state.Reset(); err != nil {
+		t.Fatalf("Reset 失败: %v", err)
+	}
+	if got := state.GetEarnedMinutes(); got != 0 {
+		t.Errorf("Reset 后奖励时间应清零，实际 %d", got)
+	}
+}
+
+func TestAddExtension_ExpiresAtReset(t *testing.T) {
+	cfg := createTestConfig(t)
+	state, _ := NewQuotaState(cfg)
+
+	state.AddTime(120 * 60)
+	state.ConsumeLimitNotification()
+	if !state.IsLimitExceeded() {
+		t.Fatal("用完每日限制后应超限")
+	}
+
+	if total := state.AddExtension(10); total != 10 {
+		t.Fatalf("延长后当天共延长应为 10 分钟，实际 %d", total)
+	}
+	if got := state.GetRemainingMinutes(); got != 10 {
+		t.Errorf("临时延长应计入剩余时间，实际剩余 %d 分钟", got)
+	}
+	if state.IsLimitExceeded() {
+		t.Error("延长后不应超限")
+	}
+	if state.AddExtension(5); state.GetExtensionMinutes() != 15 {
+		t.Errorf("多次延长应累加，实际 %d", state.GetExtensionMinutes())
+	}
+
+	// 延长的时间用完时再次通知超限
+	state.AddTime(15 * 60)
+	if !state.ConsumeLimitNotification() {
+		t.Error("延长的时间用完时应再次触发超限通知")
+	}
+
+	if err := state.Reset(); err != nil {
+		t.Fatalf("Reset 失败: %v", err)
+	}
+	if state.GetExtensionMinutes() != 0 || state.GetRemainingMinutes() != 120 {
+		t.Errorf("重置后临时延长应失效，实际延长 %d 分钟、剩余 %d 分钟", state.GetExtensionMinutes(), state.GetRemainingMinutes())
+	}
+}
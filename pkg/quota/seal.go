@@ -0,0 +1,122 @@
+package quota
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/game-control/pkg/config"
+)
+
+// ErrStateTampered 状态文件签名校验或解密失败
+var ErrStateTampered = errors.New("状态文件校验失败，可能已被篡改")
+
+// ErrNoState 状态文件不存在（守护进程尚未运行过）
+var ErrNoState = errors.New("状态文件不存在")
+
+// ErrStateCorrupt 状态文件内容无法解析
+var ErrStateCorrupt = errors.New("状态文件已损坏，无法解析")
+
+// sealedState 签名或加密后的状态文件格式
+type sealedState struct {
+	Payload    json.RawMessage `json:"payload,omitempty"`    // 签名模式下的明文状态
+	Signature  string          `json:"signature,omitempty"`  // payload 的 HMAC-SHA256（十六进制）
+	Nonce      []byte          `json:"nonce,omitempty"`      // 加密模式下的 AES-GCM nonce
+	Ciphertext []byte          `json:"ciphertext,omitempty"` // 加密模式下的密文
+}
+
+// sealState 按配置对状态 JSON 签名或加密，未配置密钥时原样返回
+func sealState(cfg config.StateConfig, data []byte) ([]byte, error) {
+	if cfg.HMACKey == "" {
+		return data, nil
+	}
+
+	var sealed sealedState
+	if cfg.Encrypt {
+		gcm, err := newGCM(cfg.HMACKey)
+		if err != nil {
+			return nil, err
+		}
+		sealed.Nonce = make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(sealed.Nonce); err != nil {
+			return nil, fmt.Errorf("无法生成随机数: %w", err)
+		}
+		sealed.Ciphertext = gcm.Seal(nil, sealed.Nonce, data, nil)
+	} else {
+		payload, err := compactJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		sealed.Payload = payload
+		sealed.Signature = sign(cfg.HMACKey, payload)
+	}
+
+	return json.MarshalIndent(sealed, "", "  ")
+}
+
+// openState 校验签名或解密状态文件，返回状态 JSON；校验失败时返回 ErrStateTampered
+func openState(cfg config.StateConfig, data []byte) ([]byte, error) {
+	if cfg.HMACKey == "" {
+		return data, nil
+	}
+
+	var sealed sealedState
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStateTampered, err)
+	}
+
+	if cfg.Encrypt {
+		gcm, err := newGCM(cfg.HMACKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(sealed.Nonce) != gcm.NonceSize() {
+			return nil, fmt.Errorf("%w: 缺少或无效的 nonce", ErrStateTampered)
+		}
+		plain, err := gcm.Open(nil, sealed.Nonce, sealed.Ciphertext, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrStateTampered, err)
+		}
+		return plain, nil
+	}
+
+	// 写入文件时 payload 会被重新缩进，签名针对紧凑格式计算
+	payload, err := compactJSON(sealed.Payload)
+	if err != nil || !hmac.Equal([]byte(sealed.Signature), []byte(sign(cfg.HMACKey, payload))) {
+		return nil, fmt.Errorf("%w: 签名不匹配", ErrStateTampered)
+	}
+	return payload, nil
+}
+
+// compactJSON 去除 JSON 中的空白，作为签名的规范形式
+func compactJSON(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, fmt.Errorf("无效的状态 JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// sign 计算 HMAC-SHA256 签名
+func sign(key string, data []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newGCM 由密钥派生 AES-256-GCM
+func newGCM(key string) (cipher.AEAD, error) {
+	derived := sha256.Sum256([]byte("game-control state encryption:" + key))
+	block, err := aes.NewCipher(derived[:])
+	if err != nil {
+		return nil, fmt.Errorf("无法初始化加密: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
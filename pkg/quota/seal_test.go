@@ -0,0 +1,70 @@
+package quota
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSignedStateRoundTrip(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		cfg := createTestConfig(t)
+		cfg.State.HMACKey = "secret"
+		cfg.State.Encrypt = encrypt
+
+		state, _ := NewQuotaState(cfg)
+		state.AddTime(1800)
+		if err := state.SaveToFile(); err != nil {
+			t.Fatalf("SaveToFile 失败 (encrypt=%v): %v", encrypt, err)
+		}
+
+		loaded, err := LoadFromFile(cfg)
+		if err != nil {
+			t.Fatalf("加载有效的受保护状态失败 (encrypt=%v): %v", encrypt, err)
+		}
+		if loaded.AccumulatedTime != 1800 {
+			t.Errorf("累计时间应为 1800，实际为 %d (encrypt=%v)", loaded.AccumulatedTime, encrypt)
+		}
+
+		data, _ := os.ReadFile(cfg.StateFile)
+		if encrypt && strings.Contains(string(data), "accumulatedTime") {
+			t.Error("加密后的状态文件不应包含明文字段")
+		}
+	}
+}
+
+func TestSignedStateRejectsModification(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.State.HMACKey = "secret"
+
+	state, _ := NewQuotaState(cfg)
+	state.AddTime(1800)
+	if err := state.SaveToFile(); err != nil {
+		t.Fatalf("SaveToFile 失败: %v", err)
+	}
+
+	data, err := os.ReadFile(cfg.StateFile)
+	if err != nil {
+		t.Fatalf("读取状态文件失败: %v", err)
+	}
+	tampered := strings.Replace(string(data), `"accumulatedTime": 1800`, `"accumulatedTime": 0`, 1)
+	if tampered == string(data) {
+		t.Fatalf("测试未能修改状态文件内容: %s", data)
+	}
+	if err := os.WriteFile(cfg.StateFile, []byte(tampered), 0644); err != nil {
+		t.Fatalf("写入状态文件失败: %v", err)
+	}
+
+	if _, err := LoadFromFile(cfg); !errors.Is(err, ErrStateTampered) {
+		t.Fatalf("被修改的签名状态应返回 ErrStateTampered，实际 %v", err)
+	}
+
+	// 用明文状态替换签名文件同样应被拒绝
+	if err := os.WriteFile(cfg.StateFile, []byte(`{"accumulatedTime": 0}`), 0644); err != nil {
+		t.Fatalf("写入状态文件失败: %v", err)
+	}
+	if _, err := LoadFromFile(cfg); !errors.Is(err, ErrStateTampered) {
+		t.Fatalf("未签名的状态应返回 ErrStateTampered，实际 %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package quota
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+const (
+	// stateLockTimeout 等待共享状态文件锁的最长时间
+	stateLockTimeout = 10 * time.Second
+	// stateLockStale 锁文件超过该时长未释放即视为持有者已崩溃（锁只在读取-合并-写入期间持有）
+	stateLockStale = 30 * time.Second
+	// stateLockRetry 锁被占用时的重试间隔
+	stateLockRetry = 50 * time.Millisecond
+)
+
+// lockStateFile 以 <path>.lock 作为咨询锁，保护多台电脑对共享状态文件的读取-合并-写入。
+// 锁文件以独占方式创建，网络共享与同步盘上均可使用；返回的函数用于释放锁
+func lockStateFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(stateLockTimeout)
+	var shareNow func() time.Time
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			host, _ := os.Hostname()
+			fmt.Fprintf(f, "%s %d %s\n", host, os.Getpid(), time.Now().Format(time.RFC3339))
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+
+		// 清理崩溃遗留的锁；Windows 上锁文件正在被删除时创建也会失败，同样重试。
+		// 锁文件的修改时间来自写入它的电脑或文件服务器，按共享所在文件系统的时钟判断是否陈旧，不受本机时钟偏差影响
+		if info, statErr := os.Stat(lockPath); statErr == nil {
+			if shareNow == nil {
+				shareNow = shareClock(lockPath)
+			}
+			if shareNow().Sub(info.ModTime()) > stateLockStale {
+				os.Remove(lockPath)
+				continue
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("等待状态文件锁超时 (%s): %w", lockPath, err)
+		}
+		time.Sleep(stateLockRetry)
+	}
+}
+
+// shareClock 返回按 lockPath 所在文件系统时钟计时的当前时间：写入一个探测文件，
+// 以其修改时间与本机时间之差校正本机时钟；探测失败时退回本机时间
+func shareClock(lockPath string) func() time.Time {
+	host, _ := os.Hostname()
+	probePath := fmt.Sprintf("%s.probe-%s-%d", lockPath, host, os.Getpid())
+	before := time.Now()
+	if err := os.WriteFile(probePath, nil, 0644); err != nil {
+		return time.Now
+	}
+	defer os.Remove(probePath)
+	info, err := os.Stat(probePath)
+	if err != nil {
+		return time.Now
+	}
+
+	skew := info.ModTime().Sub(before)
+	return func() time.Time { return time.Now().Add(skew) }
+}
+
+// mergeFromFile 将状态文件中其他电脑写入的配额合并到内存状态，调用方需持有 q.mu 与文件锁。
+// 文件不存在或无法读取时不合并，直接以本机状态覆盖；其他电脑下次保存时会重新合并回自己的累计时间
+func (q *QuotaState) mergeFromFile() {
+	if _, err := os.Stat(q.cfg.StateFile); err != nil {
+		return
+	}
+	disk, err := LoadFromFile(q.cfg)
+	if err != nil || disk.Validate() != nil {
+		return
+	}
+	q.merge(disk)
+}
+
+// merge 合并另一份同一配额的状态：
+//   - 文件已进入更新的重置周期（其他电脑已重置）时采用文件中的状态，仅保留本机的运行中会话
+//   - 文件属于更早的周期时忽略
+//   - 同一周期内各电脑的累计时间取较大值后求和，未归属任何电脑的时间（启用共享前的累计）取较大值；
+//     通知标记取或，各游戏时间取较大值（两台电脑同时玩同一游戏时按较大的一方计）
+//
+// 暂停列表、已执行的控制命令随机数与运行中会话属于本机，保留内存中的值（进入新周期时清空）
+func (q *QuotaState) merge(disk *QuotaState) {
+	switch {
+	case disk.NextResetTime > q.NextResetTime:
+		q.AccumulatedTime = disk.AccumulatedTime
+		q.LastResetTime = disk.LastResetTime
+		q.NextResetTime = disk.NextResetTime
+		q.WarningsNotified = disk.WarningsNotified
+		q.LimitNotified = disk.LimitNotified
+		q.LimitHits = disk.LimitHits
+		q.Terminations = disk.Terminations
+		q.ExtensionMinutes = disk.ExtensionMinutes
+		q.GameSeconds = disk.GameSeconds
+		q.PausedGames = nil
+		q.ControlNonces = nil
+		q.Machines = disk.Machines
+		return
+	case disk.NextResetTime < q.NextResetTime:
+		return
+	}
+
+	unattributed := max(q.unattributedSeconds(), disk.unattributedSeconds())
+	for machine, seconds := range disk.Machines {
+		if q.Machines == nil {
+			q.Machines = make(map[string]int64)
+		}
+		q.Machines[machine] = max(q.Machines[machine], seconds)
+	}
+	q.AccumulatedTime = unattributed
+	for _, seconds := range q.Machines {
+		q.AccumulatedTime += seconds
+	}
+
+	q.LastResetTime = max(q.LastResetTime, disk.LastResetTime)
+	for _, threshold := range disk.WarningsNotified {
+		if !slices.Contains(q.WarningsNotified, threshold) {
+			q.WarningsNotified = append(q.WarningsNotified, threshold)
+		}
+	}
+	q.LimitNotified = q.LimitNotified || disk.LimitNotified
+	q.LimitHits = max(q.LimitHits, disk.LimitHits)
+	q.Terminations = max(q.Terminations, disk.Terminations)
+	q.ExtensionMinutes = max(q.ExtensionMinutes, disk.ExtensionMinutes)
+	for game, seconds := range disk.GameSeconds {
+		if q.GameSeconds == nil {
+			q.GameSeconds = make(map[string]int64)
+		}
+		q.GameSeconds[game] = max(q.GameSeconds[game], seconds)
+	}
+}
+
+// unattributedSeconds 返回未记录在任何电脑名下的累计时间
+func (q *QuotaState) unattributedSeconds() int64 {
+	rest := q.AccumulatedTime
+	for _, seconds := range q.Machines {
+		rest -= seconds
+	}
+	return max(rest, 0)
+}
@@ -0,0 +1,184 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/game-control/pkg/config"
+)
+
+// sharedConfig 返回共用 stateFile 的某台电脑的配置
+func sharedConfig(stateFile, machine string) *config.Config {
+	return &config.Config{
+		DailyLimit:     120,
+		ResetTime:      "08:00",
+		FirstThreshold: 15,
+		FinalThreshold: 5,
+		StateFile:      stateFile,
+		State:          config.StateConfig{Shared: true, MachineID: machine},
+	}
+}
+
+func TestSharedState_TwoDaemonsRacing(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	var wg sync.WaitGroup
+	states := make([]*QuotaState, 2)
+	for i, machine := range []string{"laptop", "desktop"} {
+		state, err := NewQuotaState(sharedConfig(stateFile, machine))
+		if err != nil {
+			t.Fatalf("创建状态失败: %v", err)
+		}
+		states[i] = state
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				state.AddTime(5)
+				if err := state.SaveToFile(); err != nil {
+					t.Errorf("%s 保存失败: %v", machine, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := LoadFromFile(sharedConfig(stateFile, "laptop"))
+	if err != nil {
+		t.Fatalf("加载共享状态失败: %v", err)
+	}
+	if loaded.AccumulatedTime != 500 {
+		t.Errorf("两台电脑的时间应合并为 500 秒，实际 %d 秒（%v）", loaded.AccumulatedTime, loaded.Machines)
+	}
+	if loaded.Machines["laptop"] != 250 || loaded.Machines["desktop"] != 250 {
+		t.Errorf("应分别记录各电脑的时间，实际 %v", loaded.Machines)
+	}
+	if _, err := os.Stat(stateFile + ".lock"); !os.IsNotExist(err) {
+		t.Error("保存完成后应释放锁文件")
+	}
+
+	// 再保存一次后，两台电脑的内存状态都包含对方的时间，据此执行合并后的限制
+	for _, state := range states {
+		if err := state.SaveToFile(); err != nil {
+			t.Fatalf("保存失败: %v", err)
+		}
+	}
+	for _, state := range states {
+		if got := state.GetAccumulatedSeconds(); got != 500 {
+			t.Errorf("保存后内存中的累计时间应为合并值 500 秒，实际 %d 秒", got)
+		}
+	}
+}
+
+func TestSharedState_AdoptsNewerPeriod(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	desktop, _ := NewQuotaState(sharedConfig(stateFile, "desktop"))
+	desktop.AddTime(600)
+	if err := desktop.SaveToFile(); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+
+	// 笔记本仍停留在上一个周期
+	laptop, _ := NewQuotaState(sharedConfig(stateFile, "laptop"))
+	laptop.NextResetTime -= int64(24 * time.Hour / time.Second)
+	laptop.AddTime(3000)
+	laptop.LimitNotified = true
+	if err := laptop.SaveToFile(); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+
+	if laptop.AccumulatedTime != 600 || laptop.LimitNotified {
+		t.Errorf("文件已进入新周期时应采用文件中的状态，实际 %d 秒，超限标记 %v", laptop.AccumulatedTime, laptop.LimitNotified)
+	}
+	if laptop.NextResetTime != desktop.NextResetTime {
+		t.Error("应采用文件中的下次重置时间")
+	}
+}
+
+func TestSharedState_KeepsUnattributedTime(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	// 启用共享前已累计的时间不属于任何电脑
+	legacy, _ := NewQuotaState(sharedConfig(stateFile, "desktop"))
+	legacy.AccumulatedTime = 1200
+	legacy.WarningsNotified = []int{15}
+	if err := legacy.SaveToFile(); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+
+	laptop, _ := NewQuotaState(sharedConfig(stateFile, "laptop"))
+	laptop.AddTime(60)
+	if err := laptop.SaveToFile(); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+	if laptop.AccumulatedTime != 1260 {
+		t.Errorf("应保留未归属的 1200 秒并加上本机的 60 秒，实际 %d 秒", laptop.AccumulatedTime)
+	}
+	if !slices.Contains(laptop.WarningsNotified, 15) {
+		t.Error("其他电脑已发出的提醒标记应合并")
+	}
+}
+
+func TestSharedState_RemovesStaleLock(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	lockPath := stateFile + ".lock"
+	if err := os.WriteFile(lockPath, []byte("crashed"), 0644); err != nil {
+		t.Fatalf("创建锁文件失败: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatalf("修改锁文件时间失败: %v", err)
+	}
+
+	state, _ := NewQuotaState(sharedConfig(stateFile, "laptop"))
+	if err := state.SaveToFile(); err != nil {
+		t.Fatalf("应清理陈旧锁后保存成功: %v", err)
+	}
+}
+
+func TestSharedState_WaitsForFreshLock(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	lockPath := stateFile + ".lock"
+	if err := os.WriteFile(lockPath, []byte("desktop 42 holding"), 0644); err != nil {
+		t.Fatalf("创建锁文件失败: %v", err)
+	}
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		close(released)
+		os.Remove(lockPath)
+	}()
+
+	state, _ := NewQuotaState(sharedConfig(stateFile, "laptop"))
+	if err := state.SaveToFile(); err != nil {
+		t.Fatalf("锁释放后应保存成功: %v", err)
+	}
+	select {
+	case <-released:
+	default:
+		t.Fatal("刚写入的锁不应被视为陈旧而被删除")
+	}
+}
+
+func TestShareClock_UsesProbeFile(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "state.json.lock")
+
+	now := shareClock(lockPath)
+	if skew := now().Sub(time.Now()); skew < -5*time.Second || skew > 5*time.Second {
+		t.Errorf("本地文件系统的时钟应与本机一致，实际偏差 %v", skew)
+	}
+	entries, err := os.ReadDir(filepath.Dir(lockPath))
+	if err != nil {
+		t.Fatalf("读取目录失败: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("探测文件应在测量后删除，实际残留 %d 个文件", len(entries))
+	}
+}
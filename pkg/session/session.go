@@ -0,0 +1,40 @@
+// Package session 对当前交互会话执行锁定、注销等操作，用于超限动作 enforcement.onLimit 的 lock / logoff
+package session
+
+import (
+	"fmt"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+// Actions 执行会话级操作。
+// 锁定与注销作用于执行命令的进程所在的会话：以服务身份在会话 0 中运行时无法锁定或注销用户桌面。
+type Actions struct {
+	runner sysexec.CommandRunner
+}
+
+// NewActions 创建会话操作执行器
+func NewActions() *Actions {
+	return NewActionsWithRunner(sysexec.WindowsRunner{})
+}
+
+// NewActionsWithRunner 创建使用指定命令执行器的会话操作执行器（用于测试）
+func NewActionsWithRunner(runner sysexec.CommandRunner) *Actions {
+	return &Actions{runner: runner}
+}
+
+// Lock 锁定工作站（相当于 Win+L），游戏进程保持运行
+func (a *Actions) Lock() error {
+	if _, err := a.runner.Run("rundll32.exe", "user32.dll,LockWorkStation"); err != nil {
+		return fmt.Errorf("锁定工作站失败: %w", err)
+	}
+	return nil
+}
+
+// Logoff 注销当前用户，未保存的工作会丢失
+func (a *Actions) Logoff() error {
+	if _, err := a.runner.Run("shutdown", "/l"); err != nil {
+		return fmt.Errorf("注销当前用户失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package session
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/game-control/pkg/sysexec"
+)
+
+func TestActions_Commands(t *testing.T) {
+	tests := []struct {
+		name   string
+		action func(*Actions) error
+		expect string
+	}{
+		{name: "lock", action: (*Actions).Lock, expect: "rundll32.exe user32.dll,LockWorkStation"},
+		{name: "logoff", action: (*Actions).Logoff, expect: "shutdown /l"},
+	}
+
+	for _, tt := range tests {
+		fake := &sysexec.FakeRunner{}
+		if err := tt.action(NewActionsWithRunner(fake)); err != nil {
+			t.Fatalf("%s 失败: %v", tt.name, err)
+		}
+		if len(fake.Calls) != 1 {
+			t.Fatalf("%s 应只执行一条命令，实际 %v", tt.name, fake.Calls)
+		}
+		if got := strings.Join(fake.Calls[0], " "); got != tt.expect {
+			t.Errorf("%s 命令不正确: %s，预期 %s", tt.name, got, tt.expect)
+		}
+	}
+}
+
+func TestActions_CommandFailure(t *testing.T) {
+	fake := &sysexec.FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return nil, errors.New("拒绝访问")
+		},
+	}
+	if err := NewActionsWithRunner(fake).Logoff(); err == nil || !strings.Contains(err.Error(), "拒绝访问") {
+		t.Errorf("命令失败时应返回包含原因的错误，实际 %v", err)
+	}
+}
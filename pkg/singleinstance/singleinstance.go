@@ -1,50 +1,84 @@
 package singleinstance
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 )
 
 var ErrAlreadyRunning = errors.New("instance already running")
 
+// ErrNotRunning 没有找到实例锁持有者
+var ErrNotRunning = errors.New("no running instance")
+
 type Guard struct {
-	path string
-	file *os.File
+	path   string
+	file   *os.File
+	handle uintptr // Windows 命名互斥量句柄，其他平台为 0
 }
 
-func Acquire(name string) (*Guard, error) {
-	path := lockFilePath(name)
+// DefaultStaleAfter 锁文件记录的获取时间超过该时长即视为陈旧锁
+const DefaultStaleAfter = 24 * time.Hour
 
-	for attempt := 0; attempt < 2; attempt++ {
-		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
-		if err == nil {
-			_, _ = fmt.Fprintf(file, "%d\n%d\n", os.Getpid(), time.Now().Unix())
-			return &Guard{path: path, file: file}, nil
-		}
-		if !os.IsExist(err) {
-			return nil, fmt.Errorf("无法创建实例锁文件: %w", err)
-		}
+// Options 实例锁选项，零值使用默认设置
+type Options struct {
+	Dir        string        // 锁文件目录，为空时使用 os.TempDir()
+	StaleAfter time.Duration // 陈旧锁判定时长，为 0 时使用 DefaultStaleAfter；仅锁文件实现使用
+}
 
-		active, checkErr := lockOwnedByActiveProcess(path)
-		if checkErr != nil {
-			return nil, checkErr
-		}
-		if active {
-			return nil, ErrAlreadyRunning
-		}
+func (o Options) dir() string {
+	if o.Dir == "" {
+		return os.TempDir()
+	}
+	return o.Dir
+}
+
+func (o Options) staleAfter() time.Duration {
+	if o.StaleAfter <= 0 {
+		return DefaultStaleAfter
+	}
+	return o.StaleAfter
+}
+
+// Acquire 使用默认选项获取名为 name 的单实例锁。
+// Windows 使用内核命名互斥量，其他平台使用带 PID 的锁文件；两者都会在锁文件中记录持有者 PID。
+func Acquire(name string) (*Guard, error) {
+	return AcquireWithOptions(name, Options{})
+}
 
-		if removeErr := os.Remove(path); removeErr != nil && !os.IsNotExist(removeErr) {
-			return nil, fmt.Errorf("清理陈旧锁文件失败: %w", removeErr)
+// AcquireWithOptions 按指定的锁目录与陈旧锁时长获取单实例锁
+func AcquireWithOptions(name string, opts Options) (*Guard, error) {
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+			return nil, fmt.Errorf("无法创建锁文件目录: %w", err)
 		}
 	}
+	return acquire(name, opts)
+}
 
-	return nil, ErrAlreadyRunning
+// NamespacedName 在 name 后附加配置文件路径的摘要，
+// 使监控不同配置文件的守护进程各自持有独立的锁。configPath 为空时原样返回 name。
+func NamespacedName(name, configPath string) string {
+	if configPath == "" {
+		return name
+	}
+	if abs, err := filepath.Abs(configPath); err == nil {
+		configPath = abs
+	}
+	configPath = filepath.Clean(configPath)
+	if runtime.GOOS == "windows" {
+		// Windows 路径不区分大小写
+		configPath = strings.ToLower(configPath)
+	}
+	sum := sha256.Sum256([]byte(configPath))
+	return name + "-" + hex.EncodeToString(sum[:])[:12]
 }
 
 func (g *Guard) Release() error {
@@ -54,6 +88,10 @@ func (g *Guard) Release() error {
 	if g.file != nil {
 		_ = g.file.Close()
 	}
+	if g.handle != 0 {
+		closeHandle(g.handle)
+		g.handle = 0
+	}
 	if g.path == "" {
 		return nil
 	}
@@ -63,68 +101,59 @@ func (g *Guard) Release() error {
 	return nil
 }
 
-func lockOwnedByActiveProcess(path string) (bool, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return false, nil
-		}
-		return false, fmt.Errorf("读取锁文件失败: %w", err)
-	}
-
-	parts := strings.Split(strings.TrimSpace(string(data)), "\n")
-	if len(parts) == 0 {
-		return false, nil
-	}
-
-	pid, err := strconv.Atoi(strings.TrimSpace(parts[0]))
-	if err != nil || pid <= 0 {
-		return false, nil
-	}
-
-	if len(parts) > 1 {
-		if ts, parseErr := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64); parseErr == nil {
-			if time.Since(time.Unix(ts, 0)) > 24*time.Hour {
-				return false, nil
-			}
-		}
-	}
-
-	return isProcessRunning(pid), nil
-}
-
-func lockFilePath(name string) string {
+func safeName(name string) string {
 	safe := strings.ReplaceAll(name, string(os.PathSeparator), "_")
+	safe = strings.ReplaceAll(safe, "\\", "_")
 	safe = strings.ReplaceAll(safe, " ", "_")
 	if safe == "" {
 		safe = "game-control"
 	}
-	return filepath.Join(os.TempDir(), safe+".lock")
+	return safe
+}
+
+// LockFilePath 返回实例锁在 opts.Dir 下对应的锁文件路径（opts.Dir 为空时为默认锁目录）
+func LockFilePath(name string, opts Options) string {
+	return lockFilePath(name, opts)
 }
 
-func isProcessRunning(pid int) bool {
-	process, err := os.FindProcess(pid)
+func lockFilePath(name string, opts Options) string {
+	return filepath.Join(opts.dir(), safeName(name)+".lock")
+}
+
+// ReadOwner 读取默认锁目录下实例锁记录的持有者 PID 与获取时间。
+// 锁文件不存在时返回 ErrNotRunning；返回的 PID 仍需结合 IsProcessAlive 判断是否存活。
+func ReadOwner(name string) (pid int, since time.Time, err error) {
+	return ReadOwnerWithOptions(name, Options{})
+}
+
+// ReadOwnerWithOptions 读取 opts.Dir 下实例锁记录的持有者 PID 与获取时间
+func ReadOwnerWithOptions(name string, opts Options) (pid int, since time.Time, err error) {
+	data, err := os.ReadFile(lockFilePath(name, opts))
 	if err != nil {
-		return false
+		if os.IsNotExist(err) {
+			return 0, time.Time{}, ErrNotRunning
+		}
+		return 0, time.Time{}, fmt.Errorf("读取锁文件失败: %w", err)
 	}
+	return parseLockContent(data)
+}
 
-	err = process.Signal(syscall.Signal(0))
-	if err == nil {
-		return true
-	}
+// parseLockContent 解析锁文件内容：第一行为 PID，第二行（可选）为获取时间的 Unix 时间戳
+func parseLockContent(data []byte) (pid int, since time.Time, err error) {
+	parts := strings.Split(strings.TrimSpace(string(data)), "\n")
 
-	if errors.Is(err, os.ErrProcessDone) {
-		return false
+	pid, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || pid <= 0 {
+		return 0, time.Time{}, fmt.Errorf("锁文件中的 PID 无效: %q", parts[0])
 	}
 
-	if errno, ok := err.(syscall.Errno); ok {
-		if errno == syscall.EPERM {
-			return true
-		}
-		if errno == syscall.ESRCH {
-			return false
+	if len(parts) > 1 {
+		ts, parseErr := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if parseErr != nil {
+			return 0, time.Time{}, fmt.Errorf("锁文件中的时间戳无效: %q", parts[1])
 		}
+		since = time.Unix(ts, 0)
 	}
 
-	return false
+	return pid, since, nil
 }
@@ -0,0 +1,95 @@
+//go:build !windows
+
+package singleinstance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+func acquire(name string, opts Options) (*Guard, error) {
+	path := lockFilePath(name, opts)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = fmt.Fprintf(file, "%d\n%d\n", os.Getpid(), time.Now().Unix())
+			return &Guard{path: path, file: file}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("无法创建实例锁文件: %w", err)
+		}
+
+		active, checkErr := lockOwnedByActiveProcess(path, opts.staleAfter())
+		if checkErr != nil {
+			return nil, checkErr
+		}
+		if active {
+			return nil, ErrAlreadyRunning
+		}
+
+		if removeErr := os.Remove(path); removeErr != nil && !os.IsNotExist(removeErr) {
+			return nil, fmt.Errorf("清理陈旧锁文件失败: %w", removeErr)
+		}
+	}
+
+	return nil, ErrAlreadyRunning
+}
+
+func closeHandle(handle uintptr) {}
+
+func lockOwnedByActiveProcess(path string, staleAfter time.Duration) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("读取锁文件失败: %w", err)
+	}
+
+	pid, since, err := parseLockContent(data)
+	if err != nil {
+		return false, nil
+	}
+
+	if !since.IsZero() && time.Since(since) > staleAfter {
+		return false, nil
+	}
+
+	return isProcessRunning(pid), nil
+}
+
+// IsProcessAlive 检查 PID 对应的进程是否仍在运行
+func IsProcessAlive(pid int) bool {
+	return isProcessRunning(pid)
+}
+
+func isProcessRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = process.Signal(syscall.Signal(0))
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, os.ErrProcessDone) {
+		return false
+	}
+
+	if errno, ok := err.(syscall.Errno); ok {
+		if errno == syscall.EPERM {
+			return true
+		}
+		if errno == syscall.ESRCH {
+			return false
+		}
+	}
+
+	return false
+}
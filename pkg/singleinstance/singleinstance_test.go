@@ -1,8 +1,12 @@
 package singleinstance
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -37,7 +41,7 @@ func TestAcquireAfterRelease(t *testing.T) {
 
 func TestAcquireCleansStaleLock(t *testing.T) {
 	name := "stale-lock-instance"
-	path := lockFilePath(name)
+	path := lockFilePath(name, Options{})
 	_ = os.Remove(path)
 
 	staleTs := time.Now().Add(-48 * time.Hour).Unix()
@@ -53,3 +57,140 @@ func TestAcquireCleansStaleLock(t *testing.T) {
 	}
 	defer g.Release()
 }
+
+func TestParseLockContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantPID   int
+		wantSince int64
+		wantErr   bool
+	}{
+		{name: "PID 与时间戳", content: "1234\n1700000000\n", wantPID: 1234, wantSince: 1700000000},
+		{name: "仅 PID", content: "42\n", wantPID: 42},
+		{name: "Windows 换行", content: "7\r\n1700000000\r\n", wantPID: 7, wantSince: 1700000000},
+		{name: "空文件", content: "", wantErr: true},
+		{name: "无效 PID", content: "abc\n", wantErr: true},
+		{name: "无效时间戳", content: "12\nxyz\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pid, since, err := parseLockContent([]byte(tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("预期解析失败")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if pid != tt.wantPID {
+				t.Errorf("PID 应为 %d，实际为 %d", tt.wantPID, pid)
+			}
+			if tt.wantSince == 0 && !since.IsZero() {
+				t.Errorf("未记录时间戳时应返回零值，实际为 %v", since)
+			}
+			if tt.wantSince != 0 && since.Unix() != tt.wantSince {
+				t.Errorf("时间戳应为 %d，实际为 %d", tt.wantSince, since.Unix())
+			}
+		})
+	}
+}
+
+func TestReadOwner(t *testing.T) {
+	name := "test-instance-owner"
+	if _, _, err := ReadOwner(name); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("未持有锁时应返回 ErrNotRunning，实际为 %v", err)
+	}
+
+	g, err := Acquire(name)
+	if err != nil {
+		t.Fatalf("获取实例锁失败: %v", err)
+	}
+	defer g.Release()
+
+	pid, since, err := ReadOwner(name)
+	if err != nil {
+		t.Fatalf("ReadOwner 失败: %v", err)
+	}
+	if pid != os.Getpid() {
+		t.Errorf("持有者 PID 应为 %d，实际为 %d", os.Getpid(), pid)
+	}
+	if time.Since(since) > time.Minute {
+		t.Errorf("获取时间不正确: %v", since)
+	}
+	if !IsProcessAlive(pid) {
+		t.Error("当前进程应视为存活")
+	}
+}
+
+func TestAcquireWithOptions_CustomDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "locks")
+	opts := Options{Dir: dir}
+	name := "test-instance-dir"
+
+	g, err := AcquireWithOptions(name, opts)
+	if err != nil {
+		t.Fatalf("在自定义目录获取实例锁失败: %v", err)
+	}
+	defer g.Release()
+
+	if _, err := os.Stat(filepath.Join(dir, name+".lock")); err != nil {
+		t.Fatalf("锁文件应写入自定义目录: %v", err)
+	}
+	pid, _, err := ReadOwnerWithOptions(name, opts)
+	if err != nil || pid != os.Getpid() {
+		t.Fatalf("应能从自定义目录读取持有者，实际 pid=%d err=%v", pid, err)
+	}
+	if _, _, err := ReadOwner(name); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("默认目录下不应存在该锁，实际为 %v", err)
+	}
+}
+
+func TestAcquireWithOptions_CustomStaleTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 使用命名互斥量，不按时间判断陈旧锁")
+	}
+
+	opts := Options{Dir: t.TempDir(), StaleAfter: time.Hour}
+	name := "test-instance-stale"
+	path := lockFilePath(name, opts)
+
+	// 持有者仍存活，但获取时间早于 StaleAfter
+	ts := time.Now().Add(-2 * time.Hour).Unix()
+	content := strconv.Itoa(os.Getpid()) + "\n" + strconv.FormatInt(ts, 10) + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("写入锁文件失败: %v", err)
+	}
+
+	if _, err := AcquireWithOptions(name, Options{Dir: opts.Dir}); !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("默认 24 小时内的锁应视为有效，实际为 %v", err)
+	}
+
+	g, err := AcquireWithOptions(name, opts)
+	if err != nil {
+		t.Fatalf("超过自定义陈旧时长的锁应被清理: %v", err)
+	}
+	defer g.Release()
+}
+
+func TestNamespacedName(t *testing.T) {
+	dir := t.TempDir()
+	a := NamespacedName("game-control-main", filepath.Join(dir, "a.yaml"))
+	b := NamespacedName("game-control-main", filepath.Join(dir, "b.yaml"))
+
+	if a == b {
+		t.Fatal("不同配置文件应得到不同的实例名")
+	}
+	if !strings.HasPrefix(a, "game-control-main-") {
+		t.Errorf("实例名应以基础名称开头，实际 %q", a)
+	}
+	if again := NamespacedName("game-control-main", filepath.Join(dir, ".", "a.yaml")); again != a {
+		t.Errorf("等价路径应得到相同实例名，实际 %q 与 %q", again, a)
+	}
+	if got := NamespacedName("game-control-main", ""); got != "game-control-main" {
+		t.Errorf("未指定配置路径时应原样返回，实际 %q", got)
+	}
+}
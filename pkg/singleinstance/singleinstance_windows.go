@@ -0,0 +1,71 @@
+//go:build windows
+
+package singleinstance
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procCreateMutexW = kernel32.NewProc("CreateMutexW")
+)
+
+// acquire 通过 Global\ 命名互斥量保证单实例。
+// 互斥量随进程退出由系统自动释放，不会因 PID 复用被误判，因此不使用 opts.StaleAfter。
+func acquire(name string, opts Options) (*Guard, error) {
+	mutexName, err := syscall.UTF16PtrFromString(`Global\` + safeName(name))
+	if err != nil {
+		return nil, fmt.Errorf("无效的实例名称: %w", err)
+	}
+
+	h, _, callErr := procCreateMutexW.Call(0, 0, uintptr(unsafe.Pointer(mutexName)))
+	if h == 0 {
+		// 互斥量已由其他账户创建且无权打开
+		if callErr == syscall.ERROR_ACCESS_DENIED {
+			return nil, ErrAlreadyRunning
+		}
+		return nil, fmt.Errorf("创建实例互斥量失败: %w", callErr)
+	}
+	if callErr == syscall.ERROR_ALREADY_EXISTS {
+		closeHandle(h)
+		return nil, ErrAlreadyRunning
+	}
+
+	// 锁文件仅记录持有者信息，供管理命令读取，不参与互斥判断
+	path := lockFilePath(name, opts)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return &Guard{handle: h}, nil
+	}
+	_, _ = fmt.Fprintf(file, "%d\n%d\n", os.Getpid(), time.Now().Unix())
+	return &Guard{path: path, file: file, handle: h}, nil
+}
+
+func closeHandle(handle uintptr) {
+	_ = syscall.CloseHandle(syscall.Handle(handle))
+}
+
+// stillActive GetExitCodeProcess 对运行中进程返回的退出码
+const stillActive = 259
+
+// IsProcessAlive 检查 PID 对应的进程是否仍在运行
+func IsProcessAlive(pid int) bool {
+	const processQueryLimitedInformation = 0x1000
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		// 无权打开说明进程存在
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer syscall.CloseHandle(h)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
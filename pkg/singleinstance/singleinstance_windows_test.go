@@ -0,0 +1,25 @@
+//go:build windows
+
+package singleinstance
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestAcquireUsesNamedMutex(t *testing.T) {
+	name := "test-instance-mutex"
+	g1, err := Acquire(name)
+	if err != nil {
+		t.Fatalf("首次获取实例锁失败: %v", err)
+	}
+	defer g1.Release()
+
+	// 删除锁文件后互斥量仍然有效
+	_ = os.Remove(lockFilePath(name, Options{}))
+
+	if _, err := Acquire(name); !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("互斥量存在时应返回 ErrAlreadyRunning，实际为 %v", err)
+	}
+}
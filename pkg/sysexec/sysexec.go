@@ -0,0 +1,134 @@
+// Package sysexec 封装外部命令的执行，便于在测试中替换为假实现
+package sysexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// ErrUnsupportedPlatform 在非 Windows 平台调用 Windows 专用命令时返回
+var ErrUnsupportedPlatform = errors.New("当前只支持 Windows 平台")
+
+// CommandRunner 执行外部命令并返回标准输出。
+// 命令失败时返回的错误包含标准错误输出，便于写入日志。
+type CommandRunner interface {
+	Run(name string, args ...string) ([]byte, error)
+}
+
+// ContextRunner 可随 ctx 取消或超时结束命令的 CommandRunner
+type ContextRunner interface {
+	CommandRunner
+	RunContext(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// waitDelay 命令被取消后等待其输出管道关闭的时长，避免子进程持有管道导致调用方一直阻塞
+const waitDelay = time.Second
+
+// RunTimeout 在 timeout 内执行命令并返回标准输出，超时返回的错误包含 context.DeadlineExceeded。
+// runner 实现 ContextRunner 时超时会结束命令；否则命令在独立 goroutine 中执行，超时后不再等待其结果
+func RunTimeout(runner CommandRunner, timeout time.Duration, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if cr, ok := runner.(ContextRunner); ok {
+		output, err := cr.RunContext(ctx, name, args...)
+		if ctx.Err() != nil {
+			return output, fmt.Errorf("执行 %s 超过 %s 未完成: %w", name, timeout, ctx.Err())
+		}
+		return output, err
+	}
+
+	type result struct {
+		output []byte
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := runner.Run(name, args...)
+		done <- result{output, err}
+	}()
+	select {
+	case r := <-done:
+		return r.output, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("执行 %s 超过 %s 未完成: %w", name, timeout, ctx.Err())
+	}
+}
+
+// ExecRunner 通过 os/exec 执行命令
+type ExecRunner struct{}
+
+// Run 执行命令并返回标准输出
+func (ExecRunner) Run(name string, args ...string) ([]byte, error) {
+	return ExecRunner{}.RunContext(context.Background(), name, args...)
+}
+
+// RunContext 执行命令并返回标准输出，ctx 取消或超时时结束命令
+func (ExecRunner) RunContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = waitDelay
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if stderr := bytes.TrimSpace(exitErr.Stderr); len(stderr) > 0 {
+				return output, fmt.Errorf("%w: %s", err, stderr)
+			}
+		}
+		return output, err
+	}
+	return output, nil
+}
+
+// WindowsRunner 仅在 Windows 上执行命令，其他平台直接返回 ErrUnsupportedPlatform，
+// 用于 tasklist、taskkill、schtasks 等 Windows 专用命令
+type WindowsRunner struct{}
+
+// Run 执行命令并返回标准输出
+func (WindowsRunner) Run(name string, args ...string) ([]byte, error) {
+	if runtime.GOOS != "windows" {
+		return nil, ErrUnsupportedPlatform
+	}
+	return ExecRunner{}.Run(name, args...)
+}
+
+// RunContext 执行命令并返回标准输出，ctx 取消或超时时结束命令
+func (WindowsRunner) RunContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if runtime.GOOS != "windows" {
+		return nil, ErrUnsupportedPlatform
+	}
+	return ExecRunner{}.RunContext(ctx, name, args...)
+}
+
+// FakeRunner 记录调用参数并返回预设结果的 CommandRunner，供测试使用
+type FakeRunner struct {
+	// Calls 每次调用的命令名与参数，命令名在首位
+	Calls [][]string
+	// Handler 决定每次调用的返回值，为 nil 时返回空输出
+	Handler func(name string, args ...string) ([]byte, error)
+	// ContextHandler 通过 RunContext 调用时优先使用，可据 ctx 模拟卡住后被取消的命令；为 nil 时使用 Handler
+	ContextHandler func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// Run 记录本次调用并交由 Handler 返回结果
+func (f *FakeRunner) Run(name string, args ...string) ([]byte, error) {
+	f.Calls = append(f.Calls, append([]string{name}, args...))
+	if f.Handler == nil {
+		return nil, nil
+	}
+	return f.Handler(name, args...)
+}
+
+// RunContext 记录本次调用并交由 ContextHandler（未设置时为 Handler）返回结果
+func (f *FakeRunner) RunContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if f.ContextHandler == nil {
+		return f.Run(name, args...)
+	}
+	f.Calls = append(f.Calls, append([]string{name}, args...))
+	return f.ContextHandler(ctx, name, args...)
+}
@@ -0,0 +1,99 @@
+package sysexec
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWindowsRunner_UnsupportedPlatform(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("仅在非Windows平台测试")
+	}
+
+	if _, err := (WindowsRunner{}).Run("tasklist"); !errors.Is(err, ErrUnsupportedPlatform) {
+		t.Fatalf("非 Windows 平台应返回 ErrUnsupportedPlatform，实际 %v", err)
+	}
+}
+
+func TestFakeRunner_RecordsCalls(t *testing.T) {
+	fake := &FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			if name == "fail" {
+				return nil, errors.New("失败")
+			}
+			return []byte("ok"), nil
+		},
+	}
+
+	output, err := fake.Run("echo", "a", "b")
+	if err != nil || string(output) != "ok" {
+		t.Fatalf("应返回 Handler 的结果，实际 %q, %v", output, err)
+	}
+	if _, err := fake.Run("fail"); err == nil {
+		t.Fatal("Handler 返回的错误应原样返回")
+	}
+
+	if len(fake.Calls) != 2 {
+		t.Fatalf("应记录 2 次调用，实际 %d", len(fake.Calls))
+	}
+	if got := fake.Calls[0]; len(got) != 3 || got[0] != "echo" || got[2] != "b" {
+		t.Errorf("调用记录不正确: %v", got)
+	}
+}
+
+// blockingRunner 不支持取消、一直阻塞到 release 关闭的执行器
+type blockingRunner struct {
+	release chan struct{}
+}
+
+func (b blockingRunner) Run(name string, args ...string) ([]byte, error) {
+	<-b.release
+	return nil, nil
+}
+
+func TestRunTimeout_CancelsHangingCommand(t *testing.T) {
+	var cancelled bool
+	fake := &FakeRunner{
+		ContextHandler: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			<-ctx.Done()
+			cancelled = true
+			return nil, ctx.Err()
+		},
+	}
+
+	start := time.Now()
+	_, err := RunTimeout(fake, 50*time.Millisecond, "powershell", "-Command", "hang")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("卡住的命令应返回超时错误，实际 %v", err)
+	}
+	if !cancelled {
+		t.Error("超时后应取消命令")
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("应在超时后立即返回，实际耗时 %s", took)
+	}
+}
+
+func TestRunTimeout_AbandonsRunnerWithoutContext(t *testing.T) {
+	runner := blockingRunner{release: make(chan struct{})}
+	defer close(runner.release)
+
+	if _, err := RunTimeout(runner, 50*time.Millisecond, "msg"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("不支持取消的执行器超时后也应返回超时错误，实际 %v", err)
+	}
+}
+
+func TestRunTimeout_ReturnsResult(t *testing.T) {
+	fake := &FakeRunner{
+		Handler: func(name string, args ...string) ([]byte, error) {
+			return []byte("ok"), nil
+		},
+	}
+	output, err := RunTimeout(fake, time.Second, "echo")
+	if err != nil || string(output) != "ok" {
+		t.Fatalf("未超时时应返回命令结果，实际 %q, %v", output, err)
+	}
+}
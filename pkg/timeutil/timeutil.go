@@ -0,0 +1,36 @@
+// Package timeutil 提供面向用户显示的时间格式化（按 i18n 当前语言）
+package timeutil
+
+import (
+	"time"
+
+	"github.com/yourusername/game-control/pkg/i18n"
+)
+
+// FormatDuration 将时长四舍五入到分钟并格式化为 "X 小时 Y 分钟"，
+// 不足一分钟时按秒显示（如 "45 秒"），负数按 0 处理
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return i18n.T("time.seconds", int(d.Seconds()))
+	}
+
+	total := int(d.Round(time.Minute).Minutes())
+	hours, minutes := total/60, total%60
+	if hours == 0 {
+		return i18n.T("time.minutes", minutes)
+	}
+	return i18n.T("time.hoursMinutes", hours, minutes)
+}
+
+// FormatMinutesSeconds 将时长截断到秒并格式化为 "X 分 Y 秒"，用于需要精确到秒的剩余时间显示，负数按 0 处理
+func FormatMinutesSeconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	total := int(d / time.Second)
+	return i18n.T("time.minutesSeconds", total/60, total%60)
+}
@@ -0,0 +1,47 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: -time.Second, want: "0 秒"},
+		{d: 45 * time.Second, want: "45 秒"},
+		{d: 59*time.Second + 600*time.Millisecond, want: "1 分钟"},
+		{d: 5 * time.Minute, want: "5 分钟"},
+		{d: 5*time.Minute + 29*time.Second, want: "5 分钟"},
+		{d: 5*time.Minute + 30*time.Second, want: "6 分钟"},
+		{d: 59*time.Minute + 59*time.Second, want: "1 小时 0 分钟"},
+		{d: time.Hour + time.Second, want: "1 小时 0 分钟"},
+		{d: time.Hour + 59*time.Minute + 30*time.Second, want: "2 小时 0 分钟"},
+		{d: 2*time.Hour + 3*time.Minute, want: "2 小时 3 分钟"},
+		{d: 24 * time.Hour, want: "24 小时 0 分钟"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) 应为 %q，实际为 %q", tt.d, tt.want, got)
+		}
+	}
+}
+
+func TestFormatMinutesSeconds(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: -time.Second, want: "0 分 0 秒"},
+		{d: 10*time.Second + 900*time.Millisecond, want: "0 分 10 秒"},
+		{d: 119*time.Minute + 59*time.Second, want: "119 分 59 秒"},
+		{d: 2 * time.Hour, want: "120 分 0 秒"},
+	}
+	for _, tt := range tests {
+		if got := FormatMinutesSeconds(tt.d); got != tt.want {
+			t.Errorf("FormatMinutesSeconds(%v) 应为 %q，实际为 %q", tt.d, tt.want, got)
+		}
+	}
+}